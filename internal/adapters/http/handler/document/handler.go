@@ -0,0 +1,93 @@
+package document
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/response"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/application/document"
+)
+
+// Handler 单据处理器
+type Handler struct {
+	documentService *document.Service
+}
+
+// NewHandler 创建单据处理器
+func NewHandler(documentService *document.Service) *Handler {
+	return &Handler{
+		documentService: documentService,
+	}
+}
+
+// GetOrderInvoicePDF 下载当前用户订单的发票
+// GET /api/orders/:id/invoice.pdf
+func (h *Handler) GetOrderInvoicePDF(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	file, err := h.documentService.GetUserOrderInvoicePDF(c.Request.Context(), userID, c.Param("id"), language(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	sendFile(c, file)
+}
+
+// AdminGetOrderInvoicePDF 下载订单的发票（管理员）
+// GET /api/admin/orders/:id/invoice.pdf
+func (h *Handler) AdminGetOrderInvoicePDF(c *gin.Context) {
+	file, err := h.documentService.GetOrderInvoicePDF(c.Request.Context(), c.Param("id"), language(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	sendFile(c, file)
+}
+
+// AdminGetPackingSlipPDF 下载发货的装箱单（管理员）
+// GET /api/admin/shipments/:id/packing-slip.pdf
+func (h *Handler) AdminGetPackingSlipPDF(c *gin.Context) {
+	file, err := h.documentService.GetPackingSlipPDF(c.Request.Context(), c.Param("id"), language(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	sendFile(c, file)
+}
+
+// AdminGetInvoicePDF 下载指定发票或红字发票（管理员）
+// GET /api/admin/invoices/:id/pdf
+func (h *Handler) AdminGetInvoicePDF(c *gin.Context) {
+	file, err := h.documentService.GetInvoicePDF(c.Request.Context(), c.Param("id"), language(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	sendFile(c, file)
+}
+
+// language 解析单据语言：优先 ?lang=，其次 Accept-Language 的首选语言
+func language(c *gin.Context) string {
+	if lang := c.Query("lang"); lang != "" {
+		return lang
+	}
+	accept := c.GetHeader("Accept-Language")
+	if accept == "" {
+		return ""
+	}
+	first := strings.Split(accept, ",")[0]
+	return strings.TrimSpace(strings.Split(first, ";")[0])
+}
+
+// sendFile 输出文件
+func sendFile(c *gin.Context, file *document.FileDTO) {
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, file.Filename))
+	c.Header("Cache-Control", "private, no-cache")
+	c.Data(http.StatusOK, file.ContentType, file.Data)
+}
@@ -0,0 +1,19 @@
+package export
+
+import (
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/response"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+	apperrors "github.com/lwmacct/251112-go-ddd-skeleton/internal/shared/errors"
+)
+
+func init() {
+	response.RegisterDomainErrors(apperrors.CodeNotFound,
+		order.ErrExportJobNotFound,
+	)
+	response.RegisterDomainErrors(apperrors.CodeConflict,
+		order.ErrExportNotReady,
+	)
+	response.RegisterDomainErrors(apperrors.CodeBadRequest,
+		order.ErrUnsupportedExportFormat,
+	)
+}
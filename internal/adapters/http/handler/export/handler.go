@@ -0,0 +1,81 @@
+package export
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/response"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/application/export"
+)
+
+// Handler 订单导出处理器
+type Handler struct {
+	exportService *export.Service
+}
+
+// NewHandler 创建订单导出处理器
+func NewHandler(exportService *export.Service) *Handler {
+	return &Handler{
+		exportService: exportService,
+	}
+}
+
+// ExportOrders 按订单搜索条件导出 CSV / XLSX，规模较大时转后台任务并返回 202
+// GET /api/admin/orders/export?format=csv|xlsx&async=&status=&user_id=&created_from=&created_to=&...
+func (h *Handler) ExportOrders(c *gin.Context) {
+	adminID := c.GetString("userID")
+
+	var req export.ExportOrdersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	file, job, err := h.exportService.StartOrderExport(c.Request.Context(), adminID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if job != nil {
+		response.Accepted(c, job)
+		return
+	}
+
+	c.Header("Content-Type", file.ContentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, file.Filename))
+	c.Header("Cache-Control", "private, no-cache")
+	c.Status(http.StatusOK)
+	if _, err := h.exportService.ExportOrders(c.Request.Context(), req, c.Writer); err != nil {
+		// 响应头已发送，只能中断输出并记录错误
+		_ = c.Error(err)
+	}
+}
+
+// GetExportJob 获取导出任务状态
+// GET /api/admin/exports/:id
+func (h *Handler) GetExportJob(c *gin.Context) {
+	dto, err := h.exportService.GetExportJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
+
+// DownloadExport 下载已完成的导出文件
+// GET /api/admin/exports/:id/download
+func (h *Handler) DownloadExport(c *gin.Context) {
+	file, content, err := h.exportService.OpenExportFile(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, -1, file.ContentType, content, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="%s"`, file.Filename),
+		"Cache-Control":       "private, no-cache",
+	})
+}
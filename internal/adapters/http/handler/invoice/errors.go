@@ -0,0 +1,19 @@
+package invoice
+
+import (
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/response"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/invoice"
+	apperrors "github.com/lwmacct/251112-go-ddd-skeleton/internal/shared/errors"
+)
+
+func init() {
+	response.RegisterDomainErrors(apperrors.CodeNotFound, invoice.ErrInvoiceNotFound)
+	response.RegisterDomainErrors(apperrors.CodeConflict,
+		invoice.ErrInvalidInvoiceStatus,
+		invoice.ErrInvoiceAlreadyIssued,
+	)
+	response.RegisterDomainErrors(apperrors.CodeBadRequest,
+		invoice.ErrOrderNotInvoiceable,
+		invoice.ErrInvalidCreditAmount,
+	)
+}
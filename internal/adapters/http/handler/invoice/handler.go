@@ -0,0 +1,102 @@
+package invoice
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/response"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/application/invoice"
+)
+
+// Handler 发票处理器
+type Handler struct {
+	invoiceService *invoice.Service
+}
+
+// NewHandler 创建发票处理器
+func NewHandler(invoiceService *invoice.Service) *Handler {
+	return &Handler{
+		invoiceService: invoiceService,
+	}
+}
+
+// ListInvoices 列出当前用户的发票
+// GET /api/invoices
+func (h *Handler) ListInvoices(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	resp, err := h.invoiceService.ListUserInvoices(c.Request.Context(), userID, parseListRequest(c))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// GetInvoice 获取当前用户的发票详情
+// GET /api/invoices/:id
+func (h *Handler) GetInvoice(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	dto, err := h.invoiceService.GetUserInvoice(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
+
+// ListAllInvoices 列出所有发票（管理员）
+// GET /api/admin/invoices
+func (h *Handler) ListAllInvoices(c *gin.Context) {
+	req := parseListRequest(c)
+	req.UserID = c.Query("user_id")
+
+	resp, err := h.invoiceService.ListInvoices(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// GetInvoiceByID 获取发票详情（管理员）
+// GET /api/admin/invoices/:id
+func (h *Handler) GetInvoiceByID(c *gin.Context) {
+	dto, err := h.invoiceService.GetInvoice(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
+
+// VoidInvoice 作废发票（管理员）
+// POST /api/admin/invoices/:id/void
+func (h *Handler) VoidInvoice(c *gin.Context) {
+	dto, err := h.invoiceService.VoidInvoice(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
+
+// parseListRequest 解析列表查询参数
+func parseListRequest(c *gin.Context) invoice.ListInvoicesRequest {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	return invoice.ListInvoicesRequest{
+		Page:     page,
+		PageSize: pageSize,
+		OrderID:  c.Query("order_id"),
+		Type:     c.Query("type"),
+		Status:   c.Query("status"),
+	}
+}
@@ -0,0 +1,56 @@
+package order
+
+import (
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/response"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+	apperrors "github.com/lwmacct/251112-go-ddd-skeleton/internal/shared/errors"
+)
+
+func init() {
+	response.RegisterDomainErrors(apperrors.CodeNotFound,
+		order.ErrOrderNotFound,
+		order.ErrPaymentNotFound,
+		order.ErrShipmentNotFound,
+		order.ErrRefundNotFound,
+		order.ErrReturnNotFound,
+		order.ErrFraudReviewNotFound,
+	)
+	response.RegisterDomainErrors(apperrors.CodeConflict,
+		order.ErrConcurrentModification,
+		order.ErrInvalidOrderStatus,
+		order.ErrManualTransitionNotAllowed,
+		order.ErrCannotCancelOrder,
+		order.ErrPaymentNotCapturable,
+		order.ErrPaymentAlreadySucceeded,
+		order.ErrPaymentPending,
+		order.ErrRefundPending,
+		order.ErrInvalidShipmentStatus,
+		order.ErrPaymentNotConfirmable,
+		order.ErrCannotRefund,
+		order.ErrRefundExceedsPayment,
+		order.ErrRefundKeyConflict,
+		order.ErrInvalidReturnStatus,
+		order.ErrReturnWindowExpired,
+		order.ErrOrderUnderReview,
+		order.ErrOrderNotFlagged,
+		order.ErrInvalidFraudReview,
+	)
+	response.RegisterDomainErrors(apperrors.CodePaymentFailed,
+		order.ErrPaymentFailed,
+		order.ErrRefundFailed,
+	)
+	response.RegisterDomainErrors(apperrors.CodeBadRequest,
+		order.ErrInvalidAddress,
+		order.ErrInvalidOrderNumber,
+		order.ErrInvalidSearchCriteria,
+		order.ErrUnsupportedPaymentMethod,
+		order.ErrInvalidWebhookSignature,
+		order.ErrInvalidRefundAmount,
+		order.ErrInvalidRefundAllocation,
+		order.ErrDifferentCurrency,
+		order.ErrShippingAddressRequired,
+		order.ErrShippingRateUnavailable,
+		order.ErrInvalidShipmentLines,
+		order.ErrInvalidReturnLines,
+	)
+}
@@ -1,6 +1,8 @@
 package order
 
 import (
+	"errors"
+	"io"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -41,14 +43,16 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 
 // GetOrder 获取订单
 func (h *Handler) GetOrder(c *gin.Context) {
+	userID := c.GetString("userID")
 	orderID := c.Param("id")
 
-	dto, err := h.orderService.GetOrder(c.Request.Context(), orderID)
+	dto, err := h.orderService.GetOrder(c.Request.Context(), userID, orderID)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
+	response.SetETag(c, dto.Version)
 	response.Success(c, dto)
 }
 
@@ -74,9 +78,10 @@ func (h *Handler) ListOrders(c *gin.Context) {
 
 // CancelOrder 取消订单
 func (h *Handler) CancelOrder(c *gin.Context) {
+	userID := c.GetString("userID")
 	orderID := c.Param("id")
 
-	if err := h.orderService.CancelOrder(c.Request.Context(), orderID); err != nil {
+	if err := h.orderService.CancelOrder(c.Request.Context(), userID, orderID); err != nil {
 		response.Error(c, err)
 		return
 	}
@@ -86,6 +91,7 @@ func (h *Handler) CancelOrder(c *gin.Context) {
 
 // ProcessPayment 处理支付
 func (h *Handler) ProcessPayment(c *gin.Context) {
+	userID := c.GetString("userID")
 	orderID := c.Param("id")
 
 	var req order.ProcessPaymentRequest
@@ -93,8 +99,9 @@ func (h *Handler) ProcessPayment(c *gin.Context) {
 		response.Error(c, err)
 		return
 	}
+	req.ClientIP = c.ClientIP()
 
-	dto, err := h.orderService.ProcessPayment(c.Request.Context(), orderID, req)
+	dto, err := h.orderService.ProcessPayment(c.Request.Context(), userID, orderID, req)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -105,6 +112,7 @@ func (h *Handler) ProcessPayment(c *gin.Context) {
 
 // CreateShipment 创建发货
 func (h *Handler) CreateShipment(c *gin.Context) {
+	userID := c.GetString("userID")
 	orderID := c.Param("id")
 
 	var req order.CreateShipmentRequest
@@ -113,7 +121,7 @@ func (h *Handler) CreateShipment(c *gin.Context) {
 		return
 	}
 
-	dto, err := h.orderService.CreateShipment(c.Request.Context(), orderID, req)
+	dto, err := h.orderService.CreateShipment(c.Request.Context(), userID, orderID, req)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -122,38 +130,150 @@ func (h *Handler) CreateShipment(c *gin.Context) {
 	response.Created(c, dto)
 }
 
+// GetShippingOptions 获取可选运输服务及运费
+// GET /api/orders/:id/shipping-options
+func (h *Handler) GetShippingOptions(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	dtos, err := h.orderService.GetShippingOptions(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dtos)
+}
+
+// SelectShippingRate 为待支付订单选择运输服务
+// PUT /api/orders/:id/shipping
+func (h *Handler) SelectShippingRate(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var req order.SelectShippingRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	version, err := response.IfMatch(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	req.ExpectedVersion = version
+
+	dto, err := h.orderService.SelectShippingRate(c.Request.Context(), userID, c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SetETag(c, dto.Version)
+	response.Success(c, dto)
+}
+
 // GetPayment 获取支付信息
 func (h *Handler) GetPayment(c *gin.Context) {
+	userID := c.GetString("userID")
+	orderID := c.Param("id")
+
+	dto, err := h.orderService.GetPayment(c.Request.Context(), userID, orderID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
+
+// ListPayments 列出订单的全部支付尝试
+func (h *Handler) ListPayments(c *gin.Context) {
+	userID := c.GetString("userID")
+	orderID := c.Param("id")
+
+	dtos, err := h.orderService.ListPayments(c.Request.Context(), userID, orderID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dtos)
+}
+
+// ListShipments 列出订单的全部发货
+func (h *Handler) ListShipments(c *gin.Context) {
+	userID := c.GetString("userID")
 	orderID := c.Param("id")
 
-	// TODO: 实现获取支付信息逻辑
-	_ = orderID
-	response.Success(c, gin.H{"order_id": orderID, "status": "pending"})
+	dtos, err := h.orderService.ListShipments(c.Request.Context(), userID, orderID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dtos)
 }
 
-// GetShipment 获取发货信息
+// GetShipment 获取最近一次发货
+// GET /api/orders/:id/shipment（已废弃，请使用 /shipments）
 func (h *Handler) GetShipment(c *gin.Context) {
+	userID := c.GetString("userID")
 	orderID := c.Param("id")
 
-	// TODO: 实现获取发货信息逻辑
-	_ = orderID
-	response.Success(c, gin.H{"order_id": orderID, "status": "preparing"})
+	dto, err := h.orderService.GetShipment(c.Request.Context(), userID, orderID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
+
+// RequestReturn 申请退货
+// POST /api/orders/:id/returns
+func (h *Handler) RequestReturn(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var req order.CreateReturnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	dto, err := h.orderService.RequestReturn(c.Request.Context(), userID, c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, dto)
+}
+
+// ListReturns 列出订单的退货及其状态
+// GET /api/orders/:id/returns
+func (h *Handler) ListReturns(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	dtos, err := h.orderService.ListReturns(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dtos)
 }
 
 // ========== 管理员订单管理端点（需要admin权限）==========
 
-// ListAllOrders 列出所有订单
-// GET /api/admin/orders
+// ListAllOrders 列出所有订单（支持过滤、排序与全文检索）
+// GET /api/admin/orders?status=paid,completed&user_id=&created_from=&created_to=&min_amount=&max_amount=&currency=&payment_method=&order_number=&q=&sort=-created_at
 func (h *Handler) ListAllOrders(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
-
-	req := order.ListOrdersRequest{
-		Page:     page,
-		PageSize: pageSize,
+	var req order.SearchOrdersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, err)
+		return
 	}
 
-	resp, err := h.orderService.ListOrders(c.Request.Context(), "", req) // 空 userID 表示所有订单
+	resp, err := h.orderService.SearchOrders(c.Request.Context(), req)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -165,32 +285,404 @@ func (h *Handler) ListAllOrders(c *gin.Context) {
 // GetOrderByID 获取指定订单详情（管理员用）
 // GET /api/admin/orders/:id
 func (h *Handler) GetOrderByID(c *gin.Context) {
+	adminID := c.GetString("userID")
 	orderID := c.Param("id")
 
-	dto, err := h.orderService.GetOrder(c.Request.Context(), orderID)
+	dto, err := h.orderService.GetOrder(c.Request.Context(), adminID, orderID)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
+	response.SetETag(c, dto.Version)
+	response.Success(c, dto)
+}
+
+// GetOrderByNumber 根据订单号获取订单详情（管理员用），订单号格式或校验位无效时返回 400
+// GET /api/admin/orders/number/:number
+func (h *Handler) GetOrderByNumber(c *gin.Context) {
+	dto, err := h.orderService.GetOrderByNumber(c.Request.Context(), c.Param("number"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SetETag(c, dto.Version)
 	response.Success(c, dto)
 }
 
 // UpdateOrderStatus 更新订单状态
 // PUT /api/admin/orders/:id/status
 func (h *Handler) UpdateOrderStatus(c *gin.Context) {
+	adminID := c.GetString("userID")
 	orderID := c.Param("id")
 
-	var req struct {
-		Status string `json:"status" binding:"required"`
-		Note   string `json:"note"`
+	var req order.UpdateOrderStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	version, err := response.IfMatch(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	req.ExpectedVersion = version
+
+	dto, err := h.orderService.UpdateOrderStatus(c.Request.Context(), adminID, orderID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SetETag(c, dto.Version)
+	response.Success(c, dto)
+}
+
+// CapturePayment 对已授权的支付请款
+// POST /api/admin/orders/:id/payment/capture
+func (h *Handler) CapturePayment(c *gin.Context) {
+	dto, err := h.orderService.CapturePayment(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
+
+// ConfirmPayment 确认线下收款到账
+// POST /api/admin/orders/:id/payment/confirm
+func (h *Handler) ConfirmPayment(c *gin.Context) {
+	adminID := c.GetString("userID")
+
+	var req order.ConfirmPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, err)
+		return
+	}
+
+	dto, err := h.orderService.ConfirmPayment(c.Request.Context(), adminID, c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
+
+// ResolveOrderAttention 清除订单的待处理标记
+// POST /api/admin/orders/:id/attention/resolve
+func (h *Handler) ResolveOrderAttention(c *gin.Context) {
+	dto, err := h.orderService.ResolveOrderAttention(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
 	}
+
+	response.SetETag(c, dto.Version)
+	response.Success(c, dto)
+}
+
+// CreateRefund 创建退款（支持部分退款与按订单项退款）
+// POST /api/admin/orders/:id/refunds
+func (h *Handler) CreateRefund(c *gin.Context) {
+	adminID := c.GetString("userID")
+
+	var req order.CreateRefundRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		response.Error(c, err)
 		return
 	}
+	req.IdempotencyKey = c.GetHeader("Idempotency-Key")
+
+	dto, err := h.orderService.RefundPayment(c.Request.Context(), adminID, c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
 
-	// TODO: 实现订单状态更新逻辑
-	_ = orderID
-	response.Success(c, gin.H{"message": "订单状态已更新", "status": req.Status})
+	response.Created(c, dto)
+}
+
+// ListRefunds 列出订单的退款记录
+// GET /api/admin/orders/:id/refunds
+func (h *Handler) ListRefunds(c *gin.Context) {
+	dtos, err := h.orderService.ListRefunds(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dtos)
+}
+
+// ========== 管理员发货管理端点 ==========
+
+// GetShipmentByID 获取发货详情（含承运商原始数据）
+// GET /api/admin/shipments/:id
+func (h *Handler) GetShipmentByID(c *gin.Context) {
+	dto, err := h.orderService.GetShipmentByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
+
+// StartShipmentProcessing 发货开始拣货打包
+// POST /api/admin/shipments/:id/process
+func (h *Handler) StartShipmentProcessing(c *gin.Context) {
+	dto, err := h.orderService.StartShipmentProcessing(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
+
+// PurchaseShipmentLabel 向承运商购买面单
+// POST /api/admin/shipments/:id/label
+func (h *Handler) PurchaseShipmentLabel(c *gin.Context) {
+	dto, err := h.orderService.PurchaseShipmentLabel(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
+
+// SyncShipmentTracking 从承运商同步跟踪轨迹
+// POST /api/admin/shipments/:id/tracking/sync
+func (h *Handler) SyncShipmentTracking(c *gin.Context) {
+	dto, err := h.orderService.SyncShipmentTracking(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
+
+// ShipShipment 交付承运商发出
+// POST /api/admin/shipments/:id/ship
+func (h *Handler) ShipShipment(c *gin.Context) {
+	var req order.ShipShipmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, err)
+		return
+	}
+
+	dto, err := h.orderService.ShipShipment(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
+
+// DeliverShipment 确认送达，订单随之完成
+// POST /api/admin/shipments/:id/deliver
+func (h *Handler) DeliverShipment(c *gin.Context) {
+	adminID := c.GetString("userID")
+
+	dto, err := h.orderService.DeliverShipment(c.Request.Context(), adminID, c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
+
+// CancelShipment 取消发货
+// POST /api/admin/shipments/:id/cancel
+func (h *Handler) CancelShipment(c *gin.Context) {
+	var req order.CancelShipmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, err)
+		return
+	}
+
+	dto, err := h.orderService.CancelShipment(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
+
+// AddShipmentEvent 追加承运商跟踪事件
+// POST /api/admin/shipments/:id/events
+func (h *Handler) AddShipmentEvent(c *gin.Context) {
+	var req order.AddShipmentEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	dto, err := h.orderService.AddShipmentEvent(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, dto)
+}
+
+// ========== 管理员退货管理端点 ==========
+
+// GetReturnByID 获取退货详情
+// GET /api/admin/returns/:id
+func (h *Handler) GetReturnByID(c *gin.Context) {
+	dto, err := h.orderService.GetReturn(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
+
+// ApproveReturn 批准退货并生成退货面单
+// POST /api/admin/returns/:id/approve
+func (h *Handler) ApproveReturn(c *gin.Context) {
+	var req order.ReviewReturnRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, err)
+		return
+	}
+
+	dto, err := h.orderService.ApproveReturn(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
+
+// RejectReturn 拒绝退货
+// POST /api/admin/returns/:id/reject
+func (h *Handler) RejectReturn(c *gin.Context) {
+	var req order.ReviewReturnRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, err)
+		return
+	}
+
+	dto, err := h.orderService.RejectReturn(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
+
+// ReceiveReturn 退货收货并记录质检结果
+// POST /api/admin/returns/:id/receive
+func (h *Handler) ReceiveReturn(c *gin.Context) {
+	var req order.ReceiveReturnRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, err)
+		return
+	}
+
+	dto, err := h.orderService.ReceiveReturn(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
+
+// RefundReturn 为质检合格的退货商品退款
+// POST /api/admin/returns/:id/refund
+func (h *Handler) RefundReturn(c *gin.Context) {
+	adminID := c.GetString("userID")
+
+	dto, err := h.orderService.RefundReturn(c.Request.Context(), adminID, c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
+
+// ListFraudReviews 列出等待人工审核的欺诈筛查队列
+// GET /api/admin/fraud-reviews?page=&page_size=
+func (h *Handler) ListFraudReviews(c *gin.Context) {
+	var req order.ListFraudReviewsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	resp, err := h.orderService.ListFraudReviews(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// ListFraudAssessments 列出订单的欺诈筛查记录
+// GET /api/admin/orders/:id/fraud-assessments
+func (h *Handler) ListFraudAssessments(c *gin.Context) {
+	assessments, err := h.orderService.ListFraudAssessments(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, assessments)
+}
+
+// ApproveFraudReview 欺诈审核通过并以保存的支付方式扣款
+// POST /api/admin/orders/:id/fraud-review/approve
+func (h *Handler) ApproveFraudReview(c *gin.Context) {
+	adminID := c.GetString("userID")
+
+	var req order.FraudReviewDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, err)
+		return
+	}
+
+	dto, err := h.orderService.ApproveFraudReview(c.Request.Context(), adminID, c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
+
+// RejectFraudReview 欺诈审核拒绝并取消订单
+// POST /api/admin/orders/:id/fraud-review/reject
+func (h *Handler) RejectFraudReview(c *gin.Context) {
+	adminID := c.GetString("userID")
+
+	var req order.FraudReviewDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(c, err)
+		return
+	}
+
+	dto, err := h.orderService.RejectFraudReview(c.Request.Context(), adminID, c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
 }
@@ -0,0 +1,27 @@
+package promotion
+
+import (
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/response"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/promotion"
+	apperrors "github.com/lwmacct/251112-go-ddd-skeleton/internal/shared/errors"
+)
+
+func init() {
+	response.RegisterDomainErrors(apperrors.CodeNotFound, promotion.ErrPromotionNotFound)
+	response.RegisterDomainErrors(apperrors.CodeConflict,
+		promotion.ErrCodeAlreadyExists,
+		promotion.ErrUsageLimitReached,
+		promotion.ErrUserUsageLimitReached,
+		promotion.ErrConcurrentModification,
+	)
+	response.RegisterDomainErrors(apperrors.CodeBadRequest,
+		promotion.ErrInvalidCode,
+		promotion.ErrInvalidDiscount,
+		promotion.ErrPromotionInactive,
+		promotion.ErrPromotionNotStarted,
+		promotion.ErrPromotionExpired,
+		promotion.ErrMinSpendNotMet,
+		promotion.ErrNoEligibleItems,
+		promotion.ErrCurrencyMismatch,
+	)
+}
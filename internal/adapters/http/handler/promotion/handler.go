@@ -0,0 +1,108 @@
+package promotion
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/response"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/application/promotion"
+)
+
+// Handler 优惠处理器（管理员端点）
+type Handler struct {
+	promotionService *promotion.Service
+}
+
+// NewHandler 创建优惠处理器
+func NewHandler(promotionService *promotion.Service) *Handler {
+	return &Handler{
+		promotionService: promotionService,
+	}
+}
+
+// CreatePromotion 创建优惠
+// POST /api/admin/promotions
+func (h *Handler) CreatePromotion(c *gin.Context) {
+	var req promotion.CreatePromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	dto, err := h.promotionService.CreatePromotion(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, dto)
+}
+
+// ListPromotions 列出优惠
+// GET /api/admin/promotions
+func (h *Handler) ListPromotions(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	req := promotion.ListPromotionsRequest{
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	resp, err := h.promotionService.ListPromotions(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, resp)
+}
+
+// GetPromotion 获取优惠详情
+// GET /api/admin/promotions/:id
+func (h *Handler) GetPromotion(c *gin.Context) {
+	dto, err := h.promotionService.GetPromotion(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SetETag(c, dto.Version)
+	response.Success(c, dto)
+}
+
+// UpdatePromotion 更新优惠
+// PUT /api/admin/promotions/:id
+func (h *Handler) UpdatePromotion(c *gin.Context) {
+	var req promotion.UpdatePromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	version, err := response.IfMatch(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	req.ExpectedVersion = version
+
+	dto, err := h.promotionService.UpdatePromotion(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SetETag(c, dto.Version)
+	response.Success(c, dto)
+}
+
+// DeletePromotion 删除优惠
+// DELETE /api/admin/promotions/:id
+func (h *Handler) DeletePromotion(c *gin.Context) {
+	if err := h.promotionService.DeletePromotion(c.Request.Context(), c.Param("id")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
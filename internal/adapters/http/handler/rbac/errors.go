@@ -0,0 +1,11 @@
+package rbac
+
+import (
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/response"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/rbac"
+	apperrors "github.com/lwmacct/251112-go-ddd-skeleton/internal/shared/errors"
+)
+
+func init() {
+	response.RegisterDomainErrors(apperrors.CodeConflict, rbac.ErrConcurrentModification)
+}
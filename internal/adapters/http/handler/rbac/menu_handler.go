@@ -75,6 +75,12 @@ func (h *MenuHandler) UpdateMenu(c *gin.Context) {
 		response.Error(c, err)
 		return
 	}
+	version, err := response.IfMatch(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	req.ExpectedVersion = version
 
 	result, err := h.menuService.UpdateMenu(c.Request.Context(), id, req)
 	if err != nil {
@@ -82,6 +88,7 @@ func (h *MenuHandler) UpdateMenu(c *gin.Context) {
 		return
 	}
 
+	response.SetETag(c, result.Version)
 	response.Success(c, result)
 }
 
@@ -55,6 +55,12 @@ func (h *RoleHandler) UpdateRole(c *gin.Context) {
 		response.Error(c, err)
 		return
 	}
+	version, err := response.IfMatch(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	req.ExpectedVersion = version
 
 	result, err := h.roleService.UpdateRole(c.Request.Context(), id, req)
 	if err != nil {
@@ -62,6 +68,7 @@ func (h *RoleHandler) UpdateRole(c *gin.Context) {
 		return
 	}
 
+	response.SetETag(c, result.Version)
 	response.Success(c, result)
 }
 
@@ -97,6 +104,7 @@ func (h *RoleHandler) GetRole(c *gin.Context) {
 		return
 	}
 
+	response.SetETag(c, result.Version)
 	response.Success(c, result)
 }
 
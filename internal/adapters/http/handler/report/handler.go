@@ -0,0 +1,73 @@
+package report
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/response"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/application/report"
+)
+
+// Handler 报表处理器
+type Handler struct {
+	reportService *report.Service
+}
+
+// NewHandler 创建报表处理器
+func NewHandler(reportService *report.Service) *Handler {
+	return &Handler{
+		reportService: reportService,
+	}
+}
+
+// SalesReport 按日/周/月汇总的销售报表
+// GET /api/admin/reports/sales?granularity=&from=&to=&currency=
+func (h *Handler) SalesReport(c *gin.Context) {
+	var req report.SalesReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	dto, err := h.reportService.SalesReport(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
+
+// TopProducts 热销商品排行
+// GET /api/admin/reports/top-products?from=&to=&currency=&limit=
+func (h *Handler) TopProducts(c *gin.Context) {
+	var req report.TopProductsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	dto, err := h.reportService.TopProducts(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
+
+// Dashboard 仪表盘汇总
+// GET /api/admin/reports/dashboard?days=&currency=
+func (h *Handler) Dashboard(c *gin.Context) {
+	var req report.DashboardRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	dto, err := h.reportService.Dashboard(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
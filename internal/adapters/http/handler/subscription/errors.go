@@ -0,0 +1,27 @@
+package subscription
+
+import (
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/response"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/subscription"
+	apperrors "github.com/lwmacct/251112-go-ddd-skeleton/internal/shared/errors"
+)
+
+func init() {
+	response.RegisterDomainErrors(apperrors.CodeNotFound,
+		subscription.ErrPlanNotFound,
+		subscription.ErrSubscriptionNotFound,
+	)
+	response.RegisterDomainErrors(apperrors.CodeConflict,
+		subscription.ErrPlanCodeAlreadyExists,
+		subscription.ErrAlreadySubscribed,
+		subscription.ErrInvalidSubscriptionStatus,
+		subscription.ErrSamePlan,
+		subscription.ErrConcurrentModification,
+	)
+	response.RegisterDomainErrors(apperrors.CodeBadRequest,
+		subscription.ErrInvalidPlan,
+		subscription.ErrPlanInactive,
+		subscription.ErrCurrencyMismatch,
+		subscription.ErrInvalidPaymentMethod,
+	)
+}
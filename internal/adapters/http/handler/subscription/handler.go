@@ -0,0 +1,217 @@
+package subscription
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/response"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/application/subscription"
+)
+
+// Handler 订阅处理器
+type Handler struct {
+	subscriptionService *subscription.Service
+}
+
+// NewHandler 创建订阅处理器
+func NewHandler(subscriptionService *subscription.Service) *Handler {
+	return &Handler{
+		subscriptionService: subscriptionService,
+	}
+}
+
+// ListPlans 列出在售的订阅计划
+// GET /api/subscription-plans
+func (h *Handler) ListPlans(c *gin.Context) {
+	plans, err := h.subscriptionService.ListPlans(c.Request.Context(), true)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, plans)
+}
+
+// ListSubscriptions 列出当前用户的订阅
+// GET /api/user/subscriptions
+func (h *Handler) ListSubscriptions(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	subscriptions, err := h.subscriptionService.ListSubscriptions(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, subscriptions)
+}
+
+// Subscribe 订阅计划
+// POST /api/user/subscriptions
+func (h *Handler) Subscribe(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var req subscription.CreateSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	dto, err := h.subscriptionService.Subscribe(c.Request.Context(), userID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, dto)
+}
+
+// GetSubscription 获取订阅详情
+// GET /api/user/subscriptions/:id
+func (h *Handler) GetSubscription(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	dto, err := h.subscriptionService.GetSubscription(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SetETag(c, dto.Version)
+	response.Success(c, dto)
+}
+
+// ChangePlan 变更订阅计划，升级差价立即扣款
+// PUT /api/user/subscriptions/:id/plan
+func (h *Handler) ChangePlan(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var req subscription.ChangePlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	version, err := response.IfMatch(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	req.ExpectedVersion = version
+
+	dto, err := h.subscriptionService.ChangePlan(c.Request.Context(), userID, c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SetETag(c, dto.Version)
+	response.Success(c, dto)
+}
+
+// UpdatePaymentMethod 更换续费支付方式
+// PUT /api/user/subscriptions/:id/payment-method
+func (h *Handler) UpdatePaymentMethod(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var req subscription.UpdatePaymentMethodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	version, err := response.IfMatch(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	req.ExpectedVersion = version
+
+	dto, err := h.subscriptionService.UpdatePaymentMethod(c.Request.Context(), userID, c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SetETag(c, dto.Version)
+	response.Success(c, dto)
+}
+
+// CancelSubscription 取消订阅，当前周期结束时终止
+// POST /api/user/subscriptions/:id/cancel
+func (h *Handler) CancelSubscription(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	dto, err := h.subscriptionService.CancelSubscription(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
+
+// ResumeSubscription 撤销期末取消
+// POST /api/user/subscriptions/:id/resume
+func (h *Handler) ResumeSubscription(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	dto, err := h.subscriptionService.ResumeSubscription(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dto)
+}
+
+// ListAllPlans 列出全部订阅计划（含已停售）
+// GET /api/admin/subscription-plans
+func (h *Handler) ListAllPlans(c *gin.Context) {
+	plans, err := h.subscriptionService.ListPlans(c.Request.Context(), false)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, plans)
+}
+
+// CreatePlan 创建订阅计划
+// POST /api/admin/subscription-plans
+func (h *Handler) CreatePlan(c *gin.Context) {
+	var req subscription.CreatePlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	dto, err := h.subscriptionService.CreatePlan(c.Request.Context(), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, dto)
+}
+
+// UpdatePlan 更新订阅计划
+// PUT /api/admin/subscription-plans/:id
+func (h *Handler) UpdatePlan(c *gin.Context) {
+	var req subscription.UpdatePlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	version, err := response.IfMatch(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	req.ExpectedVersion = version
+
+	dto, err := h.subscriptionService.UpdatePlan(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SetETag(c, dto.Version)
+	response.Success(c, dto)
+}
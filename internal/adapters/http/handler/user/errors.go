@@ -0,0 +1,12 @@
+package user
+
+import (
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/response"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/user"
+	apperrors "github.com/lwmacct/251112-go-ddd-skeleton/internal/shared/errors"
+)
+
+func init() {
+	response.RegisterDomainErrors(apperrors.CodeNotFound, user.ErrAddressNotFound)
+	response.RegisterDomainErrors(apperrors.CodeConflict, user.ErrConcurrentModification)
+}
@@ -73,6 +73,7 @@ func (h *Handler) GetProfile(c *gin.Context) {
 		return
 	}
 
+	response.SetETag(c, dto.Version)
 	response.Success(c, dto)
 }
 
@@ -90,6 +91,12 @@ func (h *Handler) UpdateProfile(c *gin.Context) {
 		response.Error(c, err)
 		return
 	}
+	version, err := response.IfMatch(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	req.ExpectedVersion = version
 
 	dto, err := h.userService.UpdateUser(c.Request.Context(), userID, req)
 	if err != nil {
@@ -97,6 +104,7 @@ func (h *Handler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
+	response.SetETag(c, dto.Version)
 	response.Success(c, dto)
 }
 
@@ -114,6 +122,12 @@ func (h *Handler) PatchProfile(c *gin.Context) {
 		response.Error(c, err)
 		return
 	}
+	version, err := response.IfMatch(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	req.ExpectedVersion = version
 
 	dto, err := h.userService.UpdateUser(c.Request.Context(), userID, req)
 	if err != nil {
@@ -121,6 +135,7 @@ func (h *Handler) PatchProfile(c *gin.Context) {
 		return
 	}
 
+	response.SetETag(c, dto.Version)
 	response.Success(c, dto)
 }
 
@@ -207,6 +222,96 @@ func (h *Handler) UploadAvatar(c *gin.Context) {
 	response.Success(c, gin.H{"message": "头像已上传", "filename": file.Filename})
 }
 
+// ListAddresses 列出地址簿
+// GET /api/user/addresses
+func (h *Handler) ListAddresses(c *gin.Context) {
+	userID, err := getCurrentUserID(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	dtos, err := h.userService.ListAddresses(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, dtos)
+}
+
+// CreateAddress 新增地址
+// POST /api/user/addresses
+func (h *Handler) CreateAddress(c *gin.Context) {
+	userID, err := getCurrentUserID(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var req user.SaveAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	dto, err := h.userService.CreateAddress(c.Request.Context(), userID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Created(c, dto)
+}
+
+// UpdateAddress 修改地址
+// PUT /api/user/addresses/:id
+func (h *Handler) UpdateAddress(c *gin.Context) {
+	userID, err := getCurrentUserID(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var req user.SaveAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, err)
+		return
+	}
+	version, err := response.IfMatch(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	req.ExpectedVersion = version
+
+	dto, err := h.userService.UpdateAddress(c.Request.Context(), userID, c.Param("id"), req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SetETag(c, dto.Version)
+	response.Success(c, dto)
+}
+
+// DeleteAddress 删除地址
+// DELETE /api/user/addresses/:id
+func (h *Handler) DeleteAddress(c *gin.Context) {
+	userID, err := getCurrentUserID(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.userService.DeleteAddress(c.Request.Context(), userID, c.Param("id")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.NoContent(c)
+}
+
 // ========== 管理员端点（需要admin权限）==========
 
 // GetUser 获取指定用户信息
@@ -220,6 +325,7 @@ func (h *Handler) GetUser(c *gin.Context) {
 		return
 	}
 
+	response.SetETag(c, dto.Version)
 	response.Success(c, dto)
 }
 
@@ -253,6 +359,12 @@ func (h *Handler) UpdateUser(c *gin.Context) {
 		response.Error(c, err)
 		return
 	}
+	version, err := response.IfMatch(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	req.ExpectedVersion = version
 
 	dto, err := h.userService.UpdateUser(c.Request.Context(), userID, req)
 	if err != nil {
@@ -260,6 +372,7 @@ func (h *Handler) UpdateUser(c *gin.Context) {
 		return
 	}
 
+	response.SetETag(c, dto.Version)
 	response.Success(c, dto)
 }
 
@@ -0,0 +1,37 @@
+package webhook
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/response"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/application/order"
+)
+
+// Handler 第三方回调处理器
+type Handler struct {
+	orderService *order.Service
+}
+
+// NewHandler 创建回调处理器
+func NewHandler(orderService *order.Service) *Handler {
+	return &Handler{
+		orderService: orderService,
+	}
+}
+
+// Stripe 接收 Stripe 事件回调
+// POST /api/webhooks/stripe
+func (h *Handler) Stripe(c *gin.Context) {
+	// 验签需要原始请求体，不能先做 JSON 绑定
+	payload, err := c.GetRawData()
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if err := h.orderService.HandlePaymentWebhook(c.Request.Context(), payload, c.GetHeader("Stripe-Signature")); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{"received": true})
+}
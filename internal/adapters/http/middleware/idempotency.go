@@ -0,0 +1,195 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/response"
+	apperrors "github.com/lwmacct/251112-go-ddd-skeleton/internal/shared/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// IdempotencyKeyHeader 幂等键请求头
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// idempotencyReplayedHeader 标记响应为重放结果
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+	// maxIdempotencyKeyLength 幂等键最大长度
+	maxIdempotencyKeyLength = 255
+)
+
+// IdempotencyStore 幂等记录存储接口
+type IdempotencyStore interface {
+	// Reserve 键不存在时以 value 占用并返回 true；已存在时返回 false 与已存储的值
+	Reserve(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, []byte, error)
+	// Save 覆盖保存记录
+	Save(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete 删除记录
+	Delete(ctx context.Context, key string) error
+}
+
+var (
+	idempotencyStore       IdempotencyStore
+	idempotencyTTL         = 24 * time.Hour
+	idempotencyLockTimeout = time.Minute
+)
+
+// SetIdempotencyStore 设置幂等记录存储
+// ttl 为响应保留时长，lockTimeout 为处理中占用的最长时间（进程异常退出后自动释放）
+func SetIdempotencyStore(store IdempotencyStore, ttl, lockTimeout time.Duration) {
+	idempotencyStore = store
+	if ttl > 0 {
+		idempotencyTTL = ttl
+	}
+	if lockTimeout > 0 {
+		idempotencyLockTimeout = lockTimeout
+	}
+}
+
+// idempotencyRecord 幂等记录：请求指纹与首次处理的响应
+type idempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Completed   bool   `json:"completed"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// responseRecorder 在写出响应的同时保留响应体
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write 写出并记录响应体
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString 写出并记录响应体
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency 幂等键中间件
+// 携带 Idempotency-Key 的请求按用户保存请求指纹与响应：相同键的重试重放首次响应，
+// 相同键但请求不同返回 422，首次请求仍在处理中返回 409。
+// 未携带该请求头的请求不受影响；5xx 响应不保存，允许客户端以同一键重试。
+// 需要认证的路由必须在 Auth() 中间件之后使用
+func Idempotency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || idempotencyStore == nil {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			response.Error(c, apperrors.New(apperrors.CodeBadRequest, "Idempotency-Key is too long"))
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			response.Error(c, apperrors.ErrBadRequest)
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		scope := c.GetString("userID")
+		if scope == "" {
+			scope = "anonymous"
+		}
+		storeKey := scope + ":" + key
+		fingerprint := requestFingerprint(c.Request.Method, c.Request.URL.Path, body)
+
+		ctx := c.Request.Context()
+		pending, _ := json.Marshal(idempotencyRecord{Fingerprint: fingerprint})
+		acquired, existing, err := idempotencyStore.Reserve(ctx, storeKey, pending, idempotencyLockTimeout)
+		if err != nil {
+			response.Error(c, apperrors.Wrap(apperrors.CodeInternal, "Idempotency store unavailable", err))
+			c.Abort()
+			return
+		}
+		if !acquired {
+			replayIdempotentResponse(c, existing, fingerprint)
+			return
+		}
+
+		// 处理未正常完成（如 panic）时释放占用，允许客户端重试
+		saved := false
+		defer func() {
+			if !saved {
+				releaseIdempotencyKey(storeKey)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+
+		record, _ := json.Marshal(idempotencyRecord{
+			Fingerprint: fingerprint,
+			Completed:   true,
+			Status:      status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		})
+		if err := idempotencyStore.Save(context.Background(), storeKey, record, idempotencyTTL); err != nil {
+			if logger != nil {
+				logger.Warn("failed to save idempotency record", zap.String("key", storeKey), zap.Error(err))
+			}
+			return
+		}
+		saved = true
+	}
+}
+
+// replayIdempotentResponse 处理已存在的幂等记录：指纹不符返回 422，处理中返回 409，否则重放响应
+func replayIdempotentResponse(c *gin.Context, existing []byte, fingerprint string) {
+	var record idempotencyRecord
+	if existing != nil {
+		_ = json.Unmarshal(existing, &record)
+	}
+
+	switch {
+	case record.Fingerprint != "" && record.Fingerprint != fingerprint:
+		response.Error(c, apperrors.New(apperrors.CodeUnprocessableEntity, "Idempotency-Key was used with a different request"))
+	case !record.Completed:
+		response.Error(c, apperrors.New(apperrors.CodeConflict, "A request with this Idempotency-Key is still being processed"))
+	default:
+		c.Header(idempotencyReplayedHeader, "true")
+		c.Data(record.Status, record.ContentType, record.Body)
+	}
+	c.Abort()
+}
+
+// releaseIdempotencyKey 释放处理中的占用
+func releaseIdempotencyKey(storeKey string) {
+	if err := idempotencyStore.Delete(context.Background(), storeKey); err != nil && logger != nil {
+		logger.Warn("failed to release idempotency key", zap.String("key", storeKey), zap.Error(err))
+	}
+}
+
+// requestFingerprint 计算请求指纹（方法、路径与请求体）
+func requestFingerprint(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -26,7 +26,7 @@ func Logger() gin.HandlerFunc {
 		latency := time.Since(start)
 
 		if logger != nil {
-			logger.Info("HTTP Request",
+			fields := []zap.Field{
 				zap.Int("status", c.Writer.Status()),
 				zap.String("method", c.Request.Method),
 				zap.String("path", path),
@@ -34,7 +34,12 @@ func Logger() gin.HandlerFunc {
 				zap.String("ip", c.ClientIP()),
 				zap.Duration("latency", latency),
 				zap.String("user-agent", c.Request.UserAgent()),
-			)
+			}
+			// 响应已开始输出后发生的错误（如流式下载中断）无法返回给客户端，记录在日志中
+			if len(c.Errors) > 0 {
+				fields = append(fields, zap.String("errors", c.Errors.String()))
+			}
+			logger.Info("HTTP Request", fields...)
 		}
 	}
 }
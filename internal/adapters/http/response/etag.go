@@ -0,0 +1,32 @@
+package response
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/shared/errors"
+)
+
+// SetETag 以聚合根的版本号设置强 ETag，需在写入响应体之前调用
+// 客户端修改资源时在 If-Match 中回传，服务端据此拒绝基于过期数据的修改
+func SetETag(c *gin.Context, version int) {
+	c.Header("ETag", fmt.Sprintf(`"%d"`, version))
+}
+
+// IfMatch 解析请求头 If-Match 中的版本号，未携带或为 * 时返回 0 表示不校验
+// 只接受由 SetETag 生成的单个强 ETag
+func IfMatch(c *gin.Context) (int, error) {
+	value := strings.TrimSpace(c.GetHeader("If-Match"))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+
+	if len(value) > 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		if version, err := strconv.Atoi(value[1 : len(value)-1]); err == nil && version > 0 {
+			return version, nil
+		}
+	}
+	return 0, errors.New(errors.CodeBadRequest, "invalid If-Match header: expected a single ETag returned by this API")
+}
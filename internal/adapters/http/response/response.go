@@ -1,6 +1,7 @@
 package response
 
 import (
+	stderrors "errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -22,6 +23,31 @@ type ErrorInfo struct {
 	Details interface{} `json:"details,omitempty"`
 }
 
+// domainErrorCodes 领域错误到错误码的映射（由各处理器包注册）
+var domainErrorCodes = make(map[error]errors.ErrorCode)
+
+// RegisterDomainErrors 注册领域错误对应的错误码
+// 领域层错误不依赖共享错误码，由适配器层在此声明其 HTTP 语义
+func RegisterDomainErrors(code errors.ErrorCode, errs ...error) {
+	for _, err := range errs {
+		domainErrorCodes[err] = code
+	}
+}
+
+// resolveCode 解析错误码：优先使用 AppError，其次查找已注册的领域错误
+func resolveCode(err error) errors.ErrorCode {
+	code := errors.GetCode(err)
+	if code != errors.CodeInternal {
+		return code
+	}
+	for domainErr, domainCode := range domainErrorCodes {
+		if stderrors.Is(err, domainErr) {
+			return domainCode
+		}
+	}
+	return code
+}
+
 // Success 成功响应
 func Success(c *gin.Context, data interface{}) {
 	c.JSON(http.StatusOK, Response{
@@ -38,6 +64,14 @@ func Created(c *gin.Context, data interface{}) {
 	})
 }
 
+// Accepted 已受理响应，用于转入后台处理的请求
+func Accepted(c *gin.Context, data interface{}) {
+	c.JSON(http.StatusAccepted, Response{
+		Success: true,
+		Data:    data,
+	})
+}
+
 // NoContent 无内容响应
 func NoContent(c *gin.Context) {
 	c.Status(http.StatusNoContent)
@@ -45,7 +79,7 @@ func NoContent(c *gin.Context) {
 
 // Error 错误响应
 func Error(c *gin.Context, err error) {
-	code := errors.GetCode(err)
+	code := resolveCode(err)
 	statusCode := getHTTPStatus(code)
 
 	c.JSON(statusCode, Response{
@@ -59,7 +93,7 @@ func Error(c *gin.Context, err error) {
 
 // ErrorWithDetails 带详情的错误响应
 func ErrorWithDetails(c *gin.Context, err error, details interface{}) {
-	code := errors.GetCode(err)
+	code := resolveCode(err)
 	statusCode := getHTTPStatus(code)
 
 	c.JSON(statusCode, Response{
@@ -85,6 +119,10 @@ func getHTTPStatus(code errors.ErrorCode) int {
 		return http.StatusNotFound
 	case errors.CodeConflict:
 		return http.StatusConflict
+	case errors.CodePaymentFailed:
+		return http.StatusPaymentRequired
+	case errors.CodeUnprocessableEntity:
+		return http.StatusUnprocessableEntity
 	case errors.CodeTooManyRequests:
 		return http.StatusTooManyRequests
 	default:
@@ -3,9 +3,16 @@ package http
 import (
 	"github.com/gin-gonic/gin"
 	authhandler "github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/handler/auth"
+	documenthandler "github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/handler/document"
+	exporthandler "github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/handler/export"
+	invoicehandler "github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/handler/invoice"
 	orderhandler "github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/handler/order"
+	promotionhandler "github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/handler/promotion"
 	rbachandler "github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/handler/rbac"
+	reporthandler "github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/handler/report"
+	subscriptionhandler "github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/handler/subscription"
 	userhandler "github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/handler/user"
+	webhookhandler "github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/handler/webhook"
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/middleware"
 )
 
@@ -14,8 +21,15 @@ func SetupRouter(
 	userHandler *userhandler.Handler,
 	authHandler *authhandler.Handler,
 	orderHandler *orderhandler.Handler,
+	exportHandler *exporthandler.Handler,
 	menuHandler *rbachandler.MenuHandler,
 	roleHandler *rbachandler.RoleHandler,
+	promotionHandler *promotionhandler.Handler,
+	subscriptionHandler *subscriptionhandler.Handler,
+	invoiceHandler *invoicehandler.Handler,
+	documentHandler *documenthandler.Handler,
+	reportHandler *reporthandler.Handler,
+	webhookHandler *webhookhandler.Handler,
 ) *gin.Engine {
 	r := gin.New()
 
@@ -35,11 +49,17 @@ func SetupRouter(
 		// ========== 公开端点 ==========
 		auth := api.Group("/auth")
 		{
-			auth.POST("/register", userHandler.Register)
+			auth.POST("/register", middleware.Idempotency(), userHandler.Register)
 			auth.POST("/login", authHandler.Login)
 			auth.POST("/refresh", authHandler.RefreshToken)
 		}
 
+		// 支付网关回调（以签名鉴权，不走登录认证）
+		webhooks := api.Group("/webhooks")
+		{
+			webhooks.POST("/stripe", webhookHandler.Stripe)
+		}
+
 		// ========== 需要认证的端点 ==========
 		authenticated := api.Group("")
 		authenticated.Use(middleware.Auth())
@@ -55,6 +75,21 @@ func SetupRouter(
 				user.PUT("/email", userHandler.ChangeEmail)
 				user.POST("/avatar", userHandler.UploadAvatar)
 
+				// 地址簿
+				user.GET("/addresses", userHandler.ListAddresses)
+				user.POST("/addresses", userHandler.CreateAddress)
+				user.PUT("/addresses/:id", userHandler.UpdateAddress)
+				user.DELETE("/addresses/:id", userHandler.DeleteAddress)
+
+				// 订阅
+				user.GET("/subscriptions", subscriptionHandler.ListSubscriptions)
+				user.POST("/subscriptions", middleware.Idempotency(), subscriptionHandler.Subscribe)
+				user.GET("/subscriptions/:id", subscriptionHandler.GetSubscription)
+				user.PUT("/subscriptions/:id/plan", middleware.Idempotency(), subscriptionHandler.ChangePlan)
+				user.PUT("/subscriptions/:id/payment-method", subscriptionHandler.UpdatePaymentMethod)
+				user.POST("/subscriptions/:id/cancel", subscriptionHandler.CancelSubscription)
+				user.POST("/subscriptions/:id/resume", subscriptionHandler.ResumeSubscription)
+
 				// 会话管理
 				user.GET("/sessions", authHandler.GetSessions)
 				user.DELETE("/sessions/:id", authHandler.RevokeSession)
@@ -79,14 +114,33 @@ func SetupRouter(
 			// 用户订单
 			orders := authenticated.Group("/orders")
 			{
-				orders.POST("", orderHandler.CreateOrder)
+				orders.POST("", middleware.Idempotency(), orderHandler.CreateOrder)
 				orders.GET("", orderHandler.ListOrders)
 				orders.GET("/:id", orderHandler.GetOrder)
-				orders.POST("/:id/cancel", orderHandler.CancelOrder)
-				orders.POST("/:id/payment", orderHandler.ProcessPayment)
+				orders.POST("/:id/cancel", middleware.Idempotency(), orderHandler.CancelOrder)
+				orders.POST("/:id/payment", middleware.Idempotency(), orderHandler.ProcessPayment)
 				orders.GET("/:id/payment", orderHandler.GetPayment)
+				orders.GET("/:id/payments", orderHandler.ListPayments)
+				orders.GET("/:id/shipping-options", orderHandler.GetShippingOptions)
+				orders.PUT("/:id/shipping", orderHandler.SelectShippingRate)
+				orders.POST("/:id/shipments", orderHandler.CreateShipment)
+				orders.GET("/:id/shipments", orderHandler.ListShipments)
+				// 兼容旧的单数路径，新客户端请使用 /shipments
 				orders.POST("/:id/shipment", orderHandler.CreateShipment)
 				orders.GET("/:id/shipment", orderHandler.GetShipment)
+				orders.POST("/:id/returns", middleware.Idempotency(), orderHandler.RequestReturn)
+				orders.GET("/:id/returns", orderHandler.ListReturns)
+				orders.GET("/:id/invoice.pdf", documentHandler.GetOrderInvoicePDF)
+			}
+
+			// 订阅计划
+			authenticated.GET("/subscription-plans", subscriptionHandler.ListPlans)
+
+			// 用户发票
+			invoices := authenticated.Group("/invoices")
+			{
+				invoices.GET("", invoiceHandler.ListInvoices)
+				invoices.GET("/:id", invoiceHandler.GetInvoice)
 			}
 
 			// 用户菜单（RBAC）
@@ -118,8 +172,88 @@ func SetupRouter(
 			adminOrders := admin.Group("/orders")
 			{
 				adminOrders.GET("", orderHandler.ListAllOrders)
+				adminOrders.GET("/export", exportHandler.ExportOrders)
+				adminOrders.GET("/number/:number", orderHandler.GetOrderByNumber)
 				adminOrders.GET("/:id", orderHandler.GetOrderByID)
 				adminOrders.PUT("/:id/status", orderHandler.UpdateOrderStatus)
+				adminOrders.POST("/:id/attention/resolve", orderHandler.ResolveOrderAttention)
+				adminOrders.POST("/:id/payment/capture", middleware.Idempotency(), orderHandler.CapturePayment)
+				adminOrders.POST("/:id/payment/confirm", middleware.Idempotency(), orderHandler.ConfirmPayment)
+				adminOrders.POST("/:id/refunds", middleware.Idempotency(), orderHandler.CreateRefund)
+				adminOrders.GET("/:id/refunds", orderHandler.ListRefunds)
+				adminOrders.GET("/:id/invoice.pdf", documentHandler.AdminGetOrderInvoicePDF)
+				adminOrders.GET("/:id/fraud-assessments", orderHandler.ListFraudAssessments)
+				adminOrders.POST("/:id/fraud-review/approve", middleware.Idempotency(), orderHandler.ApproveFraudReview)
+				adminOrders.POST("/:id/fraud-review/reject", orderHandler.RejectFraudReview)
+			}
+
+			// 欺诈审核队列
+			admin.GET("/fraud-reviews", orderHandler.ListFraudReviews)
+
+			// 订单导出任务
+			adminExports := admin.Group("/exports")
+			{
+				adminExports.GET("/:id", exportHandler.GetExportJob)
+				adminExports.GET("/:id/download", exportHandler.DownloadExport)
+			}
+
+			// 发货管理
+			adminShipments := admin.Group("/shipments")
+			{
+				adminShipments.GET("/:id", orderHandler.GetShipmentByID)
+				adminShipments.GET("/:id/packing-slip.pdf", documentHandler.AdminGetPackingSlipPDF)
+				adminShipments.POST("/:id/process", orderHandler.StartShipmentProcessing)
+				adminShipments.POST("/:id/label", middleware.Idempotency(), orderHandler.PurchaseShipmentLabel)
+				adminShipments.POST("/:id/tracking/sync", orderHandler.SyncShipmentTracking)
+				adminShipments.POST("/:id/ship", orderHandler.ShipShipment)
+				adminShipments.POST("/:id/deliver", orderHandler.DeliverShipment)
+				adminShipments.POST("/:id/cancel", orderHandler.CancelShipment)
+				adminShipments.POST("/:id/events", orderHandler.AddShipmentEvent)
+			}
+
+			// 退货管理
+			adminReturns := admin.Group("/returns")
+			{
+				adminReturns.GET("/:id", orderHandler.GetReturnByID)
+				adminReturns.POST("/:id/approve", middleware.Idempotency(), orderHandler.ApproveReturn)
+				adminReturns.POST("/:id/reject", orderHandler.RejectReturn)
+				adminReturns.POST("/:id/receive", orderHandler.ReceiveReturn)
+				adminReturns.POST("/:id/refund", middleware.Idempotency(), orderHandler.RefundReturn)
+			}
+
+			// 优惠券管理
+			adminPromotions := admin.Group("/promotions")
+			{
+				adminPromotions.POST("", promotionHandler.CreatePromotion)
+				adminPromotions.GET("", promotionHandler.ListPromotions)
+				adminPromotions.GET("/:id", promotionHandler.GetPromotion)
+				adminPromotions.PUT("/:id", promotionHandler.UpdatePromotion)
+				adminPromotions.DELETE("/:id", promotionHandler.DeletePromotion)
+			}
+
+			// 订阅计划管理
+			adminPlans := admin.Group("/subscription-plans")
+			{
+				adminPlans.GET("", subscriptionHandler.ListAllPlans)
+				adminPlans.POST("", subscriptionHandler.CreatePlan)
+				adminPlans.PUT("/:id", subscriptionHandler.UpdatePlan)
+			}
+
+			// 发票管理
+			adminInvoices := admin.Group("/invoices")
+			{
+				adminInvoices.GET("", invoiceHandler.ListAllInvoices)
+				adminInvoices.GET("/:id", invoiceHandler.GetInvoiceByID)
+				adminInvoices.POST("/:id/void", invoiceHandler.VoidInvoice)
+				adminInvoices.GET("/:id/pdf", documentHandler.AdminGetInvoicePDF)
+			}
+
+			// 销售报表
+			adminReports := admin.Group("/reports")
+			{
+				adminReports.GET("/sales", reportHandler.SalesReport)
+				adminReports.GET("/top-products", reportHandler.TopProducts)
+				adminReports.GET("/dashboard", reportHandler.Dashboard)
 			}
 
 			// RBAC管理
@@ -0,0 +1,8 @@
+package document
+
+// FileDTO 生成的单据文件
+type FileDTO struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
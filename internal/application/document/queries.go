@@ -0,0 +1,141 @@
+package document
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/invoice"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+)
+
+const pdfContentType = "application/pdf"
+
+// GetUserOrderInvoicePDF 获取当前用户订单的发票 PDF（查询）
+// 非本人订单按不存在处理
+func (s *Service) GetUserOrderInvoicePDF(ctx context.Context, userID, orderID, lang string) (*FileDTO, error) {
+	o, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if o.UserID != userID {
+		return nil, order.ErrOrderNotFound
+	}
+
+	inv, err := s.invoiceRepo.FindActiveByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.invoicePDF(ctx, inv, o, lang)
+}
+
+// GetOrderInvoicePDF 获取订单的发票 PDF（查询，管理员）
+func (s *Service) GetOrderInvoicePDF(ctx context.Context, orderID, lang string) (*FileDTO, error) {
+	o, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	inv, err := s.invoiceRepo.FindActiveByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.invoicePDF(ctx, inv, o, lang)
+}
+
+// GetInvoicePDF 获取指定发票或红字发票的 PDF（查询，管理员）
+func (s *Service) GetInvoicePDF(ctx context.Context, invoiceID, lang string) (*FileDTO, error) {
+	inv, err := s.invoiceRepo.FindByID(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := s.orderRepo.FindByID(ctx, inv.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.invoicePDF(ctx, inv, o, lang)
+}
+
+// GetPackingSlipPDF 获取发货的装箱单 PDF（查询，管理员），仅列出该次发货包含的商品
+func (s *Service) GetPackingSlipPDF(ctx context.Context, shipmentID, lang string) (*FileDTO, error) {
+	shipment, err := s.shipmentRepo.FindByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := s.orderRepo.FindByID(ctx, shipment.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("packing-slips/%s/%s-%d.pdf", shipment.ID, cacheLang(lang), shipment.UpdatedAt.Unix())
+	data, err := s.cached(ctx, key, func() ([]byte, error) {
+		return s.renderer.RenderPackingSlip(shipment, o, lang)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileDTO{
+		Filename:    fmt.Sprintf("packing-slip-%s.pdf", o.OrderNumber),
+		ContentType: pdfContentType,
+		Data:        data,
+	}, nil
+}
+
+// invoicePDF 渲染发票 PDF，按发票版本缓存
+func (s *Service) invoicePDF(ctx context.Context, inv *invoice.Invoice, o *order.Order, lang string) (*FileDTO, error) {
+	key := fmt.Sprintf("invoices/%s/%s-%d.pdf", inv.ID, cacheLang(lang), inv.UpdatedAt.Unix())
+	data, err := s.cached(ctx, key, func() ([]byte, error) {
+		return s.renderer.RenderInvoice(inv, o, lang)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	name := inv.Number
+	if name == "" {
+		name = inv.ID
+	}
+
+	return &FileDTO{
+		Filename:    name + ".pdf",
+		ContentType: pdfContentType,
+		Data:        data,
+	}, nil
+}
+
+// cached 优先读取对象存储中的已生成文件，未命中时渲染并写入
+// 单据内容变化时更新时间随之变化，缓存键自然失效
+func (s *Service) cached(ctx context.Context, key string, render func() ([]byte, error)) ([]byte, error) {
+	data, found, err := s.blobStore.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		return data, nil
+	}
+
+	data, err = render()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.blobStore.Put(ctx, key, data, pdfContentType); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// cacheLang 缓存键中的语言部分（仅保留两位语言代码，避免任意输入产生大量缓存）
+func cacheLang(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if len(lang) < 2 || lang[0] < 'a' || lang[0] > 'z' || lang[1] < 'a' || lang[1] > 'z' {
+		return "default"
+	}
+	return lang[:2]
+}
@@ -0,0 +1,51 @@
+package document
+
+import (
+	"context"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/invoice"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+)
+
+// Renderer 单据渲染接口（端口）
+type Renderer interface {
+	// RenderInvoice 渲染发票或红字发票 PDF
+	RenderInvoice(inv *invoice.Invoice, o *order.Order, lang string) ([]byte, error)
+	// RenderPackingSlip 渲染装箱单 PDF
+	RenderPackingSlip(s *order.Shipment, o *order.Order, lang string) ([]byte, error)
+}
+
+// BlobStore 对象存储接口（端口）
+type BlobStore interface {
+	// Get 读取对象，不存在时 found 为 false
+	Get(ctx context.Context, key string) (data []byte, found bool, err error)
+	// Put 写入对象
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+}
+
+// Service 单据应用服务
+type Service struct {
+	orderRepo    order.OrderRepository
+	shipmentRepo order.ShipmentRepository
+	invoiceRepo  invoice.Repository
+
+	renderer  Renderer
+	blobStore BlobStore
+}
+
+// NewService 创建单据应用服务
+func NewService(
+	orderRepo order.OrderRepository,
+	shipmentRepo order.ShipmentRepository,
+	invoiceRepo invoice.Repository,
+	renderer Renderer,
+	blobStore BlobStore,
+) *Service {
+	return &Service{
+		orderRepo:    orderRepo,
+		shipmentRepo: shipmentRepo,
+		invoiceRepo:  invoiceRepo,
+		renderer:     renderer,
+		blobStore:    blobStore,
+	}
+}
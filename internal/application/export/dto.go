@@ -0,0 +1,33 @@
+package export
+
+import (
+	"time"
+
+	apporder "github.com/lwmacct/251112-go-ddd-skeleton/internal/application/order"
+)
+
+// ExportOrdersRequest 订单导出请求，过滤条件与订单搜索一致（忽略分页与排序）
+type ExportOrdersRequest struct {
+	apporder.SearchOrdersRequest
+	Format string `form:"format"` // csv（默认）或 xlsx
+	Async  bool   `form:"async"`  // 强制转后台任务
+}
+
+// ExportFileDTO 导出文件信息
+type ExportFileDTO struct {
+	Filename    string
+	ContentType string
+}
+
+// ExportJobDTO 导出任务DTO
+type ExportJobDTO struct {
+	ID          string     `json:"id"`
+	Format      string     `json:"format"`
+	Status      string     `json:"status"`
+	RowCount    int        `json:"row_count"`
+	Error       string     `json:"error,omitempty"`
+	RequestedBy string     `json:"requested_by"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
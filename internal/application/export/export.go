@@ -0,0 +1,419 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	apporder "github.com/lwmacct/251112-go-ddd-skeleton/internal/application/order"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+	"github.com/oklog/ulid/v2"
+)
+
+// defaultExportBatchSize 未配置批大小时每批读取的订单数
+const defaultExportBatchSize = 500
+
+// exportColumns 导出列：每个订单项一行，订单、支付、退款与发货列随订单项重复
+var exportColumns = []string{
+	"order_number", "order_id", "created_at", "status", "user_id", "currency",
+	"order_subtotal", "order_tax", "order_total",
+	"item_id", "product_id", "product_name", "category", "quantity", "unit_price", "item_subtotal", "item_tax",
+	"payment_method", "payment_status", "transaction_id", "paid_amount",
+	"refunded_amount", "item_refunded_quantity",
+	"fulfilled_quantity", "delivered_quantity", "shipment_statuses", "carriers", "tracking_numbers",
+}
+
+// StartOrderExport 开始订单导出（命令）
+// 指定 async 或符合条件的订单数超过阈值时创建后台导出任务并返回；
+// 否则返回导出文件信息，由调用方随后调用 ExportOrders 同步流式输出
+func (s *Service) StartOrderExport(ctx context.Context, adminID string, req ExportOrdersRequest) (*ExportFileDTO, *ExportJobDTO, error) {
+	format, err := order.ParseExportFormat(req.Format)
+	if err != nil {
+		return nil, nil, err
+	}
+	criteria, err := exportCriteria(req.SearchOrdersRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	async := req.Async
+	if !async && s.opts.AsyncThreshold > 0 {
+		criteria.Limit = 1
+		_, total, err := s.orderRepo.Search(ctx, criteria)
+		if err != nil {
+			return nil, nil, err
+		}
+		async = total > s.opts.AsyncThreshold
+	}
+	if !async {
+		return exportFile(format, time.Now()), nil, nil
+	}
+
+	filters, err := json.Marshal(req.SearchOrdersRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+	job, err := order.NewExportJob(adminID, format, string(filters))
+	if err != nil {
+		return nil, nil, err
+	}
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
+	job.ID = ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
+
+	if err := s.exportRepo.Create(ctx, job); err != nil {
+		return nil, nil, err
+	}
+	return nil, domainExportJobToDTO(job), nil
+}
+
+// ExportOrders 按搜索条件流式导出订单（查询），返回导出的数据行数
+// 按订单ID分批读取订单及其支付、退款与发货，内存占用与导出规模无关
+func (s *Service) ExportOrders(ctx context.Context, req ExportOrdersRequest, w io.Writer) (int, error) {
+	format, err := order.ParseExportFormat(req.Format)
+	if err != nil {
+		return 0, err
+	}
+	criteria, err := exportCriteria(req.SearchOrdersRequest)
+	if err != nil {
+		return 0, err
+	}
+
+	writer, err := s.encoder.NewWriter(format, w)
+	if err != nil {
+		return 0, err
+	}
+	if err := writer.WriteRow(exportColumns); err != nil {
+		return 0, err
+	}
+
+	batchSize := s.opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultExportBatchSize
+	}
+
+	rows := 0
+	afterID := ""
+	for {
+		orders, err := s.orderRepo.SearchAfter(ctx, criteria, afterID, batchSize)
+		if err != nil {
+			return rows, err
+		}
+		if len(orders) == 0 {
+			break
+		}
+
+		batch, err := s.loadExportBatch(ctx, orders)
+		if err != nil {
+			return rows, err
+		}
+		for _, o := range orders {
+			for _, row := range batch.rows(o) {
+				if err := writer.WriteRow(row); err != nil {
+					return rows, err
+				}
+				rows++
+			}
+		}
+
+		afterID = orders[len(orders)-1].ID
+		if len(orders) < batchSize {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return rows, err
+		}
+	}
+
+	return rows, writer.Close()
+}
+
+// GetExportJob 获取导出任务（查询）
+func (s *Service) GetExportJob(ctx context.Context, jobID string) (*ExportJobDTO, error) {
+	job, err := s.exportRepo.FindByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	return domainExportJobToDTO(job), nil
+}
+
+// OpenExportFile 打开已完成导出任务的文件（查询），调用方负责关闭
+func (s *Service) OpenExportFile(ctx context.Context, jobID string) (*ExportFileDTO, io.ReadCloser, error) {
+	job, err := s.exportRepo.FindByID(ctx, jobID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !job.IsCompleted() {
+		return nil, nil, fmt.Errorf("%w: job is %s", order.ErrExportNotReady, job.Status)
+	}
+
+	file, found, err := s.store.Open(ctx, job.FileKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !found {
+		return nil, nil, fmt.Errorf("%w: export file is missing", order.ErrExportJobNotFound)
+	}
+	return exportFile(job.Format, job.CreatedAt), file, nil
+}
+
+// RunExportJobs 执行待处理的后台导出任务（命令），每次至多执行 limit 个，返回完成的任务数
+// 单个任务失败不影响其余任务，错误合并后随返回值报告
+func (s *Service) RunExportJobs(ctx context.Context, limit int) (int, error) {
+	if limit <= 0 {
+		return 0, fmt.Errorf("invalid export job limit: %d", limit)
+	}
+
+	completed := 0
+	var jobErrs []error
+	for i := 0; i < limit && ctx.Err() == nil; i++ {
+		job, err := s.claimExportJob(ctx)
+		if err != nil {
+			return completed, errors.Join(append(jobErrs, err)...)
+		}
+		if job == nil {
+			break
+		}
+
+		if err := s.runExportJob(ctx, job); err != nil {
+			jobErrs = append(jobErrs, fmt.Errorf("export job %s: %w", job.ID, err))
+			continue
+		}
+		completed++
+	}
+
+	return completed, errors.Join(jobErrs...)
+}
+
+// claimExportJob 领取一个待执行的导出任务并标记为执行中，没有时返回 nil
+func (s *Service) claimExportJob(ctx context.Context) (*order.ExportJob, error) {
+	var job *order.ExportJob
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		now := time.Now()
+		staleBefore := time.Time{}
+		if s.opts.JobTimeout > 0 {
+			staleBefore = now.Add(-s.opts.JobTimeout)
+		}
+
+		locked, err := s.exportRepo.LockNext(ctx, staleBefore)
+		if err != nil || locked == nil {
+			return err
+		}
+		locked.Start(now)
+		job = locked
+		return s.exportRepo.Update(ctx, locked)
+	})
+	return job, err
+}
+
+// runExportJob 执行导出任务并记录结果；失败任务的文件不会被下载
+func (s *Service) runExportJob(ctx context.Context, job *order.ExportJob) error {
+	key, rows, err := s.writeExportFile(ctx, job)
+	if err != nil {
+		job.Fail(err.Error(), time.Now())
+		if updateErr := s.exportRepo.Update(ctx, job); updateErr != nil {
+			return errors.Join(err, updateErr)
+		}
+		return err
+	}
+
+	job.Complete(key, rows, time.Now())
+	return s.exportRepo.Update(ctx, job)
+}
+
+// writeExportFile 按任务保存的条件导出订单并写入存储，返回文件键与数据行数
+func (s *Service) writeExportFile(ctx context.Context, job *order.ExportJob) (string, int, error) {
+	var filters apporder.SearchOrdersRequest
+	if err := json.Unmarshal([]byte(job.Filters), &filters); err != nil {
+		return "", 0, err
+	}
+
+	key := fmt.Sprintf("exports/%s.%s", job.ID, job.Format)
+	file, err := s.store.Create(ctx, key, job.Format.ContentType())
+	if err != nil {
+		return "", 0, err
+	}
+
+	rows, err := s.ExportOrders(ctx, ExportOrdersRequest{SearchOrdersRequest: filters, Format: string(job.Format)}, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return key, rows, err
+}
+
+// exportCriteria 将查询参数转换为导出的搜索条件
+func exportCriteria(req apporder.SearchOrdersRequest) (order.SearchCriteria, error) {
+	criteria, err := apporder.BuildSearchCriteria(req)
+	if err != nil {
+		return criteria, err
+	}
+	if err := criteria.Validate(); err != nil {
+		return criteria, err
+	}
+	return criteria, nil
+}
+
+// exportFile 导出文件名与类型
+func exportFile(format order.ExportFormat, at time.Time) *ExportFileDTO {
+	return &ExportFileDTO{
+		Filename:    fmt.Sprintf("orders-%s.%s", at.UTC().Format("20060102-150405"), format),
+		ContentType: format.ContentType(),
+	}
+}
+
+// exportBatch 一批订单的支付、退款与发货，按订单ID分组
+type exportBatch struct {
+	payments  map[string][]*order.Payment
+	refunds   map[string][]*order.Refund
+	shipments map[string][]*order.Shipment
+}
+
+// loadExportBatch 批量加载一批订单的关联记录
+func (s *Service) loadExportBatch(ctx context.Context, orders []*order.Order) (*exportBatch, error) {
+	ids := make([]string, len(orders))
+	for i, o := range orders {
+		ids[i] = o.ID
+	}
+
+	payments, err := s.paymentRepo.ListByOrderIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	refunds, err := s.refundRepo.ListByOrderIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	shipments, err := s.shipmentRepo.ListByOrderIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &exportBatch{
+		payments:  make(map[string][]*order.Payment),
+		refunds:   make(map[string][]*order.Refund),
+		shipments: make(map[string][]*order.Shipment),
+	}
+	for _, p := range payments {
+		batch.payments[p.OrderID] = append(batch.payments[p.OrderID], p)
+	}
+	for _, r := range refunds {
+		batch.refunds[r.OrderID] = append(batch.refunds[r.OrderID], r)
+	}
+	for _, sh := range shipments {
+		batch.shipments[sh.OrderID] = append(batch.shipments[sh.OrderID], sh)
+	}
+	return batch, nil
+}
+
+// rows 生成订单的导出行
+func (b *exportBatch) rows(o *order.Order) [][]string {
+	// 支付取已收款的一笔，没有时取最近一次尝试
+	var payment *order.Payment
+	payments := b.payments[o.ID]
+	for _, p := range payments {
+		if p.IsCaptured() {
+			payment = p
+			break
+		}
+	}
+	if payment == nil && len(payments) > 0 {
+		payment = payments[len(payments)-1]
+	}
+	paymentCols := []string{"", "", "", ""}
+	if payment != nil {
+		paid := ""
+		if payment.IsCaptured() {
+			paid = formatExportAmount(payment.Amount.Amount)
+		}
+		paymentCols = []string{string(payment.Method), string(payment.Status), payment.TransactionID, paid}
+	}
+
+	refunded := 0.0
+	refundedQty := make(map[string]int)
+	for _, r := range b.refunds[o.ID] {
+		if r.Status != order.RefundStatusSucceeded {
+			continue
+		}
+		refunded += r.Amount.Amount
+		for _, line := range r.Lines {
+			refundedQty[line.OrderItemID] += line.Quantity
+		}
+	}
+
+	rows := make([][]string, 0, len(o.Items))
+	for _, item := range o.Items {
+		var statuses, carriers, trackingNumbers []string
+		for _, sh := range b.shipments[o.ID] {
+			if !shipmentContains(sh, item.ID) {
+				continue
+			}
+			statuses = append(statuses, string(sh.Status))
+			if sh.Carrier != "" {
+				carriers = appendUnique(carriers, sh.Carrier)
+			}
+			if sh.TrackingNumber != "" {
+				trackingNumbers = append(trackingNumbers, sh.TrackingNumber)
+			}
+		}
+
+		row := []string{
+			o.OrderNumber, o.ID, o.CreatedAt.UTC().Format(time.RFC3339), string(o.Status), o.UserID, o.TotalAmount.Currency,
+			formatExportAmount(o.Subtotal.Amount), formatExportAmount(o.TaxTotal.Amount), formatExportAmount(o.TotalAmount.Amount),
+			item.ID, item.ProductID, item.ProductName, item.Category, strconv.Itoa(item.Quantity),
+			formatExportAmount(item.UnitPrice.Amount), formatExportAmount(item.Subtotal.Amount), formatExportAmount(item.TaxAmount.Amount),
+		}
+		row = append(row, paymentCols...)
+		row = append(row,
+			formatExportAmount(refunded), strconv.Itoa(refundedQty[item.ID]),
+			strconv.Itoa(item.FulfilledQuantity), strconv.Itoa(item.DeliveredQuantity),
+			strings.Join(statuses, ";"), strings.Join(carriers, ";"), strings.Join(trackingNumbers, ";"),
+		)
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// shipmentContains 发货是否包含该订单项
+func shipmentContains(sh *order.Shipment, orderItemID string) bool {
+	for _, line := range sh.Lines {
+		if line.OrderItemID == orderItemID {
+			return true
+		}
+	}
+	return false
+}
+
+// appendUnique 追加不重复的值
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// formatExportAmount 金额保留两位小数
+func formatExportAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+// domainExportJobToDTO 转换导出任务为DTO
+func domainExportJobToDTO(j *order.ExportJob) *ExportJobDTO {
+	return &ExportJobDTO{
+		ID:          j.ID,
+		Format:      string(j.Format),
+		Status:      string(j.Status),
+		RowCount:    j.RowCount,
+		Error:       j.Error,
+		RequestedBy: j.RequestedBy,
+		StartedAt:   j.StartedAt,
+		CompletedAt: j.CompletedAt,
+		CreatedAt:   j.CreatedAt,
+	}
+}
@@ -0,0 +1,74 @@
+package export
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+)
+
+// Encoder 导出文件编码接口（端口）
+type Encoder interface {
+	// NewWriter 按格式创建流式写入器
+	NewWriter(format order.ExportFormat, w io.Writer) (order.ExportWriter, error)
+}
+
+// Store 导出文件存储接口（端口）
+type Store interface {
+	// Create 流式写入文件，Close 时提交
+	Create(ctx context.Context, key, contentType string) (io.WriteCloser, error)
+	// Open 读取文件，不存在时 found 为 false
+	Open(ctx context.Context, key string) (io.ReadCloser, bool, error)
+}
+
+// TxManager 事务管理接口（端口）
+type TxManager interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Options 订单导出配置
+type Options struct {
+	BatchSize      int           // 每批读取的订单数
+	AsyncThreshold int64         // 符合条件的订单数超过该值时转后台任务，0 表示总是同步导出
+	JobTimeout     time.Duration // 后台任务开始后超过该时长仍未完成时可被重新领取
+}
+
+// Service 订单导出应用服务
+type Service struct {
+	orderRepo    order.OrderRepository
+	paymentRepo  order.PaymentRepository
+	refundRepo   order.RefundRepository
+	shipmentRepo order.ShipmentRepository
+	exportRepo   order.ExportJobRepository
+	opts         Options
+
+	encoder   Encoder
+	store     Store
+	txManager TxManager
+}
+
+// NewService 创建订单导出应用服务
+func NewService(
+	orderRepo order.OrderRepository,
+	paymentRepo order.PaymentRepository,
+	refundRepo order.RefundRepository,
+	shipmentRepo order.ShipmentRepository,
+	exportRepo order.ExportJobRepository,
+	opts Options,
+	encoder Encoder,
+	store Store,
+	txManager TxManager,
+) *Service {
+	return &Service{
+		orderRepo:    orderRepo,
+		paymentRepo:  paymentRepo,
+		refundRepo:   refundRepo,
+		shipmentRepo: shipmentRepo,
+		exportRepo:   exportRepo,
+		opts:         opts,
+		encoder:      encoder,
+		store:        store,
+		txManager:    txManager,
+	}
+}
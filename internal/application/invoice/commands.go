@@ -0,0 +1,95 @@
+package invoice
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/invoice"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+	"github.com/oklog/ulid/v2"
+)
+
+// IssueForPaidOrder 为已支付订单开具发票并标记为已支付（命令）
+// 需在订单支付事务中调用；订单已有有效发票时直接返回
+func (s *Service) IssueForPaidOrder(ctx context.Context, o *order.Order) error {
+	if err := s.invoiceService.ValidateNotInvoiced(ctx, o.ID); err != nil {
+		if errors.Is(err, invoice.ErrInvoiceAlreadyIssued) {
+			return nil
+		}
+		return err
+	}
+
+	inv, err := invoice.NewFromOrder(o)
+	if err != nil {
+		return err
+	}
+	assignIDs(inv)
+
+	if err := s.invoiceService.Issue(ctx, inv, invoice.DefaultPaymentTermDays); err != nil {
+		return err
+	}
+	if err := inv.MarkAsPaid(time.Now()); err != nil {
+		return err
+	}
+
+	return s.invoiceRepo.Create(ctx, inv)
+}
+
+// IssueCreditNote 为订单退款开具红字发票（命令）
+// 订单没有有效发票（如功能上线前已支付的订单）时不开具
+func (s *Service) IssueCreditNote(ctx context.Context, o *order.Order, amount order.Money, reason string) error {
+	original, err := s.invoiceRepo.FindActiveByOrderID(ctx, o.ID)
+	if err != nil {
+		if errors.Is(err, invoice.ErrInvoiceNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	credited, err := s.invoiceRepo.SumCredited(ctx, original.ID)
+	if err != nil {
+		return err
+	}
+
+	note, err := invoice.NewCreditNote(original, amount, credited, reason)
+	if err != nil {
+		return err
+	}
+	assignIDs(note)
+
+	if err := s.invoiceService.Issue(ctx, note, 0); err != nil {
+		return err
+	}
+
+	return s.invoiceRepo.Create(ctx, note)
+}
+
+// VoidInvoice 作废发票（命令）
+func (s *Service) VoidInvoice(ctx context.Context, id string) (*InvoiceDTO, error) {
+	inv, err := s.invoiceRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := inv.Void(); err != nil {
+		return nil, err
+	}
+
+	if err := s.invoiceRepo.Update(ctx, inv); err != nil {
+		return nil, err
+	}
+
+	return domainInvoiceToDTO(inv), nil
+}
+
+// assignIDs 为发票及发票行分配ID
+func assignIDs(inv *invoice.Invoice) {
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
+	inv.ID = ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
+	for _, line := range inv.Lines {
+		line.ID = ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
+		line.InvoiceID = inv.ID
+	}
+}
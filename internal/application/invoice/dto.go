@@ -0,0 +1,63 @@
+package invoice
+
+import "time"
+
+// InvoiceDTO 发票DTO
+type InvoiceDTO struct {
+	ID               string     `json:"id"`
+	OrderID          string     `json:"order_id"`
+	UserID           string     `json:"user_id"`
+	Type             string     `json:"type"`
+	Number           string     `json:"number,omitempty"`
+	Status           string     `json:"status"`
+	RelatedInvoiceID string     `json:"related_invoice_id,omitempty"`
+	Reason           string     `json:"reason,omitempty"`
+	Note             string     `json:"note,omitempty"`
+	Lines            []*LineDTO `json:"lines"`
+	Subtotal         MoneyDTO   `json:"subtotal"`
+	TaxTotal         MoneyDTO   `json:"tax_total"`
+	Total            MoneyDTO   `json:"total"`
+	IssuedAt         *time.Time `json:"issued_at,omitempty"`
+	DueAt            *time.Time `json:"due_at,omitempty"`
+	PaidAt           *time.Time `json:"paid_at,omitempty"`
+	VoidedAt         *time.Time `json:"voided_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// LineDTO 发票行DTO
+type LineDTO struct {
+	ID          string   `json:"id"`
+	OrderItemID string   `json:"order_item_id,omitempty"`
+	Description string   `json:"description"`
+	Quantity    int      `json:"quantity"`
+	UnitPrice   MoneyDTO `json:"unit_price"`
+	TaxRate     float64  `json:"tax_rate"`
+	TaxAmount   MoneyDTO `json:"tax_amount"`
+	Total       MoneyDTO `json:"total"`
+}
+
+// MoneyDTO 金额DTO
+type MoneyDTO struct {
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+// ListInvoicesRequest 列出发票请求
+type ListInvoicesRequest struct {
+	Page     int    `json:"page" validate:"gte=1"`
+	PageSize int    `json:"page_size" validate:"gte=1,lte=100"`
+	UserID   string `json:"user_id"`
+	OrderID  string `json:"order_id"`
+	Type     string `json:"type"`
+	Status   string `json:"status"`
+}
+
+// ListInvoicesResponse 列出发票响应
+type ListInvoicesResponse struct {
+	Invoices   []*InvoiceDTO `json:"invoices"`
+	Total      int64         `json:"total"`
+	Page       int           `json:"page"`
+	PageSize   int           `json:"page_size"`
+	TotalPages int           `json:"total_pages"`
+}
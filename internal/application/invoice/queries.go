@@ -0,0 +1,70 @@
+package invoice
+
+import (
+	"context"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/invoice"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/shared/pagination"
+)
+
+// GetInvoice 获取发票（查询）
+func (s *Service) GetInvoice(ctx context.Context, id string) (*InvoiceDTO, error) {
+	inv, err := s.invoiceRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return domainInvoiceToDTO(inv), nil
+}
+
+// GetUserInvoice 获取当前用户的发票（查询）
+// 非本人发票按不存在处理，避免泄露其他用户的发票
+func (s *Service) GetUserInvoice(ctx context.Context, userID, id string) (*InvoiceDTO, error) {
+	inv, err := s.invoiceRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if inv.UserID != userID {
+		return nil, invoice.ErrInvoiceNotFound
+	}
+
+	return domainInvoiceToDTO(inv), nil
+}
+
+// ListUserInvoices 列出当前用户的发票（查询）
+func (s *Service) ListUserInvoices(ctx context.Context, userID string, req ListInvoicesRequest) (*ListInvoicesResponse, error) {
+	req.UserID = userID
+	return s.ListInvoices(ctx, req)
+}
+
+// ListInvoices 列出发票（查询）
+func (s *Service) ListInvoices(ctx context.Context, req ListInvoicesRequest) (*ListInvoicesResponse, error) {
+	offset, limit := pagination.ParsePaginationParams(req.Page, req.PageSize)
+
+	filter := invoice.ListFilter{
+		UserID:  req.UserID,
+		OrderID: req.OrderID,
+		Type:    invoice.Type(req.Type),
+		Status:  invoice.Status(req.Status),
+	}
+
+	invoices, total, err := s.invoiceRepo.List(ctx, filter, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*InvoiceDTO, len(invoices))
+	for i, inv := range invoices {
+		dtos[i] = domainInvoiceToDTO(inv)
+	}
+
+	pg := pagination.NewPagination(req.Page, req.PageSize, total)
+
+	return &ListInvoicesResponse{
+		Invoices:   dtos,
+		Total:      total,
+		Page:       pg.Page,
+		PageSize:   pg.PageSize,
+		TotalPages: pg.TotalPages,
+	}, nil
+}
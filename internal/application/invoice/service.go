@@ -0,0 +1,67 @@
+package invoice
+
+import (
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/invoice"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+)
+
+// Service 发票应用服务
+type Service struct {
+	invoiceRepo    invoice.Repository
+	invoiceService *invoice.Service
+}
+
+// NewService 创建发票应用服务
+func NewService(invoiceRepo invoice.Repository, invoiceService *invoice.Service) *Service {
+	return &Service{
+		invoiceRepo:    invoiceRepo,
+		invoiceService: invoiceService,
+	}
+}
+
+// domainInvoiceToDTO 转换发票为DTO
+func domainInvoiceToDTO(inv *invoice.Invoice) *InvoiceDTO {
+	lines := make([]*LineDTO, len(inv.Lines))
+	for i, line := range inv.Lines {
+		lines[i] = &LineDTO{
+			ID:          line.ID,
+			OrderItemID: line.OrderItemID,
+			Description: line.Description,
+			Quantity:    line.Quantity,
+			UnitPrice:   moneyToDTO(line.UnitPrice),
+			TaxRate:     line.TaxRate,
+			TaxAmount:   moneyToDTO(line.TaxAmount),
+			Total:       moneyToDTO(line.Total),
+		}
+	}
+
+	return &InvoiceDTO{
+		ID:               inv.ID,
+		OrderID:          inv.OrderID,
+		UserID:           inv.UserID,
+		Type:             string(inv.Type),
+		Number:           inv.Number,
+		Status:           string(inv.Status),
+		RelatedInvoiceID: inv.RelatedInvoiceID,
+		Reason:           inv.Reason,
+		Note:             inv.Note,
+		Lines:            lines,
+		Subtotal:         moneyToDTO(inv.Subtotal),
+		TaxTotal:         moneyToDTO(inv.TaxTotal),
+		Total:            moneyToDTO(inv.Total),
+		IssuedAt:         inv.IssuedAt,
+		DueAt:            inv.DueAt,
+		PaidAt:           inv.PaidAt,
+		VoidedAt:         inv.VoidedAt,
+		CreatedAt:        inv.CreatedAt,
+		UpdatedAt:        inv.UpdatedAt,
+	}
+}
+
+// moneyToDTO 转换金额为DTO
+func moneyToDTO(m order.Money) MoneyDTO {
+	return MoneyDTO{
+		Amount:   m.Amount,
+		Currency: m.Currency,
+	}
+}
@@ -46,6 +46,9 @@ func (s *Service) UpdateMenu(ctx context.Context, id string, req UpdateMenuReque
 	if err != nil {
 		return nil, err
 	}
+	if err := menu.CheckVersion(req.ExpectedVersion); err != nil {
+		return nil, err
+	}
 
 	// 如果更新了父菜单，验证层级
 	if req.ParentID != nil && (menu.ParentID == nil || *menu.ParentID != *req.ParentID) {
@@ -144,6 +147,7 @@ func toMenuResponse(m *rbac.Menu) *MenuResponse {
 		Component:   m.Component,
 		Permission:  m.Permission,
 		Description: m.Description,
+		Version:     m.Version,
 		CreatedAt:   m.CreatedAt,
 		UpdatedAt:   m.UpdatedAt,
 	}
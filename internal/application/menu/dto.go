@@ -28,6 +28,8 @@ type UpdateMenuRequest struct {
 	Permission  string  `json:"permission"`
 	Description string  `json:"description"`
 	IsVisible   *bool   `json:"isVisible"`
+
+	ExpectedVersion int `json:"-"` // 请求头 If-Match 中的版本号，0 表示不校验
 }
 
 // AssignMenusRequest 分配菜单给角色请求
@@ -61,6 +63,7 @@ type MenuResponse struct {
 	Component   string    `json:"component,omitempty"`
 	Permission  string    `json:"permission,omitempty"`
 	Description string    `json:"description,omitempty"`
+	Version     int       `json:"version"` // 乐观锁版本号，与响应头 ETag 一致
 	CreatedAt   time.Time `json:"createdAt"`
 	UpdatedAt   time.Time `json:"updatedAt"`
 }
@@ -82,5 +85,6 @@ type MenuTreeItem struct {
 	Component   string          `json:"component,omitempty"`
 	Permission  string          `json:"permission,omitempty"`
 	Description string          `json:"description,omitempty"`
+	Version     int             `json:"version"`            // 乐观锁版本号，修改菜单时通过 If-Match 回传
 	Children    []*MenuTreeItem `json:"children,omitempty"` // 子菜单（递归）
 }
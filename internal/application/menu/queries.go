@@ -119,6 +119,7 @@ func (s *Service) nodeToTreeItem(node *rbac.MenuNode) *MenuTreeItem {
 		Component:   node.Menu.Component,
 		Permission:  node.Menu.Permission,
 		Description: node.Menu.Description,
+		Version:     node.Menu.Version,
 	}
 
 	// 递归处理子节点
@@ -0,0 +1,49 @@
+package order
+
+import (
+	"context"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+)
+
+// orderAddresses 确定下单时快照的收货与账单地址
+// 未指定时取用户的默认地址，账单地址再缺省同收货地址
+func (s *Service) orderAddresses(ctx context.Context, userID string, req CreateOrderRequest) (shipping, billing *order.Address, err error) {
+	defaultShipping, defaultBilling, err := s.addressBook.DefaultAddresses(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shipping, err = s.resolveAddress(ctx, userID, req.ShippingAddressID, req.ShippingAddress, defaultShipping)
+	if err != nil {
+		return nil, nil, err
+	}
+	billing, err = s.resolveAddress(ctx, userID, req.BillingAddressID, req.BillingAddress, defaultBilling)
+	if err != nil {
+		return nil, nil, err
+	}
+	if billing == nil {
+		billing = shipping
+	}
+	return shipping, billing, nil
+}
+
+// resolveAddress 按优先级取地址：地址簿中的地址、请求中的新地址、缺省地址
+func (s *Service) resolveAddress(ctx context.Context, userID, addressID string, dto *AddressDTO, fallback *order.Address) (*order.Address, error) {
+	switch {
+	case addressID != "":
+		address, err := s.addressBook.FindAddress(ctx, userID, addressID)
+		if err != nil {
+			return nil, err
+		}
+		return &address, nil
+	case dto != nil:
+		address, err := addressFromDTO(*dto)
+		if err != nil {
+			return nil, err
+		}
+		return &address, nil
+	default:
+		return fallback, nil
+	}
+}
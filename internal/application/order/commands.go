@@ -2,6 +2,8 @@ package order
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math/rand"
 	"time"
 
@@ -12,7 +14,10 @@ import (
 // CreateOrder 创建订单（命令）
 func (s *Service) CreateOrder(ctx context.Context, userID string, req CreateOrderRequest) (*OrderDTO, error) {
 	// 生成订单号
-	orderNumber := s.orderService.GenerateOrderNumber()
+	orderNumber, err := s.orderService.GenerateOrderNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	// 创建订单
 	o, err := order.NewOrder(userID, orderNumber)
@@ -33,12 +38,42 @@ func (s *Service) CreateOrder(ctx context.Context, userID string, req CreateOrde
 			return nil, err
 		}
 		item.ID = itemID
+		item.SetCategory(itemReq.Category)
+		item.SetWeight(itemReq.WeightKg)
 
 		if err := o.AddItem(item); err != nil {
 			return nil, err
 		}
 	}
 
+	// 应用优惠码
+	if req.CouponCode != "" {
+		if err := s.promotions.Apply(ctx, userID, req.CouponCode, o); err != nil {
+			return nil, err
+		}
+	}
+
+	// 快照收货与账单地址，之后修改地址簿不影响订单；按收货地址计税（折扣之后计算）
+	shipping, billing, err := s.orderAddresses(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	if shipping != nil {
+		o.SetShippingAddress(*shipping)
+	}
+	if billing != nil {
+		o.SetBillingAddress(*billing)
+	}
+	o.SetVATID(req.VATID)
+
+	breakdown, err := s.taxCalculator.Calculate(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+	if err := o.ApplyTax(*breakdown); err != nil {
+		return nil, err
+	}
+
 	// 保存订单
 	if err := s.orderRepo.Create(ctx, o); err != nil {
 		return nil, err
@@ -48,21 +83,130 @@ func (s *Service) CreateOrder(ctx context.Context, userID string, req CreateOrde
 }
 
 // CancelOrder 取消订单（命令）
-func (s *Service) CancelOrder(ctx context.Context, orderID string) error {
-	o, err := s.orderRepo.FindByID(ctx, orderID)
+func (s *Service) CancelOrder(ctx context.Context, userID, orderID string) error {
+	o, actor, err := s.findOrderForUser(ctx, userID, orderID)
 	if err != nil {
 		return err
 	}
 
-	if err := o.Cancel(); err != nil {
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		locked, err := s.orderRepo.FindByIDForUpdate(ctx, o.ID)
+		if err != nil {
+			return err
+		}
+		return s.cancelOrder(ctx, locked, actor, "cancelled by "+order.ActorType(actor))
+	})
+	if err != nil {
 		return err
 	}
+	s.voidOpenAttempts(ctx, o.ID)
+	return nil
+}
 
-	return s.orderRepo.Update(ctx, o)
+// cancelOrder 取消订单，未支付的订单同时释放支付尝试占用的优惠核销，需在事务中调用
+func (s *Service) cancelOrder(ctx context.Context, o *order.Order, actor, reason string) error {
+	unpaid := o.Status == order.StatusPending || o.Status == order.StatusReview
+	if err := o.Cancel(actor, reason); err != nil {
+		return err
+	}
+	if err := s.orderRepo.Update(ctx, o); err != nil {
+		return err
+	}
+	if !unpaid {
+		return nil
+	}
+	return s.promotions.Release(ctx, o)
+}
+
+// voidOpenAttempts 订单取消的事务提交后作废仍在进行的支付尝试
+// 作废失败不影响已生效的取消：该交易之后若扣款成功，按无法入账的付款自动全额退款（见 settlePayment）
+func (s *Service) voidOpenAttempts(ctx context.Context, orderID string) {
+	_ = s.cancelOpenAttempts(ctx, orderID)
+}
+
+// UpdateOrderStatus 管理员更新订单状态（命令）
+// 在事务中锁定订单后校验版本与转换；取消经由与顾客取消相同的流程，释放优惠并作废进行中的支付尝试
+func (s *Service) UpdateOrderStatus(ctx context.Context, adminID, orderID string, req UpdateOrderStatusRequest) (*OrderDTO, error) {
+	to := order.OrderStatus(req.Status)
+	actor := order.AdminActor(adminID)
+
+	var o *order.Order
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		locked, err := s.orderRepo.FindByIDForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if err := locked.CheckVersion(req.ExpectedVersion); err != nil {
+			return err
+		}
+		o = locked
+
+		if to == order.StatusCancelled {
+			if err := locked.CheckManualTransition(to); err != nil {
+				return err
+			}
+			reason := req.Note
+			if reason == "" {
+				reason = "cancelled by admin"
+			}
+			return s.cancelOrder(ctx, locked, actor, reason)
+		}
+		if err := locked.ChangeStatus(to, actor, req.Note); err != nil {
+			return err
+		}
+		return s.orderRepo.Update(ctx, locked)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if o.Status == order.StatusCancelled {
+		s.voidOpenAttempts(ctx, o.ID)
+	}
+	return domainOrderToDTO(o), nil
+}
+
+// ResolveOrderAttention 管理员处理完毕后清除订单的待处理标记（命令）
+func (s *Service) ResolveOrderAttention(ctx context.Context, orderID string) (*OrderDTO, error) {
+	var o *order.Order
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		locked, err := s.orderRepo.FindByIDForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if err := locked.ResolveAttention(); err != nil {
+			return err
+		}
+		o = locked
+		return s.orderRepo.Update(ctx, o)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domainOrderToDTO(o), nil
 }
 
 // ProcessPayment 处理支付（命令）
-func (s *Service) ProcessPayment(ctx context.Context, orderID string, req ProcessPaymentRequest) (*PaymentDTO, error) {
+func (s *Service) ProcessPayment(ctx context.Context, userID, orderID string, req ProcessPaymentRequest) (*PaymentDTO, error) {
+	// 验证订单归属
+	if _, _, err := s.findOrderForUser(ctx, userID, orderID); err != nil {
+		return nil, err
+	}
+
+	return s.chargeOrder(ctx, orderID, req, true)
+}
+
+// chargeOrder 对待支付订单发起扣款，screen 为 true 时扣款前进行欺诈筛查，
+// 筛查分数达到阈值时订单转人工审核并返回 order.ErrOrderUnderReview。
+// 网关调用期间不持有事务与订单行锁，扣款分三步完成：
+//  1. 事务中锁定订单、筛查、核销优惠并登记待确认的支付尝试
+//  2. 在事务之外调用网关
+//  3. 事务中锁定支付并记录结果，支付已由回调处理时以回调为准
+//
+// 网关明确拒绝时支付标记为失败；结果未知（超时、网关错误）或第 3 步失败时支付保持待确认，
+// 由网关回调按交易号或元数据中的支付ID补记，调用方收到 order.ErrPaymentPending
+func (s *Service) chargeOrder(ctx context.Context, orderID string, req ProcessPaymentRequest, screen bool) (*PaymentDTO, error) {
 	// 验证订单是否可以支付
 	if err := s.orderService.ValidateOrderForPayment(ctx, orderID); err != nil {
 		return nil, err
@@ -74,165 +218,536 @@ func (s *Service) ProcessPayment(ctx context.Context, orderID string, req Proces
 		return nil, err
 	}
 
-	// 创建支付
+	// 按支付方式选择网关
 	method := order.PaymentMethod(req.Method)
-	payment, err := order.NewPayment(orderID, o.TotalAmount, method)
+	gateway, err := s.gateways.ForMethod(method)
 	if err != nil {
 		return nil, err
 	}
 
-	entropy := ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
-	payment.ID = ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
+	var accountAge time.Duration
+	screen = screen && s.fraudPolicy.Enabled()
+	if screen {
+		createdAt, err := s.accounts.AccountCreatedAt(ctx, o.UserID)
+		if err != nil {
+			return nil, err
+		}
+		accountAge = time.Since(createdAt)
+	}
 
-	// 调用支付网关
-	transactionID, gatewayResponse, err := s.paymentGateway.ProcessPayment(ctx, o.TotalAmount, method)
+	payment, err := s.beginPaymentAttempt(ctx, orderID, method, req, screen, accountAge)
 	if err != nil {
-		if markErr := payment.MarkAsFailed(err.Error()); markErr != nil {
-			return nil, markErr
+		return nil, err
+	}
+
+	// 调用支付网关，幂等键随支付尝试沿用，客户端超时后重试不会再次扣款
+	result, gatewayErr := gateway.ProcessPayment(ctx, order.ChargeRequest{
+		PaymentID:      payment.ID,
+		OrderID:        orderID,
+		Amount:         payment.Amount,
+		Method:         method,
+		Token:          req.PaymentMethodID,
+		ReturnURL:      req.ReturnURL,
+		IdempotencyKey: payment.IdempotencyKey(),
+	})
+	if gatewayErr != nil {
+		if errors.Is(gatewayErr, order.ErrGatewayUnavailable) {
+			return nil, fmt.Errorf("%w: %v", order.ErrPaymentPending, gatewayErr)
 		}
-		if err := s.paymentRepo.Create(ctx, payment); err != nil {
+		if err := s.failPaymentAttempt(ctx, payment.ID, gatewayErr.Error()); err != nil {
 			return nil, err
 		}
 		return nil, order.ErrPaymentFailed
 	}
 
-	// 标记支付成功
-	if err := payment.MarkAsCompleted(transactionID, gatewayResponse); err != nil {
+	payment, err = s.recordChargeResult(ctx, payment.ID, result)
+	if err != nil {
+		if errors.Is(err, order.ErrInvalidOrderStatus) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", order.ErrPaymentPending, err)
+	}
+
+	dto := domainPaymentToDTO(payment)
+	if result.Status == order.ChargeRequiresAction && payment.Status == order.PaymentStatusPending {
+		dto.RequiresAction = true
+		dto.ClientSecret = result.ClientSecret
+	}
+	return dto, nil
+}
+
+// beginPaymentAttempt 锁定订单并登记待确认的支付尝试（扣款第 1 步），已有结果未知的尝试时沿用
+// 已有成功支付时拒绝，避免重复收款；优惠在此核销，保证使用上限在并发下不被突破
+func (s *Service) beginPaymentAttempt(ctx context.Context, orderID string, method order.PaymentMethod, req ProcessPaymentRequest, screen bool, accountAge time.Duration) (*order.Payment, error) {
+	var (
+		payment     *order.Payment
+		underReview bool
+	)
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		o, err := s.orderRepo.FindByIDForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if o.Status != order.StatusPending {
+			return order.ErrInvalidOrderStatus
+		}
+		if err := s.orderService.ValidatePaymentAttempt(ctx, orderID); err != nil {
+			return err
+		}
+
+		if screen {
+			held, err := s.screenOrder(ctx, o, req, accountAge)
+			if err != nil || held {
+				underReview = held
+				return err
+			}
+		}
+
+		if err := s.promotions.Redeem(ctx, o); err != nil {
+			return err
+		}
+
+		// 上次调用结果未知的尝试直接沿用，重试请求不会产生新的幂等键
+		resumable, err := s.orderService.ResumablePaymentAttempt(ctx, orderID, method, o.TotalAmount)
+		if err != nil || resumable != nil {
+			payment = resumable
+			return err
+		}
+
+		p, err := order.NewPayment(orderID, o.TotalAmount, method)
+		if err != nil {
+			return err
+		}
+		entropy := ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
+		p.ID = ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
+
+		payment = p
+		return s.paymentRepo.Create(ctx, p)
+	})
+	if err != nil {
 		return nil, err
 	}
+	if underReview {
+		return nil, order.ErrOrderUnderReview
+	}
+	return payment, nil
+}
 
-	// 保存支付
-	if err := s.paymentRepo.Create(ctx, payment); err != nil {
+// recordChargeResult 锁定支付并记录网关扣款结果（扣款第 3 步），收款成功时订单置为已支付
+// 支付已不处于待确认状态（回调先到达）时不做变更，返回当前状态
+func (s *Service) recordChargeResult(ctx context.Context, paymentID string, result *order.ChargeResult) (*order.Payment, error) {
+	var (
+		payment *order.Payment
+		orphan  *order.Refund
+	)
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		p, err := s.paymentRepo.FindByIDForUpdate(ctx, paymentID)
+		if err != nil {
+			return err
+		}
+		payment = p
+		if p.Status != order.PaymentStatusPending {
+			return nil
+		}
+
+		switch result.Status {
+		case order.ChargeSucceeded:
+			err = p.MarkAsCompleted(result.TransactionID, result.Response)
+		case order.ChargeRequiresCapture:
+			err = p.MarkAsAuthorized(result.TransactionID, result.Response)
+		default:
+			// 需持卡人验证或网关处理中：支付保持待确认，由回调完成后续状态变更
+			err = p.AttachTransaction(result.TransactionID, result.Response)
+		}
+		if err != nil {
+			return err
+		}
+		if err := s.paymentRepo.Update(ctx, p); err != nil {
+			return err
+		}
+		orphan, err = s.settlePayment(ctx, p)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
+	// 扣款期间订单已被取消（如超时）：款项已退回，告知客户订单不可支付
+	if orphan != nil {
+		if err := s.refundOrphanPayment(ctx, orphan); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: payment refunded because the order was cancelled", order.ErrInvalidOrderStatus)
+	}
+	return payment, nil
+}
+
+// failPaymentAttempt 网关明确拒绝：支付标记为失败，订单没有其他进行中的尝试时释放优惠核销
+func (s *Service) failPaymentAttempt(ctx context.Context, paymentID, reason string) error {
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		p, err := s.paymentRepo.FindByIDForUpdate(ctx, paymentID)
+		if err != nil {
+			return err
+		}
+		if p.Status != order.PaymentStatusPending {
+			return nil
+		}
+		if err := p.MarkAsFailed(reason); err != nil {
+			return err
+		}
+		if err := s.paymentRepo.Update(ctx, p); err != nil {
+			return err
+		}
+		return s.releasePromotions(ctx, p.OrderID)
+	})
+}
+
+// orphanPaymentReason 订单已不能支付时收到的付款，全额退款并标记订单待管理员处理
+const orphanPaymentReason = "payment received for an order that can no longer be paid"
 
-	// 更新订单状态为已支付
-	if err := o.MarkAsPaid(); err != nil {
+// settlePayment 刚完成收款的支付将订单置为已支付，需在事务中调用
+// 订单已不能转为已支付（如超时取消后网关才扣款成功，或已由另一笔支付入账）时登记全额退款并标记订单待管理员处理，
+// 返回登记的退款，由调用方在事务提交后经 refundOrphanPayment 调用网关
+func (s *Service) settlePayment(ctx context.Context, payment *order.Payment) (*order.Refund, error) {
+	if !payment.IsCompleted() {
+		return nil, nil
+	}
+
+	o, err := s.orderRepo.FindByIDForUpdate(ctx, payment.OrderID)
+	if err != nil {
 		return nil, err
 	}
+	if o.CanTransitionTo(order.StatusPaid) {
+		return nil, s.markOrderPaid(ctx, o, order.ActorSystem)
+	}
 
+	refund, err := order.NewRefund(o, payment, payment.Amount.Amount, orphanPaymentReason, order.ActorSystem, nil)
+	if err != nil {
+		return nil, err
+	}
+	assignRefundIDs(refund)
+	if err := s.refundRepo.Create(ctx, refund); err != nil {
+		return nil, err
+	}
+
+	o.FlagForAttention(orphanPaymentReason)
 	if err := s.orderRepo.Update(ctx, o); err != nil {
 		return nil, err
 	}
+	return refund, nil
+}
 
-	return domainPaymentToDTO(payment), nil
+// refundOrphanPayment 事务提交后为无法入账的付款调用网关退款
+// 结果未知的退款保持待处理由 worker 重试，被拒绝的退款随订单标记由管理员处理
+func (s *Service) refundOrphanPayment(ctx context.Context, refund *order.Refund) error {
+	_, err := s.executeRefund(ctx, refund, order.ActorSystem)
+	if errors.Is(err, order.ErrRefundPending) || errors.Is(err, order.ErrRefundFailed) {
+		return nil
+	}
+	return err
 }
 
-// RefundPayment 退款（命令）
-func (s *Service) RefundPayment(ctx context.Context, orderID string) error {
-	// 验证是否可以退款
-	if err := s.orderService.ValidateRefund(ctx, orderID); err != nil {
+// releasePromotions 订单没有进行中的支付尝试时释放其优惠核销，需在事务中调用
+func (s *Service) releasePromotions(ctx context.Context, orderID string) error {
+	inProgress, err := s.orderService.HasPaymentInProgress(ctx, orderID, time.Time{})
+	if err != nil || inProgress {
 		return err
 	}
-
-	// 获取支付记录
-	payment, err := s.paymentRepo.FindByOrderID(ctx, orderID)
+	o, err := s.orderRepo.FindByID(ctx, orderID)
 	if err != nil {
 		return err
 	}
+	return s.promotions.Release(ctx, o)
+}
 
-	// 调用支付网关退款
-	if err := s.paymentGateway.RefundPayment(ctx, payment.TransactionID, payment.Amount); err != nil {
-		return err
+// CapturePayment 对已授权的支付请款（命令）
+func (s *Service) CapturePayment(ctx context.Context, orderID string) (*PaymentDTO, error) {
+	payment, err := s.paymentRepo.FindLatestByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !payment.IsAuthorized() {
+		return nil, order.ErrPaymentNotCapturable
 	}
 
-	// 更新支付状态
-	if err := payment.Refund(); err != nil {
-		return err
+	gateway, err := s.gateways.ForMethod(payment.Method)
+	if err != nil {
+		return nil, err
+	}
+	if err := gateway.CapturePayment(ctx, payment.TransactionID, payment.Amount, "capture-"+payment.ID); err != nil {
+		return nil, err
 	}
 
-	if err := s.paymentRepo.Update(ctx, payment); err != nil {
-		return err
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := payment.MarkAsCompleted(payment.TransactionID, "captured"); err != nil {
+			return err
+		}
+		if err := s.paymentRepo.Update(ctx, payment); err != nil {
+			return err
+		}
+
+		o, err := s.orderRepo.FindByID(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		return s.markOrderPaid(ctx, o, order.ActorSystem)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// 更新订单状态
-	o, err := s.orderRepo.FindByID(ctx, orderID)
+	return domainPaymentToDTO(payment), nil
+}
+
+// ConfirmPayment 管理员确认线下收款到账（命令）
+func (s *Service) ConfirmPayment(ctx context.Context, adminID, orderID string, req ConfirmPaymentRequest) (*PaymentDTO, error) {
+	payment, err := s.paymentRepo.FindLatestByOrderID(ctx, orderID)
 	if err != nil {
+		return nil, err
+	}
+	if payment.Status != order.PaymentStatusPending || !s.gateways.requiresManualConfirmation(payment.Method) {
+		return nil, order.ErrPaymentNotConfirmable
+	}
+
+	transactionID := payment.TransactionID
+	if req.Reference != "" {
+		transactionID = req.Reference
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := payment.MarkAsCompleted(transactionID, "confirmed by admin"); err != nil {
+			return err
+		}
+		if err := s.paymentRepo.Update(ctx, payment); err != nil {
+			return err
+		}
+
+		o, err := s.orderRepo.FindByID(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		return s.markOrderPaid(ctx, o, order.AdminActor(adminID))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domainPaymentToDTO(payment), nil
+}
+
+// markOrderPaid 订单置为已支付并开具发票，需在事务中调用
+func (s *Service) markOrderPaid(ctx context.Context, o *order.Order, actor string) error {
+	if err := o.MarkAsPaid(actor); err != nil {
 		return err
 	}
 
-	if err := o.Refund(); err != nil {
+	if err := s.orderRepo.Update(ctx, o); err != nil {
 		return err
 	}
 
-	return s.orderRepo.Update(ctx, o)
+	// 自动开具发票
+	return s.invoices.IssueForPaidOrder(ctx, o)
 }
 
-// CreateShipment 创建发货（命令）
-func (s *Service) CreateShipment(ctx context.Context, orderID string, req CreateShipmentRequest) (*ShipmentDTO, error) {
+// CreateShipment 创建发货（命令），未指定发货行时发出订单剩余的全部未发货商品
+func (s *Service) CreateShipment(ctx context.Context, userID, orderID string, req CreateShipmentRequest) (*ShipmentDTO, error) {
+	// 验证订单归属
+	o, _, err := s.findOrderForUser(ctx, userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
 	// 验证订单是否可以发货
 	if err := s.orderService.ValidateOrderForShipment(ctx, orderID); err != nil {
 		return nil, err
 	}
 
-	// 创建地址
-	address, err := order.NewAddress(
-		req.Address.Street,
-		req.Address.City,
-		req.Address.State,
-		req.Address.PostalCode,
-		req.Address.Country,
-	)
+	// 发货地址：地址簿中的地址、请求中的新地址或订单的收货地址
+	address, err := s.resolveAddress(ctx, o.UserID, req.AddressID, req.Address, o.ShippingAddress)
 	if err != nil {
 		return nil, err
 	}
+	if address == nil {
+		return nil, order.ErrShippingAddressRequired
+	}
 
-	// 创建发货
-	shipment, err := order.NewShipment(orderID, address, req.ShippingMethod)
+	lines := make([]*order.ShipmentLine, len(req.Lines))
+	for i, line := range req.Lines {
+		lines[i] = &order.ShipmentLine{OrderItemID: line.OrderItemID, Quantity: line.Quantity}
+	}
+	if len(lines) == 0 {
+		lines = o.UnfulfilledLines()
+	}
+
+	// 运输服务缺省为下单时所选，按发货地址与本次包裹重新报价以确定承运商与时效
+	service := req.ShippingMethod
+	if service == "" {
+		if adj := o.ShippingAdjustment(); adj != nil {
+			service = adj.Code
+		}
+	}
+	rate, err := s.shippingRate(ctx, *address, o.ParcelFor(lines), service)
 	if err != nil {
 		return nil, err
 	}
 
-	entropy := ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
-	shipment.ID = ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
+	var shipment *order.Shipment
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		// 锁定订单，避免并发发货超出未发货数量
+		locked, err := s.orderRepo.FindByIDForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if err := locked.AllocateShipment(lines); err != nil {
+			return err
+		}
+
+		shipment, err = order.NewShipment(orderID, *address, service, lines)
+		if err != nil {
+			return err
+		}
+		entropy := ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
+		shipment.ID = ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
 
-	// 计算预计送达时间
-	estimatedDate := s.orderService.CalculateEstimatedDelivery(req.ShippingMethod)
-	shipment.SetEstimatedDeliveryDate(estimatedDate)
+		if err := shipment.ApplyRate(*rate); err != nil {
+			return err
+		}
 
-	// 保存发货
-	if err := s.shipmentRepo.Create(ctx, shipment); err != nil {
+		if err := s.orderRepo.Update(ctx, locked); err != nil {
+			return err
+		}
+		return s.shipmentRepo.Create(ctx, shipment)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return domainShipmentToDTO(shipment), nil
 }
 
-// UpdateShipment 更新发货（命令）
-func (s *Service) UpdateShipment(ctx context.Context, shipmentID string, req UpdateShipmentRequest) (*ShipmentDTO, error) {
-	shipment, err := s.shipmentRepo.FindByID(ctx, shipmentID)
+// StartShipmentProcessing 发货开始拣货打包（命令）
+func (s *Service) StartShipmentProcessing(ctx context.Context, shipmentID string) (*ShipmentDTO, error) {
+	shipment, err := s.changeShipment(ctx, shipmentID, func(ctx context.Context, shipment *order.Shipment) error {
+		return shipment.StartProcessing()
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// 开始处理
-	if err := shipment.StartProcessing(); err != nil {
+	return domainShipmentToDTO(shipment), nil
+}
+
+// ShipShipment 交付承运商发出（命令）
+func (s *Service) ShipShipment(ctx context.Context, shipmentID string, req ShipShipmentRequest) (*ShipmentDTO, error) {
+	shipment, err := s.changeShipment(ctx, shipmentID, func(ctx context.Context, shipment *order.Shipment) error {
+		return shipment.Ship(req.TrackingNumber, req.Carrier)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// 发货
-	if err := shipment.Ship(req.TrackingNumber, req.Carrier); err != nil {
+	return domainShipmentToDTO(shipment), nil
+}
+
+// DeliverShipment 确认送达（命令），订单随之完成
+func (s *Service) DeliverShipment(ctx context.Context, adminID, shipmentID string) (*ShipmentDTO, error) {
+	shipment, err := s.changeShipment(ctx, shipmentID, func(ctx context.Context, shipment *order.Shipment) error {
+		if err := shipment.Deliver(); err != nil {
+			return err
+		}
+		return s.completeDeliveredOrder(ctx, shipment, order.AdminActor(adminID))
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// 保存更新
-	if err := s.shipmentRepo.Update(ctx, shipment); err != nil {
-		return nil, err
+	return domainShipmentToDTO(shipment), nil
+}
+
+// CancelShipment 取消发货（命令）
+func (s *Service) CancelShipment(ctx context.Context, shipmentID string, req CancelShipmentRequest) (*ShipmentDTO, error) {
+	reason := req.Reason
+	if reason == "" {
+		reason = "shipment cancelled"
 	}
 
-	// 更新订单状态为已完成
-	o, err := s.orderRepo.FindByID(ctx, shipment.OrderID)
+	shipment, err := s.changeShipment(ctx, shipmentID, func(ctx context.Context, shipment *order.Shipment) error {
+		if err := shipment.Cancel(reason); err != nil {
+			return err
+		}
+
+		// 释放发货占用的数量，商品可重新发货
+		o, err := s.orderRepo.FindByIDForUpdate(ctx, shipment.OrderID)
+		if err != nil {
+			return err
+		}
+		o.ReleaseShipment(shipment.Lines)
+		return s.orderRepo.Update(ctx, o)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if err := o.Complete(); err != nil {
-		return nil, err
+	return domainShipmentToDTO(shipment), nil
+}
+
+// AddShipmentEvent 追加承运商跟踪事件（命令）
+func (s *Service) AddShipmentEvent(ctx context.Context, shipmentID string, req AddShipmentEventRequest) (*ShipmentDTO, error) {
+	var occurredAt time.Time
+	if req.OccurredAt != nil {
+		occurredAt = *req.OccurredAt
 	}
 
-	if err := s.orderRepo.Update(ctx, o); err != nil {
+	shipment, err := s.changeShipment(ctx, shipmentID, func(ctx context.Context, shipment *order.Shipment) error {
+		shipment.RecordEvent(req.Status, req.Location, req.Description, string(req.RawPayload), occurredAt)
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return domainShipmentToDTO(shipment), nil
 }
+
+// errShipmentUnchanged 发货变更函数返回该错误表示无需保存
+var errShipmentUnchanged = errors.New("shipment unchanged")
+
+// changeShipment 在事务中锁定发货、执行变更并保存，返回变更后的发货
+// 状态校验以锁定后读取的状态为准，并发的状态变更（如手动确认送达与跟踪同步）只有一方生效；
+// 需要同时修改订单时先锁发货再锁订单，与所有发货命令的加锁顺序一致
+func (s *Service) changeShipment(ctx context.Context, shipmentID string, change func(ctx context.Context, shipment *order.Shipment) error) (*order.Shipment, error) {
+	var shipment *order.Shipment
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		locked, err := s.shipmentRepo.FindByIDForUpdate(ctx, shipmentID)
+		if err != nil {
+			return err
+		}
+		shipment = locked
+		if err := change(ctx, locked); err != nil {
+			if errors.Is(err, errShipmentUnchanged) {
+				return nil
+			}
+			return err
+		}
+		return s.shipmentRepo.Update(ctx, locked)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return shipment, nil
+}
+
+// completeDeliveredOrder 记录发货送达的数量，全部商品送达后完成订单，需在事务中调用
+// 订单已不处于可完成状态（如已退款）时只记录数量
+func (s *Service) completeDeliveredOrder(ctx context.Context, shipment *order.Shipment, actor string) error {
+	o, err := s.orderRepo.FindByIDForUpdate(ctx, shipment.OrderID)
+	if err != nil {
+		return err
+	}
+	o.RecordDelivery(shipment.Lines)
+	if o.IsFullyDelivered() && o.CanTransitionTo(order.StatusCompleted) {
+		if err := o.Complete(actor); err != nil {
+			return err
+		}
+	}
+	return s.orderRepo.Update(ctx, o)
+}
@@ -1,27 +1,72 @@
 package order
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // OrderDTO 订单DTO
 type OrderDTO struct {
-	ID          string         `json:"id"`
-	UserID      string         `json:"user_id"`
-	OrderNumber string         `json:"order_number"`
-	Status      string         `json:"status"`
-	Items       []*OrderItemDTO `json:"items"`
-	TotalAmount MoneyDTO       `json:"total_amount"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
+	ID          string           `json:"id"`
+	UserID      string           `json:"user_id"`
+	OrderNumber string           `json:"order_number"`
+	Status      string           `json:"status"`
+	Items       []*OrderItemDTO  `json:"items"`
+	Adjustments []*AdjustmentDTO `json:"adjustments"`
+	Subtotal    MoneyDTO         `json:"subtotal"`
+	TaxTotal    MoneyDTO         `json:"tax_total"`
+	TotalAmount MoneyDTO         `json:"total_amount"`
+
+	ShippingAddress *AddressDTO `json:"shipping_address,omitempty"`
+	BillingAddress  *AddressDTO `json:"billing_address,omitempty"`
+	VATID           string      `json:"vat_id,omitempty"`
+	TaxInclusive    bool        `json:"tax_inclusive"`
+	ReverseCharge   bool        `json:"reverse_charge"`
+	TaxNote         string      `json:"tax_note,omitempty"`
+	AttentionReason string      `json:"attention_reason,omitempty"` // 待管理员处理的原因
+
+	StatusHistory []*StatusChangeDTO `json:"status_history"`
+	LatestPayment *PaymentDTO        `json:"latest_payment,omitempty"` // 最近一次支付尝试
+
+	Version   int       `json:"version"` // 乐观锁版本号，与响应头 ETag 一致
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // OrderItemDTO 订单项DTO
 type OrderItemDTO struct {
-	ID          string    `json:"id"`
-	ProductID   string    `json:"product_id"`
-	ProductName string    `json:"product_name"`
-	Quantity    int       `json:"quantity"`
-	UnitPrice   MoneyDTO  `json:"unit_price"`
-	Subtotal    MoneyDTO  `json:"subtotal"`
+	ID          string   `json:"id"`
+	ProductID   string   `json:"product_id"`
+	ProductName string   `json:"product_name"`
+	Category    string   `json:"category,omitempty"`
+	Quantity    int      `json:"quantity"`
+	UnitPrice   MoneyDTO `json:"unit_price"`
+	Subtotal    MoneyDTO `json:"subtotal"`
+	TaxName     string   `json:"tax_name,omitempty"`
+	TaxRate     float64  `json:"tax_rate"`
+	TaxAmount   MoneyDTO `json:"tax_amount"`
+	WeightKg    float64  `json:"weight_kg,omitempty"`
+
+	FulfilledQuantity int `json:"fulfilled_quantity"` // 已分配到有效发货的数量
+	DeliveredQuantity int `json:"delivered_quantity"`
+}
+
+// AdjustmentDTO 订单调整行DTO
+type AdjustmentDTO struct {
+	ID     string   `json:"id"`
+	Type   string   `json:"type"`
+	Label  string   `json:"label"`
+	Code   string   `json:"code,omitempty"`
+	Amount MoneyDTO `json:"amount"`
+}
+
+// StatusChangeDTO 订单状态变更记录DTO
+type StatusChangeDTO struct {
+	From      string    `json:"from,omitempty"`
+	To        string    `json:"to"`
+	Actor     string    `json:"actor"`
+	Note      string    `json:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 // MoneyDTO 金额DTO
@@ -31,17 +76,26 @@ type MoneyDTO struct {
 }
 
 // CreateOrderRequest 创建订单请求
+// 收货与账单地址可引用地址簿（*_address_id）或直接提供，缺省为用户的默认地址，账单地址再缺省同收货地址
 type CreateOrderRequest struct {
-	Items []CreateOrderItemRequest `json:"items" validate:"required,min=1,dive"`
+	Items             []CreateOrderItemRequest `json:"items" validate:"required,min=1,dive"`
+	CouponCode        string                   `json:"coupon_code"`
+	ShippingAddress   *AddressDTO              `json:"shipping_address"`
+	ShippingAddressID string                   `json:"shipping_address_id"`
+	BillingAddress    *AddressDTO              `json:"billing_address"`
+	BillingAddressID  string                   `json:"billing_address_id"`
+	VATID             string                   `json:"vat_id"`
 }
 
 // CreateOrderItemRequest 创建订单项请求
 type CreateOrderItemRequest struct {
 	ProductID   string  `json:"product_id" validate:"required"`
 	ProductName string  `json:"product_name" validate:"required"`
+	Category    string  `json:"category"`
 	Quantity    int     `json:"quantity" validate:"required,gte=1"`
 	UnitPrice   float64 `json:"unit_price" validate:"required,gt=0"`
 	Currency    string  `json:"currency" validate:"required,len=3"`
+	WeightKg    float64 `json:"weight_kg" validate:"gte=0"` // 单件重量，用于运费报价
 }
 
 // PaymentDTO 支付DTO
@@ -53,29 +107,155 @@ type PaymentDTO struct {
 	Status          string    `json:"status"`
 	TransactionID   string    `json:"transaction_id,omitempty"`
 	GatewayResponse string    `json:"gateway_response,omitempty"`
+	RefundedAmount  MoneyDTO  `json:"refunded_amount"`
+	RequiresAction  bool      `json:"requires_action"`         // 需持卡人在前端完成验证（如 3DS）
+	ClientSecret    string    `json:"client_secret,omitempty"` // 前端完成验证所需，仅在发起支付时返回
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
 }
 
 // ProcessPaymentRequest 处理支付请求
 type ProcessPaymentRequest struct {
-	Method string `json:"method" validate:"required"`
+	Method          string `json:"method" validate:"required"`
+	PaymentMethodID string `json:"payment_method_id"` // 前端收集的支付方式令牌，如 Stripe 的 pm_xxx
+	ReturnURL       string `json:"return_url"`        // 持卡人验证完成后的回跳地址
+	ClientIP        string `json:"-"`                 // 发起支付的客户端 IP，用于欺诈筛查
+}
+
+// ConfirmPaymentRequest 管理员确认线下收款请求
+type ConfirmPaymentRequest struct {
+	Reference string `json:"reference"` // 收据号、银行流水号等，替代系统生成的交易号
+}
+
+// RefundDTO 退款DTO
+type RefundDTO struct {
+	ID              string           `json:"id"`
+	OrderID         string           `json:"order_id"`
+	PaymentID       string           `json:"payment_id"`
+	Amount          MoneyDTO         `json:"amount"`
+	Reason          string           `json:"reason"`
+	Status          string           `json:"status"`
+	Actor           string           `json:"actor"`
+	GatewayResponse string           `json:"gateway_response,omitempty"`
+	Lines           []*RefundLineDTO `json:"lines"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+}
+
+// RefundLineDTO 退款行DTO
+type RefundLineDTO struct {
+	OrderItemID string   `json:"order_item_id"`
+	Quantity    int      `json:"quantity"`
+	Amount      MoneyDTO `json:"amount"`
+}
+
+// CreateRefundRequest 创建退款请求
+// 指定 lines 时 amount 可省略（取各行之和）；不指定 lines 时为不关联商品的退款（如运费、补偿）
+type CreateRefundRequest struct {
+	PaymentID      string                    `json:"payment_id"` // 可选，默认为订单已收款的支付
+	Amount         float64                   `json:"amount" binding:"gte=0"`
+	Reason         string                    `json:"reason" binding:"required"`
+	Lines          []CreateRefundLineRequest `json:"lines" binding:"dive"`
+	IdempotencyKey string                    `json:"-"` // 取自 Idempotency-Key 请求头
+}
+
+// CreateRefundLineRequest 退款行请求
+type CreateRefundLineRequest struct {
+	OrderItemID string  `json:"order_item_id" binding:"required"`
+	Quantity    int     `json:"quantity" binding:"required,gte=1"`
+	Amount      float64 `json:"amount" binding:"gte=0"` // 为 0 时按单价计算
+}
+
+// ReturnDTO 退货DTO
+type ReturnDTO struct {
+	ID             string           `json:"id"`
+	OrderID        string           `json:"order_id"`
+	UserID         string           `json:"user_id"`
+	Status         string           `json:"status"`
+	Reason         string           `json:"reason"`
+	Note           string           `json:"note,omitempty"`
+	Lines          []*ReturnLineDTO `json:"lines"`
+	Carrier        string           `json:"carrier,omitempty"`
+	TrackingNumber string           `json:"tracking_number,omitempty"`
+	LabelURL       string           `json:"label_url,omitempty"`
+	RefundID       string           `json:"refund_id,omitempty"`
+	ApprovedAt     *time.Time       `json:"approved_at,omitempty"`
+	RejectedAt     *time.Time       `json:"rejected_at,omitempty"`
+	ReceivedAt     *time.Time       `json:"received_at,omitempty"`
+	RefundedAt     *time.Time       `json:"refunded_at,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+}
+
+// ReturnLineDTO 退货行DTO
+type ReturnLineDTO struct {
+	OrderItemID      string `json:"order_item_id"`
+	Quantity         int    `json:"quantity"`
+	AcceptedQuantity int    `json:"accepted_quantity"`
+}
+
+// CreateReturnRequest 申请退货请求
+type CreateReturnRequest struct {
+	Reason string                    `json:"reason" binding:"required"`
+	Lines  []CreateReturnLineRequest `json:"lines" binding:"required,min=1,dive"`
+}
+
+// CreateReturnLineRequest 退货行请求
+type CreateReturnLineRequest struct {
+	OrderItemID string `json:"order_item_id" binding:"required"`
+	Quantity    int    `json:"quantity" binding:"required,gte=1"`
+}
+
+// ReviewReturnRequest 审核退货请求
+type ReviewReturnRequest struct {
+	Note string `json:"note"`
+}
+
+// ReceiveReturnRequest 退货收货质检请求，缺省 lines 时全部合格
+type ReceiveReturnRequest struct {
+	Lines []ReceiveReturnLineRequest `json:"lines" binding:"dive"`
+	Note  string                     `json:"note"`
+}
+
+// ReceiveReturnLineRequest 质检结果行，未列出的商品视为不合格
+type ReceiveReturnLineRequest struct {
+	OrderItemID      string `json:"order_item_id" binding:"required"`
+	AcceptedQuantity int    `json:"accepted_quantity" binding:"gte=0"`
 }
 
 // ShipmentDTO 发货DTO
 type ShipmentDTO struct {
-	ID             string       `json:"id"`
-	OrderID        string       `json:"order_id"`
-	TrackingNumber string       `json:"tracking_number"`
-	Carrier        string       `json:"carrier"`
-	ShippingMethod string       `json:"shipping_method"`
-	Address        AddressDTO   `json:"address"`
-	Status         string       `json:"status"`
-	EstimatedDate  *time.Time   `json:"estimated_date,omitempty"`
-	ShippedAt      *time.Time   `json:"shipped_at,omitempty"`
-	DeliveredAt    *time.Time   `json:"delivered_at,omitempty"`
-	CreatedAt      time.Time    `json:"created_at"`
-	UpdatedAt      time.Time    `json:"updated_at"`
+	ID             string              `json:"id"`
+	OrderID        string              `json:"order_id"`
+	TrackingNumber string              `json:"tracking_number"`
+	Carrier        string              `json:"carrier"`
+	ShippingMethod string              `json:"shipping_method"`
+	ShippingCost   MoneyDTO            `json:"shipping_cost"`
+	LabelURL       string              `json:"label_url,omitempty"`
+	Address        AddressDTO          `json:"address"`
+	Lines          []*ShipmentLineDTO  `json:"lines"`
+	Status         string              `json:"status"`
+	EstimatedDate  *time.Time          `json:"estimated_date,omitempty"`
+	ShippedAt      *time.Time          `json:"shipped_at,omitempty"`
+	DeliveredAt    *time.Time          `json:"delivered_at,omitempty"`
+	Events         []*ShipmentEventDTO `json:"events"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
+
+// ShipmentLineDTO 发货行DTO
+type ShipmentLineDTO struct {
+	OrderItemID string `json:"order_item_id"`
+	Quantity    int    `json:"quantity"`
+}
+
+// ShipmentEventDTO 发货跟踪事件DTO
+type ShipmentEventDTO struct {
+	Status      string    `json:"status"`
+	Location    string    `json:"location,omitempty"`
+	Description string    `json:"description,omitempty"`
+	RawPayload  string    `json:"raw_payload,omitempty"` // 仅管理员可见
+	OccurredAt  time.Time `json:"occurred_at"`
 }
 
 // AddressDTO 地址DTO
@@ -87,16 +267,101 @@ type AddressDTO struct {
 	Country    string `json:"country"`
 }
 
+// ShippingRateDTO 运费报价DTO
+type ShippingRateDTO struct {
+	Carrier           string    `json:"carrier"`
+	Service           string    `json:"service"`
+	ServiceName       string    `json:"service_name"`
+	Amount            MoneyDTO  `json:"amount"`
+	EstimatedDays     int       `json:"estimated_days"`
+	EstimatedDelivery time.Time `json:"estimated_delivery"`
+}
+
+// SelectShippingRateRequest 选择运输服务请求
+type SelectShippingRateRequest struct {
+	Service         string `json:"service" binding:"required"`
+	ExpectedVersion int    `json:"-"` // 请求头 If-Match 中的版本号，0 表示不校验
+}
+
 // CreateShipmentRequest 创建发货请求
 type CreateShipmentRequest struct {
-	ShippingMethod string     `json:"shipping_method" validate:"required"`
-	Address        AddressDTO `json:"address" validate:"required"`
+	ShippingMethod string                      `json:"shipping_method"`      // 运输服务编码，缺省为下单时所选
+	Address        *AddressDTO                 `json:"address"`              // 缺省为订单的收货地址
+	AddressID      string                      `json:"address_id"`           // 引用订单所属用户地址簿中的地址
+	Lines          []CreateShipmentLineRequest `json:"lines" binding:"dive"` // 缺省为全部未发货商品
+}
+
+// CreateShipmentLineRequest 发货行请求
+type CreateShipmentLineRequest struct {
+	OrderItemID string `json:"order_item_id" binding:"required"`
+	Quantity    int    `json:"quantity" binding:"required,gte=1"`
+}
+
+// ShipShipmentRequest 发出发货请求，已购面单时追踪号与承运商可省略
+type ShipShipmentRequest struct {
+	TrackingNumber string `json:"tracking_number"`
+	Carrier        string `json:"carrier"`
+}
+
+// CancelShipmentRequest 取消发货请求
+type CancelShipmentRequest struct {
+	Reason string `json:"reason"`
+}
+
+// AddShipmentEventRequest 追加跟踪事件请求
+type AddShipmentEventRequest struct {
+	Status      string          `json:"status" binding:"required"`
+	Location    string          `json:"location"`
+	Description string          `json:"description"`
+	OccurredAt  *time.Time      `json:"occurred_at"` // 缺省为当前时间
+	RawPayload  json.RawMessage `json:"raw_payload"` // 承运商原始数据
+}
+
+// UpdateOrderStatusRequest 管理员更新订单状态请求
+type UpdateOrderStatusRequest struct {
+	Status          string `json:"status" binding:"required"`
+	Note            string `json:"note"`
+	ExpectedVersion int    `json:"-"` // 请求头 If-Match 中的版本号，0 表示不校验
+}
+
+// FraudAssessmentDTO 欺诈筛查记录DTO
+type FraudAssessmentDTO struct {
+	ID            string     `json:"id"`
+	OrderID       string     `json:"order_id"`
+	UserID        string     `json:"user_id"`
+	ClientIP      string     `json:"client_ip,omitempty"`
+	Score         int        `json:"score"`
+	Rules         []string   `json:"rules"`
+	Outcome       string     `json:"outcome"`
+	Amount        MoneyDTO   `json:"amount"`
+	PaymentMethod string     `json:"payment_method,omitempty"`
+	Decision      string     `json:"decision,omitempty"`
+	DecidedBy     string     `json:"decided_by,omitempty"`
+	DecisionNote  string     `json:"decision_note,omitempty"`
+	DecidedAt     *time.Time `json:"decided_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+
+	NotificationError string `json:"notification_error,omitempty"` // 审核决定已生效但客户通知发送失败的原因
 }
 
-// UpdateShipmentRequest 更新发货请求
-type UpdateShipmentRequest struct {
-	TrackingNumber string `json:"tracking_number" validate:"required"`
-	Carrier        string `json:"carrier" validate:"required"`
+// FraudReviewDecisionRequest 欺诈人工审核请求
+type FraudReviewDecisionRequest struct {
+	Note string `json:"note"`
+}
+
+// ListFraudReviewsRequest 列出欺诈审核队列请求
+type ListFraudReviewsRequest struct {
+	Page     int `form:"page"`
+	PageSize int `form:"page_size"`
+}
+
+// ListFraudReviewsResponse 欺诈审核队列响应
+type ListFraudReviewsResponse struct {
+	Reviews    []*FraudAssessmentDTO `json:"reviews"`
+	Total      int64                 `json:"total"`
+	Page       int                   `json:"page"`
+	PageSize   int                   `json:"page_size"`
+	TotalPages int                   `json:"total_pages"`
 }
 
 // ListOrdersRequest 列出订单请求
@@ -105,6 +370,24 @@ type ListOrdersRequest struct {
 	PageSize int `json:"page_size" validate:"gte=1,lte=100"`
 }
 
+// SearchOrdersRequest 管理员搜索订单请求（字段均为可选的查询参数）
+type SearchOrdersRequest struct {
+	Page           int    `form:"page"`
+	PageSize       int    `form:"page_size"`
+	Status         string `form:"status"` // 逗号分隔的多个状态
+	UserID         string `form:"user_id"`
+	CreatedFrom    string `form:"created_from"` // RFC3339 或 YYYY-MM-DD
+	CreatedTo      string `form:"created_to"`   // RFC3339（不含）或 YYYY-MM-DD（含当天）
+	MinAmount      string `form:"min_amount"`
+	MaxAmount      string `form:"max_amount"`
+	Currency       string `form:"currency"`
+	PaymentMethod  string `form:"payment_method"`
+	OrderNumber    string `form:"order_number"`    // 订单号前缀
+	Query          string `form:"q"`               // 全文检索：订单号、商品名称
+	NeedsAttention bool   `form:"needs_attention"` // 仅待管理员处理的订单
+	Sort           string `form:"sort"`            // 如 "-created_at,total_amount"
+}
+
 // ListOrdersResponse 列出订单响应
 type ListOrdersResponse struct {
 	Orders     []*OrderDTO `json:"orders"`
@@ -113,4 +396,3 @@ type ListOrdersResponse struct {
 	PageSize   int         `json:"page_size"`
 	TotalPages int         `json:"total_pages"`
 }
-
@@ -0,0 +1,115 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+)
+
+const (
+	// expiredOrderReason 超时取消的原因
+	expiredOrderReason = "payment not received in time"
+
+	// cancelledAttemptReason 订单取消时在网关撤销的支付尝试的失败原因
+	cancelledAttemptReason = "cancelled with the order"
+)
+
+// CancelExpiredOrders 取消超过 ttl 仍未支付的订单（命令），返回取消数量
+// 按批次在事务中锁定订单（跳过其他 worker 或支付流程已锁定的订单），有进行中支付的订单保留；
+// 支付尝试占用的优惠核销随取消释放。
+// 事务提交后撤销订单在网关仍未完成的支付意图并发送客户通知，二者失败均不影响取消结果，合并后随返回值报告；
+// 撤销前网关已扣款的，回调到达时全额退款并标记订单待管理员处理
+func (s *Service) CancelExpiredOrders(ctx context.Context, ttl time.Duration, batchSize int) (int, error) {
+	if ttl <= 0 || batchSize <= 0 {
+		return 0, fmt.Errorf("invalid expiration settings: ttl=%v batch=%d", ttl, batchSize)
+	}
+
+	cutoff := time.Now().Add(-ttl)
+	total := 0
+	afterID := ""
+	var errs []error
+
+	for {
+		var (
+			cancelled []*order.Order
+			locked    int
+		)
+		err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+			cancelled = cancelled[:0]
+			orders, err := s.orderRepo.LockExpiredPending(ctx, cutoff, afterID, batchSize)
+			if err != nil {
+				return err
+			}
+			locked = len(orders)
+
+			for _, o := range orders {
+				afterID = o.ID
+
+				inProgress, err := s.orderService.HasPaymentInProgress(ctx, o.ID, cutoff)
+				if err != nil {
+					return err
+				}
+				if inProgress {
+					continue
+				}
+
+				if err := s.cancelOrder(ctx, o, order.ActorSystem, expiredOrderReason); err != nil {
+					return err
+				}
+				cancelled = append(cancelled, o)
+			}
+			return nil
+		})
+		if err != nil {
+			return total, err
+		}
+
+		total += len(cancelled)
+		for _, o := range cancelled {
+			if err := s.cancelOpenAttempts(ctx, o.ID); err != nil {
+				errs = append(errs, fmt.Errorf("cancel payments of order %s: %w", o.ID, err))
+			}
+			if err := s.notifier.OrderCancelled(ctx, o, expiredOrderReason); err != nil {
+				errs = append(errs, fmt.Errorf("notify order %s: %w", o.ID, err))
+			}
+		}
+
+		if locked < batchSize || ctx.Err() != nil {
+			break
+		}
+	}
+
+	return total, errors.Join(errs...)
+}
+
+// cancelOpenAttempts 在网关撤销已取消订单仍待确认的支付意图，撤销成功的尝试记为失败，不持有事务
+// 网关拒绝撤销（如已扣款）时尝试保持待确认，由回调退款；尚未取得交易号的尝试无法撤销，同样由回调处理
+func (s *Service) cancelOpenAttempts(ctx context.Context, orderID string) error {
+	attempts, err := s.paymentRepo.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, p := range attempts {
+		if p.Status != order.PaymentStatusPending || p.TransactionID == "" {
+			continue
+		}
+		gateway, err := s.gateways.ForMethod(p.Method)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := gateway.CancelPayment(ctx, p.TransactionID, "cancel-"+p.ID); err != nil {
+			errs = append(errs, fmt.Errorf("cancel payment %s: %w", p.ID, err))
+			continue
+		}
+		if err := s.failPaymentAttempt(ctx, p.ID, cancelledAttemptReason); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,194 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/shared/pagination"
+	"github.com/oklog/ulid/v2"
+)
+
+// fraudRejectedReason 欺诈审核拒绝时取消订单的原因
+const fraudRejectedReason = "rejected by fraud review"
+
+// screenOrder 在扣款事务中对已锁定的订单进行欺诈筛查并保存筛查记录，返回订单是否转人工审核
+// 转审核时保存本次的支付方式，订单置为 review 状态
+func (s *Service) screenOrder(ctx context.Context, o *order.Order, req ProcessPaymentRequest, accountAge time.Duration) (bool, error) {
+	assessment, err := s.orderService.AssessFraud(ctx, o, s.fraudPolicy, req.ClientIP, accountAge, time.Now())
+	if err != nil || assessment == nil {
+		return false, err
+	}
+
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
+	assessment.ID = ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
+
+	if !assessment.RequiresReview() {
+		return false, s.fraudRepo.Create(ctx, assessment)
+	}
+
+	assessment.HoldPayment(order.PaymentMethod(req.Method), req.PaymentMethodID, req.ReturnURL)
+	if err := o.HoldForReview(order.ActorSystem, strings.Join(assessment.Rules, ",")); err != nil {
+		return false, err
+	}
+	if err := s.orderRepo.Update(ctx, o); err != nil {
+		return false, err
+	}
+	return true, s.fraudRepo.Create(ctx, assessment)
+}
+
+// ListFraudReviews 列出等待人工审核的欺诈筛查记录（查询），按进入队列的先后排序
+func (s *Service) ListFraudReviews(ctx context.Context, req ListFraudReviewsRequest) (*ListFraudReviewsResponse, error) {
+	offset, limit := pagination.ParsePaginationParams(req.Page, req.PageSize)
+
+	assessments, total, err := s.fraudRepo.ListAwaitingReview(ctx, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*FraudAssessmentDTO, len(assessments))
+	for i, a := range assessments {
+		dtos[i] = domainFraudAssessmentToDTO(a)
+	}
+
+	pg := pagination.NewPagination(req.Page, req.PageSize, total)
+
+	return &ListFraudReviewsResponse{
+		Reviews:    dtos,
+		Total:      total,
+		Page:       pg.Page,
+		PageSize:   pg.PageSize,
+		TotalPages: pg.TotalPages,
+	}, nil
+}
+
+// ListFraudAssessments 列出订单的欺诈筛查记录（查询）
+func (s *Service) ListFraudAssessments(ctx context.Context, orderID string) ([]*FraudAssessmentDTO, error) {
+	if _, err := s.orderRepo.FindByID(ctx, orderID); err != nil {
+		return nil, err
+	}
+
+	assessments, err := s.fraudRepo.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*FraudAssessmentDTO, len(assessments))
+	for i, a := range assessments {
+		dtos[i] = domainFraudAssessmentToDTO(a)
+	}
+	return dtos, nil
+}
+
+// ApproveFraudReview 人工审核通过（命令），订单恢复待支付并以转审核时保存的支付方式扣款
+// 审核决定先行提交：扣款失败时订单保持待支付，顾客可重新支付且不再筛查
+func (s *Service) ApproveFraudReview(ctx context.Context, adminID, orderID string, req FraudReviewDecisionRequest) (*PaymentDTO, error) {
+	var assessment *order.FraudAssessment
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		o, pending, err := s.lockFraudReview(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if err := pending.Approve(adminID, req.Note); err != nil {
+			return err
+		}
+		if err := o.ReleaseFromReview(order.AdminActor(adminID), req.Note); err != nil {
+			return err
+		}
+		if err := s.orderRepo.Update(ctx, o); err != nil {
+			return err
+		}
+		assessment = pending
+		return s.fraudRepo.Update(ctx, pending)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.chargeOrder(ctx, orderID, ProcessPaymentRequest{
+		Method:          string(assessment.PaymentMethod),
+		PaymentMethodID: assessment.PaymentToken,
+		ReturnURL:       assessment.ReturnURL,
+		ClientIP:        assessment.ClientIP,
+	}, true)
+}
+
+// RejectFraudReview 人工审核拒绝（命令），取消订单并通知客户
+// 通知在事务提交后发送，发送失败不影响已生效的拒绝，原因随 DTO 的 NotificationError 返回
+func (s *Service) RejectFraudReview(ctx context.Context, adminID, orderID string, req FraudReviewDecisionRequest) (*FraudAssessmentDTO, error) {
+	var (
+		o          *order.Order
+		assessment *order.FraudAssessment
+	)
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		locked, pending, err := s.lockFraudReview(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if err := pending.Reject(adminID, req.Note); err != nil {
+			return err
+		}
+		if err := s.cancelOrder(ctx, locked, order.AdminActor(adminID), fraudRejectedReason); err != nil {
+			return err
+		}
+		o, assessment = locked, pending
+		return s.fraudRepo.Update(ctx, pending)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dto := domainFraudAssessmentToDTO(assessment)
+	if err := s.notifier.OrderCancelled(ctx, o, fraudRejectedReason); err != nil {
+		dto.NotificationError = fmt.Sprintf("notify order %s: %v", o.ID, err)
+	}
+	return dto, nil
+}
+
+// lockFraudReview 锁定审核中的订单并返回其等待审核的筛查记录
+func (s *Service) lockFraudReview(ctx context.Context, orderID string) (*order.Order, *order.FraudAssessment, error) {
+	o, err := s.orderRepo.FindByIDForUpdate(ctx, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if o.Status != order.StatusReview {
+		return nil, nil, order.ErrInvalidOrderStatus
+	}
+
+	assessments, err := s.fraudRepo.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, a := range assessments {
+		if a.IsAwaitingReview() {
+			return o, a, nil
+		}
+	}
+	return nil, nil, order.ErrFraudReviewNotFound
+}
+
+// domainFraudAssessmentToDTO 转换欺诈筛查记录为DTO，不含支付令牌
+func domainFraudAssessmentToDTO(a *order.FraudAssessment) *FraudAssessmentDTO {
+	return &FraudAssessmentDTO{
+		ID:       a.ID,
+		OrderID:  a.OrderID,
+		UserID:   a.UserID,
+		ClientIP: a.ClientIP,
+		Score:    a.Score,
+		Rules:    a.Rules,
+		Outcome:  string(a.Outcome),
+		Amount: MoneyDTO{
+			Amount:   a.Amount.Amount,
+			Currency: a.Amount.Currency,
+		},
+		PaymentMethod: string(a.PaymentMethod),
+		Decision:      string(a.Decision),
+		DecidedBy:     a.DecidedBy,
+		DecisionNote:  a.DecisionNote,
+		DecidedAt:     a.DecidedAt,
+		CreatedAt:     a.CreatedAt,
+	}
+}
@@ -0,0 +1,46 @@
+package order
+
+import (
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+)
+
+// ManualConfirmation 需人工确认到账的网关（可选能力），如现金、银行转账
+type ManualConfirmation interface {
+	RequiresManualConfirmation() bool
+}
+
+// GatewayRegistry 支付网关注册表，按支付方式路由到对应网关
+type GatewayRegistry struct {
+	gateways map[order.PaymentMethod]PaymentGateway
+}
+
+// NewGatewayRegistry 创建支付网关注册表
+func NewGatewayRegistry() *GatewayRegistry {
+	return &GatewayRegistry{
+		gateways: make(map[order.PaymentMethod]PaymentGateway),
+	}
+}
+
+// Register 为支付方式注册网关，重复注册时覆盖
+func (r *GatewayRegistry) Register(method order.PaymentMethod, gateway PaymentGateway) {
+	r.gateways[method] = gateway
+}
+
+// ForMethod 获取支付方式对应的网关
+func (r *GatewayRegistry) ForMethod(method order.PaymentMethod) (PaymentGateway, error) {
+	gateway, ok := r.gateways[method]
+	if !ok || !method.IsValid() {
+		return nil, order.ErrUnsupportedPaymentMethod
+	}
+	return gateway, nil
+}
+
+// requiresManualConfirmation 支付方式是否需人工确认到账
+func (r *GatewayRegistry) requiresManualConfirmation(method order.PaymentMethod) bool {
+	gateway, ok := r.gateways[method]
+	if !ok {
+		return false
+	}
+	manual, ok := gateway.(ManualConfirmation)
+	return ok && manual.RequiresManualConfirmation()
+}
@@ -2,22 +2,40 @@ package order
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/shared/pagination"
 )
 
 // GetOrder 获取订单（查询）
-func (s *Service) GetOrder(ctx context.Context, orderID string) (*OrderDTO, error) {
-	o, err := s.orderRepo.FindByID(ctx, orderID)
+func (s *Service) GetOrder(ctx context.Context, userID, orderID string) (*OrderDTO, error) {
+	o, _, err := s.findOrderForUser(ctx, userID, orderID)
 	if err != nil {
 		return nil, err
 	}
 
-	return domainOrderToDTO(o), nil
+	dto := domainOrderToDTO(o)
+	latest, err := s.paymentRepo.FindLatestByOrderID(ctx, orderID)
+	switch {
+	case err == nil:
+		dto.LatestPayment = domainPaymentToDTO(latest)
+	case !errors.Is(err, order.ErrPaymentNotFound):
+		return nil, err
+	}
+	return dto, nil
 }
 
 // GetOrderByNumber 根据订单号获取订单（查询）
 func (s *Service) GetOrderByNumber(ctx context.Context, orderNumber string) (*OrderDTO, error) {
+	if err := s.orderService.ValidateOrderNumber(orderNumber); err != nil {
+		return nil, err
+	}
+
 	o, err := s.orderRepo.FindByOrderNumber(ctx, orderNumber)
 	if err != nil {
 		return nil, err
@@ -55,9 +73,118 @@ func (s *Service) ListOrders(ctx context.Context, userID string, req ListOrdersR
 	}, nil
 }
 
-// GetPayment 获取支付（查询）
-func (s *Service) GetPayment(ctx context.Context, orderID string) (*PaymentDTO, error) {
-	payment, err := s.paymentRepo.FindByOrderID(ctx, orderID)
+// SearchOrders 管理员按条件搜索订单（查询）
+func (s *Service) SearchOrders(ctx context.Context, req SearchOrdersRequest) (*ListOrdersResponse, error) {
+	offset, limit := pagination.ParsePaginationParams(req.Page, req.PageSize)
+
+	criteria, err := BuildSearchCriteria(req)
+	if err != nil {
+		return nil, err
+	}
+	criteria.Offset = offset
+	criteria.Limit = limit
+
+	if err := criteria.Validate(); err != nil {
+		return nil, err
+	}
+
+	orders, total, err := s.orderRepo.Search(ctx, criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	orderDTOs := make([]*OrderDTO, len(orders))
+	for i, o := range orders {
+		orderDTOs[i] = domainOrderToDTO(o)
+	}
+
+	pg := pagination.NewPagination(req.Page, req.PageSize, total)
+
+	return &ListOrdersResponse{
+		Orders:     orderDTOs,
+		Total:      total,
+		Page:       pg.Page,
+		PageSize:   pg.PageSize,
+		TotalPages: pg.TotalPages,
+	}, nil
+}
+
+// BuildSearchCriteria 将查询参数转换为领域搜索条件，供订单搜索与导出共用
+func BuildSearchCriteria(req SearchOrdersRequest) (order.SearchCriteria, error) {
+	criteria := order.SearchCriteria{
+		UserID:            strings.TrimSpace(req.UserID),
+		Currency:          strings.TrimSpace(req.Currency),
+		PaymentMethod:     order.PaymentMethod(strings.TrimSpace(req.PaymentMethod)),
+		OrderNumberPrefix: strings.TrimSpace(req.OrderNumber),
+		Query:             strings.TrimSpace(req.Query),
+		NeedsAttention:    req.NeedsAttention,
+	}
+
+	for _, status := range strings.Split(req.Status, ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			criteria.Statuses = append(criteria.Statuses, order.OrderStatus(status))
+		}
+	}
+
+	var err error
+	if criteria.CreatedFrom, err = parseSearchTime(req.CreatedFrom, false); err != nil {
+		return criteria, err
+	}
+	if criteria.CreatedTo, err = parseSearchTime(req.CreatedTo, true); err != nil {
+		return criteria, err
+	}
+	if criteria.MinAmount, err = parseSearchAmount(req.MinAmount); err != nil {
+		return criteria, err
+	}
+	if criteria.MaxAmount, err = parseSearchAmount(req.MaxAmount); err != nil {
+		return criteria, err
+	}
+	if criteria.Sort, err = order.ParseSort(req.Sort); err != nil {
+		return criteria, err
+	}
+
+	return criteria, nil
+}
+
+// parseSearchTime 解析时间参数；仅日期的结束时间包含当天
+func parseSearchTime(value string, endOfRange bool) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid date %q", order.ErrInvalidSearchCriteria, value)
+	}
+	if endOfRange {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+// parseSearchAmount 解析金额参数
+func parseSearchAmount(value string) (*float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid amount %q", order.ErrInvalidSearchCriteria, value)
+	}
+	return &amount, nil
+}
+
+// GetPayment 获取最近一次支付尝试（查询）
+func (s *Service) GetPayment(ctx context.Context, userID, orderID string) (*PaymentDTO, error) {
+	if _, _, err := s.findOrderForUser(ctx, userID, orderID); err != nil {
+		return nil, err
+	}
+
+	payment, err := s.paymentRepo.FindLatestByOrderID(ctx, orderID)
 	if err != nil {
 		return nil, err
 	}
@@ -65,9 +192,61 @@ func (s *Service) GetPayment(ctx context.Context, orderID string) (*PaymentDTO,
 	return domainPaymentToDTO(payment), nil
 }
 
-// GetShipment 获取发货（查询）
-func (s *Service) GetShipment(ctx context.Context, orderID string) (*ShipmentDTO, error) {
-	shipment, err := s.shipmentRepo.FindByOrderID(ctx, orderID)
+// ListPayments 列出订单的全部支付尝试（查询）
+func (s *Service) ListPayments(ctx context.Context, userID, orderID string) ([]*PaymentDTO, error) {
+	if _, _, err := s.findOrderForUser(ctx, userID, orderID); err != nil {
+		return nil, err
+	}
+
+	payments, err := s.paymentRepo.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*PaymentDTO, len(payments))
+	for i, p := range payments {
+		dtos[i] = domainPaymentToDTO(p)
+	}
+	return dtos, nil
+}
+
+// ListShipments 列出订单的全部发货（查询）
+func (s *Service) ListShipments(ctx context.Context, userID, orderID string) ([]*ShipmentDTO, error) {
+	if _, _, err := s.findOrderForUser(ctx, userID, orderID); err != nil {
+		return nil, err
+	}
+
+	shipments, err := s.shipmentRepo.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 承运商原始数据仅对管理员可见
+	dtos := make([]*ShipmentDTO, len(shipments))
+	for i, shipment := range shipments {
+		dtos[i] = domainShipmentToDTO(shipment)
+		for _, e := range dtos[i].Events {
+			e.RawPayload = ""
+		}
+	}
+	return dtos, nil
+}
+
+// GetShipment 获取最近一次发货（查询，兼容旧的单数路径）
+func (s *Service) GetShipment(ctx context.Context, userID, orderID string) (*ShipmentDTO, error) {
+	dtos, err := s.ListShipments(ctx, userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if len(dtos) == 0 {
+		return nil, order.ErrShipmentNotFound
+	}
+	return dtos[len(dtos)-1], nil
+}
+
+// GetShipmentByID 获取发货详情（含承运商原始数据，管理员用）（查询）
+func (s *Service) GetShipmentByID(ctx context.Context, shipmentID string) (*ShipmentDTO, error) {
+	shipment, err := s.shipmentRepo.FindByID(ctx, shipmentID)
 	if err != nil {
 		return nil, err
 	}
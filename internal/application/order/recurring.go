@@ -0,0 +1,194 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+	"github.com/oklog/ulid/v2"
+)
+
+// recurringPaymentFailedReason 周期扣款失败时取消订单的原因
+const recurringPaymentFailedReason = "recurring payment failed"
+
+// ChargeRecurring 为周期扣款生成订单并以保存的支付方式离线扣款（命令），返回订单ID
+// 订单按用户默认地址快照地址并计税，余额以折扣调整行抵扣，全额抵扣时直接置为已支付。
+// 指定了 charge.OrderID 且订单已存在时沿用该订单（见 resumeRecurringCharge），不会重复扣款。
+// 离线扣款无法完成持卡人验证：扣款失败或需要验证时先在网关撤销支付意图，再取消订单并返回 order.ErrPaymentFailed；
+// 网关结果未知或处理中时，可续上的订单（指定了 OrderID）保持待支付并返回 order.ErrPaymentPending，由回调或重试完成
+func (s *Service) ChargeRecurring(ctx context.Context, charge order.RecurringCharge) (string, error) {
+	if charge.OrderID != "" {
+		existing, err := s.orderRepo.FindByID(ctx, charge.OrderID)
+		if err == nil {
+			return s.resumeRecurringCharge(ctx, existing, charge)
+		}
+		if !errors.Is(err, order.ErrOrderNotFound) {
+			return "", err
+		}
+	}
+
+	orderNumber, err := s.orderService.GenerateOrderNumber(ctx)
+	if err != nil {
+		return "", err
+	}
+	o, err := order.NewOrder(charge.UserID, orderNumber)
+	if err != nil {
+		return "", err
+	}
+
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
+	o.ID = charge.OrderID
+	if o.ID == "" {
+		o.ID = ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
+	}
+
+	item, err := order.NewOrderItem(o.ID, charge.ProductID, charge.ProductName, 1, charge.Amount)
+	if err != nil {
+		return "", err
+	}
+	item.ID = ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
+	item.SetCategory(charge.Category)
+	if err := o.AddItem(item); err != nil {
+		return "", err
+	}
+
+	if charge.Credit.IsPositive() {
+		adj, err := order.NewDiscountAdjustment(o.ID, "", "", "Account credit", charge.Credit)
+		if err != nil {
+			return "", err
+		}
+		adj.ID = ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
+		if err := o.AddAdjustment(adj); err != nil {
+			return "", err
+		}
+	}
+
+	shipping, billing, err := s.orderAddresses(ctx, charge.UserID, CreateOrderRequest{})
+	if err != nil {
+		return "", err
+	}
+	if shipping != nil {
+		o.SetShippingAddress(*shipping)
+	}
+	if billing != nil {
+		o.SetBillingAddress(*billing)
+	}
+
+	breakdown, err := s.taxCalculator.Calculate(ctx, o)
+	if err != nil {
+		return "", err
+	}
+	if err := o.ApplyTax(*breakdown); err != nil {
+		return "", err
+	}
+
+	if !o.TotalAmount.IsPositive() {
+		err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+			if err := s.orderRepo.Create(ctx, o); err != nil {
+				return err
+			}
+			return s.markOrderPaid(ctx, o, order.ActorSystem)
+		})
+		if err != nil {
+			return "", err
+		}
+		return o.ID, nil
+	}
+
+	if err := s.orderRepo.Create(ctx, o); err != nil {
+		return "", err
+	}
+
+	return s.chargeRecurringOrder(ctx, o.ID, charge)
+}
+
+// resumeRecurringCharge 以同一订单ID重试周期扣款
+// 订单已支付或支付已授权时直接返回；已取消视为扣款失败；网关处理中的尝试等待回调；
+// 结果未知的尝试由 chargeOrder 沿用（同一幂等键），尝试均已失败时取消订单
+func (s *Service) resumeRecurringCharge(ctx context.Context, o *order.Order, charge order.RecurringCharge) (string, error) {
+	switch o.Status {
+	case order.StatusPending:
+	case order.StatusCancelled:
+		return "", order.ErrPaymentFailed
+	default:
+		return o.ID, nil
+	}
+
+	attempts, err := s.paymentRepo.ListByOrderID(ctx, o.ID)
+	if err != nil {
+		return "", err
+	}
+	resumable, failed := len(attempts) == 0, false
+	for _, p := range attempts {
+		switch {
+		case p.IsAuthorized():
+			return o.ID, nil
+		case p.AwaitingGateway():
+			resumable = true
+		case p.Status == order.PaymentStatusPending:
+			return "", order.ErrPaymentPending
+		case p.Status == order.PaymentStatusFailed:
+			failed = true
+		}
+	}
+	if !resumable && failed {
+		if err := s.abandonRecurringOrder(ctx, o.ID); err != nil {
+			return "", err
+		}
+		return "", order.ErrPaymentFailed
+	}
+
+	return s.chargeRecurringOrder(ctx, o.ID, charge)
+}
+
+// chargeRecurringOrder 对周期订单离线扣款，扣款未完成时按 ChargeRecurring 的约定撤销或保持待支付
+func (s *Service) chargeRecurringOrder(ctx context.Context, orderID string, charge order.RecurringCharge) (string, error) {
+	resumable := charge.OrderID != ""
+
+	// 用户已授权的周期扣款不做欺诈筛查，离线订单无法等待人工审核
+	payment, err := s.chargeOrder(ctx, orderID, ProcessPaymentRequest{
+		Method:          string(charge.Method),
+		PaymentMethodID: charge.Token,
+	}, false)
+	if err == nil && (payment.Status == string(order.PaymentStatusCompleted) || payment.Status == string(order.PaymentStatusAuthorized)) {
+		return orderID, nil
+	}
+
+	// 结果未知或网关处理中（非持卡人验证）：可续上的订单等待回调或下次重试，不撤销也不取消
+	processing := err == nil && payment.Status == string(order.PaymentStatusPending) && !payment.RequiresAction
+	if resumable && (errors.Is(err, order.ErrPaymentPending) || processing) {
+		return "", order.ErrPaymentPending
+	}
+
+	// 扣款未成功：撤销网关支付意图后取消订单，避免遗留待支付订单与仍可完成的意图
+	if abandonErr := s.abandonRecurringOrder(ctx, orderID); abandonErr != nil {
+		return "", abandonErr
+	}
+	if err != nil && !errors.Is(err, order.ErrPaymentFailed) && !errors.Is(err, order.ErrPaymentPending) {
+		return "", err
+	}
+	return "", order.ErrPaymentFailed
+}
+
+// abandonRecurringOrder 在网关撤销周期订单待确认的支付意图后取消订单
+// 撤销失败（如网关已扣款）时订单保持待支付并返回 order.ErrPaymentPending，由回调完成；
+// 尚未取得交易号的尝试无法撤销，事后到账时由回调全额退款并标记订单
+func (s *Service) abandonRecurringOrder(ctx context.Context, orderID string) error {
+	if err := s.cancelOpenAttempts(ctx, orderID); err != nil {
+		return fmt.Errorf("%w: %v", order.ErrPaymentPending, err)
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		o, err := s.orderRepo.FindByIDForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if o.Status != order.StatusPending {
+			return nil
+		}
+		return s.cancelOrder(ctx, o, order.ActorSystem, recurringPaymentFailedReason)
+	})
+}
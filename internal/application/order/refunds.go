@@ -0,0 +1,343 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+	"github.com/oklog/ulid/v2"
+)
+
+// RefundPayment 创建退款（命令）
+// 先在锁定支付的事务中登记待处理退款以占用可退额度，再以退款ID为幂等键调用网关，
+// 成功后在事务中记入支付、更新订单状态并开具红字发票。
+// 携带幂等键的重复请求返回已有结果，未完成的退款会以同一网关幂等键继续处理。
+func (s *Service) RefundPayment(ctx context.Context, adminID, orderID string, req CreateRefundRequest) (*RefundDTO, error) {
+	actor := order.AdminActor(adminID)
+
+	if req.IdempotencyKey != "" {
+		existing, err := s.refundRepo.FindByIdempotencyKey(ctx, req.IdempotencyKey)
+		switch {
+		case err == nil:
+			if existing.OrderID != orderID {
+				return nil, order.ErrRefundKeyConflict
+			}
+			if existing.Status == order.RefundStatusPending {
+				return s.executeRefund(ctx, existing, actor)
+			}
+			return domainRefundToDTO(existing), nil
+		case !errors.Is(err, order.ErrRefundNotFound):
+			return nil, err
+		}
+	}
+
+	o, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	payment, err := s.refundablePayment(ctx, orderID, req.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	allocations := make([]order.RefundAllocation, len(req.Lines))
+	for i, line := range req.Lines {
+		allocations[i] = order.RefundAllocation{
+			OrderItemID: line.OrderItemID,
+			Quantity:    line.Quantity,
+			Amount:      line.Amount,
+		}
+	}
+
+	refund, err := s.createRefund(ctx, o, payment.ID, req.Amount, req.Reason, actor, req.IdempotencyKey, allocations)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.executeRefund(ctx, refund, actor)
+}
+
+// createRefund 在锁定支付的事务中登记待处理退款
+func (s *Service) createRefund(ctx context.Context, o *order.Order, paymentID string, amount float64, reason, actor, idempotencyKey string, allocations []order.RefundAllocation) (*order.Refund, error) {
+	var refund *order.Refund
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		r, err := s.registerRefund(ctx, o, paymentID, amount, reason, actor, idempotencyKey, allocations)
+		refund = r
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return refund, nil
+}
+
+// registerRefund 锁定支付并登记待处理退款，需在事务中调用
+func (s *Service) registerRefund(ctx context.Context, o *order.Order, paymentID string, amount float64, reason, actor, idempotencyKey string, allocations []order.RefundAllocation) (*order.Refund, error) {
+	// 锁定支付，串行化同一支付上的并发退款
+	p, err := s.paymentRepo.FindByIDForUpdate(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := order.NewRefund(o, p, amount, reason, actor, allocations)
+	if err != nil {
+		return nil, err
+	}
+	r.IdempotencyKey = idempotencyKey
+	assignRefundIDs(r)
+
+	if err := s.orderService.ValidateRefund(ctx, o, p, r); err != nil {
+		return nil, err
+	}
+	if err := s.refundRepo.Create(ctx, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// refundablePayment 确定退款对应的支付：指定支付ID时须属于该订单，否则取订单已收款的支付
+func (s *Service) refundablePayment(ctx context.Context, orderID, paymentID string) (*order.Payment, error) {
+	if paymentID == "" {
+		return s.paymentRepo.FindCapturedByOrderID(ctx, orderID)
+	}
+
+	payment, err := s.paymentRepo.FindByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.OrderID != orderID {
+		return nil, order.ErrPaymentNotFound
+	}
+	return payment, nil
+}
+
+// executeRefund 以退款ID派生的幂等键调用网关退款并记录结果
+// 网关明确拒绝时标记失败以释放额度；超时或网关错误时结果未知，退款保持待处理，
+// 之后以同一幂等键重试（重复请求或 worker），网关不会重复退款
+func (s *Service) executeRefund(ctx context.Context, refund *order.Refund, actor string) (*RefundDTO, error) {
+	payment, err := s.paymentRepo.FindByID(ctx, refund.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+	gateway, err := s.gateways.ForMethod(payment.Method)
+	if err != nil {
+		return nil, err
+	}
+
+	gatewayErr := gateway.RefundPayment(ctx, payment.TransactionID, refund.Amount, "refund-"+refund.ID)
+	if errors.Is(gatewayErr, order.ErrGatewayUnavailable) {
+		return nil, fmt.Errorf("%w: %v", order.ErrRefundPending, gatewayErr)
+	}
+
+	// 锁定退款后记录结果，并发处理同一退款时只有一方生效
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		locked, err := s.refundRepo.FindByIDForUpdate(ctx, refund.ID)
+		if err != nil {
+			return err
+		}
+		refund = locked
+		if refund.Status != order.RefundStatusPending {
+			return nil
+		}
+
+		if gatewayErr != nil {
+			if err := refund.MarkAsFailed(gatewayErr.Error()); err != nil {
+				return err
+			}
+			return s.refundRepo.Update(ctx, refund)
+		}
+
+		if err := refund.MarkAsSucceeded("refunded"); err != nil {
+			return err
+		}
+		if err := s.refundRepo.Update(ctx, refund); err != nil {
+			return err
+		}
+		return s.applyRefund(ctx, refund, actor)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if refund.Status == order.RefundStatusFailed {
+		return nil, fmt.Errorf("%w: %s", order.ErrRefundFailed, refund.GatewayResponse)
+	}
+
+	return domainRefundToDTO(refund), nil
+}
+
+// RetryPendingRefunds 以同一幂等键重试结果未知的退款（命令），返回完成处理（成功或明确失败）的数量
+// 只处理 olderThan 之前更新的退款，避免与仍在进行的请求同时调用网关；仍无结果的退款留待下次重试
+func (s *Service) RetryPendingRefunds(ctx context.Context, olderThan time.Duration, batchSize int) (int, error) {
+	if olderThan <= 0 || batchSize <= 0 {
+		return 0, fmt.Errorf("invalid refund retry settings: after=%v batch=%d", olderThan, batchSize)
+	}
+
+	updatedBefore := time.Now().Add(-olderThan)
+	total := 0
+	afterID := ""
+	var retryErrs []error
+
+	for {
+		refunds, err := s.refundRepo.ListPending(ctx, updatedBefore, afterID, batchSize)
+		if err != nil {
+			return total, err
+		}
+
+		for _, refund := range refunds {
+			afterID = refund.ID
+			_, err := s.executeRefund(ctx, refund, refund.Actor)
+			switch {
+			case err == nil, errors.Is(err, order.ErrRefundFailed):
+				total++
+			case !errors.Is(err, order.ErrRefundPending):
+				retryErrs = append(retryErrs, fmt.Errorf("retry refund %s: %w", refund.ID, err))
+			}
+		}
+
+		if len(refunds) < batchSize || ctx.Err() != nil {
+			break
+		}
+	}
+
+	return total, errors.Join(retryErrs...)
+}
+
+// applyRefund 将成功的退款记入支付与订单并开具红字发票，需在事务中调用
+func (s *Service) applyRefund(ctx context.Context, refund *order.Refund, actor string) error {
+	payment, err := s.paymentRepo.FindByIDForUpdate(ctx, refund.PaymentID)
+	if err != nil {
+		return err
+	}
+	if err := payment.ApplyRefund(refund.Amount); err != nil {
+		return err
+	}
+	if err := s.paymentRepo.Update(ctx, payment); err != nil {
+		return err
+	}
+
+	fullyRefunded, err := s.orderService.IsOrderFullyRefunded(ctx, payment.OrderID)
+	if err != nil {
+		return err
+	}
+
+	o, err := s.orderRepo.FindByID(ctx, payment.OrderID)
+	if err != nil {
+		return err
+	}
+	// 无法入账的付款（重复支付或取消后到账）退款不改变订单状态，也不冲销发票
+	if refund.Reason == orphanPaymentReason {
+		return nil
+	}
+	// 订单已处于不允许退款转换的状态（如网关侧对已取消订单退款）时只记账
+	if err := o.ApplyRefund(fullyRefunded, actor, refund.Reason); err != nil {
+		if !errors.Is(err, order.ErrInvalidOrderStatus) {
+			return err
+		}
+	} else if err := s.orderRepo.Update(ctx, o); err != nil {
+		return err
+	}
+
+	return s.invoices.IssueCreditNote(ctx, o, refund.Amount, refund.Reason)
+}
+
+// reconcileGatewayRefund 按网关回调的累计退款金额补记在网关侧直接发起的退款，需在事务中调用
+// 本系统发起的退款（含处理中的）已在台账中，差额为零时不做处理
+func (s *Service) reconcileGatewayRefund(ctx context.Context, payment *order.Payment, refunded order.Money, reference string) error {
+	outstanding, err := s.orderService.OutstandingRefunds(ctx, payment)
+	if err != nil {
+		return err
+	}
+
+	// 回调未携带金额时视为全额退款
+	target := refunded.Amount
+	if refunded.IsZero() {
+		target = payment.Amount.Amount
+	}
+	delta := math.Round((target-outstanding.Amount)*100) / 100
+	if delta <= 0 {
+		return nil
+	}
+
+	o, err := s.orderRepo.FindByID(ctx, payment.OrderID)
+	if err != nil {
+		return err
+	}
+
+	refund, err := order.NewRefund(o, payment, delta, "refunded at payment gateway", order.ActorSystem, nil)
+	if err != nil {
+		return err
+	}
+	if err := refund.MarkAsSucceeded(reference); err != nil {
+		return err
+	}
+	assignRefundIDs(refund)
+
+	if err := s.refundRepo.Create(ctx, refund); err != nil {
+		return err
+	}
+	return s.applyRefund(ctx, refund, order.ActorSystem)
+}
+
+// ListRefunds 列出订单的退款记录（查询）
+func (s *Service) ListRefunds(ctx context.Context, orderID string) ([]*RefundDTO, error) {
+	if _, err := s.orderRepo.FindByID(ctx, orderID); err != nil {
+		return nil, err
+	}
+
+	refunds, err := s.refundRepo.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*RefundDTO, len(refunds))
+	for i, r := range refunds {
+		dtos[i] = domainRefundToDTO(r)
+	}
+	return dtos, nil
+}
+
+// assignRefundIDs 为退款及退款行分配ID
+func assignRefundIDs(r *order.Refund) {
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
+	r.ID = ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
+	for _, line := range r.Lines {
+		line.ID = ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
+		line.RefundID = r.ID
+	}
+}
+
+// domainRefundToDTO 转换退款为DTO
+func domainRefundToDTO(r *order.Refund) *RefundDTO {
+	lines := make([]*RefundLineDTO, len(r.Lines))
+	for i, line := range r.Lines {
+		lines[i] = &RefundLineDTO{
+			OrderItemID: line.OrderItemID,
+			Quantity:    line.Quantity,
+			Amount: MoneyDTO{
+				Amount:   line.Amount.Amount,
+				Currency: line.Amount.Currency,
+			},
+		}
+	}
+
+	return &RefundDTO{
+		ID:        r.ID,
+		OrderID:   r.OrderID,
+		PaymentID: r.PaymentID,
+		Amount: MoneyDTO{
+			Amount:   r.Amount.Amount,
+			Currency: r.Amount.Currency,
+		},
+		Reason:          r.Reason,
+		Status:          string(r.Status),
+		Actor:           r.Actor,
+		GatewayResponse: r.GatewayResponse,
+		Lines:           lines,
+		CreatedAt:       r.CreatedAt,
+		UpdatedAt:       r.UpdatedAt,
+	}
+}
@@ -0,0 +1,274 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+	"github.com/oklog/ulid/v2"
+)
+
+// RequestReturn 顾客申请退货（命令），仅已送达且在分类退货期限内的商品可退
+func (s *Service) RequestReturn(ctx context.Context, userID, orderID string, req CreateReturnRequest) (*ReturnDTO, error) {
+	if _, _, err := s.findOrderForUser(ctx, userID, orderID); err != nil {
+		return nil, err
+	}
+
+	lines := make([]*order.ReturnLine, len(req.Lines))
+	for i, line := range req.Lines {
+		lines[i] = &order.ReturnLine{OrderItemID: line.OrderItemID, Quantity: line.Quantity}
+	}
+
+	var r *order.Return
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		// 锁定订单，避免并发申请超出可退数量
+		locked, err := s.orderRepo.FindByIDForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		created, err := order.NewReturn(locked, req.Reason, lines)
+		if err != nil {
+			return err
+		}
+		if err := s.orderService.ValidateReturn(ctx, locked, created, s.returnPolicy, time.Now()); err != nil {
+			return err
+		}
+		assignReturnIDs(created)
+
+		r = created
+		return s.returnRepo.Create(ctx, created)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domainReturnToDTO(r), nil
+}
+
+// ListReturns 列出订单的退货记录（查询）
+func (s *Service) ListReturns(ctx context.Context, userID, orderID string) ([]*ReturnDTO, error) {
+	if _, _, err := s.findOrderForUser(ctx, userID, orderID); err != nil {
+		return nil, err
+	}
+
+	returns, err := s.returnRepo.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*ReturnDTO, len(returns))
+	for i, r := range returns {
+		dtos[i] = domainReturnToDTO(r)
+	}
+	return dtos, nil
+}
+
+// GetReturn 获取退货详情（查询）
+func (s *Service) GetReturn(ctx context.Context, returnID string) (*ReturnDTO, error) {
+	r, err := s.returnRepo.FindByID(ctx, returnID)
+	if err != nil {
+		return nil, err
+	}
+	return domainReturnToDTO(r), nil
+}
+
+// ApproveReturn 批准退货并向承运商购买退货面单（命令），取件地址为商品送达的地址
+// 购买面单期间持有退货行锁，并发或重复的批准请求不会重复购买
+func (s *Service) ApproveReturn(ctx context.Context, returnID string, req ReviewReturnRequest) (*ReturnDTO, error) {
+	r, err := s.changeReturn(ctx, returnID, func(ctx context.Context, r *order.Return) error {
+		// 先校验状态，避免为已处理的退货购买面单
+		if r.Status != order.ReturnStatusRequested {
+			return fmt.Errorf("%w: can only approve requested returns", order.ErrInvalidReturnStatus)
+		}
+
+		o, err := s.orderRepo.FindByID(ctx, r.OrderID)
+		if err != nil {
+			return err
+		}
+		origin, err := s.orderService.ReturnOrigin(ctx, o, r)
+		if err != nil {
+			return err
+		}
+
+		label, err := s.carrier.PurchaseReturnLabel(ctx, origin, o.ReturnParcel(r))
+		if err != nil {
+			return err
+		}
+		return r.Approve(*label, req.Note)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domainReturnToDTO(r), nil
+}
+
+// RejectReturn 拒绝退货（命令）
+func (s *Service) RejectReturn(ctx context.Context, returnID string, req ReviewReturnRequest) (*ReturnDTO, error) {
+	r, err := s.changeReturn(ctx, returnID, func(ctx context.Context, r *order.Return) error {
+		return r.Reject(req.Note)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domainReturnToDTO(r), nil
+}
+
+// ReceiveReturn 退货收货并记录质检结果（命令）
+func (s *Service) ReceiveReturn(ctx context.Context, returnID string, req ReceiveReturnRequest) (*ReturnDTO, error) {
+	var accepted map[string]int
+	if len(req.Lines) > 0 {
+		accepted = make(map[string]int, len(req.Lines))
+		for _, line := range req.Lines {
+			accepted[line.OrderItemID] += line.AcceptedQuantity
+		}
+	}
+
+	r, err := s.changeReturn(ctx, returnID, func(ctx context.Context, r *order.Return) error {
+		return r.Receive(accepted, req.Note)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domainReturnToDTO(r), nil
+}
+
+// RefundReturn 为质检合格的退货商品退款（命令）
+// 退款经由支付层按商品实付单价计算；退款在锁定退货的同一事务中登记并关联，
+// 重复或并发请求沿用已发起的退款，前次退款失败时重新发起
+func (s *Service) RefundReturn(ctx context.Context, adminID, returnID string) (*ReturnDTO, error) {
+	actor := order.AdminActor(adminID)
+
+	var refund *order.Refund
+	_, err := s.changeReturn(ctx, returnID, func(ctx context.Context, r *order.Return) error {
+		if r.Status != order.ReturnStatusReceived {
+			return fmt.Errorf("%w: can only refund received returns", order.ErrInvalidReturnStatus)
+		}
+
+		var previous *order.Refund
+		if r.RefundID != "" {
+			existing, err := s.refundRepo.FindByID(ctx, r.RefundID)
+			if err != nil {
+				return err
+			}
+			if existing.Status != order.RefundStatusFailed {
+				refund = existing
+				return errReturnUnchanged
+			}
+			previous = existing
+		}
+
+		o, err := s.orderRepo.FindByID(ctx, r.OrderID)
+		if err != nil {
+			return err
+		}
+		payment, err := s.refundablePayment(ctx, o.ID, "")
+		if err != nil {
+			return err
+		}
+
+		reason := fmt.Sprintf("return %s: %s", r.ID, r.Reason)
+		created, err := s.registerRefund(ctx, o, payment.ID, 0, reason, actor, "", r.AcceptedAllocations())
+		if err != nil {
+			return err
+		}
+		refund = created
+		return r.AttachRefund(created.ID, previous)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if refund.Status == order.RefundStatusPending {
+		if _, err := s.executeRefund(ctx, refund, actor); err != nil {
+			return nil, err
+		}
+	}
+
+	r, err := s.changeReturn(ctx, returnID, func(ctx context.Context, r *order.Return) error {
+		if r.Status == order.ReturnStatusRefunded {
+			return errReturnUnchanged
+		}
+		return r.MarkRefunded()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domainReturnToDTO(r), nil
+}
+
+// errReturnUnchanged 退货变更函数返回该错误表示无需保存
+var errReturnUnchanged = errors.New("return unchanged")
+
+// changeReturn 在事务中锁定退货、执行变更并保存，返回变更后的退货
+// 状态校验以锁定后读取的状态为准，并发的审核、收货与退款请求只有一方生效
+func (s *Service) changeReturn(ctx context.Context, returnID string, change func(ctx context.Context, r *order.Return) error) (*order.Return, error) {
+	var r *order.Return
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		locked, err := s.returnRepo.FindByIDForUpdate(ctx, returnID)
+		if err != nil {
+			return err
+		}
+		r = locked
+		if err := change(ctx, locked); err != nil {
+			if errors.Is(err, errReturnUnchanged) {
+				return nil
+			}
+			return err
+		}
+		return s.returnRepo.Update(ctx, locked)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// assignReturnIDs 为退货及退货行分配ID
+func assignReturnIDs(r *order.Return) {
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
+	r.ID = ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
+	for _, line := range r.Lines {
+		line.ID = ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
+		line.ReturnID = r.ID
+	}
+}
+
+// domainReturnToDTO 转换退货为DTO
+func domainReturnToDTO(r *order.Return) *ReturnDTO {
+	lines := make([]*ReturnLineDTO, len(r.Lines))
+	for i, line := range r.Lines {
+		lines[i] = &ReturnLineDTO{
+			OrderItemID:      line.OrderItemID,
+			Quantity:         line.Quantity,
+			AcceptedQuantity: line.AcceptedQuantity,
+		}
+	}
+
+	return &ReturnDTO{
+		ID:             r.ID,
+		OrderID:        r.OrderID,
+		UserID:         r.UserID,
+		Status:         string(r.Status),
+		Reason:         r.Reason,
+		Note:           r.Note,
+		Lines:          lines,
+		Carrier:        r.Carrier,
+		TrackingNumber: r.TrackingNumber,
+		LabelURL:       r.LabelURL,
+		RefundID:       r.RefundID,
+		ApprovedAt:     r.ApprovedAt,
+		RejectedAt:     r.RejectedAt,
+		ReceivedAt:     r.ReceivedAt,
+		RefundedAt:     r.RefundedAt,
+		CreatedAt:      r.CreatedAt,
+		UpdatedAt:      r.UpdatedAt,
+	}
+}
@@ -2,14 +2,93 @@ package order
 
 import (
 	"context"
+	"time"
 
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
 )
 
 // PaymentGateway 支付网关接口（端口）
 type PaymentGateway interface {
-	ProcessPayment(ctx context.Context, amount order.Money, method order.PaymentMethod) (transactionID, response string, err error)
-	RefundPayment(ctx context.Context, transactionID string, amount order.Money) error
+	// ProcessPayment 发起扣款，结果可能需要持卡人验证或后续请款
+	ProcessPayment(ctx context.Context, req order.ChargeRequest) (*order.ChargeResult, error)
+	// CapturePayment 对已授权的交易请款
+	CapturePayment(ctx context.Context, transactionID string, amount order.Money, idempotencyKey string) error
+	// RefundPayment 退款
+	RefundPayment(ctx context.Context, transactionID string, amount order.Money, idempotencyKey string) error
+	// CancelPayment 撤销尚未收款的交易（待验证、处理中或已授权），交易已收款时返回错误
+	CancelPayment(ctx context.Context, transactionID string, idempotencyKey string) error
+}
+
+// PaymentEventVerifier 支付网关回调验签接口（端口）
+type PaymentEventVerifier interface {
+	// Verify 校验回调签名并解析为网关事件，签名无效时返回 order.ErrInvalidWebhookSignature
+	Verify(payload []byte, signature string) (*order.GatewayEvent, error)
+}
+
+// PromotionApplier 优惠应用接口（端口）
+type PromotionApplier interface {
+	// Apply 校验优惠码并以折扣调整行的形式应用到订单
+	Apply(ctx context.Context, userID, code string, o *order.Order) error
+	// Redeem 核销订单上的优惠，订单已核销时不重复核销，需在登记支付尝试的事务中执行
+	Redeem(ctx context.Context, o *order.Order) error
+	// Release 释放订单的优惠核销，用于支付被拒或未支付订单取消
+	Release(ctx context.Context, o *order.Order) error
+}
+
+// TaxCalculator 税额计算接口（端口）
+type TaxCalculator interface {
+	// Calculate 按订单收货地址、税号与订单项计算逐项税额
+	Calculate(ctx context.Context, o *order.Order) (*order.TaxBreakdown, error)
+}
+
+// Carrier 承运商接口（端口）
+type Carrier interface {
+	// Quote 按目的地址与包裹报价，返回可选的运输服务
+	Quote(ctx context.Context, destination order.Address, parcel order.Parcel) ([]order.ShippingRate, error)
+	// PurchaseLabel 按发货已采用的运输服务购买面单
+	PurchaseLabel(ctx context.Context, shipment *order.Shipment, parcel order.Parcel) (*order.ShippingLabel, error)
+	// PurchaseReturnLabel 购买由顾客寄回的退货面单
+	PurchaseReturnLabel(ctx context.Context, from order.Address, parcel order.Parcel) (*order.ShippingLabel, error)
+	// Track 查询运单的跟踪轨迹
+	Track(ctx context.Context, trackingNumber string) ([]order.TrackingUpdate, error)
+}
+
+// InvoiceIssuer 发票开具接口（端口）
+type InvoiceIssuer interface {
+	// IssueForPaidOrder 为已支付订单开具发票，需与支付在同一事务中执行
+	IssueForPaidOrder(ctx context.Context, o *order.Order) error
+	// IssueCreditNote 为退款开具红字发票
+	IssueCreditNote(ctx context.Context, o *order.Order, amount order.Money, reason string) error
+}
+
+// CustomerNotifier 客户通知接口（端口）
+type CustomerNotifier interface {
+	// OrderCancelled 通知客户订单已取消
+	OrderCancelled(ctx context.Context, o *order.Order, reason string) error
+}
+
+// AddressBook 用户地址簿接口（端口）
+type AddressBook interface {
+	// FindAddress 查找用户保存的地址
+	FindAddress(ctx context.Context, userID, addressID string) (order.Address, error)
+	// DefaultAddresses 返回用户的默认收货与账单地址，未设置时为 nil
+	DefaultAddresses(ctx context.Context, userID string) (shipping, billing *order.Address, err error)
+}
+
+// AccountDirectory 用户账户信息接口（端口），用于欺诈筛查
+type AccountDirectory interface {
+	// AccountCreatedAt 返回用户账户的注册时间
+	AccountCreatedAt(ctx context.Context, userID string) (time.Time, error)
+}
+
+// RoleChecker 角色检查接口（端口），用于管理员绕过订单归属检查
+type RoleChecker interface {
+	IsAdmin(userID string) (bool, error)
+}
+
+// TxManager 事务管理接口（端口）
+type TxManager interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 // Service 订单应用服务
@@ -17,9 +96,25 @@ type Service struct {
 	orderRepo    order.OrderRepository
 	paymentRepo  order.PaymentRepository
 	shipmentRepo order.ShipmentRepository
+	refundRepo   order.RefundRepository
+	returnRepo   order.ReturnRepository
+	fraudRepo    order.FraudAssessmentRepository
 	orderService *order.Service
+	eventRepo    order.GatewayEventRepository
+	returnPolicy order.ReturnPolicy
+	fraudPolicy  order.FraudPolicy
 
-	paymentGateway PaymentGateway
+	gateways      *GatewayRegistry
+	eventVerifier PaymentEventVerifier
+	promotions    PromotionApplier
+	taxCalculator TaxCalculator
+	carrier       Carrier
+	invoices      InvoiceIssuer
+	notifier      CustomerNotifier
+	addressBook   AddressBook
+	accounts      AccountDirectory
+	roleChecker   RoleChecker
+	txManager     TxManager
 }
 
 // NewService 创建订单应用服务
@@ -27,16 +122,69 @@ func NewService(
 	orderRepo order.OrderRepository,
 	paymentRepo order.PaymentRepository,
 	shipmentRepo order.ShipmentRepository,
+	refundRepo order.RefundRepository,
+	returnRepo order.ReturnRepository,
+	fraudRepo order.FraudAssessmentRepository,
 	orderService *order.Service,
-	paymentGateway PaymentGateway,
+	eventRepo order.GatewayEventRepository,
+	returnPolicy order.ReturnPolicy,
+	fraudPolicy order.FraudPolicy,
+	gateways *GatewayRegistry,
+	eventVerifier PaymentEventVerifier,
+	promotions PromotionApplier,
+	taxCalculator TaxCalculator,
+	carrier Carrier,
+	invoices InvoiceIssuer,
+	notifier CustomerNotifier,
+	addressBook AddressBook,
+	accounts AccountDirectory,
+	roleChecker RoleChecker,
+	txManager TxManager,
 ) *Service {
 	return &Service{
-		orderRepo:      orderRepo,
-		paymentRepo:    paymentRepo,
-		shipmentRepo:   shipmentRepo,
-		orderService:   orderService,
-		paymentGateway: paymentGateway,
+		orderRepo:     orderRepo,
+		paymentRepo:   paymentRepo,
+		shipmentRepo:  shipmentRepo,
+		refundRepo:    refundRepo,
+		returnRepo:    returnRepo,
+		fraudRepo:     fraudRepo,
+		orderService:  orderService,
+		eventRepo:     eventRepo,
+		returnPolicy:  returnPolicy,
+		fraudPolicy:   fraudPolicy,
+		gateways:      gateways,
+		eventVerifier: eventVerifier,
+		promotions:    promotions,
+		taxCalculator: taxCalculator,
+		carrier:       carrier,
+		invoices:      invoices,
+		notifier:      notifier,
+		addressBook:   addressBook,
+		accounts:      accounts,
+		roleChecker:   roleChecker,
+		txManager:     txManager,
+	}
+}
+
+// findOrderForUser 查找用户可访问的订单，返回订单及状态变更的发起方
+// 非本人订单按不存在处理，避免通过ID枚举他人订单；管理员可访问所有订单
+func (s *Service) findOrderForUser(ctx context.Context, userID, orderID string) (*order.Order, string, error) {
+	o, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, "", err
 	}
+	if userID != "" && o.UserID == userID {
+		return o, order.UserActor(userID), nil
+	}
+
+	isAdmin, err := s.roleChecker.IsAdmin(userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !isAdmin {
+		return nil, "", order.ErrOrderNotFound
+	}
+	return o, order.AdminActor(userID), nil
 }
 
 // domainOrderToDTO 转换订单为DTO
@@ -47,6 +195,7 @@ func domainOrderToDTO(o *order.Order) *OrderDTO {
 			ID:          item.ID,
 			ProductID:   item.ProductID,
 			ProductName: item.ProductName,
+			Category:    item.Category,
 			Quantity:    item.Quantity,
 			UnitPrice: MoneyDTO{
 				Amount:   item.UnitPrice.Amount,
@@ -56,6 +205,40 @@ func domainOrderToDTO(o *order.Order) *OrderDTO {
 				Amount:   item.Subtotal.Amount,
 				Currency: item.Subtotal.Currency,
 			},
+			TaxName: item.TaxName,
+			TaxRate: item.TaxRate,
+			TaxAmount: MoneyDTO{
+				Amount:   item.TaxAmount.Amount,
+				Currency: item.TaxAmount.Currency,
+			},
+			WeightKg:          item.WeightKg,
+			FulfilledQuantity: item.FulfilledQuantity,
+			DeliveredQuantity: item.DeliveredQuantity,
+		}
+	}
+
+	adjustments := make([]*AdjustmentDTO, len(o.Adjustments))
+	for i, adj := range o.Adjustments {
+		adjustments[i] = &AdjustmentDTO{
+			ID:    adj.ID,
+			Type:  string(adj.Type),
+			Label: adj.Label,
+			Code:  adj.Code,
+			Amount: MoneyDTO{
+				Amount:   adj.Amount.Amount,
+				Currency: adj.Amount.Currency,
+			},
+		}
+	}
+
+	history := make([]*StatusChangeDTO, len(o.History))
+	for i, h := range o.History {
+		history[i] = &StatusChangeDTO{
+			From:      string(h.From),
+			To:        string(h.To),
+			Actor:     h.Actor,
+			Note:      h.Note,
+			CreatedAt: h.CreatedAt,
 		}
 	}
 
@@ -65,15 +248,52 @@ func domainOrderToDTO(o *order.Order) *OrderDTO {
 		OrderNumber: o.OrderNumber,
 		Status:      string(o.Status),
 		Items:       items,
+		Adjustments: adjustments,
+		Subtotal: MoneyDTO{
+			Amount:   o.Subtotal.Amount,
+			Currency: o.Subtotal.Currency,
+		},
+		TaxTotal: MoneyDTO{
+			Amount:   o.TaxTotal.Amount,
+			Currency: o.TaxTotal.Currency,
+		},
 		TotalAmount: MoneyDTO{
 			Amount:   o.TotalAmount.Amount,
 			Currency: o.TotalAmount.Currency,
 		},
-		CreatedAt: o.CreatedAt,
-		UpdatedAt: o.UpdatedAt,
+		ShippingAddress: addressToDTO(o.ShippingAddress),
+		BillingAddress:  addressToDTO(o.BillingAddress),
+		VATID:           o.VATID,
+		TaxInclusive:    o.TaxInclusive,
+		ReverseCharge:   o.ReverseCharge,
+		TaxNote:         o.TaxNote,
+		AttentionReason: o.AttentionReason,
+		StatusHistory:   history,
+		Version:         o.Version,
+		CreatedAt:       o.CreatedAt,
+		UpdatedAt:       o.UpdatedAt,
 	}
 }
 
+// addressToDTO 转换可选地址为DTO
+func addressToDTO(a *order.Address) *AddressDTO {
+	if a == nil {
+		return nil
+	}
+	return &AddressDTO{
+		Street:     a.Street,
+		City:       a.City,
+		State:      a.State,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+	}
+}
+
+// addressFromDTO 由请求构造并校验地址
+func addressFromDTO(dto AddressDTO) (order.Address, error) {
+	return order.NewAddress(dto.Street, dto.City, dto.State, dto.PostalCode, dto.Country)
+}
+
 // domainPaymentToDTO 转换支付为DTO
 func domainPaymentToDTO(p *order.Payment) *PaymentDTO {
 	return &PaymentDTO{
@@ -87,19 +307,47 @@ func domainPaymentToDTO(p *order.Payment) *PaymentDTO {
 		Status:          string(p.Status),
 		TransactionID:   p.TransactionID,
 		GatewayResponse: p.GatewayResponse,
-		CreatedAt:       p.CreatedAt,
-		UpdatedAt:       p.UpdatedAt,
+		RefundedAmount: MoneyDTO{
+			Amount:   p.RefundedAmount.Amount,
+			Currency: p.RefundedAmount.Currency,
+		},
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
 	}
 }
 
 // domainShipmentToDTO 转换发货为DTO
 func domainShipmentToDTO(s *order.Shipment) *ShipmentDTO {
+	events := make([]*ShipmentEventDTO, len(s.Events))
+	for i, e := range s.Events {
+		events[i] = &ShipmentEventDTO{
+			Status:      e.Status,
+			Location:    e.Location,
+			Description: e.Description,
+			RawPayload:  e.RawPayload,
+			OccurredAt:  e.OccurredAt,
+		}
+	}
+
+	lines := make([]*ShipmentLineDTO, len(s.Lines))
+	for i, line := range s.Lines {
+		lines[i] = &ShipmentLineDTO{
+			OrderItemID: line.OrderItemID,
+			Quantity:    line.Quantity,
+		}
+	}
+
 	return &ShipmentDTO{
 		ID:             s.ID,
 		OrderID:        s.OrderID,
 		TrackingNumber: s.TrackingNumber,
 		Carrier:        s.Carrier,
 		ShippingMethod: s.ShippingMethod,
+		ShippingCost: MoneyDTO{
+			Amount:   s.ShippingCost.Amount,
+			Currency: s.ShippingCost.Currency,
+		},
+		LabelURL: s.LabelURL,
 		Address: AddressDTO{
 			Street:     s.Address.Street,
 			City:       s.Address.City,
@@ -107,11 +355,28 @@ func domainShipmentToDTO(s *order.Shipment) *ShipmentDTO {
 			PostalCode: s.Address.PostalCode,
 			Country:    s.Address.Country,
 		},
+		Lines:         lines,
 		Status:        string(s.Status),
 		EstimatedDate: s.EstimatedDate,
 		ShippedAt:     s.ShippedAt,
 		DeliveredAt:   s.DeliveredAt,
+		Events:        events,
 		CreatedAt:     s.CreatedAt,
 		UpdatedAt:     s.UpdatedAt,
 	}
 }
+
+// shippingRateToDTO 转换运费报价为DTO
+func shippingRateToDTO(r order.ShippingRate) *ShippingRateDTO {
+	return &ShippingRateDTO{
+		Carrier:     r.Carrier,
+		Service:     r.Service,
+		ServiceName: r.ServiceName,
+		Amount: MoneyDTO{
+			Amount:   r.Amount.Amount,
+			Currency: r.Amount.Currency,
+		},
+		EstimatedDays:     r.EstimatedDays,
+		EstimatedDelivery: r.EstimatedDelivery(time.Now()),
+	}
+}
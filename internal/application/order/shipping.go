@@ -0,0 +1,239 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+	"github.com/oklog/ulid/v2"
+)
+
+// GetShippingOptions 按订单收货地址与包裹重量获取可选运输服务及运费（查询）
+func (s *Service) GetShippingOptions(ctx context.Context, userID, orderID string) ([]*ShippingRateDTO, error) {
+	o, _, err := s.findOrderForUser(ctx, userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, err := s.quoteForOrder(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*ShippingRateDTO, len(rates))
+	for i, r := range rates {
+		dtos[i] = shippingRateToDTO(r)
+	}
+	return dtos, nil
+}
+
+// SelectShippingRate 为待支付订单选择运输服务（命令），运费以调整行计入订单总额
+// 运费按服务端重新报价确定，重复选择时替换之前的运费
+func (s *Service) SelectShippingRate(ctx context.Context, userID, orderID string, req SelectShippingRateRequest) (*OrderDTO, error) {
+	if _, _, err := s.findOrderForUser(ctx, userID, orderID); err != nil {
+		return nil, err
+	}
+
+	var o *order.Order
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		// 锁定订单，避免与支付并发修改总额
+		locked, err := s.orderRepo.FindByIDForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if err := locked.CheckVersion(req.ExpectedVersion); err != nil {
+			return err
+		}
+		if locked.Status != order.StatusPending {
+			return order.ErrInvalidOrderStatus
+		}
+
+		rates, err := s.quoteForOrder(ctx, locked)
+		if err != nil {
+			return err
+		}
+		rate, ok := findShippingRate(rates, req.Service)
+		if !ok {
+			return order.ErrShippingRateUnavailable
+		}
+
+		adj, err := order.NewShippingAdjustment(locked.ID, rate)
+		if err != nil {
+			return err
+		}
+		entropy := ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
+		adj.ID = ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
+
+		if err := locked.SetShipping(adj); err != nil {
+			return err
+		}
+		o = locked
+		return s.orderRepo.Update(ctx, locked)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domainOrderToDTO(o), nil
+}
+
+// PurchaseShipmentLabel 向承运商购买面单（命令），追踪号在发出时沿用
+// 购买期间持有发货行锁：并发或重试的请求等待后看到已购买的面单并被拒绝，不会重复购买
+func (s *Service) PurchaseShipmentLabel(ctx context.Context, shipmentID string) (*ShipmentDTO, error) {
+	shipment, err := s.changeShipment(ctx, shipmentID, func(ctx context.Context, shipment *order.Shipment) error {
+		if err := shipment.CheckLabelPurchasable(); err != nil {
+			return err
+		}
+		o, err := s.orderRepo.FindByID(ctx, shipment.OrderID)
+		if err != nil {
+			return err
+		}
+
+		label, err := s.carrier.PurchaseLabel(ctx, shipment, o.ParcelFor(shipment.Lines))
+		if err != nil {
+			return err
+		}
+		return shipment.AttachLabel(*label)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domainShipmentToDTO(shipment), nil
+}
+
+// SyncShipmentTracking 从承运商拉取跟踪轨迹并追加到时间线（命令），承运商确认签收时完成送达
+func (s *Service) SyncShipmentTracking(ctx context.Context, shipmentID string) (*ShipmentDTO, error) {
+	shipment, err := s.shipmentRepo.FindByID(ctx, shipmentID)
+	if err != nil {
+		return nil, err
+	}
+	if shipment.TrackingNumber == "" {
+		return nil, fmt.Errorf("%w: shipment has no tracking number", order.ErrInvalidShipmentStatus)
+	}
+
+	shipment, _, err = s.syncTracking(ctx, shipment)
+	if err != nil {
+		return nil, err
+	}
+
+	return domainShipmentToDTO(shipment), nil
+}
+
+// SyncShippedShipments 为运输中的发货拉取跟踪轨迹（命令），返回有新轨迹的发货数量
+// 单个运单查询失败不影响其余发货，错误合并后随返回值报告
+func (s *Service) SyncShippedShipments(ctx context.Context, batchSize int) (int, error) {
+	if batchSize <= 0 {
+		return 0, fmt.Errorf("invalid tracking batch size: %d", batchSize)
+	}
+
+	total := 0
+	afterID := ""
+	var trackErrs []error
+
+	for {
+		shipments, err := s.shipmentRepo.ListByStatus(ctx, order.ShipmentStatusShipped, afterID, batchSize)
+		if err != nil {
+			return total, err
+		}
+
+		for _, shipment := range shipments {
+			afterID = shipment.ID
+			_, updated, err := s.syncTracking(ctx, shipment)
+			if err != nil {
+				trackErrs = append(trackErrs, fmt.Errorf("track shipment %s: %w", shipment.ID, err))
+				continue
+			}
+			if updated {
+				total++
+			}
+		}
+
+		if len(shipments) < batchSize || ctx.Err() != nil {
+			break
+		}
+	}
+
+	return total, errors.Join(trackErrs...)
+}
+
+// syncTracking 拉取并记录单个发货的跟踪轨迹，返回记录后的发货与是否有变更
+// 承运商查询在事务之外进行；轨迹记录在锁定后的发货上，签收时仅对仍处于运输中的发货确认送达
+func (s *Service) syncTracking(ctx context.Context, shipment *order.Shipment) (*order.Shipment, bool, error) {
+	updates, err := s.carrier.Track(ctx, shipment.TrackingNumber)
+	if err != nil {
+		return nil, false, err
+	}
+
+	changed := false
+	synced, err := s.changeShipment(ctx, shipment.ID, func(ctx context.Context, shipment *order.Shipment) error {
+		added, delivered := shipment.RecordTrackingUpdates(updates)
+		changed = added > 0
+		if !delivered || shipment.Status != order.ShipmentStatusShipped {
+			if !changed {
+				return errShipmentUnchanged
+			}
+			return nil
+		}
+
+		changed = true
+		if err := shipment.Deliver(); err != nil {
+			return err
+		}
+		return s.completeDeliveredOrder(ctx, shipment, order.ActorSystem)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return synced, changed, nil
+}
+
+// quoteForOrder 按订单收货地址报价，仅保留与订单币种一致的运费
+func (s *Service) quoteForOrder(ctx context.Context, o *order.Order) ([]order.ShippingRate, error) {
+	if o.ShippingAddress == nil {
+		return nil, order.ErrShippingAddressRequired
+	}
+
+	rates, err := s.carrier.Quote(ctx, *o.ShippingAddress, o.Parcel())
+	if err != nil {
+		return nil, err
+	}
+
+	available := make([]order.ShippingRate, 0, len(rates))
+	for _, r := range rates {
+		if r.Amount.Currency == o.TotalAmount.Currency {
+			available = append(available, r)
+		}
+	}
+	return available, nil
+}
+
+// shippingRate 按地址与包裹报价并取指定运输服务的运费
+func (s *Service) shippingRate(ctx context.Context, destination order.Address, parcel order.Parcel, service string) (*order.ShippingRate, error) {
+	if service == "" {
+		return nil, order.ErrShippingRateUnavailable
+	}
+
+	rates, err := s.carrier.Quote(ctx, destination, parcel)
+	if err != nil {
+		return nil, err
+	}
+	rate, ok := findShippingRate(rates, service)
+	if !ok {
+		return nil, order.ErrShippingRateUnavailable
+	}
+	return &rate, nil
+}
+
+// findShippingRate 按运输服务编码查找报价
+func findShippingRate(rates []order.ShippingRate, service string) (order.ShippingRate, bool) {
+	for _, r := range rates {
+		if r.Service == service {
+			return r, true
+		}
+	}
+	return order.ShippingRate{}, false
+}
@@ -0,0 +1,131 @@
+package order
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+)
+
+// HandlePaymentWebhook 处理支付网关回调（命令）
+// 同一事件仅处理一次：事件记录与支付、订单状态更新在同一事务中提交，
+// 网关重试投递的重复事件直接确认返回；
+// 订单已不能支付时收到的付款在事务提交后全额退款，订单标记待管理员处理
+func (s *Service) HandlePaymentWebhook(ctx context.Context, payload []byte, signature string) error {
+	event, err := s.eventVerifier.Verify(payload, signature)
+	if err != nil {
+		return err
+	}
+
+	var orphan *order.Refund
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		recorded, err := s.eventRepo.Record(ctx, event)
+		if err != nil {
+			return err
+		}
+		if !recorded || event.Kind == order.GatewayEventIgnored || event.TransactionID == "" {
+			return nil
+		}
+
+		payment, err := s.findEventPayment(ctx, event)
+		if err != nil {
+			// 非本系统发起的交易，记录事件后忽略
+			if errors.Is(err, order.ErrPaymentNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		switch event.Kind {
+		case order.GatewayEventSucceeded:
+			orphan, err = s.applyPaymentSucceeded(ctx, payment)
+			return err
+		case order.GatewayEventFailed:
+			return s.applyPaymentFailed(ctx, payment, event.Reason)
+		case order.GatewayEventRefunded:
+			return s.applyPaymentRefunded(ctx, payment, event)
+		case order.GatewayEventDisputed:
+			return s.applyPaymentDisputed(ctx, payment, event.Reason)
+		}
+		return nil
+	})
+	if err != nil || orphan == nil {
+		return err
+	}
+	return s.refundOrphanPayment(ctx, orphan)
+}
+
+// findEventPayment 查找并锁定事件对应的支付，需在事务中调用
+// 按交易号查找；扣款结果未及保存时交易号为空，按网关元数据中的支付ID关联并补记交易号
+func (s *Service) findEventPayment(ctx context.Context, event *order.GatewayEvent) (*order.Payment, error) {
+	payment, err := s.paymentRepo.FindByTransactionID(ctx, event.TransactionID)
+	if errors.Is(err, order.ErrPaymentNotFound) && event.PaymentID != "" {
+		payment, err = s.paymentRepo.FindByID(ctx, event.PaymentID)
+		if err == nil && payment.TransactionID != "" {
+			return nil, order.ErrPaymentNotFound
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	payment, err = s.paymentRepo.FindByIDForUpdate(ctx, payment.ID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.TransactionID == "" && payment.Status == order.PaymentStatusPending {
+		if err := payment.AttachTransaction(event.TransactionID, event.Type); err != nil {
+			return nil, err
+		}
+	}
+	return payment, nil
+}
+
+// applyPaymentSucceeded 支付成功：完成支付、订单置为已支付并开具发票
+// 订单已不能支付时登记退款，返回的退款在事务提交后调用网关
+func (s *Service) applyPaymentSucceeded(ctx context.Context, payment *order.Payment) (*order.Refund, error) {
+	// 扣款结果已先行记录（订单已随之处理）时不重复处理
+	if payment.Status != order.PaymentStatusPending && !payment.IsAuthorized() {
+		return nil, nil
+	}
+	if err := payment.MarkAsCompleted(payment.TransactionID, payment.GatewayResponse); err != nil {
+		return nil, err
+	}
+	if err := s.paymentRepo.Update(ctx, payment); err != nil {
+		return nil, err
+	}
+	return s.settlePayment(ctx, payment)
+}
+
+// applyPaymentFailed 支付失败：仅待支付的支付记录会被标记为失败，订单没有其他进行中的尝试时释放优惠核销
+func (s *Service) applyPaymentFailed(ctx context.Context, payment *order.Payment, reason string) error {
+	if payment.Status != order.PaymentStatusPending {
+		return nil
+	}
+	if err := payment.MarkAsFailed(reason); err != nil {
+		return err
+	}
+	if err := s.paymentRepo.Update(ctx, payment); err != nil {
+		return err
+	}
+	return s.releasePromotions(ctx, payment.OrderID)
+}
+
+// applyPaymentRefunded 网关退款：与退款台账对账，补记网关侧直接发起的退款
+func (s *Service) applyPaymentRefunded(ctx context.Context, payment *order.Payment, event *order.GatewayEvent) error {
+	if !payment.CanBeRefunded() {
+		return nil
+	}
+	return s.reconcileGatewayRefund(ctx, payment, event.Amount, event.ID)
+}
+
+// applyPaymentDisputed 持卡人拒付：标记支付为争议中，待人工处理
+func (s *Service) applyPaymentDisputed(ctx context.Context, payment *order.Payment, reason string) error {
+	if !payment.CanBeRefunded() {
+		return nil
+	}
+	if err := payment.MarkAsDisputed(reason); err != nil {
+		return err
+	}
+	return s.paymentRepo.Update(ctx, payment)
+}
@@ -0,0 +1,158 @@
+package promotion
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/promotion"
+	"github.com/oklog/ulid/v2"
+)
+
+// CreatePromotion 创建优惠（命令）
+func (s *Service) CreatePromotion(ctx context.Context, req CreatePromotionRequest) (*PromotionDTO, error) {
+	if err := s.promotionService.ValidateCodeUniqueness(ctx, req.Code); err != nil {
+		return nil, err
+	}
+
+	p, err := promotion.NewPromotion(req.Code, req.Name, promotion.DiscountType(req.DiscountType), req.Value, req.Currency)
+	if err != nil {
+		return nil, err
+	}
+	p.Description = req.Description
+
+	if err := p.SetLimits(req.MinSpend, req.UsageLimit, req.PerUserLimit); err != nil {
+		return nil, err
+	}
+	p.SetValidity(req.StartsAt, req.EndsAt)
+	p.SetTargets(req.ProductIDs, req.Categories)
+
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
+	p.ID = ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
+
+	if err := s.promotionRepo.Create(ctx, p); err != nil {
+		return nil, err
+	}
+
+	return domainPromotionToDTO(p), nil
+}
+
+// UpdatePromotion 更新优惠（命令）
+func (s *Service) UpdatePromotion(ctx context.Context, id string, req UpdatePromotionRequest) (*PromotionDTO, error) {
+	p, err := s.promotionRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckVersion(req.ExpectedVersion); err != nil {
+		return nil, err
+	}
+
+	p.Name = req.Name
+	p.Description = req.Description
+	if err := p.UpdateDiscount(promotion.DiscountType(req.DiscountType), req.Value, req.Currency); err != nil {
+		return nil, err
+	}
+	if err := p.SetLimits(req.MinSpend, req.UsageLimit, req.PerUserLimit); err != nil {
+		return nil, err
+	}
+	p.SetValidity(req.StartsAt, req.EndsAt)
+	p.SetTargets(req.ProductIDs, req.Categories)
+
+	if req.IsActive != nil {
+		if *req.IsActive {
+			p.Activate()
+		} else {
+			p.Deactivate()
+		}
+	}
+
+	if err := s.promotionRepo.Update(ctx, p); err != nil {
+		return nil, err
+	}
+
+	return domainPromotionToDTO(p), nil
+}
+
+// DeletePromotion 删除优惠（命令）
+func (s *Service) DeletePromotion(ctx context.Context, id string) error {
+	if _, err := s.promotionRepo.FindByID(ctx, id); err != nil {
+		return err
+	}
+
+	return s.promotionRepo.Delete(ctx, id)
+}
+
+// Apply 将优惠码作为折扣调整行应用到订单（实现订单应用服务的 PromotionApplier 端口）
+func (s *Service) Apply(ctx context.Context, userID, code string, o *order.Order) error {
+	p, err := s.promotionRepo.FindByCode(ctx, promotion.NormalizeCode(code))
+	if err != nil {
+		return err
+	}
+
+	if err := s.promotionService.ValidateForUser(ctx, p, userID); err != nil {
+		return err
+	}
+
+	discount, err := p.CalculateDiscount(o)
+	if err != nil {
+		return err
+	}
+
+	adj, err := order.NewDiscountAdjustment(o.ID, p.ID, p.Code, p.Name, discount)
+	if err != nil {
+		return err
+	}
+	// 限定商品或分类的优惠只分摊到适用的订单项，计税与退款按同一分摊计算
+	if p.IsTargeted() {
+		for _, item := range o.Items {
+			if p.AppliesTo(item) {
+				adj.ItemIDs = append(adj.ItemIDs, item.ID)
+			}
+		}
+	}
+
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
+	adj.ID = ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
+
+	return o.AddAdjustment(adj)
+}
+
+// Redeem 核销订单上的全部优惠（需在登记支付尝试的事务中调用），订单已核销时跳过，
+// 同一订单的重复支付尝试不会重复占用使用次数
+func (s *Service) Redeem(ctx context.Context, o *order.Order) error {
+	redeemed, err := s.redemptionRepo.FindByOrderID(ctx, o.ID)
+	if err != nil {
+		return err
+	}
+	if len(redeemed) > 0 {
+		return nil
+	}
+
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
+
+	for _, adj := range o.Discounts() {
+		if adj.SourceID == "" {
+			continue
+		}
+
+		p, err := s.promotionRepo.FindByID(ctx, adj.SourceID)
+		if err != nil {
+			return err
+		}
+
+		redemption := promotion.NewRedemption(p.ID, o.UserID, o.ID, adj.Amount.Multiply(-1))
+		redemption.ID = ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
+
+		if err := s.promotionService.Redeem(ctx, p, redemption); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Release 释放订单的优惠核销（需在事务中调用），用于支付被拒或未支付订单取消
+func (s *Service) Release(ctx context.Context, o *order.Order) error {
+	return s.promotionService.Release(ctx, o.ID)
+}
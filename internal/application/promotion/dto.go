@@ -0,0 +1,76 @@
+package promotion
+
+import "time"
+
+// PromotionDTO 优惠DTO
+type PromotionDTO struct {
+	ID           string     `json:"id"`
+	Code         string     `json:"code"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	DiscountType string     `json:"discount_type"`
+	Value        float64    `json:"value"`
+	Currency     string     `json:"currency,omitempty"`
+	MinSpend     float64    `json:"min_spend"`
+	UsageLimit   int        `json:"usage_limit"`
+	PerUserLimit int        `json:"per_user_limit"`
+	UsedCount    int        `json:"used_count"`
+	ProductIDs   []string   `json:"product_ids"`
+	Categories   []string   `json:"categories"`
+	StartsAt     *time.Time `json:"starts_at,omitempty"`
+	EndsAt       *time.Time `json:"ends_at,omitempty"`
+	IsActive     bool       `json:"is_active"`
+	Version      int        `json:"version"` // 乐观锁版本号，与响应头 ETag 一致
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// CreatePromotionRequest 创建优惠请求
+type CreatePromotionRequest struct {
+	Code         string     `json:"code" binding:"required,max=50"`
+	Name         string     `json:"name" binding:"required"`
+	Description  string     `json:"description"`
+	DiscountType string     `json:"discount_type" binding:"required,oneof=percentage fixed"`
+	Value        float64    `json:"value" binding:"required,gt=0"`
+	Currency     string     `json:"currency" binding:"omitempty,len=3"`
+	MinSpend     float64    `json:"min_spend" binding:"gte=0"`
+	UsageLimit   int        `json:"usage_limit" binding:"gte=0"`
+	PerUserLimit int        `json:"per_user_limit" binding:"gte=0"`
+	ProductIDs   []string   `json:"product_ids"`
+	Categories   []string   `json:"categories"`
+	StartsAt     *time.Time `json:"starts_at"`
+	EndsAt       *time.Time `json:"ends_at"`
+}
+
+// UpdatePromotionRequest 更新优惠请求
+type UpdatePromotionRequest struct {
+	Name            string     `json:"name" binding:"required"`
+	Description     string     `json:"description"`
+	DiscountType    string     `json:"discount_type" binding:"required,oneof=percentage fixed"`
+	Value           float64    `json:"value" binding:"required,gt=0"`
+	Currency        string     `json:"currency" binding:"omitempty,len=3"`
+	MinSpend        float64    `json:"min_spend" binding:"gte=0"`
+	UsageLimit      int        `json:"usage_limit" binding:"gte=0"`
+	PerUserLimit    int        `json:"per_user_limit" binding:"gte=0"`
+	ProductIDs      []string   `json:"product_ids"`
+	Categories      []string   `json:"categories"`
+	StartsAt        *time.Time `json:"starts_at"`
+	EndsAt          *time.Time `json:"ends_at"`
+	IsActive        *bool      `json:"is_active"`
+	ExpectedVersion int        `json:"-"` // 请求头 If-Match 中的版本号，0 表示不校验
+}
+
+// ListPromotionsRequest 列出优惠请求
+type ListPromotionsRequest struct {
+	Page     int `json:"page" validate:"gte=1"`
+	PageSize int `json:"page_size" validate:"gte=1,lte=100"`
+}
+
+// ListPromotionsResponse 列出优惠响应
+type ListPromotionsResponse struct {
+	Promotions []*PromotionDTO `json:"promotions"`
+	Total      int64           `json:"total"`
+	Page       int             `json:"page"`
+	PageSize   int             `json:"page_size"`
+	TotalPages int             `json:"total_pages"`
+}
@@ -0,0 +1,42 @@
+package promotion
+
+import (
+	"context"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/shared/pagination"
+)
+
+// GetPromotion 获取优惠（查询）
+func (s *Service) GetPromotion(ctx context.Context, id string) (*PromotionDTO, error) {
+	p, err := s.promotionRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return domainPromotionToDTO(p), nil
+}
+
+// ListPromotions 列出优惠（查询）
+func (s *Service) ListPromotions(ctx context.Context, req ListPromotionsRequest) (*ListPromotionsResponse, error) {
+	offset, limit := pagination.ParsePaginationParams(req.Page, req.PageSize)
+
+	promotions, total, err := s.promotionRepo.List(ctx, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*PromotionDTO, len(promotions))
+	for i, p := range promotions {
+		dtos[i] = domainPromotionToDTO(p)
+	}
+
+	pg := pagination.NewPagination(req.Page, req.PageSize, total)
+
+	return &ListPromotionsResponse{
+		Promotions: dtos,
+		Total:      total,
+		Page:       pg.Page,
+		PageSize:   pg.PageSize,
+		TotalPages: pg.TotalPages,
+	}, nil
+}
@@ -0,0 +1,50 @@
+package promotion
+
+import (
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/promotion"
+)
+
+// Service 优惠应用服务
+type Service struct {
+	promotionRepo    promotion.Repository
+	redemptionRepo   promotion.RedemptionRepository
+	promotionService *promotion.Service
+}
+
+// NewService 创建优惠应用服务
+func NewService(
+	promotionRepo promotion.Repository,
+	redemptionRepo promotion.RedemptionRepository,
+	promotionService *promotion.Service,
+) *Service {
+	return &Service{
+		promotionRepo:    promotionRepo,
+		redemptionRepo:   redemptionRepo,
+		promotionService: promotionService,
+	}
+}
+
+// domainPromotionToDTO 转换优惠为DTO
+func domainPromotionToDTO(p *promotion.Promotion) *PromotionDTO {
+	return &PromotionDTO{
+		ID:           p.ID,
+		Code:         p.Code,
+		Name:         p.Name,
+		Description:  p.Description,
+		DiscountType: string(p.DiscountType),
+		Value:        p.Value,
+		Currency:     p.Currency,
+		MinSpend:     p.MinSpend,
+		UsageLimit:   p.UsageLimit,
+		PerUserLimit: p.PerUserLimit,
+		UsedCount:    p.UsedCount,
+		ProductIDs:   p.ProductIDs,
+		Categories:   p.Categories,
+		StartsAt:     p.StartsAt,
+		EndsAt:       p.EndsAt,
+		IsActive:     p.IsActive,
+		Version:      p.Version,
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
+	}
+}
@@ -0,0 +1,81 @@
+package report
+
+import "time"
+
+// SalesReportRequest 销售报表请求
+type SalesReportRequest struct {
+	Granularity string `form:"granularity"` // day（默认）、week、month
+	From        string `form:"from"`        // YYYY-MM-DD，默认 To 之前 30 天
+	To          string `form:"to"`          // YYYY-MM-DD（含当天），默认今天（UTC）
+	Currency    string `form:"currency"`    // 为空时按币种分别列出
+}
+
+// TopProductsRequest 热销商品请求
+type TopProductsRequest struct {
+	From     string `form:"from"`
+	To       string `form:"to"`
+	Currency string `form:"currency"`
+	Limit    int    `form:"limit"` // 默认 10，最大 100
+}
+
+// DashboardRequest 仪表盘请求
+type DashboardRequest struct {
+	Days     int    `form:"days"` // 统计最近多少天（含今天），默认 30
+	Currency string `form:"currency"`
+}
+
+// SalesReportDTO 销售报表DTO
+type SalesReportDTO struct {
+	Granularity string            `json:"granularity"`
+	From        string            `json:"from"`
+	To          string            `json:"to"`
+	Periods     []*SalesPeriodDTO `json:"periods"`
+	GeneratedAt time.Time         `json:"generated_at"`
+}
+
+// SalesPeriodDTO 周期销售指标DTO
+type SalesPeriodDTO struct {
+	PeriodStart       string                     `json:"period_start"`
+	Currency          string                     `json:"currency"`
+	OrderCount        int                        `json:"order_count"`
+	GMV               float64                    `json:"gmv"`
+	RefundCount       int                        `json:"refund_count"`
+	RefundedAmount    float64                    `json:"refunded_amount"`
+	NetSales          float64                    `json:"net_sales"`
+	AverageOrderValue float64                    `json:"average_order_value"`
+	ByStatus          map[string]StatusTotalsDTO `json:"by_status"`
+}
+
+// StatusTotalsDTO 订单状态分布DTO
+type StatusTotalsDTO struct {
+	OrderCount int     `json:"order_count"`
+	Amount     float64 `json:"amount"`
+}
+
+// TopProductsDTO 热销商品DTO
+type TopProductsDTO struct {
+	From        string             `json:"from"`
+	To          string             `json:"to"`
+	Products    []*ProductSalesDTO `json:"products"`
+	GeneratedAt time.Time          `json:"generated_at"`
+}
+
+// ProductSalesDTO 商品销量DTO
+type ProductSalesDTO struct {
+	ProductID   string  `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	Currency    string  `json:"currency"`
+	Quantity    int     `json:"quantity"`
+	Revenue     float64 `json:"revenue"`
+	OrderCount  int     `json:"order_count"`
+}
+
+// DashboardDTO 仪表盘DTO
+type DashboardDTO struct {
+	From        string             `json:"from"`
+	To          string             `json:"to"`
+	Totals      []*SalesPeriodDTO  `json:"totals"` // 区间合计，每个币种一条
+	Daily       []*SalesPeriodDTO  `json:"daily"`
+	TopProducts []*ProductSalesDTO `json:"top_products"`
+	GeneratedAt time.Time          `json:"generated_at"`
+}
@@ -0,0 +1,171 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/report"
+)
+
+const (
+	// defaultRangeDays 未指定起始日期时的统计天数
+	defaultRangeDays = 30
+
+	// defaultTopProducts 热销商品默认条数
+	defaultTopProducts = 10
+
+	// maxTopProducts 热销商品最大条数
+	maxTopProducts = 100
+
+	// dashboardTopProducts 仪表盘展示的热销商品条数
+	dashboardTopProducts = 5
+
+	// dateLayout 报表日期参数格式
+	dateLayout = "2006-01-02"
+)
+
+// SalesReport 按日/周/月汇总订单数、GMV、退款与客单价（查询）
+func (s *Service) SalesReport(ctx context.Context, req SalesReportRequest) (*SalesReportDTO, error) {
+	g, err := report.ParseGranularity(strings.TrimSpace(req.Granularity))
+	if err != nil {
+		return nil, err
+	}
+	rng, err := parseDateRange(req.From, req.To, defaultRangeDays, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	currency := normalizeCurrency(req.Currency)
+
+	key := fmt.Sprintf("sales:%s:%s:%s:%s", g, formatDate(rng.From), formatDate(rng.To), currency)
+	return cachedQuery(ctx, s, key, func() (*SalesReportDTO, error) {
+		sales, err := s.reportRepo.ListDailySales(ctx, rng, currency)
+		if err != nil {
+			return nil, err
+		}
+		refunds, err := s.reportRepo.ListDailyRefunds(ctx, rng, currency)
+		if err != nil {
+			return nil, err
+		}
+
+		return &SalesReportDTO{
+			Granularity: string(g),
+			From:        formatDate(rng.From),
+			To:          formatDate(lastDay(rng)),
+			Periods:     domainPeriodsToDTO(report.Rollup(g, sales, refunds)),
+			GeneratedAt: time.Now(),
+		}, nil
+	})
+}
+
+// TopProducts 按销售额列出热销商品（查询）
+func (s *Service) TopProducts(ctx context.Context, req TopProductsRequest) (*TopProductsDTO, error) {
+	rng, err := parseDateRange(req.From, req.To, defaultRangeDays, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	currency := normalizeCurrency(req.Currency)
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultTopProducts
+	}
+	if limit > maxTopProducts {
+		limit = maxTopProducts
+	}
+
+	key := fmt.Sprintf("top-products:%s:%s:%s:%d", formatDate(rng.From), formatDate(rng.To), currency, limit)
+	return cachedQuery(ctx, s, key, func() (*TopProductsDTO, error) {
+		products, err := s.reportRepo.TopProducts(ctx, rng, currency, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		return &TopProductsDTO{
+			From:        formatDate(rng.From),
+			To:          formatDate(lastDay(rng)),
+			Products:    domainProductsToDTO(products),
+			GeneratedAt: time.Now(),
+		}, nil
+	})
+}
+
+// Dashboard 最近若干天的销售合计、每日趋势与热销商品（查询）
+func (s *Service) Dashboard(ctx context.Context, req DashboardRequest) (*DashboardDTO, error) {
+	days := req.Days
+	if days <= 0 {
+		days = defaultRangeDays
+	}
+	if days > report.MaxRangeDays {
+		return nil, fmt.Errorf("%w: days must not exceed %d", report.ErrInvalidReportQuery, report.MaxRangeDays)
+	}
+	rng, err := parseDateRange("", "", days, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	currency := normalizeCurrency(req.Currency)
+
+	key := fmt.Sprintf("dashboard:%s:%s:%s", formatDate(rng.From), formatDate(rng.To), currency)
+	return cachedQuery(ctx, s, key, func() (*DashboardDTO, error) {
+		sales, err := s.reportRepo.ListDailySales(ctx, rng, currency)
+		if err != nil {
+			return nil, err
+		}
+		refunds, err := s.reportRepo.ListDailyRefunds(ctx, rng, currency)
+		if err != nil {
+			return nil, err
+		}
+		products, err := s.reportRepo.TopProducts(ctx, rng, currency, dashboardTopProducts)
+		if err != nil {
+			return nil, err
+		}
+
+		return &DashboardDTO{
+			From:        formatDate(rng.From),
+			To:          formatDate(lastDay(rng)),
+			Totals:      domainPeriodsToDTO(report.Summarize(rng, sales, refunds)),
+			Daily:       domainPeriodsToDTO(report.Rollup(report.GranularityDay, sales, refunds)),
+			TopProducts: domainProductsToDTO(products),
+			GeneratedAt: time.Now(),
+		}, nil
+	})
+}
+
+// parseDateRange 解析报表日期区间，to 含当天；未指定时截至今天（UTC），起始日期默认为 to 之前 defaultDays 天
+func parseDateRange(from, to string, defaultDays int, now time.Time) (report.DateRange, error) {
+	end := now.UTC()
+	if to = strings.TrimSpace(to); to != "" {
+		t, err := time.Parse(dateLayout, to)
+		if err != nil {
+			return report.DateRange{}, fmt.Errorf("%w: invalid date %q", report.ErrInvalidReportQuery, to)
+		}
+		end = t
+	}
+	end = end.AddDate(0, 0, 1)
+
+	start := end.AddDate(0, 0, -defaultDays)
+	if from = strings.TrimSpace(from); from != "" {
+		t, err := time.Parse(dateLayout, from)
+		if err != nil {
+			return report.DateRange{}, fmt.Errorf("%w: invalid date %q", report.ErrInvalidReportQuery, from)
+		}
+		start = t
+	}
+
+	return report.NewDateRange(start, end)
+}
+
+// normalizeCurrency 币种参数统一为大写
+func normalizeCurrency(currency string) string {
+	return strings.ToUpper(strings.TrimSpace(currency))
+}
+
+// lastDay 区间的最后一天（含）
+func lastDay(rng report.DateRange) time.Time {
+	return rng.To.AddDate(0, 0, -1)
+}
+
+// formatDate 格式化报表日期
+func formatDate(t time.Time) string {
+	return t.Format(dateLayout)
+}
@@ -0,0 +1,27 @@
+package report
+
+import (
+	"context"
+	"time"
+)
+
+// RefreshReports 增量刷新销售汇总表（命令），返回重算的天数
+// 有数据变动时递增缓存版本使已缓存的报表失效，并预热默认区间的报表
+func (s *Service) RefreshReports(ctx context.Context) (int, error) {
+	days, err := s.reportRepo.Refresh(ctx, time.Now())
+	if err != nil || days == 0 {
+		return days, err
+	}
+
+	if _, err := s.cache.BumpVersion(ctx); err != nil {
+		return days, err
+	}
+	if _, err := s.Dashboard(ctx, DashboardRequest{}); err != nil {
+		return days, err
+	}
+	if _, err := s.SalesReport(ctx, SalesReportRequest{}); err != nil {
+		return days, err
+	}
+	_, err = s.TopProducts(ctx, TopProductsRequest{})
+	return days, err
+}
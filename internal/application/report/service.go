@@ -0,0 +1,107 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/report"
+)
+
+// Cache 报表结果缓存接口
+// 汇总表刷新后递增版本号，缓存键带版本号，旧版本的缓存不再命中并自然过期
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Version(ctx context.Context) (int64, error)
+	BumpVersion(ctx context.Context) (int64, error)
+}
+
+// Service 报表应用服务
+type Service struct {
+	reportRepo report.Repository
+	cache      Cache
+	cacheTTL   time.Duration
+}
+
+// NewService 创建报表应用服务
+func NewService(reportRepo report.Repository, cache Cache, cacheTTL time.Duration) *Service {
+	return &Service{
+		reportRepo: reportRepo,
+		cache:      cache,
+		cacheTTL:   cacheTTL,
+	}
+}
+
+// cachedQuery 优先读取缓存，未命中时计算并写入缓存
+// 未启用或缓存不可用时直接查询汇总表，缓存读写失败不影响查询结果
+func cachedQuery[T any](ctx context.Context, s *Service, key string, compute func() (*T, error)) (*T, error) {
+	if s.cacheTTL <= 0 {
+		return compute()
+	}
+	version, err := s.cache.Version(ctx)
+	if err != nil {
+		return compute()
+	}
+	key = fmt.Sprintf("v%d:%s", version, key)
+
+	if data, ok, err := s.cache.Get(ctx, key); err == nil && ok {
+		var cached T
+		if err := json.Unmarshal(data, &cached); err == nil {
+			return &cached, nil
+		}
+	}
+
+	result, err := compute()
+	if err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(result); err == nil {
+		_ = s.cache.Set(ctx, key, data, s.cacheTTL)
+	}
+	return result, nil
+}
+
+// domainPeriodsToDTO 转换周期销售指标为DTO
+func domainPeriodsToDTO(periods []*report.SalesPeriod) []*SalesPeriodDTO {
+	dtos := make([]*SalesPeriodDTO, len(periods))
+	for i, p := range periods {
+		byStatus := make(map[string]StatusTotalsDTO, len(p.ByStatus))
+		for status, totals := range p.ByStatus {
+			byStatus[string(status)] = StatusTotalsDTO{
+				OrderCount: totals.OrderCount,
+				Amount:     totals.Amount,
+			}
+		}
+
+		dtos[i] = &SalesPeriodDTO{
+			PeriodStart:       formatDate(p.PeriodStart),
+			Currency:          p.Currency,
+			OrderCount:        p.OrderCount,
+			GMV:               p.GMV,
+			RefundCount:       p.RefundCount,
+			RefundedAmount:    p.RefundedAmount,
+			NetSales:          p.NetSales,
+			AverageOrderValue: p.AverageOrderValue,
+			ByStatus:          byStatus,
+		}
+	}
+	return dtos
+}
+
+// domainProductsToDTO 转换商品销量为DTO
+func domainProductsToDTO(products []*report.ProductSales) []*ProductSalesDTO {
+	dtos := make([]*ProductSalesDTO, len(products))
+	for i, p := range products {
+		dtos[i] = &ProductSalesDTO{
+			ProductID:   p.ProductID,
+			ProductName: p.ProductName,
+			Currency:    p.Currency,
+			Quantity:    p.Quantity,
+			Revenue:     p.Revenue,
+			OrderCount:  p.OrderCount,
+		}
+	}
+	return dtos
+}
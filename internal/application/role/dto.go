@@ -13,9 +13,10 @@ type CreateRoleRequest struct {
 
 // UpdateRoleRequest 更新角色请求
 type UpdateRoleRequest struct {
-	Name        string `json:"name" binding:"required"`
-	Description string `json:"description"`
-	IsActive    *bool  `json:"isActive"`
+	Name            string `json:"name" binding:"required"`
+	Description     string `json:"description"`
+	IsActive        *bool  `json:"isActive"`
+	ExpectedVersion int    `json:"-"` // 请求头 If-Match 中的版本号，0 表示不校验
 }
 
 // AssignUserRoleRequest 分配角色给用户请求
@@ -37,6 +38,7 @@ type RoleResponse struct {
 	Code        string    `json:"code"`
 	Description string    `json:"description"`
 	IsActive    bool      `json:"isActive"`
+	Version     int       `json:"version"` // 乐观锁版本号，与响应头 ETag 一致
 	CreatedAt   time.Time `json:"createdAt"`
 	UpdatedAt   time.Time `json:"updatedAt"`
 }
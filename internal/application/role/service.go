@@ -62,6 +62,9 @@ func (s *Service) UpdateRole(ctx context.Context, id string, req UpdateRoleReque
 	if err != nil {
 		return nil, err
 	}
+	if err := role.CheckVersion(req.ExpectedVersion); err != nil {
+		return nil, err
+	}
 
 	// 更新角色信息
 	if err := role.UpdateInfo(req.Name, req.Description); err != nil {
@@ -194,6 +197,7 @@ func toRoleResponse(r *rbac.Role) *RoleResponse {
 		Code:        r.Code,
 		Description: r.Description,
 		IsActive:    r.IsActive,
+		Version:     r.Version,
 		CreatedAt:   r.CreatedAt,
 		UpdatedAt:   r.UpdatedAt,
 	}
@@ -0,0 +1,260 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/subscription"
+	"github.com/oklog/ulid/v2"
+)
+
+// CreatePlan 创建订阅计划（命令）
+func (s *Service) CreatePlan(ctx context.Context, req CreatePlanRequest) (*PlanDTO, error) {
+	if err := s.subscriptionService.ValidatePlanCodeUniqueness(ctx, req.Code); err != nil {
+		return nil, err
+	}
+
+	intervalCount := req.IntervalCount
+	if intervalCount == 0 {
+		intervalCount = 1
+	}
+	p, err := subscription.NewPlan(req.Code, req.Name, req.ProductID, order.NewMoney(req.Price, req.Currency), subscription.Interval(req.Interval), intervalCount)
+	if err != nil {
+		return nil, err
+	}
+	p.Description = req.Description
+	p.Category = req.Category
+	if err := p.UpdatePricing(p.Price, req.TrialDays); err != nil {
+		return nil, err
+	}
+
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
+	p.ID = ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
+
+	if err := s.planRepo.Create(ctx, p); err != nil {
+		return nil, err
+	}
+
+	return domainPlanToDTO(p), nil
+}
+
+// UpdatePlan 更新订阅计划（命令），价格变更自已有订阅的下一周期起生效
+func (s *Service) UpdatePlan(ctx context.Context, id string, req UpdatePlanRequest) (*PlanDTO, error) {
+	p, err := s.planRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckVersion(req.ExpectedVersion); err != nil {
+		return nil, err
+	}
+
+	p.Name = req.Name
+	p.Description = req.Description
+	if err := p.UpdatePricing(order.NewMoney(req.Price, req.Currency), req.TrialDays); err != nil {
+		return nil, err
+	}
+	if req.IsActive != nil {
+		if *req.IsActive {
+			p.Activate()
+		} else {
+			p.Deactivate()
+		}
+	}
+
+	if err := s.planRepo.Update(ctx, p); err != nil {
+		return nil, err
+	}
+
+	return domainPlanToDTO(p), nil
+}
+
+// Subscribe 订阅计划（命令）：有试用时试用结束后首次扣款，否则立即扣取首期费用
+// 扣款前先提交未完成的订阅及首期扣款记录，记录预分配扣款订单：扣款后生效失败或网关结果未知时，
+// 再次订阅同一计划会续上同一订单，不会重复扣款；扣款失败时订阅终止
+func (s *Service) Subscribe(ctx context.Context, userID string, req CreateSubscriptionRequest) (*SubscriptionDTO, error) {
+	plan, err := s.planRepo.FindByID(ctx, req.PlanID)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := s.subscriptionService.ValidateNewSubscription(ctx, userID, plan.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var renewal *subscription.Renewal
+	if sub == nil {
+		sub, renewal, err = s.createSubscription(ctx, userID, plan, req, now)
+		if err != nil {
+			return nil, err
+		}
+		if !sub.IsIncomplete() {
+			return domainSubscriptionToDTO(sub), nil
+		}
+	} else {
+		renewal, err = s.renewalRepo.FindByPeriod(ctx, sub.ID, sub.CurrentPeriodStart)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	charge := recurringCharge(sub, plan, plan.Name, plan.Price)
+	charge.OrderID = renewal.OrderID
+	orderID, chargeErr := s.billing.ChargeRecurring(ctx, charge)
+	if chargeErr != nil && !errors.Is(chargeErr, order.ErrPaymentFailed) {
+		return nil, chargeErr
+	}
+
+	// 锁定订阅后记录扣款结果，并发续上同一订阅时只有一方生效
+	err = s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		locked, err := s.subscriptionRepo.FindByIDForUpdate(txCtx, sub.ID)
+		if err != nil {
+			return err
+		}
+		sub = locked
+		if !sub.IsIncomplete() {
+			return nil
+		}
+
+		if chargeErr != nil {
+			if err := sub.Abandon(now); err != nil {
+				return err
+			}
+			return s.subscriptionRepo.Update(txCtx, sub)
+		}
+
+		renewal.MarkCharged(now)
+		if err := s.renewalRepo.Update(txCtx, renewal); err != nil {
+			return err
+		}
+		if err := sub.Activate(plan, orderID, charge.Credit, now); err != nil {
+			return err
+		}
+		return s.subscriptionRepo.Update(txCtx, sub)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if chargeErr != nil {
+		return nil, chargeErr
+	}
+
+	return domainSubscriptionToDTO(sub), nil
+}
+
+// createSubscription 创建订阅，需要首期扣款时在同一事务中创建预分配扣款订单的首期扣款记录
+func (s *Service) createSubscription(ctx context.Context, userID string, plan *subscription.Plan, req CreateSubscriptionRequest, now time.Time) (*subscription.Subscription, *subscription.Renewal, error) {
+	sub, err := subscription.NewSubscription(userID, plan, order.PaymentMethod(req.PaymentMethod), req.PaymentMethodID, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
+	sub.ID = ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
+
+	var renewal *subscription.Renewal
+	if sub.IsIncomplete() {
+		renewal, err = subscription.NewFirstRenewal(sub, ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String(), now)
+		if err != nil {
+			return nil, nil, err
+		}
+		renewal.ID = ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.subscriptionRepo.Create(txCtx, sub); err != nil {
+			return err
+		}
+		if renewal == nil {
+			return nil
+		}
+		return s.renewalRepo.Create(txCtx, renewal)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return sub, renewal, nil
+}
+
+// ChangePlan 变更订阅计划（命令）：升级差价立即扣款，扣款失败时保持原计划；降级差价计入余额
+func (s *Service) ChangePlan(ctx context.Context, userID, id string, req ChangePlanRequest) (*SubscriptionDTO, error) {
+	return s.updateForUser(ctx, userID, id, req.ExpectedVersion, func(txCtx context.Context, sub *subscription.Subscription) error {
+		current, err := s.planRepo.FindByID(txCtx, sub.PlanID)
+		if err != nil {
+			return err
+		}
+		next, err := s.planRepo.FindByID(txCtx, req.PlanID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		proration, err := sub.ChangePlan(current, next, now)
+		if err != nil {
+			return err
+		}
+		if !proration.IsPositive() {
+			return nil
+		}
+
+		// 扣款使用不含事务的 ctx：订单与支付在各自的事务中提交，订阅行锁保持到本事务结束
+		charge := recurringCharge(sub, next, next.Name+" (proration)", proration)
+		orderID, err := s.billing.ChargeRecurring(ctx, charge)
+		if err != nil {
+			return err
+		}
+		sub.RecordCharge(orderID, charge.Credit, now)
+		return nil
+	})
+}
+
+// CancelSubscription 取消订阅（命令），当前周期结束时终止
+func (s *Service) CancelSubscription(ctx context.Context, userID, id string) (*SubscriptionDTO, error) {
+	return s.updateForUser(ctx, userID, id, 0, func(_ context.Context, sub *subscription.Subscription) error {
+		return sub.Cancel(time.Now())
+	})
+}
+
+// ResumeSubscription 撤销期末取消（命令）
+func (s *Service) ResumeSubscription(ctx context.Context, userID, id string) (*SubscriptionDTO, error) {
+	return s.updateForUser(ctx, userID, id, 0, func(_ context.Context, sub *subscription.Subscription) error {
+		return sub.Resume(time.Now())
+	})
+}
+
+// UpdatePaymentMethod 更换续费支付方式（命令），催缴中的订阅由 worker 尽快重试扣款
+func (s *Service) UpdatePaymentMethod(ctx context.Context, userID, id string, req UpdatePaymentMethodRequest) (*SubscriptionDTO, error) {
+	return s.updateForUser(ctx, userID, id, req.ExpectedVersion, func(_ context.Context, sub *subscription.Subscription) error {
+		return sub.UpdatePaymentMethod(order.PaymentMethod(req.PaymentMethod), req.PaymentMethodID, time.Now())
+	})
+}
+
+// updateForUser 在事务中锁定用户的订阅并执行变更，避免与 worker 续费并发修改
+// expectedVersion 为客户端读取时的版本号（If-Match），在执行变更（如扣款）前校验，0 表示不校验
+func (s *Service) updateForUser(ctx context.Context, userID, id string, expectedVersion int, fn func(ctx context.Context, sub *subscription.Subscription) error) (*SubscriptionDTO, error) {
+	var sub *subscription.Subscription
+	err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		locked, err := s.subscriptionRepo.FindByIDForUpdate(txCtx, id)
+		if err != nil {
+			return err
+		}
+		if locked.UserID != userID {
+			return subscription.ErrSubscriptionNotFound
+		}
+		if err := locked.CheckVersion(expectedVersion); err != nil {
+			return err
+		}
+		if err := fn(txCtx, locked); err != nil {
+			return err
+		}
+		sub = locked
+		return s.subscriptionRepo.Update(txCtx, locked)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return domainSubscriptionToDTO(sub), nil
+}
@@ -0,0 +1,90 @@
+package subscription
+
+import "time"
+
+// PlanDTO 订阅计划DTO
+type PlanDTO struct {
+	ID            string    `json:"id"`
+	Code          string    `json:"code"`
+	Name          string    `json:"name"`
+	Description   string    `json:"description"`
+	ProductID     string    `json:"product_id"`
+	Category      string    `json:"category,omitempty"`
+	Price         float64   `json:"price"`
+	Currency      string    `json:"currency"`
+	Interval      string    `json:"interval"`
+	IntervalCount int       `json:"interval_count"`
+	TrialDays     int       `json:"trial_days"`
+	IsActive      bool      `json:"is_active"`
+	Version       int       `json:"version"` // 乐观锁版本号，与响应头 ETag 一致
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// CreatePlanRequest 创建订阅计划请求
+type CreatePlanRequest struct {
+	Code          string  `json:"code" binding:"required,max=50"`
+	Name          string  `json:"name" binding:"required"`
+	Description   string  `json:"description"`
+	ProductID     string  `json:"product_id" binding:"required"`
+	Category      string  `json:"category"`
+	Price         float64 `json:"price" binding:"required,gt=0"`
+	Currency      string  `json:"currency" binding:"required,len=3"`
+	Interval      string  `json:"interval" binding:"required,oneof=day week month year"`
+	IntervalCount int     `json:"interval_count" binding:"omitempty,gte=1"`
+	TrialDays     int     `json:"trial_days" binding:"gte=0"`
+}
+
+// UpdatePlanRequest 更新订阅计划请求（计费周期创建后不可修改）
+type UpdatePlanRequest struct {
+	Name            string  `json:"name" binding:"required"`
+	Description     string  `json:"description"`
+	Price           float64 `json:"price" binding:"required,gt=0"`
+	Currency        string  `json:"currency" binding:"required,len=3"`
+	TrialDays       int     `json:"trial_days" binding:"gte=0"`
+	IsActive        *bool   `json:"is_active"`
+	ExpectedVersion int     `json:"-"` // 请求头 If-Match 中的版本号，0 表示不校验
+}
+
+// SubscriptionDTO 订阅DTO
+type SubscriptionDTO struct {
+	ID                 string     `json:"id"`
+	UserID             string     `json:"user_id"`
+	PlanID             string     `json:"plan_id"`
+	Status             string     `json:"status"`
+	PaymentMethod      string     `json:"payment_method"`
+	CurrentPeriodStart time.Time  `json:"current_period_start"`
+	CurrentPeriodEnd   time.Time  `json:"current_period_end"`
+	TrialEnd           *time.Time `json:"trial_end,omitempty"`
+	CancelAtPeriodEnd  bool       `json:"cancel_at_period_end"`
+	CanceledAt         *time.Time `json:"canceled_at,omitempty"`
+	EndedAt            *time.Time `json:"ended_at,omitempty"`
+	Credit             float64    `json:"credit"` // 降级余额，抵扣后续账单
+	Currency           string     `json:"currency"`
+	FailedAttempts     int        `json:"failed_attempts"`
+	NextRetryAt        *time.Time `json:"next_retry_at,omitempty"`
+	LastOrderID        string     `json:"last_order_id,omitempty"`
+	Version            int        `json:"version"` // 乐观锁版本号，与响应头 ETag 一致
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// CreateSubscriptionRequest 订阅计划请求，无试用时立即扣取首期费用
+type CreateSubscriptionRequest struct {
+	PlanID          string `json:"plan_id" binding:"required"`
+	PaymentMethod   string `json:"payment_method" binding:"required"`
+	PaymentMethodID string `json:"payment_method_id" binding:"required"` // 网关保存的支付方式令牌，用于续费离线扣款
+}
+
+// ChangePlanRequest 变更订阅计划请求
+type ChangePlanRequest struct {
+	PlanID          string `json:"plan_id" binding:"required"`
+	ExpectedVersion int    `json:"-"` // 请求头 If-Match 中的版本号，0 表示不校验
+}
+
+// UpdatePaymentMethodRequest 更换续费支付方式请求
+type UpdatePaymentMethodRequest struct {
+	PaymentMethod   string `json:"payment_method" binding:"required"`
+	PaymentMethodID string `json:"payment_method_id" binding:"required"`
+	ExpectedVersion int    `json:"-"` // 请求头 If-Match 中的版本号，0 表示不校验
+}
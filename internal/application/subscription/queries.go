@@ -0,0 +1,48 @@
+package subscription
+
+import (
+	"context"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/subscription"
+)
+
+// ListPlans 列出订阅计划（查询），activeOnly 时只返回在售计划
+func (s *Service) ListPlans(ctx context.Context, activeOnly bool) ([]*PlanDTO, error) {
+	plans, err := s.planRepo.List(ctx, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*PlanDTO, len(plans))
+	for i, p := range plans {
+		dtos[i] = domainPlanToDTO(p)
+	}
+	return dtos, nil
+}
+
+// ListSubscriptions 列出用户的订阅（查询）
+func (s *Service) ListSubscriptions(ctx context.Context, userID string) ([]*SubscriptionDTO, error) {
+	subscriptions, err := s.subscriptionRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*SubscriptionDTO, len(subscriptions))
+	for i, sub := range subscriptions {
+		dtos[i] = domainSubscriptionToDTO(sub)
+	}
+	return dtos, nil
+}
+
+// GetSubscription 获取用户的订阅（查询），非本人订阅按不存在处理
+func (s *Service) GetSubscription(ctx context.Context, userID, id string) (*SubscriptionDTO, error) {
+	sub, err := s.subscriptionRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub.UserID != userID {
+		return nil, subscription.ErrSubscriptionNotFound
+	}
+
+	return domainSubscriptionToDTO(sub), nil
+}
@@ -0,0 +1,135 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/subscription"
+	"github.com/oklog/ulid/v2"
+)
+
+// RenewDueSubscriptions 处理到期的订阅（命令），返回续费成功的数量
+// 每个周期生成一笔订单并扣款；扣款失败按重试计划催缴，重试用尽后终止；申请了期末取消的订阅到期终止。
+// 单个订阅处理失败不影响其余订阅，错误合并后随返回值报告
+func (s *Service) RenewDueSubscriptions(ctx context.Context, batchSize int) (int, error) {
+	if batchSize <= 0 {
+		return 0, fmt.Errorf("invalid renewal batch size: %d", batchSize)
+	}
+
+	now := time.Now()
+	total := 0
+	afterID := ""
+	var renewErrs []error
+
+	for {
+		subscriptions, err := s.subscriptionRepo.ListDue(ctx, now, afterID, batchSize)
+		if err != nil {
+			return total, err
+		}
+
+		for _, sub := range subscriptions {
+			afterID = sub.ID
+			renewed, err := s.renew(ctx, sub.ID, now)
+			if err != nil {
+				renewErrs = append(renewErrs, fmt.Errorf("renew subscription %s: %w", sub.ID, err))
+				continue
+			}
+			if renewed {
+				total++
+			}
+		}
+
+		if len(subscriptions) < batchSize || ctx.Err() != nil {
+			break
+		}
+	}
+
+	return total, errors.Join(renewErrs...)
+}
+
+// renew 锁定并处理单个到期订阅，已被其他 worker 处理的订阅跳过，返回是否续费成功
+// 扣款前提交本周期的续费记录并以其预分配的订单扣款：扣款后订阅更新失败时，下次执行沿用同一订单，
+// 已扣款的周期不会再次扣款；网关结果未知时订阅保持不变，等待回调或下次执行
+func (s *Service) renew(ctx context.Context, id string, now time.Time) (bool, error) {
+	renewed := false
+	err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		sub, err := s.subscriptionRepo.LockDue(txCtx, id, now)
+		if err != nil || sub == nil {
+			return err
+		}
+
+		if sub.ShouldEnd(now) {
+			if err := sub.End(now); err != nil {
+				return err
+			}
+			return s.subscriptionRepo.Update(txCtx, sub)
+		}
+
+		plan, err := s.planRepo.FindByID(txCtx, sub.PlanID)
+		if err != nil {
+			return err
+		}
+
+		// 续费记录与扣款使用不含事务的 ctx：二者在各自的事务中提交，不随本事务回滚；订阅行锁保持到本事务结束
+		renewal, err := s.prepareRenewal(ctx, sub, now)
+		if err != nil {
+			return err
+		}
+
+		charge := recurringCharge(sub, plan, plan.Name, plan.Price)
+		charge.OrderID = renewal.OrderID
+		orderID, err := s.billing.ChargeRecurring(ctx, charge)
+		switch {
+		case errors.Is(err, order.ErrPaymentPending):
+			return nil
+		case errors.Is(err, order.ErrPaymentFailed):
+			sub.RecordPaymentFailure(s.retrySchedule, now)
+		case err != nil:
+			return err
+		default:
+			renewal.MarkCharged(now)
+			if err := s.renewalRepo.Update(txCtx, renewal); err != nil {
+				return err
+			}
+			sub.RecordCharge(orderID, charge.Credit, now)
+			if err := sub.Renew(plan, now); err != nil {
+				return err
+			}
+			renewed = true
+		}
+		return s.subscriptionRepo.Update(txCtx, sub)
+	})
+	return renewed && err == nil, err
+}
+
+// prepareRenewal 查找或创建订阅即将开始的周期的续费记录，上次尝试的扣款失败已记入催缴时为重试分配新订单
+func (s *Service) prepareRenewal(ctx context.Context, sub *subscription.Subscription, now time.Time) (*subscription.Renewal, error) {
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
+
+	renewal, err := s.renewalRepo.FindByPeriod(ctx, sub.ID, sub.CurrentPeriodEnd)
+	if errors.Is(err, subscription.ErrRenewalNotFound) {
+		renewal, err = subscription.NewRenewal(sub, ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String(), now)
+		if err != nil {
+			return nil, err
+		}
+		renewal.ID = ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
+		return renewal, s.renewalRepo.Create(ctx, renewal)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if renewal.NeedsNewAttempt(sub) {
+		if err := renewal.Retry(sub, ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String(), now); err != nil {
+			return nil, err
+		}
+		if err := s.renewalRepo.Update(ctx, renewal); err != nil {
+			return nil, err
+		}
+	}
+	return renewal, nil
+}
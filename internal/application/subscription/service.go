@@ -0,0 +1,114 @@
+package subscription
+
+import (
+	"context"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/subscription"
+)
+
+// RecurringBilling 周期扣款接口（端口），由订单应用服务实现
+type RecurringBilling interface {
+	// ChargeRecurring 生成订单并以保存的支付方式离线扣款，返回订单ID；
+	// 扣款未成功时订单已取消并返回 order.ErrPaymentFailed；
+	// 指定 charge.OrderID 时以该ID续上已有订单，结果未知时订单保持待支付并返回 order.ErrPaymentPending
+	ChargeRecurring(ctx context.Context, charge order.RecurringCharge) (string, error)
+}
+
+// TxManager 事务管理接口（端口）
+type TxManager interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Service 订阅应用服务
+type Service struct {
+	subscriptionRepo    subscription.Repository
+	planRepo            subscription.PlanRepository
+	renewalRepo         subscription.RenewalRepository
+	subscriptionService *subscription.Service
+	retrySchedule       subscription.RetrySchedule
+
+	billing   RecurringBilling
+	txManager TxManager
+}
+
+// NewService 创建订阅应用服务
+func NewService(
+	subscriptionRepo subscription.Repository,
+	planRepo subscription.PlanRepository,
+	renewalRepo subscription.RenewalRepository,
+	subscriptionService *subscription.Service,
+	retrySchedule subscription.RetrySchedule,
+	billing RecurringBilling,
+	txManager TxManager,
+) *Service {
+	return &Service{
+		subscriptionRepo:    subscriptionRepo,
+		planRepo:            planRepo,
+		renewalRepo:         renewalRepo,
+		subscriptionService: subscriptionService,
+		retrySchedule:       retrySchedule,
+		billing:             billing,
+		txManager:           txManager,
+	}
+}
+
+// recurringCharge 按订阅保存的支付方式构造扣款，可用余额优先抵扣
+func recurringCharge(sub *subscription.Subscription, plan *subscription.Plan, productName string, amount order.Money) order.RecurringCharge {
+	return order.RecurringCharge{
+		UserID:      sub.UserID,
+		ProductID:   plan.ProductID,
+		ProductName: productName,
+		Category:    plan.Category,
+		Amount:      amount,
+		Credit:      sub.CreditFor(amount),
+		Method:      sub.PaymentMethod,
+		Token:       sub.PaymentToken,
+	}
+}
+
+// domainPlanToDTO 转换订阅计划为DTO
+func domainPlanToDTO(p *subscription.Plan) *PlanDTO {
+	return &PlanDTO{
+		ID:            p.ID,
+		Code:          p.Code,
+		Name:          p.Name,
+		Description:   p.Description,
+		ProductID:     p.ProductID,
+		Category:      p.Category,
+		Price:         p.Price.Amount,
+		Currency:      p.Price.Currency,
+		Interval:      string(p.Interval),
+		IntervalCount: p.IntervalCount,
+		TrialDays:     p.TrialDays,
+		IsActive:      p.IsActive,
+		Version:       p.Version,
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
+	}
+}
+
+// domainSubscriptionToDTO 转换订阅为DTO（不含支付方式令牌）
+func domainSubscriptionToDTO(s *subscription.Subscription) *SubscriptionDTO {
+	return &SubscriptionDTO{
+		ID:                 s.ID,
+		UserID:             s.UserID,
+		PlanID:             s.PlanID,
+		Status:             string(s.Status),
+		PaymentMethod:      string(s.PaymentMethod),
+		CurrentPeriodStart: s.CurrentPeriodStart,
+		CurrentPeriodEnd:   s.CurrentPeriodEnd,
+		TrialEnd:           s.TrialEnd,
+		CancelAtPeriodEnd:  s.CancelAtPeriodEnd,
+		CanceledAt:         s.CanceledAt,
+		EndedAt:            s.EndedAt,
+		Credit:             s.Credit.Amount,
+		Currency:           s.Credit.Currency,
+		FailedAttempts:     s.FailedAttempts,
+		NextRetryAt:        s.NextRetryAt,
+		LastOrderID:        s.LastOrderID,
+		Version:            s.Version,
+		CreatedAt:          s.CreatedAt,
+		UpdatedAt:          s.UpdatedAt,
+	}
+}
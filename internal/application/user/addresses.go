@@ -0,0 +1,154 @@
+package user
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/user"
+	"github.com/oklog/ulid/v2"
+)
+
+// CreateAddress 新增地址簿地址（命令），用户的第一个地址同时作为默认收货与账单地址
+func (s *Service) CreateAddress(ctx context.Context, userID string, req SaveAddressRequest) (*AddressDTO, error) {
+	address, err := order.NewAddress(req.Street, req.City, req.State, req.PostalCode, req.Country)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := user.NewSavedAddress(userID, req.Label, address)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.addressRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) == 0 {
+		a.SetDefaults(true, true)
+	} else {
+		a.SetDefaults(req.IsDefaultShipping, req.IsDefaultBilling)
+	}
+
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
+	a.ID = ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
+
+	if err := s.addressRepo.Create(ctx, a); err != nil {
+		return nil, err
+	}
+	if err := s.addressRepo.ClearDefaults(ctx, userID, a.ID, a.IsDefaultShipping, a.IsDefaultBilling); err != nil {
+		return nil, err
+	}
+
+	return savedAddressToDTO(a), nil
+}
+
+// UpdateAddress 修改地址簿地址（命令），已下单的订单保留下单时的地址快照
+func (s *Service) UpdateAddress(ctx context.Context, userID, addressID string, req SaveAddressRequest) (*AddressDTO, error) {
+	a, err := s.findAddress(ctx, userID, addressID)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.CheckVersion(req.ExpectedVersion); err != nil {
+		return nil, err
+	}
+
+	address, err := order.NewAddress(req.Street, req.City, req.State, req.PostalCode, req.Country)
+	if err != nil {
+		return nil, err
+	}
+	a.Update(req.Label, address)
+	a.SetDefaults(req.IsDefaultShipping, req.IsDefaultBilling)
+
+	if err := s.addressRepo.Update(ctx, a); err != nil {
+		return nil, err
+	}
+	if err := s.addressRepo.ClearDefaults(ctx, userID, a.ID, a.IsDefaultShipping, a.IsDefaultBilling); err != nil {
+		return nil, err
+	}
+
+	return savedAddressToDTO(a), nil
+}
+
+// DeleteAddress 删除地址簿地址（命令）
+func (s *Service) DeleteAddress(ctx context.Context, userID, addressID string) error {
+	if _, err := s.findAddress(ctx, userID, addressID); err != nil {
+		return err
+	}
+	return s.addressRepo.Delete(ctx, addressID)
+}
+
+// ListAddresses 列出用户的地址簿（查询）
+func (s *Service) ListAddresses(ctx context.Context, userID string) ([]*AddressDTO, error) {
+	addresses, err := s.addressRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*AddressDTO, len(addresses))
+	for i, a := range addresses {
+		dtos[i] = savedAddressToDTO(a)
+	}
+	return dtos, nil
+}
+
+// FindAddress 查找用户保存的地址，供下单与发货引用
+func (s *Service) FindAddress(ctx context.Context, userID, addressID string) (order.Address, error) {
+	a, err := s.findAddress(ctx, userID, addressID)
+	if err != nil {
+		return order.Address{}, err
+	}
+	return a.Address, nil
+}
+
+// DefaultAddresses 返回用户的默认收货与账单地址，未设置时为 nil
+func (s *Service) DefaultAddresses(ctx context.Context, userID string) (shipping, billing *order.Address, err error) {
+	addresses, err := s.addressRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, a := range addresses {
+		if a.IsDefaultShipping && shipping == nil {
+			address := a.Address
+			shipping = &address
+		}
+		if a.IsDefaultBilling && billing == nil {
+			address := a.Address
+			billing = &address
+		}
+	}
+	return shipping, billing, nil
+}
+
+// findAddress 查找属于该用户的地址，他人的地址视为不存在
+func (s *Service) findAddress(ctx context.Context, userID, addressID string) (*user.SavedAddress, error) {
+	a, err := s.addressRepo.FindByID(ctx, addressID)
+	if err != nil {
+		return nil, err
+	}
+	if a.UserID != userID {
+		return nil, user.ErrAddressNotFound
+	}
+	return a, nil
+}
+
+// savedAddressToDTO 转换地址簿地址为DTO
+func savedAddressToDTO(a *user.SavedAddress) *AddressDTO {
+	return &AddressDTO{
+		ID:                a.ID,
+		Label:             a.Label,
+		Street:            a.Address.Street,
+		City:              a.Address.City,
+		State:             a.Address.State,
+		PostalCode:        a.Address.PostalCode,
+		Country:           a.Address.Country,
+		IsDefaultShipping: a.IsDefaultShipping,
+		IsDefaultBilling:  a.IsDefaultBilling,
+		Version:           a.Version,
+		CreatedAt:         a.CreatedAt,
+		UpdatedAt:         a.UpdatedAt,
+	}
+}
@@ -52,6 +52,9 @@ func (s *Service) UpdateUser(ctx context.Context, userID string, req UpdateUserR
 	if err != nil {
 		return nil, err
 	}
+	if err := u.CheckVersion(req.ExpectedVersion); err != nil {
+		return nil, err
+	}
 
 	// 更新用户信息
 	if err := u.UpdateProfile(req.Username); err != nil {
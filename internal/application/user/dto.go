@@ -8,6 +8,7 @@ type UserDTO struct {
 	Email     string    `json:"email"`
 	Username  string    `json:"username"`
 	IsActive  bool      `json:"is_active"`
+	Version   int       `json:"version"` // 乐观锁版本号，与响应头 ETag 一致
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -21,7 +22,8 @@ type CreateUserRequest struct {
 
 // UpdateUserRequest 更新用户请求
 type UpdateUserRequest struct {
-	Username string `json:"username" validate:"required,min=3,max=50"`
+	Username        string `json:"username" validate:"required,min=3,max=50"`
+	ExpectedVersion int    `json:"-"` // 请求头 If-Match 中的版本号，0 表示不校验
 }
 
 // ChangePasswordRequest 修改密码请求
@@ -44,3 +46,32 @@ type ListUsersResponse struct {
 	PageSize   int        `json:"page_size"`
 	TotalPages int        `json:"total_pages"`
 }
+
+// AddressDTO 地址簿地址DTO
+type AddressDTO struct {
+	ID                string    `json:"id"`
+	Label             string    `json:"label,omitempty"`
+	Street            string    `json:"street"`
+	City              string    `json:"city"`
+	State             string    `json:"state"`
+	PostalCode        string    `json:"postal_code"`
+	Country           string    `json:"country"`
+	IsDefaultShipping bool      `json:"is_default_shipping"`
+	IsDefaultBilling  bool      `json:"is_default_billing"`
+	Version           int       `json:"version"` // 乐观锁版本号，与响应头 ETag 一致
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// SaveAddressRequest 新增或修改地址请求，国家为 ISO 3166-1 两位代码
+type SaveAddressRequest struct {
+	Label             string `json:"label" binding:"max=100"`
+	Street            string `json:"street" binding:"required"`
+	City              string `json:"city" binding:"required"`
+	State             string `json:"state"`
+	PostalCode        string `json:"postal_code"`
+	Country           string `json:"country" binding:"required,len=2"`
+	IsDefaultShipping bool   `json:"is_default_shipping"`
+	IsDefaultBilling  bool   `json:"is_default_billing"`
+	ExpectedVersion   int    `json:"-"` // 修改时请求头 If-Match 中的版本号，0 表示不校验
+}
@@ -2,6 +2,7 @@ package user
 
 import (
 	"context"
+	"time"
 
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/user"
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/shared/pagination"
@@ -70,3 +71,13 @@ func (s *Service) UserExists(ctx context.Context, email string) (bool, error) {
 
 	return s.userRepo.ExistsByEmail(ctx, e)
 }
+
+// AccountCreatedAt 返回用户账户的注册时间（查询）
+func (s *Service) AccountCreatedAt(ctx context.Context, userID string) (time.Time, error) {
+	u, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return u.CreatedAt, nil
+}
@@ -13,14 +13,16 @@ type PasswordHasher interface {
 // Service 用户应用服务
 type Service struct {
 	userRepo       user.Repository
+	addressRepo    user.AddressRepository
 	userService    *user.Service
 	passwordHasher PasswordHasher
 }
 
 // NewService 创建用户应用服务
-func NewService(userRepo user.Repository, userService *user.Service, passwordHasher PasswordHasher) *Service {
+func NewService(userRepo user.Repository, addressRepo user.AddressRepository, userService *user.Service, passwordHasher PasswordHasher) *Service {
 	return &Service{
 		userRepo:       userRepo,
+		addressRepo:    addressRepo,
 		userService:    userService,
 		passwordHasher: passwordHasher,
 	}
@@ -33,6 +35,7 @@ func domainToDTO(u *user.User) *UserDTO {
 		Email:     u.Email.String(),
 		Username:  u.Username,
 		IsActive:  u.IsActive,
+		Version:   u.Version,
 		CreatedAt: u.CreatedAt,
 		UpdatedAt: u.UpdatedAt,
 	}
@@ -7,26 +7,48 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http"
 	authhandler "github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/handler/auth"
+	documenthandler "github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/handler/document"
+	exporthandler "github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/handler/export"
+	invoicehandler "github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/handler/invoice"
 	orderhandler "github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/handler/order"
+	promotionhandler "github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/handler/promotion"
 	rbachandler "github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/handler/rbac"
+	reporthandler "github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/handler/report"
+	subscriptionhandler "github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/handler/subscription"
 	userhandler "github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/handler/user"
+	webhookhandler "github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/handler/webhook"
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/adapters/http/middleware"
 	appauth "github.com/lwmacct/251112-go-ddd-skeleton/internal/application/auth"
+	appdocument "github.com/lwmacct/251112-go-ddd-skeleton/internal/application/document"
+	appexport "github.com/lwmacct/251112-go-ddd-skeleton/internal/application/export"
+	appinvoice "github.com/lwmacct/251112-go-ddd-skeleton/internal/application/invoice"
 	appmenu "github.com/lwmacct/251112-go-ddd-skeleton/internal/application/menu"
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/application/order"
+	apppromotion "github.com/lwmacct/251112-go-ddd-skeleton/internal/application/promotion"
+	appreport "github.com/lwmacct/251112-go-ddd-skeleton/internal/application/report"
 	approle "github.com/lwmacct/251112-go-ddd-skeleton/internal/application/role"
+	appsubscription "github.com/lwmacct/251112-go-ddd-skeleton/internal/application/subscription"
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/application/user"
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/config"
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/auth"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/invoice"
 	domainorder "github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/promotion"
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/rbac"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/subscription"
 	domainuser "github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/user"
 	infraauth "github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/auth"
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/cache"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/document"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/email"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/export"
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/logger"
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/payment"
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/persistence"
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/persistence/repository"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/shipping"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/storage"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/tax"
 )
 
 // Container 依赖注入容器
@@ -34,6 +56,12 @@ type Container struct {
 	Config *config.Config
 	Router *gin.Engine
 	Logger *logger.ZapLogger
+
+	// OrderService、ExportService、SubscriptionService、ReportService 供 worker、导出命令等非 HTTP 入口复用
+	OrderService        *order.Service
+	ExportService       *appexport.Service
+	SubscriptionService *appsubscription.Service
+	ReportService       *appreport.Service
 }
 
 // NewContainer 创建依赖注入容器
@@ -61,7 +89,7 @@ func NewContainer(cfg *config.Config) (*Container, error) {
 	}
 
 	// Redis
-	_, err = cache.NewRedis(cache.Config{
+	redisClient, err := cache.NewRedis(cache.Config{
 		Host:     cfg.Redis.Host,
 		Port:     cfg.Redis.Port,
 		Password: cfg.Redis.Password,
@@ -73,12 +101,36 @@ func NewContainer(cfg *config.Config) (*Container, error) {
 
 	// 2. 初始化仓储
 	userRepo := repository.NewUserRepository(db)
+	addressRepo := repository.NewAddressRepository(db)
 	tfRepo := repository.NewTwoFactorRepository(db)
 	patRepo := repository.NewPATRepository(db)
 	sessionRepo := repository.NewSessionRepository(db)
 	orderRepo := repository.NewOrderRepository(db)
 	paymentRepo := repository.NewPaymentRepository(db)
 	shipmentRepo := repository.NewShipmentRepository(db)
+	promotionRepo := repository.NewPromotionRepository(db)
+	redemptionRepo := repository.NewRedemptionRepository(db)
+	invoiceRepo := repository.NewInvoiceRepository(db)
+	invoiceSequence := repository.NewInvoiceSequenceRepository(db)
+	gatewayEventRepo := repository.NewGatewayEventRepository(db)
+	refundRepo := repository.NewRefundRepository(db)
+	returnRepo := repository.NewReturnRepository(db)
+	fraudRepo := repository.NewFraudAssessmentRepository(db)
+	exportJobRepo := repository.NewExportJobRepository(db)
+	subscriptionRepo := repository.NewSubscriptionRepository(db)
+	renewalRepo := repository.NewRenewalRepository(db)
+	reportRepo := repository.NewReportRepository(db)
+	planRepo := repository.NewPlanRepository(db)
+	orderNumberFormat := domainorder.OrderNumberFormat{
+		Prefix:     cfg.Order.Number.Prefix,
+		DateLayout: cfg.Order.Number.DateLayout,
+		Digits:     cfg.Order.Number.Digits,
+		CheckDigit: cfg.Order.Number.CheckDigit,
+	}
+	if err := orderNumberFormat.Check(); err != nil {
+		return nil, fmt.Errorf("invalid order.number config: %w", err)
+	}
+	orderNumbers := repository.NewOrderNumberGenerator(db, orderNumberFormat)
 	// RBAC仓储
 	roleRepo := repository.NewRoleRepo(db)
 	permissionRepo := repository.NewPermissionRepo(db)
@@ -87,8 +139,11 @@ func NewContainer(cfg *config.Config) (*Container, error) {
 	// 3. 初始化领域服务
 	userDomainService := domainuser.NewService(userRepo)
 	authDomainService := auth.NewService(tfRepo, patRepo, sessionRepo)
-	orderDomainService := domainorder.NewService(orderRepo, paymentRepo, shipmentRepo)
+	orderDomainService := domainorder.NewService(orderRepo, paymentRepo, shipmentRepo, refundRepo, returnRepo, fraudRepo, orderNumbers, orderNumberFormat)
 	rbacDomainService := rbac.NewService(roleRepo, permissionRepo, menuRepo)
+	promotionDomainService := promotion.NewService(promotionRepo, redemptionRepo)
+	invoiceDomainService := invoice.NewService(invoiceRepo, invoiceSequence)
+	subscriptionDomainService := subscription.NewService(subscriptionRepo, planRepo)
 
 	// 4. 初始化基础设施服务（端口实现）
 	passwordHasher := infraauth.NewPasswordHasher()
@@ -98,21 +153,110 @@ func NewContainer(cfg *config.Config) (*Container, error) {
 		cfg.JWT.RefreshTokenExpiry*time.Hour*24,
 	)
 	totpGenerator := infraauth.NewTOTPGenerator(cfg.App.Name)
-	// emailSender := email.NewSMTPSender(email.Config{
-	// 	Host:     cfg.Email.SMTPHost,
-	// 	Port:     cfg.Email.SMTPPort,
-	// 	Username: cfg.Email.SMTPUsername,
-	// 	Password: cfg.Email.SMTPPassword,
-	// 	From:     cfg.Email.SMTPFrom,
-	// })
-	paymentGateway := payment.NewStripeGateway(cfg.Payment.StripeSecretKey)
+	emailSender := email.NewSMTPSender(email.Config{
+		Host:     cfg.Email.SMTPHost,
+		Port:     cfg.Email.SMTPPort,
+		Username: cfg.Email.SMTPUsername,
+		Password: cfg.Email.SMTPPassword,
+		From:     cfg.Email.SMTPFrom,
+	})
+	orderNotifier := email.NewOrderNotifier(emailSender, userRepo)
+	stripeGateway := payment.NewStripeGateway(payment.StripeConfig{
+		SecretKey:     cfg.Payment.StripeSecretKey,
+		BaseURL:       cfg.Payment.StripeBaseURL,
+		CaptureMethod: cfg.Payment.StripeCaptureMethod,
+		Timeout:       cfg.Payment.GatewayTimeout,
+	})
+	fakeGateway, err := payment.NewFakeGateway(cfg.Payment.FakeOutcomes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fake payment gateway: %w", err)
+	}
+	gatewayProviders := map[string]order.PaymentGateway{
+		"stripe": stripeGateway,
+		"manual": payment.NewManualGateway(),
+		"fake":   fakeGateway,
+	}
+	paymentGateways := order.NewGatewayRegistry()
+	for method, provider := range cfg.Payment.Gateways {
+		gateway, ok := gatewayProviders[provider]
+		if !ok {
+			return nil, fmt.Errorf("unknown payment gateway %q for method %q", provider, method)
+		}
+		if !domainorder.PaymentMethod(method).IsValid() {
+			return nil, fmt.Errorf("unknown payment method %q", method)
+		}
+		paymentGateways.Register(domainorder.PaymentMethod(method), gateway)
+	}
+	webhookVerifier := payment.NewStripeWebhookVerifier(cfg.Payment.StripeWebhookSecret, cfg.Payment.WebhookTolerance)
+	txManager := persistence.NewTxManager(db)
+	taxRules := make([]tax.Rule, len(cfg.Tax.Rules))
+	for i, rule := range cfg.Tax.Rules {
+		taxRules[i] = tax.Rule{
+			Country:   rule.Country,
+			State:     rule.State,
+			Name:      rule.Name,
+			Rate:      rule.Rate,
+			Inclusive: rule.Inclusive,
+		}
+	}
+	taxCalculator := tax.NewRulesTable(tax.Config{
+		OriginCountry:          cfg.Tax.OriginCountry,
+		PricesIncludeTax:       cfg.Tax.PricesIncludeTax,
+		ReverseChargeCountries: cfg.Tax.ReverseChargeCountries,
+		Rules:                  taxRules,
+	})
+	shippingZones := make([]shipping.Zone, len(cfg.Shipping.Zones))
+	for i, zone := range cfg.Shipping.Zones {
+		shippingZones[i] = shipping.Zone{Name: zone.Name, Countries: zone.Countries}
+	}
+	shippingServices := make([]shipping.Service, len(cfg.Shipping.Services))
+	for i, svc := range cfg.Shipping.Services {
+		bands := make([]shipping.WeightBand, len(svc.Bands))
+		for j, band := range svc.Bands {
+			bands[j] = shipping.WeightBand{MaxWeightKg: band.MaxWeightKg, Amount: band.Amount}
+		}
+		shippingServices[i] = shipping.Service{
+			Code:  svc.Code,
+			Name:  svc.Name,
+			Zone:  svc.Zone,
+			Days:  svc.Days,
+			Bands: bands,
+		}
+	}
+	carrier := shipping.NewRateTable(shipping.Config{
+		Carrier:        cfg.Shipping.Carrier,
+		Currency:       cfg.Shipping.Currency,
+		TrackingPrefix: cfg.Shipping.TrackingPrefix,
+		Zones:          shippingZones,
+		Services:       shippingServices,
+	})
+	documentRenderer, err := document.NewPDFRenderer(document.Branding{
+		CompanyName:     cfg.Document.CompanyName,
+		AddressLines:    cfg.Document.AddressLines,
+		Email:           cfg.Document.Email,
+		Website:         cfg.Document.Website,
+		VATID:           cfg.Document.VATID,
+		AccentColor:     cfg.Document.AccentColor,
+		FooterTemplate:  cfg.Document.FooterTemplate,
+		DefaultLanguage: cfg.Document.DefaultLanguage,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create document renderer: %w", err)
+	}
+	blobStore, err := storage.NewLocalBlobStore(cfg.Storage.LocalPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create blob store: %w", err)
+	}
+
+	roleChecker := middleware.NewRBACRoleChecker(rbacDomainService)
 
 	// 设置中间件依赖
 	middleware.SetTokenValidator(jwtIssuer)
-	middleware.SetRoleChecker(middleware.NewRBACRoleChecker(rbacDomainService))
+	middleware.SetRoleChecker(roleChecker)
+	middleware.SetIdempotencyStore(cache.NewIdempotencyStore(redisClient), cfg.Idempotency.TTL, cfg.Idempotency.LockTimeout)
 
 	// 5. 初始化应用服务
-	userService := user.NewService(userRepo, userDomainService, passwordHasher)
+	userService := user.NewService(userRepo, addressRepo, userDomainService, passwordHasher)
 	authService := appauth.NewService(
 		userRepo,
 		tfRepo,
@@ -123,13 +267,73 @@ func NewContainer(cfg *config.Config) (*Container, error) {
 		passwordHasher,
 		totpGenerator,
 	)
+	promotionService := apppromotion.NewService(promotionRepo, redemptionRepo, promotionDomainService)
+	invoiceService := appinvoice.NewService(invoiceRepo, invoiceDomainService)
 	orderService := order.NewService(
 		orderRepo,
 		paymentRepo,
 		shipmentRepo,
+		refundRepo,
+		returnRepo,
+		fraudRepo,
 		orderDomainService,
-		paymentGateway,
+		gatewayEventRepo,
+		domainorder.ReturnPolicy{
+			DefaultWindow:   cfg.Returns.Window,
+			CategoryWindows: cfg.Returns.CategoryWindows,
+		},
+		domainorder.FraudPolicy{
+			ReviewScore:          cfg.Fraud.ReviewScore,
+			VelocityWindow:       cfg.Fraud.VelocityWindow,
+			UserVelocityLimit:    cfg.Fraud.UserVelocityLimit,
+			UserVelocityScore:    cfg.Fraud.UserVelocityScore,
+			IPVelocityLimit:      cfg.Fraud.IPVelocityLimit,
+			IPVelocityScore:      cfg.Fraud.IPVelocityScore,
+			HighAmount:           cfg.Fraud.HighAmount,
+			HighAmountScore:      cfg.Fraud.HighAmountScore,
+			CountryMismatchScore: cfg.Fraud.CountryMismatchScore,
+			NewAccountAge:        cfg.Fraud.NewAccountAge,
+			NewAccountAmount:     cfg.Fraud.NewAccountAmount,
+			NewAccountScore:      cfg.Fraud.NewAccountScore,
+		},
+		paymentGateways,
+		webhookVerifier,
+		promotionService,
+		taxCalculator,
+		carrier,
+		invoiceService,
+		orderNotifier,
+		userService,
+		userService,
+		roleChecker,
+		txManager,
 	)
+	exportService := appexport.NewService(
+		orderRepo,
+		paymentRepo,
+		refundRepo,
+		shipmentRepo,
+		exportJobRepo,
+		appexport.Options{
+			BatchSize:      cfg.Export.BatchSize,
+			AsyncThreshold: cfg.Export.AsyncThreshold,
+			JobTimeout:     cfg.Export.JobTimeout,
+		},
+		export.NewEncoder(),
+		blobStore,
+		txManager,
+	)
+	subscriptionService := appsubscription.NewService(
+		subscriptionRepo,
+		planRepo,
+		renewalRepo,
+		subscriptionDomainService,
+		subscription.RetrySchedule(cfg.Subscription.RetrySchedule),
+		orderService,
+		txManager,
+	)
+	documentService := appdocument.NewService(orderRepo, shipmentRepo, invoiceRepo, documentRenderer, blobStore)
+	reportService := appreport.NewService(reportRepo, cache.NewReportCache(redisClient), cfg.Report.CacheTTL)
 	// RBAC应用服务
 	menuService := appmenu.NewService(rbacDomainService, menuRepo)
 	roleService := approle.NewService(roleRepo, permissionRepo, rbacDomainService)
@@ -138,15 +342,27 @@ func NewContainer(cfg *config.Config) (*Container, error) {
 	userHandler := userhandler.NewHandler(userService)
 	authHandler := authhandler.NewHandler(authService)
 	orderHandler := orderhandler.NewHandler(orderService)
+	exportHandler := exporthandler.NewHandler(exportService)
 	menuHandler := rbachandler.NewMenuHandler(menuService)
 	roleHandler := rbachandler.NewRoleHandler(roleService)
+	promotionHandler := promotionhandler.NewHandler(promotionService)
+	subscriptionHandler := subscriptionhandler.NewHandler(subscriptionService)
+	invoiceHandler := invoicehandler.NewHandler(invoiceService)
+	documentHandler := documenthandler.NewHandler(documentService)
+	reportHandler := reporthandler.NewHandler(reportService)
+	webhookHandler := webhookhandler.NewHandler(orderService)
 
 	// 7. 初始化路由
-	router := http.SetupRouter(userHandler, authHandler, orderHandler, menuHandler, roleHandler)
+	router := http.SetupRouter(userHandler, authHandler, orderHandler, exportHandler, menuHandler, roleHandler, promotionHandler, subscriptionHandler, invoiceHandler, documentHandler, reportHandler, webhookHandler)
 
 	return &Container{
 		Config: cfg,
 		Router: router,
 		Logger: log,
+
+		OrderService:        orderService,
+		ExportService:       exportService,
+		SubscriptionService: subscriptionService,
+		ReportService:       reportService,
 	}, nil
 }
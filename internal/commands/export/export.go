@@ -0,0 +1,99 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	appexport "github.com/lwmacct/251112-go-ddd-skeleton/internal/application/export"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/application/order"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/bootstrap"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/config"
+	"github.com/urfave/cli/v3"
+)
+
+// Command 定义数据导出命令
+var Command = &cli.Command{
+	Name:  "export",
+	Usage: "数据导出工具",
+	Commands: []*cli.Command{
+		{
+			Name:  "orders",
+			Usage: "导出订单（CSV / XLSX）",
+			Description: `
+   按与管理员订单搜索相同的条件导出订单，每个订单项一行，
+   包含订单、支付、退款与发货列。订单按批读取并流式写出。
+   示例：
+     go-ddd-skeleton export orders --format xlsx --status paid,completed --created-from 2026-01-01 -o orders.xlsx
+			`,
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "format", Aliases: []string{"f"}, Usage: "导出格式：csv 或 xlsx", Value: "csv"},
+				&cli.StringFlag{Name: "output", Aliases: []string{"o"}, Usage: "输出文件，- 表示标准输出", Value: "-"},
+				&cli.StringFlag{Name: "status", Usage: "订单状态，逗号分隔"},
+				&cli.StringFlag{Name: "user-id", Usage: "用户ID"},
+				&cli.StringFlag{Name: "created-from", Usage: "创建时间起（RFC3339 或 YYYY-MM-DD）"},
+				&cli.StringFlag{Name: "created-to", Usage: "创建时间止（RFC3339 不含，或 YYYY-MM-DD 含当天）"},
+				&cli.StringFlag{Name: "min-amount", Usage: "最小订单金额"},
+				&cli.StringFlag{Name: "max-amount", Usage: "最大订单金额"},
+				&cli.StringFlag{Name: "currency", Usage: "币种"},
+				&cli.StringFlag{Name: "payment-method", Usage: "支付方式"},
+				&cli.StringFlag{Name: "order-number", Usage: "订单号前缀"},
+				&cli.StringFlag{Name: "q", Usage: "全文检索：订单号、商品名称"},
+			},
+			Action: runExportOrders,
+		},
+	},
+}
+
+// runExportOrders 导出订单到文件或标准输出
+func runExportOrders(ctx context.Context, cmd *cli.Command) error {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	container, err := bootstrap.NewContainer(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize container: %v", err)
+	}
+
+	req := appexport.ExportOrdersRequest{
+		SearchOrdersRequest: order.SearchOrdersRequest{
+			Status:        cmd.String("status"),
+			UserID:        cmd.String("user-id"),
+			CreatedFrom:   cmd.String("created-from"),
+			CreatedTo:     cmd.String("created-to"),
+			MinAmount:     cmd.String("min-amount"),
+			MaxAmount:     cmd.String("max-amount"),
+			Currency:      cmd.String("currency"),
+			PaymentMethod: cmd.String("payment-method"),
+			OrderNumber:   cmd.String("order-number"),
+			Query:         cmd.String("q"),
+		},
+		Format: cmd.String("format"),
+	}
+
+	output := cmd.String("output")
+	var w io.Writer = os.Stdout
+	if output != "-" {
+		f, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	rows, err := container.ExportService.ExportOrders(ctx, req, w)
+	if err != nil {
+		if output != "-" {
+			os.Remove(output)
+		}
+		return fmt.Errorf("failed to export orders: %w", err)
+	}
+
+	log.Printf("Exported %d rows", rows)
+	return nil
+}
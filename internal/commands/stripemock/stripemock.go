@@ -0,0 +1,57 @@
+package stripemock
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/config"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/payment/stripemock"
+	"github.com/urfave/cli/v3"
+)
+
+// Command 定义 Stripe 模拟服务命令
+var Command = &cli.Command{
+	Name:  "stripe-mock",
+	Usage: "启动本地 Stripe 模拟服务",
+	Description: `
+   启动内存版 Stripe PaymentIntent API，用于本地调试支付流程。
+   将 payment.stripe_base_url 指向该服务地址即可。
+   支持 Stripe 测试令牌：pm_card_visa（成功）、pm_card_chargeDeclined（拒付）、
+   pm_card_threeDSecure2Required（需 3DS 验证，可调用 /v1/payment_intents/:id/confirm 模拟完成）。
+	`,
+	Action: runStripeMock,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "addr",
+			Usage: "监听地址",
+			Value: ":12111",
+		},
+		&cli.StringFlag{
+			Name:  "webhook-url",
+			Usage: "事件回调地址（为空则不投递回调）",
+			Value: "http://localhost:8080/api/webhooks/stripe",
+		},
+	},
+}
+
+// runStripeMock 启动模拟服务
+func runStripeMock(ctx context.Context, cmd *cli.Command) error {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	server := &http.Server{
+		Addr: cmd.String("addr"),
+		Handler: stripemock.NewServer(stripemock.Options{
+			WebhookURL:    cmd.String("webhook-url"),
+			WebhookSecret: cfg.Payment.StripeWebhookSecret,
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	log.Printf("Stripe mock listening on %s", server.Addr)
+	return server.ListenAndServe()
+}
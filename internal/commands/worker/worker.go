@@ -8,6 +8,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/bootstrap"
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/config"
 	"github.com/urfave/cli/v3"
 )
@@ -19,7 +20,14 @@ var Command = &cli.Command{
 	Usage:   "启动后台任务处理器",
 	Description: `
    启动后台 Worker 进程，用于处理异步任务。
-   例如：清理过期会话、发送通知邮件、处理订单状态等。
+   当前任务：
+     - 取消超过 order.pending_ttl 仍未支付的订单并邮件通知客户
+     - 以同一幂等键重试网关结果未知、待处理超过 order.refund_retry_after 的退款
+     - 从承运商同步运输中发货的跟踪轨迹，签收后完成订单
+     - 续费到期的订阅，扣款失败按重试计划催缴，期末取消的订阅到期终止
+     - 执行后台订单导出任务，生成的文件供管理员下载
+     - 增量刷新销售汇总表，数据变动后使报表缓存失效并预热默认报表
+   任务可在多个副本上并发执行。
 	`,
 	Action: runWorker,
 	Flags: []cli.Flag{
@@ -43,6 +51,12 @@ func runWorker(ctx context.Context, cmd *cli.Command) error {
 	interval := cmd.Duration("interval")
 	log.Printf("Starting worker in %s environment (interval: %v)...", cfg.App.Env, interval)
 
+	// 初始化容器（依赖注入），复用与 API 相同的应用服务
+	container, err := bootstrap.NewContainer(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize container: %v", err)
+	}
+
 	// 创建context用于优雅关闭
 	workerCtx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -55,12 +69,7 @@ func runWorker(ctx context.Context, cmd *cli.Command) error {
 		for {
 			select {
 			case <-ticker.C:
-				log.Println("Worker task executing...")
-				// TODO: 这里可以添加定期任务，例如：
-				// - 清理过期会话
-				// - 发送通知邮件
-				// - 处理订单状态
-				executeWorkerTasks(workerCtx, cfg)
+				executeWorkerTasks(workerCtx, cfg, container)
 			case <-workerCtx.Done():
 				log.Println("Worker stopping...")
 				return
@@ -84,13 +93,110 @@ func runWorker(ctx context.Context, cmd *cli.Command) error {
 }
 
 // executeWorkerTasks 执行具体的后台任务
-func executeWorkerTasks(ctx context.Context, cfg *config.Config) {
-	// 在这里实现具体的后台任务逻辑
-	// 例如：
-	// 1. 清理过期的会话和令牌
-	// 2. 发送待发送的邮件
-	// 3. 处理超时的订单
-	// 4. 生成统计报表
-
-	log.Println("Executing background tasks...")
+// 任务需可在多个 worker 副本上并发执行
+func executeWorkerTasks(ctx context.Context, cfg *config.Config, container *bootstrap.Container) {
+	cancelExpiredOrders(ctx, cfg, container)
+	retryPendingRefunds(ctx, cfg, container)
+	syncShipmentTracking(ctx, cfg, container)
+	renewSubscriptions(ctx, cfg, container)
+	runExportJobs(ctx, cfg, container)
+	refreshReports(ctx, cfg, container)
+}
+
+// cancelExpiredOrders 取消超时未支付的订单，order.pending_ttl 为 0 时不执行
+func cancelExpiredOrders(ctx context.Context, cfg *config.Config, container *bootstrap.Container) {
+	if cfg.Order.PendingTTL <= 0 {
+		return
+	}
+	batchSize := cfg.Order.CancelBatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	cancelled, err := container.OrderService.CancelExpiredOrders(ctx, cfg.Order.PendingTTL, batchSize)
+	if cancelled > 0 {
+		log.Printf("Cancelled %d expired orders", cancelled)
+	}
+	if err != nil {
+		log.Printf("Failed to cancel expired orders: %v", err)
+	}
+}
+
+// retryPendingRefunds 重试网关结果未知的退款，order.refund_retry_after 为 0 时不执行
+func retryPendingRefunds(ctx context.Context, cfg *config.Config, container *bootstrap.Container) {
+	if cfg.Order.RefundRetryAfter <= 0 {
+		return
+	}
+	batchSize := cfg.Order.CancelBatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	settled, err := container.OrderService.RetryPendingRefunds(ctx, cfg.Order.RefundRetryAfter, batchSize)
+	if settled > 0 {
+		log.Printf("Settled %d pending refunds", settled)
+	}
+	if err != nil {
+		log.Printf("Failed to retry pending refunds: %v", err)
+	}
+}
+
+// syncShipmentTracking 同步运输中发货的跟踪轨迹，shipping.tracking_batch_size 为 0 时不执行
+func syncShipmentTracking(ctx context.Context, cfg *config.Config, container *bootstrap.Container) {
+	if cfg.Shipping.TrackingBatchSize <= 0 {
+		return
+	}
+
+	updated, err := container.OrderService.SyncShippedShipments(ctx, cfg.Shipping.TrackingBatchSize)
+	if updated > 0 {
+		log.Printf("Synced tracking for %d shipments", updated)
+	}
+	if err != nil {
+		log.Printf("Failed to sync shipment tracking: %v", err)
+	}
+}
+
+// renewSubscriptions 续费到期的订阅，subscription.renewal_batch_size 为 0 时不执行
+func renewSubscriptions(ctx context.Context, cfg *config.Config, container *bootstrap.Container) {
+	if cfg.Subscription.RenewalBatchSize <= 0 {
+		return
+	}
+
+	renewed, err := container.SubscriptionService.RenewDueSubscriptions(ctx, cfg.Subscription.RenewalBatchSize)
+	if renewed > 0 {
+		log.Printf("Renewed %d subscriptions", renewed)
+	}
+	if err != nil {
+		log.Printf("Failed to renew subscriptions: %v", err)
+	}
+}
+
+// runExportJobs 执行后台订单导出任务，export.job_batch_size 为 0 时不执行
+func runExportJobs(ctx context.Context, cfg *config.Config, container *bootstrap.Container) {
+	if cfg.Export.JobBatchSize <= 0 {
+		return
+	}
+
+	completed, err := container.ExportService.RunExportJobs(ctx, cfg.Export.JobBatchSize)
+	if completed > 0 {
+		log.Printf("Completed %d export jobs", completed)
+	}
+	if err != nil {
+		log.Printf("Failed to run export jobs: %v", err)
+	}
+}
+
+// refreshReports 增量刷新销售汇总表，report.refresh_enabled 为 false 时不执行
+func refreshReports(ctx context.Context, cfg *config.Config, container *bootstrap.Container) {
+	if !cfg.Report.RefreshEnabled {
+		return
+	}
+
+	days, err := container.ReportService.RefreshReports(ctx)
+	if days > 0 {
+		log.Printf("Refreshed sales summaries for %d days", days)
+	}
+	if err != nil {
+		log.Printf("Failed to refresh reports: %v", err)
+	}
 }
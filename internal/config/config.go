@@ -9,13 +9,24 @@ import (
 
 // Config 应用配置
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
-	Email    EmailConfig
-	Payment  PaymentConfig
-	App      AppConfig
+	Server       ServerConfig
+	Database     DatabaseConfig
+	Redis        RedisConfig
+	JWT          JWTConfig
+	Email        EmailConfig
+	Payment      PaymentConfig
+	Order        OrderConfig
+	Tax          TaxConfig
+	Shipping     ShippingConfig
+	Returns      ReturnsConfig
+	Subscription SubscriptionConfig
+	Fraud        FraudConfig
+	Export       ExportConfig
+	Report       ReportConfig
+	Document     DocumentConfig
+	Storage      StorageConfig
+	Idempotency  IdempotencyConfig
+	App          AppConfig
 }
 
 // ServerConfig 服务器配置
@@ -45,9 +56,9 @@ type RedisConfig struct {
 
 // JWTConfig JWT配置
 type JWTConfig struct {
-	Secret               string
-	AccessTokenExpiry    time.Duration
-	RefreshTokenExpiry   time.Duration
+	Secret             string
+	AccessTokenExpiry  time.Duration
+	RefreshTokenExpiry time.Duration
 }
 
 // EmailConfig 邮件配置
@@ -63,6 +74,142 @@ type EmailConfig struct {
 type PaymentConfig struct {
 	StripeSecretKey      string
 	StripePublishableKey string
+	StripeBaseURL        string            // API 地址，可指向本地模拟服务
+	StripeCaptureMethod  string            // automatic 或 manual
+	GatewayTimeout       time.Duration     // 网关请求超时
+	Gateways             map[string]string // 支付方式 -> 网关（stripe、manual、fake）
+	FakeOutcomes         []string          // 假网关的脚本结果，依次循环
+	StripeWebhookSecret  string            // 回调签名密钥（whsec_...）
+	WebhookTolerance     time.Duration     // 回调时间戳允许偏差
+}
+
+// OrderConfig 订单配置
+type OrderConfig struct {
+	PendingTTL       time.Duration // 未支付订单的保留时长，超时由 worker 取消
+	CancelBatchSize  int           // 超时取消每批处理的订单数
+	RefundRetryAfter time.Duration // 网关结果未知的退款待处理超过该时长后由 worker 重试
+	Number           OrderNumberConfig
+}
+
+// OrderNumberConfig 订单号格式配置
+type OrderNumberConfig struct {
+	Prefix     string
+	DateLayout string // 日期段的 Go 时间格式，如 2006，为空时不含日期段
+	Digits     int    // 计数器补零位数
+	CheckDigit bool   // 是否追加 Luhn 校验位
+}
+
+// IdempotencyConfig 幂等键配置
+type IdempotencyConfig struct {
+	TTL         time.Duration // 响应保留时长
+	LockTimeout time.Duration // 处理中占用的最长时间
+}
+
+// TaxConfig 税务配置
+type TaxConfig struct {
+	OriginCountry          string
+	PricesIncludeTax       bool
+	ReverseChargeCountries []string
+	Rules                  []TaxRuleConfig
+}
+
+// TaxRuleConfig 税率规则配置
+type TaxRuleConfig struct {
+	Country   string  `mapstructure:"country"`
+	State     string  `mapstructure:"state"`
+	Name      string  `mapstructure:"name"`
+	Rate      float64 `mapstructure:"rate"`
+	Inclusive *bool   `mapstructure:"inclusive"`
+}
+
+// ShippingConfig 运费配置（离线运费表）
+type ShippingConfig struct {
+	Carrier           string
+	Currency          string
+	TrackingPrefix    string
+	TrackingBatchSize int // worker 每批同步跟踪轨迹的发货数，0 表示不同步
+	Zones             []ShippingZoneConfig
+	Services          []ShippingServiceConfig
+}
+
+// ShippingZoneConfig 运费区域配置
+type ShippingZoneConfig struct {
+	Name      string   `mapstructure:"name"`
+	Countries []string `mapstructure:"countries"`
+}
+
+// ShippingServiceConfig 运输服务在某一区域的费率配置
+type ShippingServiceConfig struct {
+	Code  string                     `mapstructure:"code"`
+	Name  string                     `mapstructure:"name"`
+	Zone  string                     `mapstructure:"zone"`
+	Days  int                        `mapstructure:"days"`
+	Bands []ShippingWeightBandConfig `mapstructure:"bands"`
+}
+
+// ShippingWeightBandConfig 重量档配置
+type ShippingWeightBandConfig struct {
+	MaxWeightKg float64 `mapstructure:"max_weight_kg"`
+	Amount      float64 `mapstructure:"amount"`
+}
+
+// ReturnsConfig 退货配置
+type ReturnsConfig struct {
+	Window          time.Duration            // 默认退货期限，自商品送达起算
+	CategoryWindows map[string]time.Duration // 按商品分类覆盖退货期限，0 表示该分类不可退货
+}
+
+// SubscriptionConfig 订阅配置
+type SubscriptionConfig struct {
+	RenewalBatchSize int             // worker 每批处理的到期订阅数，0 表示不续费
+	RetrySchedule    []time.Duration // 续费扣款失败后的重试间隔，依次使用，用尽后终止订阅
+}
+
+// FraudConfig 欺诈筛查配置，命中的规则累加分数，达到 ReviewScore 的订单转人工审核
+type FraudConfig struct {
+	ReviewScore          int           // 转人工审核的分数阈值，0 表示不筛查
+	VelocityWindow       time.Duration // 下单频率的统计窗口
+	UserVelocityLimit    int           // 窗口内同一用户允许的订单数
+	UserVelocityScore    int
+	IPVelocityLimit      int // 窗口内同一 IP 允许的订单数
+	IPVelocityScore      int
+	HighAmount           float64 // 大额订单阈值
+	HighAmountScore      int
+	CountryMismatchScore int           // 账单与收货国家不一致
+	NewAccountAge        time.Duration // 注册时长小于该值视为新账户
+	NewAccountAmount     float64       // 新账户大额订单阈值
+	NewAccountScore      int
+}
+
+// ExportConfig 订单导出配置
+type ExportConfig struct {
+	BatchSize      int           // 每批读取的订单数
+	AsyncThreshold int64         // 符合条件的订单数超过该值时转后台任务，0 表示总是同步导出
+	JobBatchSize   int           // worker 每个周期执行的导出任务数，0 表示不执行
+	JobTimeout     time.Duration // 任务开始后超过该时长仍未完成时可被重新领取
+}
+
+// ReportConfig 销售报表配置
+type ReportConfig struct {
+	RefreshEnabled bool          // worker 每个周期增量刷新销售汇总表
+	CacheTTL       time.Duration // 报表结果在 Redis 中的缓存时长，0 表示不缓存
+}
+
+// DocumentConfig 单据（发票/装箱单）品牌配置
+type DocumentConfig struct {
+	CompanyName     string
+	AddressLines    []string
+	Email           string
+	Website         string
+	VATID           string
+	AccentColor     string
+	FooterTemplate  string
+	DefaultLanguage string
+}
+
+// StorageConfig 对象存储配置
+type StorageConfig struct {
+	LocalPath string
 }
 
 // AppConfig 应用配置
@@ -123,6 +270,104 @@ func Load() (*Config, error) {
 	// Payment
 	cfg.Payment.StripeSecretKey = viper.GetString("payment.stripe_secret_key")
 	cfg.Payment.StripePublishableKey = viper.GetString("payment.stripe_publishable_key")
+	cfg.Payment.StripeBaseURL = viper.GetString("payment.stripe_base_url")
+	cfg.Payment.StripeCaptureMethod = viper.GetString("payment.stripe_capture_method")
+	cfg.Payment.GatewayTimeout = viper.GetDuration("payment.gateway_timeout")
+	cfg.Payment.Gateways = viper.GetStringMapString("payment.gateways")
+	cfg.Payment.FakeOutcomes = viper.GetStringSlice("payment.fake_outcomes")
+	cfg.Payment.StripeWebhookSecret = viper.GetString("payment.stripe_webhook_secret")
+	cfg.Payment.WebhookTolerance = viper.GetDuration("payment.webhook_tolerance")
+
+	// Order
+	cfg.Order.PendingTTL = viper.GetDuration("order.pending_ttl")
+	cfg.Order.CancelBatchSize = viper.GetInt("order.cancel_batch_size")
+	cfg.Order.RefundRetryAfter = viper.GetDuration("order.refund_retry_after")
+	cfg.Order.Number.Prefix = viper.GetString("order.number.prefix")
+	cfg.Order.Number.DateLayout = viper.GetString("order.number.date_layout")
+	cfg.Order.Number.Digits = viper.GetInt("order.number.digits")
+	cfg.Order.Number.CheckDigit = viper.GetBool("order.number.check_digit")
+
+	// Idempotency
+	cfg.Idempotency.TTL = viper.GetDuration("idempotency.ttl")
+	cfg.Idempotency.LockTimeout = viper.GetDuration("idempotency.lock_timeout")
+
+	// Tax
+	cfg.Tax.OriginCountry = viper.GetString("tax.origin_country")
+	cfg.Tax.PricesIncludeTax = viper.GetBool("tax.prices_include_tax")
+	cfg.Tax.ReverseChargeCountries = viper.GetStringSlice("tax.reverse_charge_countries")
+	if err := viper.UnmarshalKey("tax.rules", &cfg.Tax.Rules); err != nil {
+		return nil, fmt.Errorf("failed to parse tax rules: %w", err)
+	}
+
+	// Shipping
+	cfg.Shipping.Carrier = viper.GetString("shipping.carrier")
+	cfg.Shipping.Currency = viper.GetString("shipping.currency")
+	cfg.Shipping.TrackingPrefix = viper.GetString("shipping.tracking_prefix")
+	cfg.Shipping.TrackingBatchSize = viper.GetInt("shipping.tracking_batch_size")
+	if err := viper.UnmarshalKey("shipping.zones", &cfg.Shipping.Zones); err != nil {
+		return nil, fmt.Errorf("failed to parse shipping zones: %w", err)
+	}
+	if err := viper.UnmarshalKey("shipping.services", &cfg.Shipping.Services); err != nil {
+		return nil, fmt.Errorf("failed to parse shipping services: %w", err)
+	}
+
+	// Returns
+	cfg.Returns.Window = viper.GetDuration("returns.window")
+	cfg.Returns.CategoryWindows = make(map[string]time.Duration)
+	for category, value := range viper.GetStringMapString("returns.category_windows") {
+		window, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse return window of category %q: %w", category, err)
+		}
+		cfg.Returns.CategoryWindows[category] = window
+	}
+
+	// Subscription
+	cfg.Subscription.RenewalBatchSize = viper.GetInt("subscription.renewal_batch_size")
+	for _, value := range viper.GetStringSlice("subscription.retry_schedule") {
+		delay, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse subscription retry delay %q: %w", value, err)
+		}
+		cfg.Subscription.RetrySchedule = append(cfg.Subscription.RetrySchedule, delay)
+	}
+
+	// Fraud
+	cfg.Fraud.ReviewScore = viper.GetInt("fraud.review_score")
+	cfg.Fraud.VelocityWindow = viper.GetDuration("fraud.velocity_window")
+	cfg.Fraud.UserVelocityLimit = viper.GetInt("fraud.user_velocity_limit")
+	cfg.Fraud.UserVelocityScore = viper.GetInt("fraud.user_velocity_score")
+	cfg.Fraud.IPVelocityLimit = viper.GetInt("fraud.ip_velocity_limit")
+	cfg.Fraud.IPVelocityScore = viper.GetInt("fraud.ip_velocity_score")
+	cfg.Fraud.HighAmount = viper.GetFloat64("fraud.high_amount")
+	cfg.Fraud.HighAmountScore = viper.GetInt("fraud.high_amount_score")
+	cfg.Fraud.CountryMismatchScore = viper.GetInt("fraud.country_mismatch_score")
+	cfg.Fraud.NewAccountAge = viper.GetDuration("fraud.new_account_age")
+	cfg.Fraud.NewAccountAmount = viper.GetFloat64("fraud.new_account_amount")
+	cfg.Fraud.NewAccountScore = viper.GetInt("fraud.new_account_score")
+
+	// Export
+	cfg.Export.BatchSize = viper.GetInt("export.batch_size")
+	cfg.Export.AsyncThreshold = viper.GetInt64("export.async_threshold")
+	cfg.Export.JobBatchSize = viper.GetInt("export.job_batch_size")
+	cfg.Export.JobTimeout = viper.GetDuration("export.job_timeout")
+
+	// Report
+	cfg.Report.RefreshEnabled = viper.GetBool("report.refresh_enabled")
+	cfg.Report.CacheTTL = viper.GetDuration("report.cache_ttl")
+
+	// Document
+	cfg.Document.CompanyName = viper.GetString("document.company_name")
+	cfg.Document.AddressLines = viper.GetStringSlice("document.address_lines")
+	cfg.Document.Email = viper.GetString("document.email")
+	cfg.Document.Website = viper.GetString("document.website")
+	cfg.Document.VATID = viper.GetString("document.vat_id")
+	cfg.Document.AccentColor = viper.GetString("document.accent_color")
+	cfg.Document.FooterTemplate = viper.GetString("document.footer_template")
+	cfg.Document.DefaultLanguage = viper.GetString("document.default_language")
+
+	// Storage
+	cfg.Storage.LocalPath = viper.GetString("storage.local_path")
 
 	// App
 	cfg.App.Name = viper.GetString("app.name")
@@ -132,4 +377,3 @@ func Load() (*Config, error) {
 
 	return &cfg, nil
 }
-
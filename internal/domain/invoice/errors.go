@@ -0,0 +1,20 @@
+package invoice
+
+import "errors"
+
+var (
+	// ErrInvoiceNotFound 发票未找到
+	ErrInvoiceNotFound = errors.New("invoice not found")
+
+	// ErrInvalidInvoiceStatus 无效的发票状态
+	ErrInvalidInvoiceStatus = errors.New("invalid invoice status transition")
+
+	// ErrInvoiceAlreadyIssued 订单已开具发票
+	ErrInvoiceAlreadyIssued = errors.New("invoice already issued for this order")
+
+	// ErrOrderNotInvoiceable 订单不可开票
+	ErrOrderNotInvoiceable = errors.New("order cannot be invoiced")
+
+	// ErrInvalidCreditAmount 无效的红字金额
+	ErrInvalidCreditAmount = errors.New("credit amount must be positive and not exceed the remaining invoice total")
+)
@@ -0,0 +1,228 @@
+package invoice
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+)
+
+// Type 发票类型
+type Type string
+
+const (
+	TypeInvoice    Type = "invoice"
+	TypeCreditNote Type = "credit_note"
+)
+
+// Status 发票状态
+type Status string
+
+const (
+	StatusDraft  Status = "draft"
+	StatusIssued Status = "issued"
+	StatusPaid   Status = "paid"
+	StatusVoid   Status = "void"
+)
+
+// DefaultPaymentTermDays 默认付款期限（天）
+const DefaultPaymentTermDays = 14
+
+// Invoice 发票聚合根（含红字发票/贷项通知单）
+type Invoice struct {
+	ID               string
+	OrderID          string
+	UserID           string
+	Type             Type
+	Number           string // 开具后分配，草稿为空
+	Status           Status
+	RelatedInvoiceID string // 红字发票对应的原发票
+	Reason           string // 红字发票原因
+	Note             string // 发票备注（如反向征收说明）
+	Lines            []*Line
+	Subtotal         order.Money
+	TaxTotal         order.Money
+	Total            order.Money
+	IssuedAt         *time.Time
+	DueAt            *time.Time
+	PaidAt           *time.Time
+	VoidedAt         *time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// Line 发票行
+type Line struct {
+	ID          string
+	InvoiceID   string
+	OrderItemID string
+	Description string
+	Quantity    int
+	UnitPrice   order.Money
+	TaxRate     float64
+	TaxAmount   order.Money
+	Total       order.Money // 不含税金额
+}
+
+// NewFromOrder 根据订单创建发票草稿
+func NewFromOrder(o *order.Order) (*Invoice, error) {
+	if o == nil || len(o.Items) == 0 {
+		return nil, ErrOrderNotInvoiceable
+	}
+
+	currency := o.TotalAmount.Currency
+	lines := make([]*Line, 0, len(o.Items)+len(o.Adjustments))
+	for _, item := range o.Items {
+		net := item.Subtotal.Amount
+		if o.TaxInclusive {
+			net -= item.TaxAmount.Amount
+		}
+		lines = append(lines, &Line{
+			OrderItemID: item.ID,
+			Description: item.ProductName,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			TaxRate:     item.TaxRate,
+			TaxAmount:   item.TaxAmount,
+			Total:       order.NewMoney(round(net), currency),
+		})
+	}
+	for _, adj := range o.Adjustments {
+		lines = append(lines, &Line{
+			Description: adj.Label,
+			Quantity:    1,
+			UnitPrice:   adj.Amount,
+			TaxAmount:   order.NewMoney(0, currency),
+			Total:       adj.Amount,
+		})
+	}
+
+	now := time.Now()
+	inv := &Invoice{
+		OrderID:   o.ID,
+		UserID:    o.UserID,
+		Type:      TypeInvoice,
+		Status:    StatusDraft,
+		Note:      o.TaxNote,
+		Lines:     lines,
+		TaxTotal:  o.TaxTotal,
+		Total:     o.TotalAmount,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	inv.Subtotal = order.NewMoney(round(o.TotalAmount.Amount-o.TaxTotal.Amount), currency)
+	return inv, nil
+}
+
+// NewCreditNote 为已开具的发票创建红字发票草稿
+// alreadyCredited 为该发票此前已冲红的金额
+func NewCreditNote(original *Invoice, amount order.Money, alreadyCredited float64, reason string) (*Invoice, error) {
+	if original.Type != TypeInvoice || (original.Status != StatusIssued && original.Status != StatusPaid) {
+		return nil, ErrInvalidInvoiceStatus
+	}
+	if amount.Currency != original.Total.Currency {
+		return nil, order.ErrDifferentCurrency
+	}
+	remaining := round(original.Total.Amount - alreadyCredited)
+	if !amount.IsPositive() || round(amount.Amount) > remaining {
+		return nil, ErrInvalidCreditAmount
+	}
+
+	// 按原发票税负比例拆分红字金额
+	taxAmount := 0.0
+	if original.Total.Amount > 0 {
+		taxAmount = round(amount.Amount * original.TaxTotal.Amount / original.Total.Amount)
+	}
+	currency := amount.Currency
+
+	now := time.Now()
+	return &Invoice{
+		OrderID:          original.OrderID,
+		UserID:           original.UserID,
+		Type:             TypeCreditNote,
+		Status:           StatusDraft,
+		RelatedInvoiceID: original.ID,
+		Reason:           reason,
+		Note:             original.Note,
+		Lines: []*Line{{
+			Description: fmt.Sprintf("Credit for invoice %s", original.Number),
+			Quantity:    1,
+			UnitPrice:   order.NewMoney(round(amount.Amount-taxAmount), currency),
+			TaxAmount:   order.NewMoney(taxAmount, currency),
+			Total:       order.NewMoney(round(amount.Amount-taxAmount), currency),
+		}},
+		Subtotal:  order.NewMoney(round(amount.Amount-taxAmount), currency),
+		TaxTotal:  order.NewMoney(taxAmount, currency),
+		Total:     order.NewMoney(round(amount.Amount), currency),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// Issue 开具发票（分配连续编号）
+func (i *Invoice) Issue(number string, issuedAt time.Time, termDays int) error {
+	if i.Status != StatusDraft {
+		return ErrInvalidInvoiceStatus
+	}
+	if number == "" {
+		return errors.New("invoice number is required")
+	}
+
+	i.Number = number
+	i.Status = StatusIssued
+	i.IssuedAt = &issuedAt
+	if i.Type == TypeInvoice {
+		due := issuedAt.AddDate(0, 0, termDays)
+		i.DueAt = &due
+	}
+	i.UpdatedAt = time.Now()
+	return nil
+}
+
+// MarkAsPaid 标记为已支付
+func (i *Invoice) MarkAsPaid(paidAt time.Time) error {
+	if i.Type != TypeInvoice || i.Status != StatusIssued {
+		return ErrInvalidInvoiceStatus
+	}
+	i.Status = StatusPaid
+	i.PaidAt = &paidAt
+	i.UpdatedAt = time.Now()
+	return nil
+}
+
+// Void 作废发票（已支付的发票须通过红字发票冲销）
+func (i *Invoice) Void() error {
+	if i.Status != StatusDraft && i.Status != StatusIssued {
+		return ErrInvalidInvoiceStatus
+	}
+	now := time.Now()
+	i.Status = StatusVoid
+	i.VoidedAt = &now
+	i.UpdatedAt = now
+	return nil
+}
+
+// IsCreditNote 是否为红字发票
+func (i *Invoice) IsCreditNote() bool {
+	return i.Type == TypeCreditNote
+}
+
+// NumberSeries 返回发票编号序列名
+func (i *Invoice) NumberSeries() string {
+	if i.Type == TypeCreditNote {
+		return "CN"
+	}
+	return "INV"
+}
+
+// FormatNumber 格式化发票编号，如 INV-2026-000001
+func FormatNumber(series string, year int, seq int64) string {
+	return fmt.Sprintf("%s-%d-%06d", series, year, seq)
+}
+
+// round 金额保留两位小数
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,42 @@
+package invoice
+
+import "context"
+
+// ListFilter 发票列表过滤条件
+type ListFilter struct {
+	UserID  string
+	OrderID string
+	Type    Type
+	Status  Status
+}
+
+// Repository 发票仓储接口
+type Repository interface {
+	// Create 创建发票
+	Create(ctx context.Context, invoice *Invoice) error
+
+	// Update 更新发票
+	Update(ctx context.Context, invoice *Invoice) error
+
+	// FindByID 根据ID查找发票
+	FindByID(ctx context.Context, id string) (*Invoice, error)
+
+	// FindActiveByOrderID 查找订单的有效（非作废）发票
+	FindActiveByOrderID(ctx context.Context, orderID string) (*Invoice, error)
+
+	// ListByOrderID 列出订单的全部发票与红字发票
+	ListByOrderID(ctx context.Context, orderID string) ([]*Invoice, error)
+
+	// List 按条件列出发票（分页）
+	List(ctx context.Context, filter ListFilter, offset, limit int) ([]*Invoice, int64, error)
+
+	// SumCredited 统计某发票已冲红的金额
+	SumCredited(ctx context.Context, invoiceID string) (float64, error)
+}
+
+// NumberSequence 发票编号序列接口
+// 需与发票写入在同一事务中调用，回滚时序号一并回滚，保证编号连续无缺口
+type NumberSequence interface {
+	// Next 获取指定序列与年份的下一个序号
+	Next(ctx context.Context, series string, year int) (int64, error)
+}
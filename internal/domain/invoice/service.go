@@ -0,0 +1,43 @@
+package invoice
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Service 发票领域服务
+type Service struct {
+	repo     Repository
+	sequence NumberSequence
+}
+
+// NewService 创建发票领域服务
+func NewService(repo Repository, sequence NumberSequence) *Service {
+	return &Service{
+		repo:     repo,
+		sequence: sequence,
+	}
+}
+
+// Issue 为草稿发票分配当年的下一个连续编号并开具
+func (s *Service) Issue(ctx context.Context, inv *Invoice, termDays int) error {
+	now := time.Now()
+	seq, err := s.sequence.Next(ctx, inv.NumberSeries(), now.Year())
+	if err != nil {
+		return err
+	}
+	return inv.Issue(FormatNumber(inv.NumberSeries(), now.Year(), seq), now, termDays)
+}
+
+// ValidateNotInvoiced 验证订单尚未开具有效发票
+func (s *Service) ValidateNotInvoiced(ctx context.Context, orderID string) error {
+	_, err := s.repo.FindActiveByOrderID(ctx, orderID)
+	if err == nil {
+		return ErrInvoiceAlreadyIssued
+	}
+	if errors.Is(err, ErrInvoiceNotFound) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,90 @@
+package order
+
+import (
+	"errors"
+	"time"
+)
+
+// AdjustmentType 订单调整类型
+type AdjustmentType string
+
+const (
+	AdjustmentTypeDiscount AdjustmentType = "discount"
+	AdjustmentTypeShipping AdjustmentType = "shipping"
+)
+
+// Adjustment 订单调整行实体（折扣、运费等），使订单总额可解释
+type Adjustment struct {
+	ID        string
+	OrderID   string
+	Type      AdjustmentType
+	Label     string
+	SourceID  string   // 来源标识，如优惠ID、承运商
+	Code      string   // 来源编码，如优惠码、运输服务编码
+	Amount    Money    // 正数增加总额，负数减少总额
+	ItemIDs   []string // 折扣适用的订单项，为空表示适用整单
+	CreatedAt time.Time
+}
+
+// NewDiscountAdjustment 创建折扣调整行（金额以正数传入）
+func NewDiscountAdjustment(orderID, sourceID, code, label string, discount Money) (*Adjustment, error) {
+	if orderID == "" {
+		return nil, errors.New("orderID cannot be empty")
+	}
+	if !discount.IsPositive() {
+		return nil, errors.New("discount must be positive")
+	}
+
+	return &Adjustment{
+		OrderID:   orderID,
+		Type:      AdjustmentTypeDiscount,
+		Label:     label,
+		SourceID:  sourceID,
+		Code:      code,
+		Amount:    discount.Multiply(-1),
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// NewShippingAdjustment 按选定的运费报价创建运费调整行
+func NewShippingAdjustment(orderID string, rate ShippingRate) (*Adjustment, error) {
+	if orderID == "" {
+		return nil, errors.New("orderID cannot be empty")
+	}
+	if rate.Amount.Amount < 0 {
+		return nil, errors.New("shipping amount cannot be negative")
+	}
+
+	return &Adjustment{
+		OrderID:   orderID,
+		Type:      AdjustmentTypeShipping,
+		Label:     rate.ServiceName,
+		SourceID:  rate.Carrier,
+		Code:      rate.Service,
+		Amount:    rate.Amount,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// IsDiscount 是否为折扣
+func (a *Adjustment) IsDiscount() bool {
+	return a.Type == AdjustmentTypeDiscount
+}
+
+// AppliesTo 折扣是否分摊到该订单项
+func (a *Adjustment) AppliesTo(itemID string) bool {
+	if len(a.ItemIDs) == 0 {
+		return true
+	}
+	for _, id := range a.ItemIDs {
+		if id == itemID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsShipping 是否为运费
+func (a *Adjustment) IsShipping() bool {
+	return a.Type == AdjustmentTypeShipping
+}
@@ -0,0 +1,31 @@
+package order
+
+// ChargeStatus 网关扣款结果状态
+type ChargeStatus string
+
+const (
+	ChargeSucceeded       ChargeStatus = "succeeded"        // 已扣款
+	ChargeRequiresAction  ChargeStatus = "requires_action"  // 需持卡人完成验证（如 3DS）
+	ChargeRequiresCapture ChargeStatus = "requires_capture" // 已授权，待请款
+	ChargeProcessing      ChargeStatus = "processing"       // 网关处理中，结果由回调通知
+	ChargeAwaitingReceipt ChargeStatus = "awaiting_receipt" // 线下收款，待管理员确认到账
+)
+
+// ChargeRequest 网关扣款请求
+type ChargeRequest struct {
+	PaymentID      string
+	OrderID        string
+	Amount         Money
+	Method         PaymentMethod
+	Token          string // 前端收集的支付方式令牌，如 Stripe 的 pm_xxx
+	ReturnURL      string // 持卡人验证完成后的回跳地址
+	IdempotencyKey string
+}
+
+// ChargeResult 网关扣款结果
+type ChargeResult struct {
+	TransactionID string
+	Status        ChargeStatus
+	ClientSecret  string // 需持卡人操作时交给前端完成验证
+	Response      string
+}
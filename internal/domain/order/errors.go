@@ -6,9 +6,15 @@ var (
 	// ErrOrderNotFound 订单未找到
 	ErrOrderNotFound = errors.New("order not found")
 
+	// ErrInvalidOrderNumber 订单号格式或校验位无效
+	ErrInvalidOrderNumber = errors.New("invalid order number")
+
 	// ErrInvalidOrderStatus 无效的订单状态
 	ErrInvalidOrderStatus = errors.New("invalid order status")
 
+	// ErrManualTransitionNotAllowed 该状态转换不能手动设置
+	ErrManualTransitionNotAllowed = errors.New("status transition must go through its business flow")
+
 	// ErrOrderAlreadyPaid 订单已支付
 	ErrOrderAlreadyPaid = errors.New("order already paid")
 
@@ -30,18 +36,111 @@ var (
 	// ErrPaymentFailed 支付失败
 	ErrPaymentFailed = errors.New("payment failed")
 
+	// ErrPaymentPending 网关结果未知或支付仍待确认，以同一请求重试或等待回调
+	ErrPaymentPending = errors.New("payment is pending confirmation from the gateway")
+
+	// ErrGatewayUnavailable 网关超时或不可用，请求可能已被处理，须以同一幂等键重试
+	ErrGatewayUnavailable = errors.New("payment gateway unavailable")
+
+	// ErrUnsupportedPaymentMethod 不支持的支付方式（未配置对应网关）
+	ErrUnsupportedPaymentMethod = errors.New("unsupported payment method")
+
+	// ErrPaymentNotConfirmable 支付无法人工确认到账
+	ErrPaymentNotConfirmable = errors.New("payment cannot be confirmed manually")
+
+	// ErrPaymentAlreadySucceeded 订单已有授权或收款成功的支付，不能再次发起支付
+	ErrPaymentAlreadySucceeded = errors.New("order already has a successful payment")
+
+	// ErrPaymentNotCapturable 支付不处于待请款状态
+	ErrPaymentNotCapturable = errors.New("payment is not awaiting capture")
+
 	// ErrCannotRefund 无法退款
 	ErrCannotRefund = errors.New("cannot refund payment")
 
+	// ErrRefundExceedsPayment 退款金额超过可退金额
+	ErrRefundExceedsPayment = errors.New("refund amount exceeds refundable amount")
+
+	// ErrInvalidRefundAmount 无效的退款金额
+	ErrInvalidRefundAmount = errors.New("invalid refund amount")
+
+	// ErrInvalidRefundAllocation 无效的退款行分配（商品不存在或数量超出）
+	ErrInvalidRefundAllocation = errors.New("invalid refund line allocation")
+
+	// ErrRefundNotFound 退款未找到
+	ErrRefundNotFound = errors.New("refund not found")
+
+	// ErrRefundKeyConflict 幂等键已用于其他订单的退款
+	ErrRefundKeyConflict = errors.New("idempotency key already used for another refund")
+
+	// ErrRefundFailed 网关退款失败
+	ErrRefundFailed = errors.New("refund failed")
+
+	// ErrRefundPending 网关退款结果未知，退款保持待处理并以同一幂等键重试
+	ErrRefundPending = errors.New("refund is pending confirmation from the gateway")
+
+	// ErrInvalidWebhookSignature 回调签名无效
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
 	// ErrShipmentNotFound 发货未找到
 	ErrShipmentNotFound = errors.New("shipment not found")
 
 	// ErrInvalidShipmentStatus 无效的发货状态
 	ErrInvalidShipmentStatus = errors.New("invalid shipment status")
 
+	// ErrInvalidShipmentLines 发货行无效（订单项不存在或超出未发货数量）
+	ErrInvalidShipmentLines = errors.New("invalid shipment lines")
+
+	// ErrReturnNotFound 退货未找到
+	ErrReturnNotFound = errors.New("return not found")
+
+	// ErrInvalidReturnStatus 退货状态不允许该操作
+	ErrInvalidReturnStatus = errors.New("invalid return status")
+
+	// ErrInvalidReturnLines 退货行无效（订单项不存在或超出可退货数量）
+	ErrInvalidReturnLines = errors.New("invalid return lines")
+
+	// ErrReturnWindowExpired 已超过退货期限或商品不可退货
+	ErrReturnWindowExpired = errors.New("return window has expired")
+
+	// ErrShippingAddressRequired 订单缺少收货地址，无法报价运费
+	ErrShippingAddressRequired = errors.New("shipping address is required for shipping rates")
+
+	// ErrShippingRateUnavailable 所选运输服务不适用于该订单
+	ErrShippingRateUnavailable = errors.New("shipping service not available for this order")
+
+	// ErrOrderUnderReview 订单命中欺诈规则，等待人工审核
+	ErrOrderUnderReview = errors.New("order is held for fraud review")
+
+	// ErrInvalidOrderNumberFormat 订单号格式配置无效
+	ErrInvalidOrderNumberFormat = errors.New("invalid order number format")
+
+	// ErrOrderNotFlagged 订单没有待管理员处理的标记
+	ErrOrderNotFlagged = errors.New("order is not flagged for attention")
+
+	// ErrFraudReviewNotFound 订单没有等待审核的欺诈筛查记录
+	ErrFraudReviewNotFound = errors.New("fraud review not found")
+
+	// ErrInvalidFraudReview 欺诈筛查记录不在待审核状态
+	ErrInvalidFraudReview = errors.New("invalid fraud review")
+
+	// ErrUnsupportedExportFormat 不支持的导出格式
+	ErrUnsupportedExportFormat = errors.New("unsupported export format")
+
+	// ErrExportJobNotFound 导出任务未找到
+	ErrExportJobNotFound = errors.New("export job not found")
+
+	// ErrExportNotReady 导出任务尚未完成
+	ErrExportNotReady = errors.New("export is not ready")
+
 	// ErrInvalidAddress 无效的地址
 	ErrInvalidAddress = errors.New("invalid address")
 
+	// ErrInvalidSearchCriteria 无效的搜索条件
+	ErrInvalidSearchCriteria = errors.New("invalid search criteria")
+
 	// ErrDifferentCurrency 不同货币
 	ErrDifferentCurrency = errors.New("cannot operate on different currencies")
+
+	// ErrConcurrentModification 订单已被其他请求修改
+	ErrConcurrentModification = errors.New("order was modified concurrently")
 )
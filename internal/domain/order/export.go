@@ -0,0 +1,118 @@
+package order
+
+import (
+	"fmt"
+	"time"
+)
+
+// ExportFormat 订单导出文件格式
+type ExportFormat string
+
+const (
+	ExportFormatCSV  ExportFormat = "csv"
+	ExportFormatXLSX ExportFormat = "xlsx"
+)
+
+// IsValid 检查导出格式是否有效
+func (f ExportFormat) IsValid() bool {
+	return f == ExportFormatCSV || f == ExportFormatXLSX
+}
+
+// ContentType 导出文件的 MIME 类型
+func (f ExportFormat) ContentType() string {
+	if f == ExportFormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// ParseExportFormat 解析导出格式，缺省为 CSV
+func ParseExportFormat(value string) (ExportFormat, error) {
+	if value == "" {
+		return ExportFormatCSV, nil
+	}
+	format := ExportFormat(value)
+	if !format.IsValid() {
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedExportFormat, value)
+	}
+	return format, nil
+}
+
+// ExportWriter 导出文件的流式行写入器，全部行写完后须调用 Close 输出文件尾
+type ExportWriter interface {
+	WriteRow(values []string) error
+	Close() error
+}
+
+// ExportJobStatus 导出任务状态
+type ExportJobStatus string
+
+const (
+	ExportJobPending   ExportJobStatus = "pending"
+	ExportJobRunning   ExportJobStatus = "running"
+	ExportJobCompleted ExportJobStatus = "completed"
+	ExportJobFailed    ExportJobStatus = "failed"
+)
+
+// ExportJob 后台导出任务实体：大批量导出由 worker 生成文件，完成后供下载
+type ExportJob struct {
+	ID          string
+	RequestedBy string
+	Format      ExportFormat
+	Filters     string // 导出条件（JSON），与订单搜索的查询参数一致
+	Status      ExportJobStatus
+	RowCount    int
+	FileKey     string // 导出文件在对象存储中的键
+	Error       string
+	StartedAt   *time.Time
+	CompletedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewExportJob 创建待执行的导出任务
+func NewExportJob(requestedBy string, format ExportFormat, filters string) (*ExportJob, error) {
+	if !format.IsValid() {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedExportFormat, format)
+	}
+
+	now := time.Now()
+	return &ExportJob{
+		RequestedBy: requestedBy,
+		Format:      format,
+		Filters:     filters,
+		Status:      ExportJobPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// Start 开始执行，超时未完成的任务可被重新领取
+func (j *ExportJob) Start(now time.Time) {
+	j.Status = ExportJobRunning
+	j.Error = ""
+	j.StartedAt = &now
+	j.UpdatedAt = now
+}
+
+// Complete 导出完成
+func (j *ExportJob) Complete(fileKey string, rows int, now time.Time) {
+	j.Status = ExportJobCompleted
+	j.FileKey = fileKey
+	j.RowCount = rows
+	j.CompletedAt = &now
+	j.UpdatedAt = now
+}
+
+// Fail 导出失败
+func (j *ExportJob) Fail(reason string, now time.Time) {
+	j.Status = ExportJobFailed
+	j.Error = reason
+	j.CompletedAt = &now
+	j.UpdatedAt = now
+}
+
+// IsCompleted 导出文件是否可下载
+func (j *ExportJob) IsCompleted() bool {
+	return j.Status == ExportJobCompleted
+}
@@ -0,0 +1,189 @@
+package order
+
+import (
+	"fmt"
+	"time"
+)
+
+// 欺诈规则编码
+const (
+	FraudRuleUserVelocity    = "user_velocity"    // 同一用户短时间内下单过多
+	FraudRuleIPVelocity      = "ip_velocity"      // 同一 IP 短时间内下单过多
+	FraudRuleHighAmount      = "high_amount"      // 订单金额超过阈值
+	FraudRuleCountryMismatch = "country_mismatch" // 账单与收货国家不一致
+	FraudRuleNewAccount      = "new_account"      // 新注册账户的大额订单
+)
+
+// FraudPolicy 欺诈筛查规则：命中的规则累加分数，达到 ReviewScore 的订单转人工审核
+// 分数为 0 的规则不生效，ReviewScore 为 0 时不筛查
+type FraudPolicy struct {
+	ReviewScore    int
+	VelocityWindow time.Duration // 下单频率的统计窗口
+
+	UserVelocityLimit int // 窗口内同一用户的订单数超过该值时命中
+	UserVelocityScore int
+	IPVelocityLimit   int // 窗口内同一 IP 的订单数超过该值时命中
+	IPVelocityScore   int
+
+	HighAmount      float64 // 订单总额达到该值时命中（不区分币种）
+	HighAmountScore int
+
+	CountryMismatchScore int
+
+	NewAccountAge    time.Duration // 注册时长小于该值视为新账户
+	NewAccountAmount float64       // 新账户订单总额达到该值时命中
+	NewAccountScore  int
+}
+
+// Enabled 是否启用欺诈筛查
+func (p FraudPolicy) Enabled() bool {
+	return p.ReviewScore > 0
+}
+
+// FraudSignals 欺诈筛查所需的订单外部信号
+type FraudSignals struct {
+	ClientIP   string
+	UserOrders int           // 统计窗口内该用户发起支付的订单数（含本单）
+	IPOrders   int           // 统计窗口内该 IP 发起支付的订单数（含本单）
+	AccountAge time.Duration // 账户注册时长
+}
+
+// Score 按规则为订单评分，返回分数与命中的规则编码
+func (p FraudPolicy) Score(o *Order, signals FraudSignals) (int, []string) {
+	score := 0
+	rules := make([]string, 0)
+	hit := func(rule string, points int) {
+		score += points
+		rules = append(rules, rule)
+	}
+
+	if p.UserVelocityScore > 0 && signals.UserOrders > p.UserVelocityLimit {
+		hit(FraudRuleUserVelocity, p.UserVelocityScore)
+	}
+	if p.IPVelocityScore > 0 && signals.ClientIP != "" && signals.IPOrders > p.IPVelocityLimit {
+		hit(FraudRuleIPVelocity, p.IPVelocityScore)
+	}
+	if p.HighAmountScore > 0 && p.HighAmount > 0 && o.TotalAmount.Amount >= p.HighAmount {
+		hit(FraudRuleHighAmount, p.HighAmountScore)
+	}
+	if p.CountryMismatchScore > 0 && o.BillingAddress != nil && o.ShippingAddress != nil &&
+		o.BillingAddress.Country != o.ShippingAddress.Country {
+		hit(FraudRuleCountryMismatch, p.CountryMismatchScore)
+	}
+	if p.NewAccountScore > 0 && signals.AccountAge < p.NewAccountAge && o.TotalAmount.Amount >= p.NewAccountAmount {
+		hit(FraudRuleNewAccount, p.NewAccountScore)
+	}
+	return score, rules
+}
+
+// FraudOutcome 欺诈筛查结果
+type FraudOutcome string
+
+const (
+	FraudOutcomePass   FraudOutcome = "pass"   // 未达到审核分数，继续扣款
+	FraudOutcomeReview FraudOutcome = "review" // 订单转人工审核
+)
+
+// FraudDecision 人工审核决定
+type FraudDecision string
+
+const (
+	FraudDecisionApproved FraudDecision = "approved"
+	FraudDecisionRejected FraudDecision = "rejected"
+)
+
+// FraudAssessment 欺诈筛查记录实体：每次支付尝试前筛查一次，转人工审核时保存支付方式，审核通过后自动扣款
+type FraudAssessment struct {
+	ID            string
+	OrderID       string
+	UserID        string
+	ClientIP      string
+	Score         int
+	Rules         []string // 命中的规则编码
+	Outcome       FraudOutcome
+	Amount        Money
+	PaymentMethod PaymentMethod
+	PaymentToken  string
+	ReturnURL     string
+	Decision      FraudDecision
+	DecidedBy     string
+	DecisionNote  string
+	DecidedAt     *time.Time
+	CreatedAt     time.Time
+}
+
+// NewFraudAssessment 按策略为订单评分并生成筛查记录
+func NewFraudAssessment(o *Order, policy FraudPolicy, signals FraudSignals) *FraudAssessment {
+	score, rules := policy.Score(o, signals)
+	outcome := FraudOutcomePass
+	if policy.Enabled() && score >= policy.ReviewScore {
+		outcome = FraudOutcomeReview
+	}
+
+	return &FraudAssessment{
+		OrderID:   o.ID,
+		UserID:    o.UserID,
+		ClientIP:  signals.ClientIP,
+		Score:     score,
+		Rules:     rules,
+		Outcome:   outcome,
+		Amount:    o.TotalAmount,
+		CreatedAt: time.Now(),
+	}
+}
+
+// RequiresReview 是否需要人工审核
+func (a *FraudAssessment) RequiresReview() bool {
+	return a.Outcome == FraudOutcomeReview
+}
+
+// IsAwaitingReview 是否等待人工审核
+func (a *FraudAssessment) IsAwaitingReview() bool {
+	return a.RequiresReview() && a.Decision == ""
+}
+
+// IsApproved 是否已人工审核通过
+func (a *FraudAssessment) IsApproved() bool {
+	return a.Decision == FraudDecisionApproved
+}
+
+// HoldPayment 保存转审核时的支付方式，审核通过后用于扣款
+func (a *FraudAssessment) HoldPayment(method PaymentMethod, token, returnURL string) {
+	a.PaymentMethod = method
+	a.PaymentToken = token
+	a.ReturnURL = returnURL
+}
+
+// Approve 人工审核通过
+func (a *FraudAssessment) Approve(adminID, note string) error {
+	return a.decide(FraudDecisionApproved, adminID, note)
+}
+
+// Reject 人工审核拒绝
+func (a *FraudAssessment) Reject(adminID, note string) error {
+	return a.decide(FraudDecisionRejected, adminID, note)
+}
+
+// decide 记录审核决定
+func (a *FraudAssessment) decide(decision FraudDecision, adminID, note string) error {
+	if !a.IsAwaitingReview() {
+		return fmt.Errorf("%w: assessment is not awaiting review", ErrInvalidFraudReview)
+	}
+
+	now := time.Now()
+	a.Decision = decision
+	a.DecidedBy = adminID
+	a.DecisionNote = note
+	a.DecidedAt = &now
+	return nil
+}
+
+// HoldForReview 待支付订单转人工审核
+func (o *Order) HoldForReview(actor, note string) error {
+	return o.transitionTo(StatusReview, actor, note)
+}
+
+// ReleaseFromReview 审核通过，订单恢复待支付
+func (o *Order) ReleaseFromReview(actor, note string) error {
+	return o.transitionTo(StatusPending, actor, note)
+}
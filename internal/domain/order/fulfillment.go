@@ -0,0 +1,93 @@
+package order
+
+import "fmt"
+
+// ShipmentLine 发货行：发货包含的订单项及数量
+type ShipmentLine struct {
+	ID          string
+	ShipmentID  string
+	OrderItemID string
+	Quantity    int
+}
+
+// UnfulfilledQuantity 尚未分配到有效发货的数量
+func (oi *OrderItem) UnfulfilledQuantity() int {
+	return oi.Quantity - oi.FulfilledQuantity
+}
+
+// UnfulfilledLines 返回所有未发货数量对应的发货行
+func (o *Order) UnfulfilledLines() []*ShipmentLine {
+	lines := make([]*ShipmentLine, 0, len(o.Items))
+	for _, item := range o.Items {
+		if qty := item.UnfulfilledQuantity(); qty > 0 {
+			lines = append(lines, &ShipmentLine{OrderItemID: item.ID, Quantity: qty})
+		}
+	}
+	return lines
+}
+
+// AllocateShipment 将发货行计入订单项的已发货数量，不得超过未发货数量
+func (o *Order) AllocateShipment(lines []*ShipmentLine) error {
+	if len(lines) == 0 {
+		return fmt.Errorf("%w: shipment has no lines", ErrInvalidShipmentLines)
+	}
+
+	requested := make(map[string]int, len(lines))
+	for _, line := range lines {
+		item := o.findItem(line.OrderItemID)
+		if item == nil {
+			return fmt.Errorf("%w: order item %s not found", ErrInvalidShipmentLines, line.OrderItemID)
+		}
+		if line.Quantity <= 0 {
+			return fmt.Errorf("%w: quantity must be positive", ErrInvalidShipmentLines)
+		}
+		requested[item.ID] += line.Quantity
+		if requested[item.ID] > item.UnfulfilledQuantity() {
+			return fmt.Errorf("%w: only %d of item %s left to ship", ErrInvalidShipmentLines, item.UnfulfilledQuantity(), item.ID)
+		}
+	}
+
+	for _, line := range lines {
+		o.findItem(line.OrderItemID).FulfilledQuantity += line.Quantity
+	}
+	return nil
+}
+
+// ReleaseShipment 发货取消后释放其占用的已发货数量
+func (o *Order) ReleaseShipment(lines []*ShipmentLine) {
+	for _, line := range lines {
+		if item := o.findItem(line.OrderItemID); item != nil {
+			item.FulfilledQuantity = max(item.FulfilledQuantity-line.Quantity, 0)
+		}
+	}
+}
+
+// RecordDelivery 发货送达后计入订单项的已送达数量
+func (o *Order) RecordDelivery(lines []*ShipmentLine) {
+	for _, line := range lines {
+		if item := o.findItem(line.OrderItemID); item != nil {
+			item.DeliveredQuantity = min(item.DeliveredQuantity+line.Quantity, item.Quantity)
+		}
+	}
+}
+
+// IsFullyDelivered 是否所有订单项均已送达
+func (o *Order) IsFullyDelivered() bool {
+	for _, item := range o.Items {
+		if item.DeliveredQuantity < item.Quantity {
+			return false
+		}
+	}
+	return true
+}
+
+// ParcelFor 按发货行汇总包裹
+func (o *Order) ParcelFor(lines []*ShipmentLine) Parcel {
+	weight := 0.0
+	for _, line := range lines {
+		if item := o.findItem(line.OrderItemID); item != nil {
+			weight += item.WeightKg * float64(line.Quantity)
+		}
+	}
+	return Parcel{WeightKg: weight}
+}
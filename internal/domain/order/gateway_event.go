@@ -0,0 +1,28 @@
+package order
+
+import "time"
+
+// GatewayEventKind 支付网关回调事件类别
+type GatewayEventKind string
+
+const (
+	GatewayEventSucceeded GatewayEventKind = "succeeded"
+	GatewayEventFailed    GatewayEventKind = "failed"
+	GatewayEventRefunded  GatewayEventKind = "refunded"
+	GatewayEventDisputed  GatewayEventKind = "disputed"
+	GatewayEventIgnored   GatewayEventKind = "ignored" // 不影响支付状态的事件
+)
+
+// GatewayEvent 已验签的支付网关回调事件
+type GatewayEvent struct {
+	ID            string // 网关侧事件ID，用于去重
+	Provider      string
+	Type          string // 网关原始事件类型
+	Kind          GatewayEventKind
+	TransactionID string // 对应 Payment.TransactionID
+	PaymentID     string // 发起扣款时写入网关元数据的支付ID，交易号未及保存时据此关联
+	Reason        string
+	Amount        Money // 退款事件为网关侧累计退款金额，其他事件为零值
+	Payload       []byte
+	ReceivedAt    time.Time
+}
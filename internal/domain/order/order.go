@@ -2,6 +2,7 @@ package order
 
 import (
 	"errors"
+	"math"
 	"time"
 )
 
@@ -10,16 +11,19 @@ type OrderStatus string
 
 const (
 	StatusPending   OrderStatus = "pending"
+	StatusReview    OrderStatus = "review" // 欺诈筛查命中，等待人工审核
 	StatusPaid      OrderStatus = "paid"
 	StatusCancelled OrderStatus = "cancelled"
 	StatusCompleted OrderStatus = "completed"
 	StatusRefunded  OrderStatus = "refunded"
+
+	StatusPartiallyRefunded OrderStatus = "partially_refunded"
 )
 
 // IsValid 检查订单状态是否有效
 func (s OrderStatus) IsValid() bool {
 	switch s {
-	case StatusPending, StatusPaid, StatusCancelled, StatusCompleted, StatusRefunded:
+	case StatusPending, StatusReview, StatusPaid, StatusCancelled, StatusCompleted, StatusRefunded, StatusPartiallyRefunded:
 		return true
 	}
 	return false
@@ -32,9 +36,26 @@ type Order struct {
 	OrderNumber string
 	Status      OrderStatus
 	Items       []*OrderItem
-	TotalAmount Money
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	Adjustments []*Adjustment
+	Subtotal    Money // 订单项小计之和
+	TaxTotal    Money // 订单项税额之和
+	TotalAmount Money // 小计 + 调整行（+ 税额，价外税时）
+
+	ShippingAddress *Address // 收货地址，决定税务辖区
+	BillingAddress  *Address // 账单地址，开具发票时使用
+	VATID           string   // 买方增值税号（B2B）
+	TaxInclusive    bool     // 价格是否含税
+	ReverseCharge   bool     // 是否适用反向征收
+	TaxNote         string
+
+	AttentionReason string // 待管理员处理的原因（如取消后收到付款已自动退款），为空表示无需处理
+
+	History []*StatusChange // 状态变更历史
+
+	Version int // 乐观锁版本号，每次保存后递增
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 // NewOrder 创建新订单
@@ -46,15 +67,30 @@ func NewOrder(userID, orderNumber string) (*Order, error) {
 		return nil, errors.New("orderNumber cannot be empty")
 	}
 
-	return &Order{
+	o := &Order{
 		UserID:      userID,
 		OrderNumber: orderNumber,
 		Status:      StatusPending,
 		Items:       make([]*OrderItem, 0),
+		Adjustments: make([]*Adjustment, 0),
+		Subtotal:    NewMoney(0, "USD"),
+		TaxTotal:    NewMoney(0, "USD"),
 		TotalAmount: NewMoney(0, "USD"),
+		History:     make([]*StatusChange, 0),
+		Version:     1,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
-	}, nil
+	}
+	o.recordStatusChange("", StatusPending, UserActor(userID), "")
+	return o, nil
+}
+
+// CheckVersion 校验客户端读取时的版本号（If-Match），expected 为 0 时不校验
+func (o *Order) CheckVersion(expected int) error {
+	if expected != 0 && expected != o.Version {
+		return ErrConcurrentModification
+	}
+	return nil
 }
 
 // AddItem 添加订单项
@@ -82,62 +118,165 @@ func (o *Order) RemoveItem(itemID string) error {
 	return errors.New("item not found")
 }
 
+// AddAdjustment 添加调整行
+func (o *Order) AddAdjustment(adj *Adjustment) error {
+	if adj == nil {
+		return errors.New("adjustment cannot be nil")
+	}
+	if o.Status != StatusPending {
+		return errors.New("can only adjust pending orders")
+	}
+	if adj.Amount.Currency != o.Subtotal.Currency {
+		return ErrDifferentCurrency
+	}
+
+	o.Adjustments = append(o.Adjustments, adj)
+	o.calculateTotal()
+	o.UpdatedAt = time.Now()
+	return nil
+}
+
+// Discounts 返回所有折扣调整行
+func (o *Order) Discounts() []*Adjustment {
+	discounts := make([]*Adjustment, 0)
+	for _, adj := range o.Adjustments {
+		if adj.IsDiscount() {
+			discounts = append(discounts, adj)
+		}
+	}
+	return discounts
+}
+
+// ShippingAdjustment 返回已选运费的调整行，未选择时返回 nil
+func (o *Order) ShippingAdjustment() *Adjustment {
+	for _, adj := range o.Adjustments {
+		if adj.IsShipping() {
+			return adj
+		}
+	}
+	return nil
+}
+
+// SetShipping 设置运费调整行，替换已选的运费
+func (o *Order) SetShipping(adj *Adjustment) error {
+	if adj == nil || !adj.IsShipping() {
+		return errors.New("shipping adjustment required")
+	}
+	if o.Status != StatusPending {
+		return errors.New("can only adjust pending orders")
+	}
+	if adj.Amount.Currency != o.Subtotal.Currency {
+		return ErrDifferentCurrency
+	}
+
+	adjustments := make([]*Adjustment, 0, len(o.Adjustments)+1)
+	for _, existing := range o.Adjustments {
+		if !existing.IsShipping() {
+			adjustments = append(adjustments, existing)
+		}
+	}
+	o.Adjustments = append(adjustments, adj)
+	o.calculateTotal()
+	o.UpdatedAt = time.Now()
+	return nil
+}
+
+// Parcel 按订单项重量汇总包裹
+func (o *Order) Parcel() Parcel {
+	weight := 0.0
+	for _, item := range o.Items {
+		weight += item.WeightKg * float64(item.Quantity)
+	}
+	return Parcel{WeightKg: weight}
+}
+
 // calculateTotal 计算订单总额
 func (o *Order) calculateTotal() {
-	total := 0.0
+	subtotal := 0.0
 	currency := "USD"
 
 	for _, item := range o.Items {
-		total += item.Subtotal.Amount
+		subtotal += item.Subtotal.Amount
 		currency = item.Subtotal.Currency
 	}
 
-	o.TotalAmount = NewMoney(total, currency)
+	tax := 0.0
+	for _, item := range o.Items {
+		tax += item.TaxAmount.Amount
+	}
+
+	total := subtotal
+	for _, adj := range o.Adjustments {
+		total += adj.Amount.Amount
+	}
+	if total < 0 {
+		total = 0
+	}
+	// 价外税需加到总额，价内税已包含在单价中
+	if !o.TaxInclusive {
+		total += tax
+	}
+
+	o.Subtotal = NewMoney(roundAmount(subtotal), currency)
+	o.TaxTotal = NewMoney(roundAmount(tax), currency)
+	o.TotalAmount = NewMoney(roundAmount(total), currency)
 }
 
 // MarkAsPaid 标记为已支付
-func (o *Order) MarkAsPaid() error {
-	if o.Status != StatusPending {
-		return errors.New("can only mark pending orders as paid")
-	}
-	o.Status = StatusPaid
-	o.UpdatedAt = time.Now()
-	return nil
+func (o *Order) MarkAsPaid(actor string) error {
+	return o.transitionTo(StatusPaid, actor, "")
 }
 
 // Cancel 取消订单
-func (o *Order) Cancel() error {
-	if o.Status == StatusCompleted || o.Status == StatusRefunded {
-		return errors.New("cannot cancel completed or refunded orders")
+func (o *Order) Cancel(actor, reason string) error {
+	if !o.CanBeCancelled() {
+		return ErrCannotCancelOrder
 	}
-	o.Status = StatusCancelled
+	return o.transitionTo(StatusCancelled, actor, reason)
+}
+
+// FlagForAttention 标记订单待管理员处理，不改变订单状态
+func (o *Order) FlagForAttention(reason string) {
+	o.AttentionReason = reason
 	o.UpdatedAt = time.Now()
-	return nil
 }
 
-// Complete 完成订单
-func (o *Order) Complete() error {
-	if o.Status != StatusPaid {
-		return errors.New("can only complete paid orders")
+// ResolveAttention 管理员处理后清除待处理标记
+func (o *Order) ResolveAttention() error {
+	if o.AttentionReason == "" {
+		return ErrOrderNotFlagged
 	}
-	o.Status = StatusCompleted
+	o.AttentionReason = ""
 	o.UpdatedAt = time.Now()
 	return nil
 }
 
+// Complete 完成订单
+func (o *Order) Complete(actor string) error {
+	return o.transitionTo(StatusCompleted, actor, "")
+}
+
 // Refund 退款订单
-func (o *Order) Refund() error {
-	if o.Status != StatusPaid && o.Status != StatusCompleted {
-		return errors.New("can only refund paid or completed orders")
+func (o *Order) Refund(actor, note string) error {
+	return o.transitionTo(StatusRefunded, actor, note)
+}
+
+// ApplyRefund 按支付的退款进度更新订单状态：全额退款为已退款，否则为部分退款
+// 已是部分退款状态时追加的部分退款不产生新的状态变更
+func (o *Order) ApplyRefund(fullyRefunded bool, actor, note string) error {
+	if fullyRefunded {
+		return o.Refund(actor, note)
 	}
-	o.Status = StatusRefunded
-	o.UpdatedAt = time.Now()
-	return nil
+	if o.Status == StatusPartiallyRefunded {
+		o.UpdatedAt = time.Now()
+		return nil
+	}
+	return o.transitionTo(StatusPartiallyRefunded, actor, note)
 }
 
 // CanBeCancelled 是否可以取消
 func (o *Order) CanBeCancelled() bool {
-	return o.Status != StatusCompleted && o.Status != StatusRefunded
+	return o.CanTransitionTo(StatusCancelled)
 }
 
 // OrderItem 订单项实体
@@ -146,10 +285,19 @@ type OrderItem struct {
 	OrderID     string
 	ProductID   string
 	ProductName string
+	Category    string
 	Quantity    int
 	UnitPrice   Money
 	Subtotal    Money
-	CreatedAt   time.Time
+	TaxName     string
+	TaxRate     float64
+	TaxAmount   Money
+	WeightKg    float64 // 单件重量（千克），用于运费报价
+
+	FulfilledQuantity int // 已分配到有效发货的数量
+	DeliveredQuantity int // 已送达的数量
+
+	CreatedAt time.Time
 }
 
 // NewOrderItem 创建订单项
@@ -173,6 +321,7 @@ func NewOrderItem(orderID, productID, productName string, quantity int, unitPric
 		Quantity:    quantity,
 		UnitPrice:   unitPrice,
 		Subtotal:    subtotal,
+		TaxAmount:   NewMoney(0, unitPrice.Currency),
 		CreatedAt:   time.Now(),
 	}, nil
 }
@@ -187,6 +336,16 @@ func (oi *OrderItem) UpdateQuantity(quantity int) error {
 	return nil
 }
 
+// SetCategory 设置商品分类
+func (oi *OrderItem) SetCategory(category string) {
+	oi.Category = category
+}
+
+// SetWeight 设置单件重量（千克）
+func (oi *OrderItem) SetWeight(weightKg float64) {
+	oi.WeightKg = weightKg
+}
+
 // Money 金额值对象
 type Money struct {
 	Amount   float64
@@ -231,3 +390,8 @@ func (m Money) IsZero() bool {
 func (m Money) IsPositive() bool {
 	return m.Amount > 0
 }
+
+// roundAmount 金额保留两位小数
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,164 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OrderNumberGenerator 订单号生成器接口
+type OrderNumberGenerator interface {
+	// Generate 生成新的订单号，多实例并发生成不会重复
+	Generate(ctx context.Context) (string, error)
+}
+
+// legacyOrderNumber 旧版订单号（ORD-<纳秒时间戳>），仍可查询
+var legacyOrderNumber = regexp.MustCompile(`^ORD-\d{19}$`)
+
+// OrderNumberFormat 订单号格式：前缀-日期段-补零计数器-校验位，如 ORD-2026-000123-7
+type OrderNumberFormat struct {
+	Prefix     string // 前缀，为空时不含前缀段
+	DateLayout string // 日期段的 Go 时间格式（仅限数字），如 2006、200601，为空时不含日期段
+	Digits     int    // 计数器补零位数，计数超出位数时按实际位数输出
+	CheckDigit bool   // 是否追加 Luhn 校验位
+}
+
+// Check 检查格式配置本身：前缀不含分隔符，日期段格式化后为定长纯数字，
+// 且按该格式生成的订单号（含计数超出位数时）都能通过 Validate，避免启动后生成无法查询的订单号
+func (f OrderNumberFormat) Check() error {
+	if strings.Contains(f.Prefix, "-") {
+		return fmt.Errorf("%w: prefix %q must not contain '-'", ErrInvalidOrderNumberFormat, f.Prefix)
+	}
+	if f.Digits < 0 || f.Digits > 18 {
+		return fmt.Errorf("%w: digits must be between 0 and 18, got %d", ErrInvalidOrderNumberFormat, f.Digits)
+	}
+
+	// 取各字段位数不同的时间，检查日期段在任意时间都为与格式等长的纯数字
+	samples := []time.Time{
+		time.Date(2026, time.January, 2, 3, 4, 5, 0, time.UTC),
+		time.Date(2026, time.December, 31, 23, 59, 59, 0, time.UTC),
+	}
+	if f.DateLayout != "" {
+		for _, at := range samples {
+			date := at.Format(f.DateLayout)
+			if !isDigits(date) || len(date) != len(f.DateLayout) {
+				return fmt.Errorf("%w: date layout %q must format to fixed-width digits, got %q", ErrInvalidOrderNumberFormat, f.DateLayout, date)
+			}
+		}
+	}
+
+	maxSeq, _ := strconv.ParseInt(strings.Repeat("9", max(f.Digits, 1)), 10, 64)
+	for _, at := range samples {
+		for _, seq := range []int64{1, maxSeq, maxSeq + 1} {
+			if number := f.Format(at, seq); f.Validate(number) != nil {
+				return fmt.Errorf("%w: generated order number %q does not validate", ErrInvalidOrderNumberFormat, number)
+			}
+		}
+	}
+	return nil
+}
+
+// Format 按序号生成订单号
+func (f OrderNumberFormat) Format(at time.Time, seq int64) string {
+	segments := make([]string, 0, 4)
+	if f.Prefix != "" {
+		segments = append(segments, f.Prefix)
+	}
+	digits := ""
+	if f.DateLayout != "" {
+		date := at.Format(f.DateLayout)
+		segments = append(segments, date)
+		digits = date
+	}
+	counter := fmt.Sprintf("%0*d", f.Digits, seq)
+	segments = append(segments, counter)
+	if f.CheckDigit {
+		segments = append(segments, strconv.Itoa(luhnCheckDigit(digits+counter)))
+	}
+	return strings.Join(segments, "-")
+}
+
+// Validate 校验订单号的分段、位数与校验位
+func (f OrderNumberFormat) Validate(orderNumber string) error {
+	if legacyOrderNumber.MatchString(orderNumber) {
+		return nil
+	}
+
+	segments := strings.Split(orderNumber, "-")
+	if f.Prefix != "" {
+		if len(segments) == 0 || segments[0] != f.Prefix {
+			return ErrInvalidOrderNumber
+		}
+		segments = segments[1:]
+	}
+
+	expected := 1
+	if f.DateLayout != "" {
+		expected++
+	}
+	if f.CheckDigit {
+		expected++
+	}
+	if len(segments) != expected {
+		return ErrInvalidOrderNumber
+	}
+
+	digits := ""
+	if f.DateLayout != "" {
+		date := segments[0]
+		if len(date) != len(f.DateLayout) || !isDigits(date) {
+			return ErrInvalidOrderNumber
+		}
+		if _, err := time.Parse(f.DateLayout, date); err != nil {
+			return ErrInvalidOrderNumber
+		}
+		digits = date
+		segments = segments[1:]
+	}
+
+	counter := segments[0]
+	if len(counter) < f.Digits || !isDigits(counter) {
+		return ErrInvalidOrderNumber
+	}
+
+	if f.CheckDigit {
+		if segments[1] != strconv.Itoa(luhnCheckDigit(digits+counter)) {
+			return ErrInvalidOrderNumber
+		}
+	}
+	return nil
+}
+
+// luhnCheckDigit 计算数字串的 Luhn 校验位
+func luhnCheckDigit(digits string) int {
+	sum := 0
+	double := true
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return (10 - sum%10) % 10
+}
+
+// isDigits 是否为非空的纯数字串
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -9,10 +9,13 @@ import (
 type PaymentStatus string
 
 const (
-	PaymentStatusPending   PaymentStatus = "pending"
-	PaymentStatusCompleted PaymentStatus = "completed"
-	PaymentStatusFailed    PaymentStatus = "failed"
-	PaymentStatusRefunded  PaymentStatus = "refunded"
+	PaymentStatusPending           PaymentStatus = "pending"
+	PaymentStatusAuthorized        PaymentStatus = "authorized"
+	PaymentStatusCompleted         PaymentStatus = "completed"
+	PaymentStatusFailed            PaymentStatus = "failed"
+	PaymentStatusRefunded          PaymentStatus = "refunded"
+	PaymentStatusPartiallyRefunded PaymentStatus = "partially_refunded"
+	PaymentStatusDisputed          PaymentStatus = "disputed"
 )
 
 // PaymentMethod 支付方式值对象
@@ -26,6 +29,15 @@ const (
 	PaymentMethodCash       PaymentMethod = "cash"
 )
 
+// IsValid 检查支付方式是否有效
+func (m PaymentMethod) IsValid() bool {
+	switch m {
+	case PaymentMethodCreditCard, PaymentMethodDebitCard, PaymentMethodPayPal, PaymentMethodStripe, PaymentMethodCash:
+		return true
+	}
+	return false
+}
+
 // Payment 支付实体
 type Payment struct {
 	ID              string
@@ -35,6 +47,8 @@ type Payment struct {
 	Status          PaymentStatus
 	TransactionID   string
 	GatewayResponse string
+	RefundedAmount  Money // 已成功退款的累计金额
+	Version         int   // 乐观锁版本号，每次保存后递增
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 }
@@ -49,19 +63,44 @@ func NewPayment(orderID string, amount Money, method PaymentMethod) (*Payment, e
 	}
 
 	return &Payment{
-		OrderID:   orderID,
-		Amount:    amount,
-		Method:    method,
-		Status:    PaymentStatusPending,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		OrderID:        orderID,
+		Amount:         amount,
+		Method:         method,
+		Status:         PaymentStatusPending,
+		RefundedAmount: NewMoney(0, amount.Currency),
+		Version:        1,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
 	}, nil
 }
 
+// AttachTransaction 记录网关交易号，支付仍待网关最终确认
+func (p *Payment) AttachTransaction(transactionID, gatewayResponse string) error {
+	if p.Status != PaymentStatusPending {
+		return errors.New("can only attach transaction to pending payments")
+	}
+	p.TransactionID = transactionID
+	p.GatewayResponse = gatewayResponse
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// MarkAsAuthorized 标记为已授权（待请款）
+func (p *Payment) MarkAsAuthorized(transactionID, gatewayResponse string) error {
+	if p.Status != PaymentStatusPending {
+		return errors.New("can only authorize pending payments")
+	}
+	p.Status = PaymentStatusAuthorized
+	p.TransactionID = transactionID
+	p.GatewayResponse = gatewayResponse
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
 // MarkAsCompleted 标记为已完成
 func (p *Payment) MarkAsCompleted(transactionID, gatewayResponse string) error {
-	if p.Status != PaymentStatusPending {
-		return errors.New("can only complete pending payments")
+	if p.Status != PaymentStatusPending && p.Status != PaymentStatusAuthorized {
+		return errors.New("can only complete pending or authorized payments")
 	}
 	p.Status = PaymentStatusCompleted
 	p.TransactionID = transactionID
@@ -72,7 +111,7 @@ func (p *Payment) MarkAsCompleted(transactionID, gatewayResponse string) error {
 
 // MarkAsFailed 标记为失败
 func (p *Payment) MarkAsFailed(gatewayResponse string) error {
-	if p.Status == PaymentStatusCompleted || p.Status == PaymentStatusRefunded {
+	if p.Status == PaymentStatusCompleted || p.IsRefunded() {
 		return errors.New("cannot mark completed or refunded payment as failed")
 	}
 	p.Status = PaymentStatusFailed
@@ -81,22 +120,88 @@ func (p *Payment) MarkAsFailed(gatewayResponse string) error {
 	return nil
 }
 
-// Refund 退款
-func (p *Payment) Refund() error {
-	if p.Status != PaymentStatusCompleted {
-		return errors.New("can only refund completed payments")
+// ApplyRefund 记入一笔成功的退款，累计退款不能超过支付金额
+func (p *Payment) ApplyRefund(amount Money) error {
+	if !p.CanBeRefunded() {
+		return ErrCannotRefund
+	}
+	if amount.Currency != p.Amount.Currency {
+		return ErrDifferentCurrency
+	}
+	if !amount.IsPositive() || amount.Amount > p.RefundableAmount().Amount+0.005 {
+		return ErrRefundExceedsPayment
+	}
+
+	p.RefundedAmount = NewMoney(roundAmount(p.RefundedAmount.Amount+amount.Amount), p.Amount.Currency)
+	if p.IsFullyRefunded() {
+		p.Status = PaymentStatusRefunded
+	} else {
+		p.Status = PaymentStatusPartiallyRefunded
+	}
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// RefundableAmount 剩余可退款金额
+func (p *Payment) RefundableAmount() Money {
+	remaining := roundAmount(p.Amount.Amount - p.RefundedAmount.Amount)
+	if remaining < 0 {
+		remaining = 0
 	}
-	p.Status = PaymentStatusRefunded
+	return NewMoney(remaining, p.Amount.Currency)
+}
+
+// IsFullyRefunded 是否已全额退款
+func (p *Payment) IsFullyRefunded() bool {
+	return p.RefundableAmount().IsZero()
+}
+
+// IsRefunded 是否发生过退款（全额或部分）
+func (p *Payment) IsRefunded() bool {
+	return p.Status == PaymentStatusRefunded || p.Status == PaymentStatusPartiallyRefunded
+}
+
+// MarkAsDisputed 标记为争议中（持卡人发起拒付）
+func (p *Payment) MarkAsDisputed(reason string) error {
+	if p.Status != PaymentStatusCompleted && p.Status != PaymentStatusPartiallyRefunded {
+		return errors.New("can only dispute completed payments")
+	}
+	p.Status = PaymentStatusDisputed
+	p.GatewayResponse = reason
 	p.UpdatedAt = time.Now()
 	return nil
 }
 
+// AwaitingGateway 待确认且尚未取得网关交易号：上次网关调用结果未知（超时或进程中断）
+func (p *Payment) AwaitingGateway() bool {
+	return p.Status == PaymentStatusPending && p.TransactionID == ""
+}
+
+// IdempotencyKey 扣款请求的网关幂等键，由支付ID派生，重试同一支付尝试时保持不变
+func (p *Payment) IdempotencyKey() string {
+	return "payment-" + p.ID
+}
+
+// IsAuthorized 是否已授权待请款
+func (p *Payment) IsAuthorized() bool {
+	return p.Status == PaymentStatusAuthorized
+}
+
 // IsCompleted 是否已完成
 func (p *Payment) IsCompleted() bool {
 	return p.Status == PaymentStatusCompleted
 }
 
+// IsCaptured 是否已成功收款（含之后退款或拒付的）
+func (p *Payment) IsCaptured() bool {
+	switch p.Status {
+	case PaymentStatusCompleted, PaymentStatusPartiallyRefunded, PaymentStatusRefunded, PaymentStatusDisputed:
+		return true
+	}
+	return false
+}
+
 // CanBeRefunded 是否可以退款
 func (p *Payment) CanBeRefunded() bool {
-	return p.Status == PaymentStatusCompleted
+	return p.Status == PaymentStatusCompleted || p.Status == PaymentStatusPartiallyRefunded
 }
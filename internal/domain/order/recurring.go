@@ -0,0 +1,17 @@
+package order
+
+// RecurringCharge 周期扣款值对象：为订阅等周期性业务生成单商品订单并以保存的支付方式离线扣款
+type RecurringCharge struct {
+	UserID      string
+	ProductID   string
+	ProductName string
+	Category    string
+	Amount      Money         // 商品金额（税前）
+	Credit      Money         // 抵扣的余额，以折扣调整行计入订单
+	Method      PaymentMethod // 保存的支付方式
+	Token       string        // 网关保存的支付方式令牌
+
+	// OrderID 预先分配的订单ID（可选）：该订单已存在时沿用订单及其结果未知的支付尝试，
+	// 网关幂等键不变；扣款结果未知或网关处理中时订单保持待支付，以同一ID重试即可续上
+	OrderID string
+}
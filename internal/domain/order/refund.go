@@ -0,0 +1,153 @@
+package order
+
+import (
+	"errors"
+	"time"
+)
+
+// RefundStatus 退款状态
+type RefundStatus string
+
+const (
+	RefundStatusPending   RefundStatus = "pending"   // 已登记，等待网关结果
+	RefundStatusSucceeded RefundStatus = "succeeded" // 网关已退款
+	RefundStatusFailed    RefundStatus = "failed"    // 网关退款失败
+)
+
+// Refund 退款实体（退款台账中的一笔记录）
+type Refund struct {
+	ID              string
+	OrderID         string
+	PaymentID       string
+	Amount          Money
+	Reason          string
+	Status          RefundStatus
+	IdempotencyKey  string // 调用方提供的幂等键，可为空
+	Actor           string // 发起方，格式同订单状态变更
+	GatewayResponse string
+	Lines           []*RefundLine
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// RefundLine 退款行，记录退款对应的订单项与数量
+type RefundLine struct {
+	ID          string
+	RefundID    string
+	OrderItemID string
+	Quantity    int
+	Amount      Money
+}
+
+// RefundAllocation 退款行分配请求
+type RefundAllocation struct {
+	OrderItemID string
+	Quantity    int
+	Amount      float64 // 为 0 时按订单项的实付单价（扣除分摊的折扣，含价外税）计算
+}
+
+// NewRefund 创建待处理的退款
+// 指定退款行时金额可省略（取各行之和），同时指定时须与各行之和一致
+func NewRefund(o *Order, p *Payment, amount float64, reason, actor string, allocations []RefundAllocation) (*Refund, error) {
+	if o == nil || p == nil || p.OrderID != o.ID {
+		return nil, errors.New("refund requires the order and its payment")
+	}
+
+	lines := make([]*RefundLine, 0, len(allocations))
+	linesTotal := 0.0
+	for _, alloc := range allocations {
+		item := o.findItem(alloc.OrderItemID)
+		if item == nil || alloc.Quantity <= 0 || alloc.Quantity > item.Quantity || alloc.Amount < 0 {
+			return nil, ErrInvalidRefundAllocation
+		}
+
+		lineAmount := alloc.Amount
+		if lineAmount == 0 {
+			lineAmount = o.unitRefundable(item) * float64(alloc.Quantity)
+		}
+		lineAmount = roundAmount(lineAmount)
+
+		lines = append(lines, &RefundLine{
+			OrderItemID: item.ID,
+			Quantity:    alloc.Quantity,
+			Amount:      NewMoney(lineAmount, p.Amount.Currency),
+		})
+		linesTotal += lineAmount
+	}
+
+	amount = roundAmount(amount)
+	if len(lines) > 0 {
+		linesTotal = roundAmount(linesTotal)
+		if amount == 0 {
+			amount = linesTotal
+		} else if amount != linesTotal {
+			return nil, ErrInvalidRefundAmount
+		}
+	}
+	if amount <= 0 {
+		return nil, ErrInvalidRefundAmount
+	}
+
+	now := time.Now()
+	return &Refund{
+		OrderID:   o.ID,
+		PaymentID: p.ID,
+		Amount:    NewMoney(amount, p.Amount.Currency),
+		Reason:    reason,
+		Status:    RefundStatusPending,
+		Actor:     actor,
+		Lines:     lines,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// MarkAsSucceeded 标记为退款成功
+func (r *Refund) MarkAsSucceeded(gatewayResponse string) error {
+	if r.Status != RefundStatusPending {
+		return errors.New("can only complete pending refunds")
+	}
+	r.Status = RefundStatusSucceeded
+	r.GatewayResponse = gatewayResponse
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// MarkAsFailed 标记为退款失败
+func (r *Refund) MarkAsFailed(gatewayResponse string) error {
+	if r.Status != RefundStatusPending {
+		return errors.New("can only fail pending refunds")
+	}
+	r.Status = RefundStatusFailed
+	r.GatewayResponse = gatewayResponse
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsOutstanding 是否占用可退金额（待处理或已成功）
+func (r *Refund) IsOutstanding() bool {
+	return r.Status == RefundStatusPending || r.Status == RefundStatusSucceeded
+}
+
+// findItem 查找订单项
+func (o *Order) findItem(itemID string) *OrderItem {
+	for _, item := range o.Items {
+		if item.ID == itemID {
+			return item
+		}
+	}
+	return nil
+}
+
+// unitRefundable 单件应退金额：订单项计税基数（已扣除按 DiscountShare 分摊的优惠与余额抵扣）
+// 按数量均摊，价外税时包含单件税额，与计税和实付金额使用同一分摊
+func (o *Order) unitRefundable(item *OrderItem) float64 {
+	if item.Quantity == 0 {
+		return 0
+	}
+	paid := o.TaxableAmount(item)
+	if !o.TaxInclusive {
+		paid += item.TaxAmount.Amount
+	}
+	return paid / float64(item.Quantity)
+}
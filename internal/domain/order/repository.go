@@ -1,6 +1,9 @@
 package order
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // OrderRepository 订单仓储接口
 type OrderRepository interface {
@@ -13,12 +16,25 @@ type OrderRepository interface {
 	// FindByID 根据ID查找订单
 	FindByID(ctx context.Context, id string) (*Order, error)
 
+	// FindByIDForUpdate 根据ID查找并锁定订单，需在事务中调用
+	FindByIDForUpdate(ctx context.Context, id string) (*Order, error)
+
+	// LockExpiredPending 锁定创建早于 createdBefore 的待支付订单，按ID升序取 afterID 之后的至多 limit 条
+	// 已被其他事务锁定的订单会被跳过，需在事务中调用
+	LockExpiredPending(ctx context.Context, createdBefore time.Time, afterID string, limit int) ([]*Order, error)
+
 	// FindByOrderNumber 根据订单号查找订单
 	FindByOrderNumber(ctx context.Context, orderNumber string) (*Order, error)
 
 	// ListByUserID 列出用户的订单
 	ListByUserID(ctx context.Context, userID string, offset, limit int) ([]*Order, int64, error)
 
+	// Search 按条件搜索订单，返回当前页与符合条件的总数
+	Search(ctx context.Context, criteria SearchCriteria) ([]*Order, int64, error)
+
+	// SearchAfter 按条件搜索ID大于 afterID 的订单，按ID升序取至多 limit 条，忽略排序与分页参数，用于导出
+	SearchAfter(ctx context.Context, criteria SearchCriteria, afterID string, limit int) ([]*Order, error)
+
 	// Delete 删除订单
 	Delete(ctx context.Context, id string) error
 }
@@ -34,11 +50,92 @@ type PaymentRepository interface {
 	// FindByID 根据ID查找支付
 	FindByID(ctx context.Context, id string) (*Payment, error)
 
-	// FindByOrderID 根据订单ID查找支付
-	FindByOrderID(ctx context.Context, orderID string) (*Payment, error)
+	// ListByOrderID 列出订单的全部支付尝试，按创建时间升序
+	ListByOrderID(ctx context.Context, orderID string) ([]*Payment, error)
+
+	// ListByOrderIDs 批量列出多个订单的支付尝试，按创建时间升序
+	ListByOrderIDs(ctx context.Context, orderIDs []string) ([]*Payment, error)
+
+	// FindLatestByOrderID 查找订单最近一次支付尝试
+	FindLatestByOrderID(ctx context.Context, orderID string) (*Payment, error)
+
+	// FindCapturedByOrderID 查找订单已收款的支付（含之后退款或拒付的），有多笔时取最早的一笔
+	FindCapturedByOrderID(ctx context.Context, orderID string) (*Payment, error)
 
 	// FindByTransactionID 根据交易ID查找支付
 	FindByTransactionID(ctx context.Context, transactionID string) (*Payment, error)
+
+	// FindByIDForUpdate 根据ID查找并锁定支付，需在事务中调用
+	FindByIDForUpdate(ctx context.Context, id string) (*Payment, error)
+}
+
+// RefundRepository 退款仓储接口
+type RefundRepository interface {
+	// Create 创建退款（含退款行）
+	Create(ctx context.Context, refund *Refund) error
+
+	// Update 更新退款状态
+	Update(ctx context.Context, refund *Refund) error
+
+	// FindByID 根据ID查找退款
+	FindByID(ctx context.Context, id string) (*Refund, error)
+
+	// FindByIDForUpdate 根据ID查找并锁定退款，需在事务中调用
+	FindByIDForUpdate(ctx context.Context, id string) (*Refund, error)
+
+	// FindByIdempotencyKey 根据幂等键查找退款
+	FindByIdempotencyKey(ctx context.Context, key string) (*Refund, error)
+
+	// ListPending 按ID升序列出 updatedBefore 之前更新、仍待处理的退款，取 afterID 之后的至多 limit 条
+	ListPending(ctx context.Context, updatedBefore time.Time, afterID string, limit int) ([]*Refund, error)
+
+	// ListByPaymentID 列出支付的所有退款
+	ListByPaymentID(ctx context.Context, paymentID string) ([]*Refund, error)
+
+	// ListByOrderID 列出订单的所有退款，按创建时间排序
+	ListByOrderID(ctx context.Context, orderID string) ([]*Refund, error)
+
+	// ListByOrderIDs 批量列出多个订单的退款，按创建时间排序
+	ListByOrderIDs(ctx context.Context, orderIDs []string) ([]*Refund, error)
+}
+
+// ReturnRepository 退货仓储接口
+type ReturnRepository interface {
+	// Create 创建退货（含退货行）
+	Create(ctx context.Context, r *Return) error
+
+	// Update 更新退货
+	Update(ctx context.Context, r *Return) error
+
+	// FindByID 根据ID查找退货
+	FindByID(ctx context.Context, id string) (*Return, error)
+
+	// FindByIDForUpdate 根据ID查找并锁定退货，需在事务中调用
+	FindByIDForUpdate(ctx context.Context, id string) (*Return, error)
+
+	// ListByOrderID 列出订单的所有退货，按创建时间排序
+	ListByOrderID(ctx context.Context, orderID string) ([]*Return, error)
+}
+
+// FraudAssessmentRepository 欺诈筛查记录仓储接口
+type FraudAssessmentRepository interface {
+	// Create 创建筛查记录
+	Create(ctx context.Context, assessment *FraudAssessment) error
+
+	// Update 更新审核决定
+	Update(ctx context.Context, assessment *FraudAssessment) error
+
+	// ListByOrderID 列出订单的筛查记录，按创建时间排序
+	ListByOrderID(ctx context.Context, orderID string) ([]*FraudAssessment, error)
+
+	// ListAwaitingReview 列出等待人工审核的筛查记录（分页），按创建时间升序
+	ListAwaitingReview(ctx context.Context, offset, limit int) ([]*FraudAssessment, int64, error)
+
+	// CountUserOrdersSince 统计用户 since 之后经筛查的其他订单数（不含 excludeOrderID）
+	CountUserOrdersSince(ctx context.Context, userID, excludeOrderID string, since time.Time) (int64, error)
+
+	// CountIPOrdersSince 统计 IP since 之后经筛查的其他订单数（不含 excludeOrderID）
+	CountIPOrdersSince(ctx context.Context, clientIP, excludeOrderID string, since time.Time) (int64, error)
 }
 
 // ShipmentRepository 发货仓储接口
@@ -52,9 +149,40 @@ type ShipmentRepository interface {
 	// FindByID 根据ID查找发货
 	FindByID(ctx context.Context, id string) (*Shipment, error)
 
-	// FindByOrderID 根据订单ID查找发货
-	FindByOrderID(ctx context.Context, orderID string) (*Shipment, error)
+	// FindByIDForUpdate 根据ID查找并锁定发货，需在事务中调用
+	FindByIDForUpdate(ctx context.Context, id string) (*Shipment, error)
+
+	// ListByOrderID 列出订单的全部发货，按创建时间升序
+	ListByOrderID(ctx context.Context, orderID string) ([]*Shipment, error)
+
+	// ListByOrderIDs 批量列出多个订单的发货（不含跟踪时间线），按创建时间升序
+	ListByOrderIDs(ctx context.Context, orderIDs []string) ([]*Shipment, error)
 
 	// FindByTrackingNumber 根据追踪号查找发货
 	FindByTrackingNumber(ctx context.Context, trackingNumber string) (*Shipment, error)
+
+	// ListByStatus 按ID升序列出指定状态的发货，取 afterID 之后的至多 limit 条
+	ListByStatus(ctx context.Context, status ShipmentStatus, afterID string, limit int) ([]*Shipment, error)
+}
+
+// ExportJobRepository 导出任务仓储接口
+type ExportJobRepository interface {
+	// Create 创建导出任务
+	Create(ctx context.Context, job *ExportJob) error
+
+	// Update 更新导出任务
+	Update(ctx context.Context, job *ExportJob) error
+
+	// FindByID 根据ID查找导出任务
+	FindByID(ctx context.Context, id string) (*ExportJob, error)
+
+	// LockNext 锁定最早的待执行任务（含开始早于 staleBefore 仍未完成的任务），没有时返回 nil
+	// 已被其他事务锁定的任务会被跳过，需在事务中调用
+	LockNext(ctx context.Context, staleBefore time.Time) (*ExportJob, error)
+}
+
+// GatewayEventRepository 支付网关回调事件仓储接口
+type GatewayEventRepository interface {
+	// Record 记录事件，事件已处理过时返回 false
+	Record(ctx context.Context, event *GatewayEvent) (bool, error)
 }
@@ -0,0 +1,234 @@
+package order
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReturnStatus 退货状态
+type ReturnStatus string
+
+const (
+	ReturnStatusRequested ReturnStatus = "requested" // 顾客已申请，等待审核
+	ReturnStatusApproved  ReturnStatus = "approved"  // 已批准，退货面单已生成
+	ReturnStatusRejected  ReturnStatus = "rejected"  // 已拒绝
+	ReturnStatusReceived  ReturnStatus = "received"  // 已收货并质检
+	ReturnStatusRefunded  ReturnStatus = "refunded"  // 已按质检合格的商品退款
+	ReturnStatusClosed    ReturnStatus = "closed"    // 质检无合格商品，不退款
+)
+
+// Return 退货实体（RMA）
+type Return struct {
+	ID             string
+	OrderID        string
+	UserID         string
+	Status         ReturnStatus
+	Reason         string
+	Note           string // 审核或质检备注
+	Lines          []*ReturnLine
+	Carrier        string // 退货面单的承运商
+	TrackingNumber string
+	LabelURL       string
+	RefundID       string // 最近一次发起的退款
+	ApprovedAt     *time.Time
+	RejectedAt     *time.Time
+	ReceivedAt     *time.Time
+	RefundedAt     *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// ReturnLine 退货行
+type ReturnLine struct {
+	ID               string
+	ReturnID         string
+	OrderItemID      string
+	Quantity         int // 申请退货数量
+	AcceptedQuantity int // 质检合格数量
+}
+
+// ReturnPolicy 退货政策：按商品分类配置退货期限，期限为 0 的分类不可退货
+type ReturnPolicy struct {
+	DefaultWindow   time.Duration
+	CategoryWindows map[string]time.Duration // 分类名小写
+}
+
+// WindowFor 返回商品分类的退货期限
+func (p ReturnPolicy) WindowFor(category string) time.Duration {
+	if window, ok := p.CategoryWindows[strings.ToLower(category)]; ok {
+		return window
+	}
+	return p.DefaultWindow
+}
+
+// NewReturn 创建退货申请
+func NewReturn(o *Order, reason string, lines []*ReturnLine) (*Return, error) {
+	if o == nil {
+		return nil, fmt.Errorf("%w: order is required", ErrInvalidReturnLines)
+	}
+	if strings.TrimSpace(reason) == "" {
+		return nil, fmt.Errorf("%w: reason is required", ErrInvalidReturnLines)
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("%w: return has no lines", ErrInvalidReturnLines)
+	}
+	seen := make(map[string]bool, len(lines))
+	for _, line := range lines {
+		if seen[line.OrderItemID] {
+			return nil, fmt.Errorf("%w: duplicate order item %s", ErrInvalidReturnLines, line.OrderItemID)
+		}
+		seen[line.OrderItemID] = true
+		if o.findItem(line.OrderItemID) == nil {
+			return nil, fmt.Errorf("%w: order item %s not found", ErrInvalidReturnLines, line.OrderItemID)
+		}
+		if line.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: quantity must be positive", ErrInvalidReturnLines)
+		}
+	}
+
+	now := time.Now()
+	return &Return{
+		OrderID:   o.ID,
+		UserID:    o.UserID,
+		Status:    ReturnStatusRequested,
+		Reason:    reason,
+		Lines:     lines,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// Approve 批准退货并关联退货面单
+func (r *Return) Approve(label ShippingLabel, note string) error {
+	if r.Status != ReturnStatusRequested {
+		return fmt.Errorf("%w: can only approve requested returns", ErrInvalidReturnStatus)
+	}
+
+	now := time.Now()
+	r.Status = ReturnStatusApproved
+	r.Carrier = label.Carrier
+	r.TrackingNumber = label.TrackingNumber
+	r.LabelURL = label.LabelURL
+	r.Note = note
+	r.ApprovedAt = &now
+	r.UpdatedAt = now
+	return nil
+}
+
+// Reject 拒绝退货
+func (r *Return) Reject(note string) error {
+	if r.Status != ReturnStatusRequested {
+		return fmt.Errorf("%w: can only reject requested returns", ErrInvalidReturnStatus)
+	}
+
+	now := time.Now()
+	r.Status = ReturnStatusRejected
+	r.Note = note
+	r.RejectedAt = &now
+	r.UpdatedAt = now
+	return nil
+}
+
+// Receive 收货并记录质检结果（订单项ID -> 合格数量），accepted 为空时全部合格
+// 无合格商品时退货关闭，不再退款
+func (r *Return) Receive(accepted map[string]int, note string) error {
+	if r.Status != ReturnStatusApproved {
+		return fmt.Errorf("%w: can only receive approved returns", ErrInvalidReturnStatus)
+	}
+	for itemID := range accepted {
+		if r.findLine(itemID) == nil {
+			return fmt.Errorf("%w: order item %s is not part of the return", ErrInvalidReturnLines, itemID)
+		}
+	}
+
+	total := 0
+	for _, line := range r.Lines {
+		qty := line.Quantity
+		if accepted != nil {
+			qty = accepted[line.OrderItemID]
+		}
+		if qty < 0 || qty > line.Quantity {
+			return fmt.Errorf("%w: accepted quantity of item %s out of range", ErrInvalidReturnLines, line.OrderItemID)
+		}
+		line.AcceptedQuantity = qty
+		total += qty
+	}
+
+	now := time.Now()
+	r.Status = ReturnStatusReceived
+	if total == 0 {
+		r.Status = ReturnStatusClosed
+	}
+	r.Note = note
+	r.ReceivedAt = &now
+	r.UpdatedAt = now
+	return nil
+}
+
+// AttachRefund 记录为合格商品发起的退款
+// 已关联退款时只有前次退款（previous）失败后才能替换，避免同一退货重复退款
+func (r *Return) AttachRefund(refundID string, previous *Refund) error {
+	if r.Status != ReturnStatusReceived {
+		return fmt.Errorf("%w: can only refund received returns", ErrInvalidReturnStatus)
+	}
+	if r.RefundID != "" && (previous == nil || previous.ID != r.RefundID || previous.Status != RefundStatusFailed) {
+		return fmt.Errorf("%w: return already has a refund", ErrInvalidReturnStatus)
+	}
+	r.RefundID = refundID
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// MarkRefunded 标记退款完成
+func (r *Return) MarkRefunded() error {
+	if r.Status != ReturnStatusReceived || r.RefundID == "" {
+		return fmt.Errorf("%w: return has no refund to complete", ErrInvalidReturnStatus)
+	}
+
+	now := time.Now()
+	r.Status = ReturnStatusRefunded
+	r.RefundedAt = &now
+	r.UpdatedAt = now
+	return nil
+}
+
+// AcceptedAllocations 按质检合格数量生成退款行分配
+func (r *Return) AcceptedAllocations() []RefundAllocation {
+	allocations := make([]RefundAllocation, 0, len(r.Lines))
+	for _, line := range r.Lines {
+		if line.AcceptedQuantity > 0 {
+			allocations = append(allocations, RefundAllocation{
+				OrderItemID: line.OrderItemID,
+				Quantity:    line.AcceptedQuantity,
+			})
+		}
+	}
+	return allocations
+}
+
+// IsActive 是否占用可退货数量（被拒绝或关闭的退货不占用）
+func (r *Return) IsActive() bool {
+	return r.Status != ReturnStatusRejected && r.Status != ReturnStatusClosed
+}
+
+// findLine 按订单项ID查找退货行
+func (r *Return) findLine(orderItemID string) *ReturnLine {
+	for _, line := range r.Lines {
+		if line.OrderItemID == orderItemID {
+			return line
+		}
+	}
+	return nil
+}
+
+// ReturnParcel 按退货行汇总退货包裹
+func (o *Order) ReturnParcel(r *Return) Parcel {
+	weight := 0.0
+	for _, line := range r.Lines {
+		if item := o.findItem(line.OrderItemID); item != nil {
+			weight += item.WeightKg * float64(line.Quantity)
+		}
+	}
+	return Parcel{WeightKg: weight}
+}
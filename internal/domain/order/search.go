@@ -0,0 +1,96 @@
+package order
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SortKey 订单排序字段
+type SortKey string
+
+const (
+	SortByCreatedAt   SortKey = "created_at"
+	SortByUpdatedAt   SortKey = "updated_at"
+	SortByTotalAmount SortKey = "total_amount"
+	SortByOrderNumber SortKey = "order_number"
+	SortByStatus      SortKey = "status"
+)
+
+// IsValid 检查排序字段是否有效
+func (k SortKey) IsValid() bool {
+	switch k {
+	case SortByCreatedAt, SortByUpdatedAt, SortByTotalAmount, SortByOrderNumber, SortByStatus:
+		return true
+	}
+	return false
+}
+
+// SortField 排序条件
+type SortField struct {
+	Key  SortKey
+	Desc bool
+}
+
+// ParseSort 解析排序表达式，如 "-created_at,total_amount"（前缀 - 表示降序）
+func ParseSort(expr string) ([]SortField, error) {
+	fields := make([]SortField, 0)
+	for _, part := range strings.Split(expr, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		field := SortField{}
+		if strings.HasPrefix(part, "-") {
+			field.Desc = true
+			part = part[1:]
+		} else {
+			part = strings.TrimPrefix(part, "+")
+		}
+		field.Key = SortKey(part)
+		if !field.Key.IsValid() {
+			return nil, fmt.Errorf("%w: unknown sort field %q", ErrInvalidSearchCriteria, part)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// SearchCriteria 订单搜索条件
+type SearchCriteria struct {
+	Statuses          []OrderStatus
+	UserID            string
+	CreatedFrom       *time.Time
+	CreatedTo         *time.Time
+	MinAmount         *float64
+	MaxAmount         *float64
+	Currency          string
+	PaymentMethod     PaymentMethod
+	OrderNumberPrefix string
+	Query             string // 全文检索：订单号、商品名称
+	NeedsAttention    bool   // 仅待管理员处理的订单
+	Sort              []SortField
+	Offset            int
+	Limit             int
+}
+
+// Validate 验证搜索条件
+func (c SearchCriteria) Validate() error {
+	for _, status := range c.Statuses {
+		if !status.IsValid() {
+			return fmt.Errorf("%w: unknown status %q", ErrInvalidSearchCriteria, status)
+		}
+	}
+	if c.CreatedFrom != nil && c.CreatedTo != nil && c.CreatedFrom.After(*c.CreatedTo) {
+		return fmt.Errorf("%w: created_from is after created_to", ErrInvalidSearchCriteria)
+	}
+	if c.MinAmount != nil && c.MaxAmount != nil && *c.MinAmount > *c.MaxAmount {
+		return fmt.Errorf("%w: min_amount is greater than max_amount", ErrInvalidSearchCriteria)
+	}
+	for _, field := range c.Sort {
+		if !field.Key.IsValid() {
+			return fmt.Errorf("%w: unknown sort field %q", ErrInvalidSearchCriteria, field.Key)
+		}
+	}
+	return nil
+}
@@ -12,20 +12,35 @@ type Service struct {
 	orderRepo    OrderRepository
 	paymentRepo  PaymentRepository
 	shipmentRepo ShipmentRepository
+	refundRepo   RefundRepository
+	returnRepo   ReturnRepository
+	fraudRepo    FraudAssessmentRepository
+	numbers      OrderNumberGenerator
+	numberFormat OrderNumberFormat
 }
 
 // NewService 创建订单领域服务
-func NewService(orderRepo OrderRepository, paymentRepo PaymentRepository, shipmentRepo ShipmentRepository) *Service {
+func NewService(orderRepo OrderRepository, paymentRepo PaymentRepository, shipmentRepo ShipmentRepository, refundRepo RefundRepository, returnRepo ReturnRepository, fraudRepo FraudAssessmentRepository, numbers OrderNumberGenerator, numberFormat OrderNumberFormat) *Service {
 	return &Service{
 		orderRepo:    orderRepo,
 		paymentRepo:  paymentRepo,
 		shipmentRepo: shipmentRepo,
+		refundRepo:   refundRepo,
+		returnRepo:   returnRepo,
+		fraudRepo:    fraudRepo,
+		numbers:      numbers,
+		numberFormat: numberFormat,
 	}
 }
 
 // GenerateOrderNumber 生成订单号
-func (s *Service) GenerateOrderNumber() string {
-	return fmt.Sprintf("ORD-%d", time.Now().UnixNano())
+func (s *Service) GenerateOrderNumber(ctx context.Context) (string, error) {
+	return s.numbers.Generate(ctx)
+}
+
+// ValidateOrderNumber 校验订单号格式，避免无效订单号查询数据库
+func (s *Service) ValidateOrderNumber(orderNumber string) error {
+	return s.numberFormat.Validate(orderNumber)
 }
 
 // ValidateOrderForPayment 验证订单是否可以支付
@@ -35,6 +50,10 @@ func (s *Service) ValidateOrderForPayment(ctx context.Context, orderID string) e
 		return err
 	}
 
+	if order.Status == StatusReview {
+		return ErrOrderUnderReview
+	}
+
 	if order.Status != StatusPending {
 		return errors.New("only pending orders can be paid")
 	}
@@ -50,6 +69,59 @@ func (s *Service) ValidateOrderForPayment(ctx context.Context, orderID string) e
 	return nil
 }
 
+// ValidatePaymentAttempt 验证订单能否发起新的支付尝试
+// 订单只允许一笔成功收款：已有授权或已收款的尝试时拒绝，失败或待确认的尝试不影响重试
+func (s *Service) ValidatePaymentAttempt(ctx context.Context, orderID string) error {
+	attempts, err := s.paymentRepo.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range attempts {
+		if p.IsAuthorized() || p.IsCaptured() {
+			return ErrPaymentAlreadySucceeded
+		}
+	}
+
+	return nil
+}
+
+// ResumablePaymentAttempt 查找可沿用的支付尝试：网关结果未知、支付方式与金额一致的最近一次尝试，没有时返回 nil
+// 重试时沿用该尝试即沿用其幂等键，网关对首次请求已扣款时返回同一结果，不会再次扣款
+func (s *Service) ResumablePaymentAttempt(ctx context.Context, orderID string, method PaymentMethod, amount Money) (*Payment, error) {
+	attempts, err := s.paymentRepo.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(attempts) - 1; i >= 0; i-- {
+		p := attempts[i]
+		if p.AwaitingGateway() && p.Method == method && p.Amount == amount {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+// HasPaymentInProgress 订单是否有进行中的支付：已授权待请款，或 since 之后发起且仍待确认的尝试
+// 更早的待确认尝试（如放弃的持卡人验证）不再阻止订单超时取消
+func (s *Service) HasPaymentInProgress(ctx context.Context, orderID string, since time.Time) (bool, error) {
+	attempts, err := s.paymentRepo.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, p := range attempts {
+		if p.IsAuthorized() {
+			return true, nil
+		}
+		if p.Status == PaymentStatusPending && p.CreatedAt.After(since) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // ValidateOrderForShipment 验证订单是否可以发货
 func (s *Service) ValidateOrderForShipment(ctx context.Context, orderID string) error {
 	order, err := s.orderRepo.FindByID(ctx, orderID)
@@ -74,42 +146,209 @@ func (s *Service) CanCancelOrder(ctx context.Context, orderID string) (bool, err
 	return order.CanBeCancelled(), nil
 }
 
-// ValidateRefund 验证是否可以退款
-func (s *Service) ValidateRefund(ctx context.Context, orderID string) error {
-	order, err := s.orderRepo.FindByID(ctx, orderID)
+// ValidateRefund 验证退款是否超出可退范围
+// 待处理与已成功的退款都占用额度：同一支付累计金额不超过支付金额，
+// 各订单项在订单所有支付上的累计退款数量不超过购买数量
+func (s *Service) ValidateRefund(ctx context.Context, order *Order, payment *Payment, refund *Refund) error {
+	if !order.CanTransitionTo(StatusRefunded) && !order.CanTransitionTo(StatusPartiallyRefunded) {
+		return ErrCannotRefund
+	}
+	if !payment.CanBeRefunded() {
+		return ErrCannotRefund
+	}
+
+	refunds, err := s.refundRepo.ListByOrderID(ctx, order.ID)
 	if err != nil {
 		return err
 	}
 
-	if order.Status != StatusPaid && order.Status != StatusCompleted {
-		return errors.New("only paid or completed orders can be refunded")
+	outstanding := refund.Amount.Amount
+	quantities := make(map[string]int)
+	for _, line := range refund.Lines {
+		quantities[line.OrderItemID] += line.Quantity
 	}
+	for _, r := range refunds {
+		if r.ID == refund.ID || !r.IsOutstanding() {
+			continue
+		}
+		if r.PaymentID == payment.ID {
+			outstanding += r.Amount.Amount
+		}
+		for _, line := range r.Lines {
+			quantities[line.OrderItemID] += line.Quantity
+		}
+	}
+
+	if roundAmount(outstanding) > payment.Amount.Amount {
+		return ErrRefundExceedsPayment
+	}
+	for itemID, qty := range quantities {
+		item := order.findItem(itemID)
+		if item == nil || qty > item.Quantity {
+			return ErrInvalidRefundAllocation
+		}
+	}
+	return nil
+}
 
-	payment, err := s.paymentRepo.FindByOrderID(ctx, orderID)
+// IsOrderFullyRefunded 订单所有已收款的支付是否均已全额退款
+func (s *Service) IsOrderFullyRefunded(ctx context.Context, orderID string) (bool, error) {
+	payments, err := s.paymentRepo.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return false, err
+	}
+
+	captured := false
+	for _, p := range payments {
+		if !p.IsCaptured() {
+			continue
+		}
+		captured = true
+		if !p.IsFullyRefunded() {
+			return false, nil
+		}
+	}
+	return captured, nil
+}
+
+// OutstandingRefunds 支付上待处理与已成功退款的合计金额
+func (s *Service) OutstandingRefunds(ctx context.Context, payment *Payment) (Money, error) {
+	refunds, err := s.refundRepo.ListByPaymentID(ctx, payment.ID)
+	if err != nil {
+		return Money{}, err
+	}
+
+	total := 0.0
+	for _, r := range refunds {
+		if r.IsOutstanding() {
+			total += r.Amount.Amount
+		}
+	}
+	return NewMoney(roundAmount(total), payment.Amount.Currency), nil
+}
+
+// ValidateReturn 验证退货申请：各订单项累计退货数量（不含被拒绝或关闭的）不超过已送达数量，
+// 且申请时间在该商品分类的退货期限内，期限自商品最近一次送达起算
+func (s *Service) ValidateReturn(ctx context.Context, order *Order, r *Return, policy ReturnPolicy, now time.Time) error {
+	returns, err := s.returnRepo.ListByOrderID(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	shipments, err := s.shipmentRepo.ListByOrderID(ctx, order.ID)
 	if err != nil {
 		return err
 	}
 
-	if !payment.CanBeRefunded() {
-		return errors.New("payment cannot be refunded")
+	quantities := make(map[string]int)
+	for _, existing := range returns {
+		if existing.ID == r.ID || !existing.IsActive() {
+			continue
+		}
+		for _, line := range existing.Lines {
+			quantities[line.OrderItemID] += line.Quantity
+		}
 	}
 
+	for _, line := range r.Lines {
+		item := order.findItem(line.OrderItemID)
+		if item == nil {
+			return ErrInvalidReturnLines
+		}
+		if quantities[item.ID]+line.Quantity > item.DeliveredQuantity {
+			return fmt.Errorf("%w: only %d of item %s can be returned", ErrInvalidReturnLines, item.DeliveredQuantity-quantities[item.ID], item.ID)
+		}
+
+		deliveredAt := lastDelivery(shipments, item.ID)
+		window := policy.WindowFor(item.Category)
+		if deliveredAt == nil || window <= 0 || now.After(deliveredAt.Add(window)) {
+			return fmt.Errorf("%w: item %s", ErrReturnWindowExpired, item.ID)
+		}
+	}
 	return nil
 }
 
-// CalculateEstimatedDelivery 计算预计送达时间（示例：7天后）
-func (s *Service) CalculateEstimatedDelivery(shippingMethod string) time.Time {
-	daysToAdd := 7 // 默认7天
+// ReturnOrigin 退货的取件地址：取包含退货商品的最近一次送达发货的地址，缺省为订单收货地址
+func (s *Service) ReturnOrigin(ctx context.Context, order *Order, r *Return) (Address, error) {
+	shipments, err := s.shipmentRepo.ListByOrderID(ctx, order.ID)
+	if err != nil {
+		return Address{}, err
+	}
+
+	var origin *Shipment
+	for _, shipment := range shipments {
+		if !shipment.IsDelivered() || shipment.DeliveredAt == nil || !shipmentContainsAny(shipment, r.Lines) {
+			continue
+		}
+		if origin == nil || shipment.DeliveredAt.After(*origin.DeliveredAt) {
+			origin = shipment
+		}
+	}
+	if origin != nil {
+		return origin.Address, nil
+	}
+	if order.ShippingAddress != nil {
+		return *order.ShippingAddress, nil
+	}
+	return Address{}, ErrShippingAddressRequired
+}
+
+// AssessFraud 在发起支付前按策略筛查订单，已人工审核通过的订单不再筛查（返回 nil）
+// 下单频率按统计窗口内经筛查的不同订单计数，同一订单的重试不重复计入
+func (s *Service) AssessFraud(ctx context.Context, order *Order, policy FraudPolicy, clientIP string, accountAge time.Duration, now time.Time) (*FraudAssessment, error) {
+	assessments, err := s.fraudRepo.ListByOrderID(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range assessments {
+		if a.IsApproved() {
+			return nil, nil
+		}
+	}
 
-	switch shippingMethod {
-	case "express":
-		daysToAdd = 2
-	case "standard":
-		daysToAdd = 5
-	case "economy":
-		daysToAdd = 10
+	signals := FraudSignals{ClientIP: clientIP, AccountAge: accountAge, UserOrders: 1, IPOrders: 1}
+	since := now.Add(-policy.VelocityWindow)
+	if policy.UserVelocityScore > 0 {
+		count, err := s.fraudRepo.CountUserOrdersSince(ctx, order.UserID, order.ID, since)
+		if err != nil {
+			return nil, err
+		}
+		signals.UserOrders += int(count)
+	}
+	if policy.IPVelocityScore > 0 && clientIP != "" {
+		count, err := s.fraudRepo.CountIPOrdersSince(ctx, clientIP, order.ID, since)
+		if err != nil {
+			return nil, err
+		}
+		signals.IPOrders += int(count)
 	}
 
-	return time.Now().AddDate(0, 0, daysToAdd)
+	return NewFraudAssessment(order, policy, signals), nil
 }
 
+// lastDelivery 订单项最近一次送达的时间
+func lastDelivery(shipments []*Shipment, orderItemID string) *time.Time {
+	var last *time.Time
+	for _, shipment := range shipments {
+		if !shipment.IsDelivered() || shipment.DeliveredAt == nil {
+			continue
+		}
+		for _, line := range shipment.Lines {
+			if line.OrderItemID == orderItemID && (last == nil || shipment.DeliveredAt.After(*last)) {
+				last = shipment.DeliveredAt
+			}
+		}
+	}
+	return last
+}
+
+// shipmentContainsAny 发货是否包含任一退货商品
+func shipmentContainsAny(shipment *Shipment, lines []*ReturnLine) bool {
+	for _, sl := range shipment.Lines {
+		for _, rl := range lines {
+			if sl.OrderItemID == rl.OrderItemID {
+				return true
+			}
+		}
+	}
+	return false
+}
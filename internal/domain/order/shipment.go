@@ -2,6 +2,8 @@ package order
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -25,10 +27,17 @@ type Address struct {
 	Country    string
 }
 
-// NewAddress 创建地址
+// NewAddress 创建地址，国家代码与邮编统一为大写并按国家校验邮编格式
 func NewAddress(street, city, state, postalCode, country string) (Address, error) {
+	street = strings.TrimSpace(street)
+	city = strings.TrimSpace(city)
+	country = strings.ToUpper(strings.TrimSpace(country))
+	postalCode = strings.ToUpper(strings.TrimSpace(postalCode))
 	if street == "" || city == "" || country == "" {
-		return Address{}, errors.New("street, city, and country are required")
+		return Address{}, fmt.Errorf("%w: street, city, and country are required", ErrInvalidAddress)
+	}
+	if err := validatePostalCode(country, postalCode); err != nil {
+		return Address{}, err
 	}
 	return Address{
 		Street:     street,
@@ -51,45 +60,107 @@ type Shipment struct {
 	TrackingNumber string
 	Carrier        string
 	ShippingMethod string
+	ShippingCost   Money  // 选定运费报价的金额
+	LabelURL       string // 承运商面单地址
 	Address        Address
+	Lines          []*ShipmentLine // 发货包含的订单项及数量
 	Status         ShipmentStatus
 	EstimatedDate  *time.Time
 	ShippedAt      *time.Time
 	DeliveredAt    *time.Time
+	Events         []*ShipmentEvent // 跟踪时间线，按发生时间升序
+	Version        int              // 乐观锁版本号，每次保存后递增
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 }
 
-// NewShipment 创建发货
-func NewShipment(orderID string, address Address, shippingMethod string) (*Shipment, error) {
+// NewShipment 创建发货，发货行需已通过 Order.AllocateShipment 校验
+func NewShipment(orderID string, address Address, shippingMethod string, lines []*ShipmentLine) (*Shipment, error) {
 	if orderID == "" {
 		return nil, errors.New("orderID cannot be empty")
 	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("%w: shipment has no lines", ErrInvalidShipmentLines)
+	}
 
-	return &Shipment{
+	s := &Shipment{
 		OrderID:        orderID,
 		Address:        address,
+		Lines:          lines,
 		ShippingMethod: shippingMethod,
 		Status:         ShipmentStatusPending,
+		Version:        1,
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
-	}, nil
+	}
+	s.recordTransition("shipment created")
+	return s, nil
+}
+
+// ApplyRate 采用承运商报价：记录承运商、运输服务、运费与预计送达时间
+func (s *Shipment) ApplyRate(rate ShippingRate) error {
+	if s.Status != ShipmentStatusPending && s.Status != ShipmentStatusProcessing {
+		return fmt.Errorf("%w: can only rate shipments that have not been shipped", ErrInvalidShipmentStatus)
+	}
+
+	now := time.Now()
+	s.Carrier = rate.Carrier
+	s.ShippingMethod = rate.Service
+	s.ShippingCost = rate.Amount
+	estimated := rate.EstimatedDelivery(now)
+	s.EstimatedDate = &estimated
+	s.UpdatedAt = now
+	return nil
+}
+
+// CheckLabelPurchasable 校验发货能否购买面单：尚未发出且没有已购买的面单
+func (s *Shipment) CheckLabelPurchasable() error {
+	if s.Status != ShipmentStatusPending && s.Status != ShipmentStatusProcessing {
+		return fmt.Errorf("%w: can only buy labels for shipments that have not been shipped", ErrInvalidShipmentStatus)
+	}
+	if s.TrackingNumber != "" {
+		return fmt.Errorf("%w: shipment already has a label", ErrInvalidShipmentStatus)
+	}
+	return nil
+}
+
+// AttachLabel 关联已购买的面单，发出时沿用面单上的追踪号
+func (s *Shipment) AttachLabel(label ShippingLabel) error {
+	if err := s.CheckLabelPurchasable(); err != nil {
+		return err
+	}
+	if label.TrackingNumber == "" {
+		return errors.New("tracking number is required")
+	}
+
+	s.TrackingNumber = label.TrackingNumber
+	s.LabelURL = label.LabelURL
+	s.UpdatedAt = time.Now()
+	s.recordTransition("label purchased from " + s.Carrier)
+	return nil
 }
 
 // StartProcessing 开始处理
 func (s *Shipment) StartProcessing() error {
 	if s.Status != ShipmentStatusPending {
-		return errors.New("can only start processing pending shipments")
+		return fmt.Errorf("%w: can only start processing pending shipments", ErrInvalidShipmentStatus)
 	}
 	s.Status = ShipmentStatusProcessing
 	s.UpdatedAt = time.Now()
+	s.recordTransition("shipment is being prepared")
 	return nil
 }
 
-// Ship 发货
+// Ship 发货，未传追踪号或承运商时沿用已购面单上的
 func (s *Shipment) Ship(trackingNumber, carrier string) error {
 	if s.Status != ShipmentStatusProcessing {
-		return errors.New("can only ship processing shipments")
+		return fmt.Errorf("%w: can only ship processing shipments", ErrInvalidShipmentStatus)
+	}
+	if trackingNumber == "" {
+		trackingNumber = s.TrackingNumber
+	}
+	if carrier == "" {
+		carrier = s.Carrier
 	}
 	if trackingNumber == "" {
 		return errors.New("tracking number is required")
@@ -101,29 +172,32 @@ func (s *Shipment) Ship(trackingNumber, carrier string) error {
 	now := time.Now()
 	s.ShippedAt = &now
 	s.UpdatedAt = now
+	s.recordTransition("handed over to " + carrier)
 	return nil
 }
 
 // Deliver 确认送达
 func (s *Shipment) Deliver() error {
 	if s.Status != ShipmentStatusShipped {
-		return errors.New("can only deliver shipped shipments")
+		return fmt.Errorf("%w: can only deliver shipped shipments", ErrInvalidShipmentStatus)
 	}
 
 	s.Status = ShipmentStatusDelivered
 	now := time.Now()
 	s.DeliveredAt = &now
 	s.UpdatedAt = now
+	s.recordTransition("delivered")
 	return nil
 }
 
 // Cancel 取消发货
-func (s *Shipment) Cancel() error {
-	if s.Status == ShipmentStatusDelivered {
-		return errors.New("cannot cancel delivered shipments")
+func (s *Shipment) Cancel(reason string) error {
+	if !s.CanBeCancelled() {
+		return fmt.Errorf("%w: cannot cancel %s shipments", ErrInvalidShipmentStatus, s.Status)
 	}
 	s.Status = ShipmentStatusCancelled
 	s.UpdatedAt = time.Now()
+	s.recordTransition(reason)
 	return nil
 }
 
@@ -140,6 +214,5 @@ func (s *Shipment) IsDelivered() bool {
 
 // CanBeCancelled 是否可以取消
 func (s *Shipment) CanBeCancelled() bool {
-	return s.Status != ShipmentStatusDelivered
+	return s.Status != ShipmentStatusDelivered && s.Status != ShipmentStatusCancelled
 }
-
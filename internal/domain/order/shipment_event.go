@@ -0,0 +1,70 @@
+package order
+
+import "time"
+
+// ShipmentEvent 发货跟踪事件（时间线中的一条记录）
+// 生命周期变更由发货实体自动记录；承运商轨迹（如到达分拨中心）以承运商状态原样记录
+type ShipmentEvent struct {
+	ID          string
+	ShipmentID  string
+	Status      string // 发货状态或承运商轨迹状态
+	Location    string
+	Description string
+	RawPayload  string // 承运商原始数据，生命周期事件为空
+	OccurredAt  time.Time
+	CreatedAt   time.Time
+}
+
+// RecordEvent 追加跟踪事件，occurredAt 为零值时取当前时间
+func (s *Shipment) RecordEvent(status, location, description, rawPayload string, occurredAt time.Time) *ShipmentEvent {
+	now := time.Now()
+	if occurredAt.IsZero() {
+		occurredAt = now
+	}
+
+	event := &ShipmentEvent{
+		ShipmentID:  s.ID,
+		Status:      status,
+		Location:    location,
+		Description: description,
+		RawPayload:  rawPayload,
+		OccurredAt:  occurredAt,
+		CreatedAt:   now,
+	}
+	s.Events = append(s.Events, event)
+	s.UpdatedAt = now
+	return event
+}
+
+// RecordTrackingUpdates 记录承运商轨迹，已记录过的（状态与发生时间相同）跳过
+// 返回新增的事件数，以及承运商是否已确认签收
+func (s *Shipment) RecordTrackingUpdates(updates []TrackingUpdate) (int, bool) {
+	added := 0
+	delivered := false
+	for _, u := range updates {
+		if u.Delivered {
+			delivered = true
+		}
+		if s.hasEvent(u.Status, u.OccurredAt) {
+			continue
+		}
+		s.RecordEvent(u.Status, u.Location, u.Description, u.RawPayload, u.OccurredAt)
+		added++
+	}
+	return added, delivered
+}
+
+// hasEvent 是否已记录相同状态与发生时间的事件（按数据库精度比较）
+func (s *Shipment) hasEvent(status string, occurredAt time.Time) bool {
+	for _, e := range s.Events {
+		if e.Status == status && e.OccurredAt.Truncate(time.Microsecond).Equal(occurredAt.Truncate(time.Microsecond)) {
+			return true
+		}
+	}
+	return false
+}
+
+// recordTransition 记录生命周期变更事件
+func (s *Shipment) recordTransition(description string) {
+	s.RecordEvent(string(s.Status), "", description, "", s.UpdatedAt)
+}
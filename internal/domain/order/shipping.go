@@ -0,0 +1,39 @@
+package order
+
+import "time"
+
+// Parcel 包裹信息，用于运费报价与购买面单
+type Parcel struct {
+	WeightKg float64
+}
+
+// ShippingRate 运费报价值对象
+type ShippingRate struct {
+	Carrier       string
+	Service       string // 服务编码，如 standard、express
+	ServiceName   string
+	Amount        Money
+	EstimatedDays int
+}
+
+// EstimatedDelivery 按报价时效计算预计送达时间
+func (r ShippingRate) EstimatedDelivery(from time.Time) time.Time {
+	return from.AddDate(0, 0, r.EstimatedDays)
+}
+
+// ShippingLabel 承运商面单
+type ShippingLabel struct {
+	Carrier        string
+	TrackingNumber string
+	LabelURL       string // 面单文件地址，离线承运商可为空
+}
+
+// TrackingUpdate 承运商跟踪轨迹
+type TrackingUpdate struct {
+	Status      string
+	Location    string
+	Description string
+	RawPayload  string
+	OccurredAt  time.Time
+	Delivered   bool // 承运商确认已签收
+}
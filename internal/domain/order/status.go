@@ -0,0 +1,165 @@
+package order
+
+import (
+	"strings"
+	"time"
+)
+
+// 状态变更发起方
+const (
+	ActorSystem = "system"
+)
+
+// UserActor 以顾客身份发起的状态变更
+func UserActor(userID string) string {
+	return "user:" + userID
+}
+
+// AdminActor 以管理员身份发起的状态变更
+func AdminActor(userID string) string {
+	return "admin:" + userID
+}
+
+// ActorType 返回发起方类型（system / user / admin）
+func ActorType(actor string) string {
+	if i := strings.IndexByte(actor, ':'); i > 0 {
+		return actor[:i]
+	}
+	return actor
+}
+
+// transitionGuard 状态转换守卫，返回错误时拒绝转换
+type transitionGuard func(o *Order) error
+
+// transition 状态转换定义
+type transition struct {
+	From OrderStatus
+	To   OrderStatus
+	// Manual 是否允许管理员直接设置；
+	// 需要伴随业务副作用（扣款、退款）的转换只能经由对应流程触发
+	Manual bool
+	Guard  transitionGuard
+}
+
+// transitions 订单状态转换表
+var transitions = []transition{
+	{From: StatusPending, To: StatusPaid, Guard: guardPayable},
+	{From: StatusPending, To: StatusCancelled, Manual: true},
+	{From: StatusPending, To: StatusReview},
+	{From: StatusReview, To: StatusPending},
+	{From: StatusReview, To: StatusCancelled},
+	// 已支付订单经退款结束，不能直接取消；送达后由发货流程完成
+	{From: StatusPaid, To: StatusCompleted},
+	{From: StatusPaid, To: StatusRefunded},
+	{From: StatusCompleted, To: StatusRefunded},
+	{From: StatusPaid, To: StatusPartiallyRefunded},
+	{From: StatusCompleted, To: StatusPartiallyRefunded},
+	{From: StatusPartiallyRefunded, To: StatusRefunded},
+	{From: StatusPartiallyRefunded, To: StatusCompleted, Manual: true},
+}
+
+// guardPayable 订单须有订单项且金额为正才能支付
+func guardPayable(o *Order) error {
+	if len(o.Items) == 0 {
+		return ErrEmptyOrder
+	}
+	if !o.TotalAmount.IsPositive() {
+		return ErrInvalidOrderStatus
+	}
+	return nil
+}
+
+// findTransition 查找状态转换
+func findTransition(from, to OrderStatus) (transition, bool) {
+	for _, t := range transitions {
+		if t.From == from && t.To == to {
+			return t, true
+		}
+	}
+	return transition{}, false
+}
+
+// CanTransitionTo 是否可以转换到目标状态
+func (o *Order) CanTransitionTo(to OrderStatus) bool {
+	t, ok := findTransition(o.Status, to)
+	if !ok {
+		return false
+	}
+	return t.Guard == nil || t.Guard(o) == nil
+}
+
+// AllowedTransitions 返回当前状态可转换到的状态
+func (o *Order) AllowedTransitions() []OrderStatus {
+	allowed := make([]OrderStatus, 0)
+	for _, t := range transitions {
+		if t.From == o.Status && (t.Guard == nil || t.Guard(o) == nil) {
+			allowed = append(allowed, t.To)
+		}
+	}
+	return allowed
+}
+
+// CheckManualTransition 校验管理员能否手动转换到目标状态，仅允许转换表中标记为 Manual 的转换
+func (o *Order) CheckManualTransition(to OrderStatus) error {
+	if !to.IsValid() {
+		return ErrInvalidOrderStatus
+	}
+	t, ok := findTransition(o.Status, to)
+	if !ok {
+		return ErrInvalidOrderStatus
+	}
+	if !t.Manual {
+		return ErrManualTransitionNotAllowed
+	}
+	return nil
+}
+
+// ChangeStatus 管理员手动变更状态，仅允许转换表中标记为 Manual 的转换
+// 取消需释放优惠并作废支付尝试，应经由应用服务的取消流程
+func (o *Order) ChangeStatus(to OrderStatus, actor, note string) error {
+	if err := o.CheckManualTransition(to); err != nil {
+		return err
+	}
+	return o.transitionTo(to, actor, note)
+}
+
+// transitionTo 按转换表执行状态转换并记录历史
+func (o *Order) transitionTo(to OrderStatus, actor, note string) error {
+	t, ok := findTransition(o.Status, to)
+	if !ok {
+		return ErrInvalidOrderStatus
+	}
+	if t.Guard != nil {
+		if err := t.Guard(o); err != nil {
+			return err
+		}
+	}
+
+	o.recordStatusChange(o.Status, to, actor, note)
+	o.Status = to
+	o.UpdatedAt = time.Now()
+	return nil
+}
+
+// recordStatusChange 追加状态变更记录
+func (o *Order) recordStatusChange(from, to OrderStatus, actor, note string) {
+	o.History = append(o.History, &StatusChange{
+		OrderID:   o.ID,
+		From:      from,
+		To:        to,
+		Actor:     actor,
+		Note:      note,
+		CreatedAt: time.Now(),
+	})
+}
+
+// StatusChange 订单状态变更记录
+type StatusChange struct {
+	ID        string
+	OrderID   string
+	From      OrderStatus // 订单创建时为空
+	To        OrderStatus
+	Actor     string
+	Note      string
+	CreatedAt time.Time
+}
@@ -0,0 +1,100 @@
+package order
+
+import "errors"
+
+// TaxLine 单个订单项的税额明细
+type TaxLine struct {
+	ItemID       string
+	Jurisdiction string  // 税务辖区，如 "DE" 或 "US-CA"
+	Name         string  // 税种名称，如 "VAT"
+	Rate         float64 // 税率，如 0.19
+	Amount       Money
+}
+
+// TaxBreakdown 订单税额计算结果
+type TaxBreakdown struct {
+	Lines         []TaxLine
+	Inclusive     bool   // 价格是否含税
+	ReverseCharge bool   // 是否适用反向征收（B2B 跨境增值税）
+	Note          string // 发票备注，如反向征收说明
+}
+
+// ApplyTax 将税额明细写入订单项并重新计算总额
+func (o *Order) ApplyTax(b TaxBreakdown) error {
+	if o.Status != StatusPending {
+		return errors.New("can only apply tax to pending orders")
+	}
+
+	lines := make(map[string]TaxLine, len(b.Lines))
+	for _, line := range b.Lines {
+		if line.Amount.Currency != "" && line.Amount.Currency != o.Subtotal.Currency {
+			return ErrDifferentCurrency
+		}
+		lines[line.ItemID] = line
+	}
+
+	for _, item := range o.Items {
+		line, ok := lines[item.ID]
+		if !ok {
+			item.TaxName = ""
+			item.TaxRate = 0
+			item.TaxAmount = NewMoney(0, item.Subtotal.Currency)
+			continue
+		}
+		item.TaxName = line.Name
+		item.TaxRate = line.Rate
+		item.TaxAmount = NewMoney(roundAmount(line.Amount.Amount), item.Subtotal.Currency)
+	}
+
+	o.TaxInclusive = b.Inclusive
+	o.ReverseCharge = b.ReverseCharge
+	o.TaxNote = b.Note
+	o.calculateTotal()
+	return nil
+}
+
+// TaxableAmount 返回订单项的计税基数（小计减去分摊到该订单项的折扣）
+func (o *Order) TaxableAmount(item *OrderItem) float64 {
+	taxable := item.Subtotal.Amount - o.DiscountShare(item)
+	if taxable < 0 {
+		return 0
+	}
+	return taxable
+}
+
+// DiscountShare 返回分摊到订单项的折扣金额（正数）
+// 每个折扣只在其适用的订单项间按小计比例分摊，限定商品或分类的优惠不会分摊到其他订单项
+func (o *Order) DiscountShare(item *OrderItem) float64 {
+	share := 0.0
+	for _, adj := range o.Discounts() {
+		if !adj.AppliesTo(item.ID) {
+			continue
+		}
+		base := 0.0
+		for _, it := range o.Items {
+			if adj.AppliesTo(it.ID) {
+				base += it.Subtotal.Amount
+			}
+		}
+		if base <= 0 {
+			continue
+		}
+		share -= adj.Amount.Amount * item.Subtotal.Amount / base
+	}
+	return share
+}
+
+// SetShippingAddress 设置收货地址（用于确定税务辖区）
+func (o *Order) SetShippingAddress(address Address) {
+	o.ShippingAddress = &address
+}
+
+// SetBillingAddress 设置账单地址
+func (o *Order) SetBillingAddress(address Address) {
+	o.BillingAddress = &address
+}
+
+// SetVATID 设置买方增值税号（B2B）
+func (o *Order) SetVATID(vatID string) {
+	o.VATID = vatID
+}
@@ -0,0 +1,44 @@
+package promotion
+
+import "errors"
+
+var (
+	// ErrPromotionNotFound 优惠未找到
+	ErrPromotionNotFound = errors.New("promotion not found")
+
+	// ErrInvalidCode 无效的优惠码
+	ErrInvalidCode = errors.New("promotion code cannot be empty")
+
+	// ErrInvalidDiscount 无效的折扣
+	ErrInvalidDiscount = errors.New("invalid discount value")
+
+	// ErrCodeAlreadyExists 优惠码已存在
+	ErrCodeAlreadyExists = errors.New("promotion code already exists")
+
+	// ErrPromotionInactive 优惠未启用
+	ErrPromotionInactive = errors.New("promotion is not active")
+
+	// ErrPromotionNotStarted 优惠未开始
+	ErrPromotionNotStarted = errors.New("promotion has not started yet")
+
+	// ErrPromotionExpired 优惠已过期
+	ErrPromotionExpired = errors.New("promotion has expired")
+
+	// ErrMinSpendNotMet 未达到最低消费
+	ErrMinSpendNotMet = errors.New("order does not meet the minimum spend")
+
+	// ErrNoEligibleItems 没有适用的商品
+	ErrNoEligibleItems = errors.New("no order items are eligible for this promotion")
+
+	// ErrUsageLimitReached 使用次数已达上限
+	ErrUsageLimitReached = errors.New("promotion usage limit reached")
+
+	// ErrUserUsageLimitReached 用户使用次数已达上限
+	ErrUserUsageLimitReached = errors.New("promotion usage limit reached for this user")
+
+	// ErrCurrencyMismatch 货币不匹配
+	ErrCurrencyMismatch = errors.New("promotion currency does not match order currency")
+
+	// ErrConcurrentModification 优惠已被其他请求修改
+	ErrConcurrentModification = errors.New("promotion was modified concurrently")
+)
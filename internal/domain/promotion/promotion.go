@@ -0,0 +1,242 @@
+package promotion
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+)
+
+// DiscountType 折扣类型值对象
+type DiscountType string
+
+const (
+	DiscountTypePercentage DiscountType = "percentage"
+	DiscountTypeFixed      DiscountType = "fixed"
+)
+
+// IsValid 检查折扣类型是否有效
+func (t DiscountType) IsValid() bool {
+	return t == DiscountTypePercentage || t == DiscountTypeFixed
+}
+
+// Promotion 优惠活动聚合根（优惠券）
+type Promotion struct {
+	ID           string
+	Code         string
+	Name         string
+	Description  string
+	DiscountType DiscountType
+	Value        float64 // 百分比（0-100）或固定金额
+	Currency     string  // 固定金额与最低消费的货币
+	MinSpend     float64 // 0 表示不限
+	UsageLimit   int     // 全局使用上限，0 表示不限
+	PerUserLimit int     // 单用户使用上限，0 表示不限
+	UsedCount    int
+	ProductIDs   []string // 限定商品，为空表示不限
+	Categories   []string // 限定分类，为空表示不限
+	StartsAt     *time.Time
+	EndsAt       *time.Time
+	IsActive     bool
+	Version      int // 乐观锁版本号，每次保存后递增
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// NewPromotion 创建优惠活动
+func NewPromotion(code, name string, discountType DiscountType, value float64, currency string) (*Promotion, error) {
+	code = NormalizeCode(code)
+	if code == "" {
+		return nil, ErrInvalidCode
+	}
+	if err := validateDiscount(discountType, value); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &Promotion{
+		Code:         code,
+		Name:         name,
+		DiscountType: discountType,
+		Value:        value,
+		Currency:     strings.ToUpper(currency),
+		ProductIDs:   make([]string, 0),
+		Categories:   make([]string, 0),
+		IsActive:     true,
+		Version:      1,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, nil
+}
+
+// CheckVersion 校验客户端读取时的版本号（If-Match），expected 为 0 时不校验
+func (p *Promotion) CheckVersion(expected int) error {
+	if expected != 0 && expected != p.Version {
+		return ErrConcurrentModification
+	}
+	return nil
+}
+
+// NormalizeCode 规范化优惠码（去空格并转大写）
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// validateDiscount 验证折扣值
+func validateDiscount(discountType DiscountType, value float64) error {
+	if !discountType.IsValid() || value <= 0 {
+		return ErrInvalidDiscount
+	}
+	if discountType == DiscountTypePercentage && value > 100 {
+		return ErrInvalidDiscount
+	}
+	return nil
+}
+
+// UpdateDiscount 更新折扣规则
+func (p *Promotion) UpdateDiscount(discountType DiscountType, value float64, currency string) error {
+	if err := validateDiscount(discountType, value); err != nil {
+		return err
+	}
+	p.DiscountType = discountType
+	p.Value = value
+	p.Currency = strings.ToUpper(currency)
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// SetLimits 设置最低消费与使用上限
+func (p *Promotion) SetLimits(minSpend float64, usageLimit, perUserLimit int) error {
+	if minSpend < 0 || usageLimit < 0 || perUserLimit < 0 {
+		return ErrInvalidDiscount
+	}
+	p.MinSpend = minSpend
+	p.UsageLimit = usageLimit
+	p.PerUserLimit = perUserLimit
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// SetValidity 设置有效期
+func (p *Promotion) SetValidity(startsAt, endsAt *time.Time) {
+	p.StartsAt = startsAt
+	p.EndsAt = endsAt
+	p.UpdatedAt = time.Now()
+}
+
+// SetTargets 设置限定商品与分类
+func (p *Promotion) SetTargets(productIDs, categories []string) {
+	p.ProductIDs = append(make([]string, 0, len(productIDs)), productIDs...)
+	p.Categories = append(make([]string, 0, len(categories)), categories...)
+	p.UpdatedAt = time.Now()
+}
+
+// Activate 启用优惠
+func (p *Promotion) Activate() {
+	p.IsActive = true
+	p.UpdatedAt = time.Now()
+}
+
+// Deactivate 停用优惠
+func (p *Promotion) Deactivate() {
+	p.IsActive = false
+	p.UpdatedAt = time.Now()
+}
+
+// CheckAvailable 检查优惠在指定时间是否可用（不含用户维度的限制）
+func (p *Promotion) CheckAvailable(now time.Time) error {
+	if !p.IsActive {
+		return ErrPromotionInactive
+	}
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return ErrPromotionNotStarted
+	}
+	if p.EndsAt != nil && !now.Before(*p.EndsAt) {
+		return ErrPromotionExpired
+	}
+	if p.UsageLimit > 0 && p.UsedCount >= p.UsageLimit {
+		return ErrUsageLimitReached
+	}
+	return nil
+}
+
+// IsTargeted 是否限定了商品或分类
+func (p *Promotion) IsTargeted() bool {
+	return len(p.ProductIDs) > 0 || len(p.Categories) > 0
+}
+
+// AppliesTo 判断订单项是否适用本优惠
+func (p *Promotion) AppliesTo(item *order.OrderItem) bool {
+	if !p.IsTargeted() {
+		return true
+	}
+	for _, id := range p.ProductIDs {
+		if id == item.ProductID {
+			return true
+		}
+	}
+	for _, category := range p.Categories {
+		if item.Category != "" && strings.EqualFold(category, item.Category) {
+			return true
+		}
+	}
+	return false
+}
+
+// CalculateDiscount 计算订单可获得的折扣金额（正数）
+func (p *Promotion) CalculateDiscount(o *order.Order) (order.Money, error) {
+	subtotal := o.Subtotal
+	if p.requiresCurrency() && p.Currency != "" && p.Currency != subtotal.Currency {
+		return order.Money{}, ErrCurrencyMismatch
+	}
+	if p.MinSpend > 0 && subtotal.Amount < p.MinSpend {
+		return order.Money{}, ErrMinSpendNotMet
+	}
+
+	eligible := 0.0
+	for _, item := range o.Items {
+		if p.AppliesTo(item) {
+			eligible += item.Subtotal.Amount
+		}
+	}
+	if eligible <= 0 {
+		return order.Money{}, ErrNoEligibleItems
+	}
+
+	var amount float64
+	switch p.DiscountType {
+	case DiscountTypePercentage:
+		amount = eligible * p.Value / 100
+	case DiscountTypeFixed:
+		amount = math.Min(p.Value, eligible)
+	}
+
+	return order.NewMoney(math.Round(amount*100)/100, subtotal.Currency), nil
+}
+
+// requiresCurrency 是否依赖货币（固定金额或设置了最低消费）
+func (p *Promotion) requiresCurrency() bool {
+	return p.DiscountType == DiscountTypeFixed || p.MinSpend > 0
+}
+
+// Redemption 优惠核销记录实体
+type Redemption struct {
+	ID          string
+	PromotionID string
+	UserID      string
+	OrderID     string
+	Amount      order.Money
+	CreatedAt   time.Time
+}
+
+// NewRedemption 创建核销记录
+func NewRedemption(promotionID, userID, orderID string, amount order.Money) *Redemption {
+	return &Redemption{
+		PromotionID: promotionID,
+		UserID:      userID,
+		OrderID:     orderID,
+		Amount:      amount,
+		CreatedAt:   time.Now(),
+	}
+}
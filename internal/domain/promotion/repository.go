@@ -0,0 +1,48 @@
+package promotion
+
+import "context"
+
+// Repository 优惠仓储接口
+type Repository interface {
+	// Create 创建优惠
+	Create(ctx context.Context, promotion *Promotion) error
+
+	// Update 更新优惠
+	Update(ctx context.Context, promotion *Promotion) error
+
+	// Delete 删除优惠
+	Delete(ctx context.Context, id string) error
+
+	// FindByID 根据ID查找优惠
+	FindByID(ctx context.Context, id string) (*Promotion, error)
+
+	// FindByCode 根据优惠码查找优惠
+	FindByCode(ctx context.Context, code string) (*Promotion, error)
+
+	// List 列出优惠（分页）
+	List(ctx context.Context, offset, limit int) ([]*Promotion, int64, error)
+
+	// ExistsByCode 检查优惠码是否存在
+	ExistsByCode(ctx context.Context, code string) (bool, error)
+
+	// IncrementUsage 原子地增加使用次数，超出全局上限时返回 ErrUsageLimitReached
+	IncrementUsage(ctx context.Context, id string) error
+
+	// DecrementUsage 原子地减少使用次数，不低于零
+	DecrementUsage(ctx context.Context, id string) error
+}
+
+// RedemptionRepository 核销记录仓储接口
+type RedemptionRepository interface {
+	// Create 创建核销记录
+	Create(ctx context.Context, redemption *Redemption) error
+
+	// CountByUser 统计用户对某优惠的核销次数
+	CountByUser(ctx context.Context, promotionID, userID string) (int64, error)
+
+	// FindByOrderID 根据订单ID查找核销记录
+	FindByOrderID(ctx context.Context, orderID string) ([]*Redemption, error)
+
+	// DeleteByID 删除核销记录
+	DeleteByID(ctx context.Context, id string) error
+}
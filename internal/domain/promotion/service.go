@@ -0,0 +1,87 @@
+package promotion
+
+import (
+	"context"
+	"time"
+)
+
+// Service 优惠领域服务
+type Service struct {
+	repo           Repository
+	redemptionRepo RedemptionRepository
+}
+
+// NewService 创建优惠领域服务
+func NewService(repo Repository, redemptionRepo RedemptionRepository) *Service {
+	return &Service{
+		repo:           repo,
+		redemptionRepo: redemptionRepo,
+	}
+}
+
+// ValidateCodeUniqueness 验证优惠码唯一性
+func (s *Service) ValidateCodeUniqueness(ctx context.Context, code string) error {
+	exists, err := s.repo.ExistsByCode(ctx, NormalizeCode(code))
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrCodeAlreadyExists
+	}
+	return nil
+}
+
+// ValidateForUser 验证优惠对指定用户是否可用（有效期、全局与单用户上限）
+func (s *Service) ValidateForUser(ctx context.Context, p *Promotion, userID string) error {
+	if err := p.CheckAvailable(time.Now()); err != nil {
+		return err
+	}
+	return s.checkUserLimit(ctx, p, userID)
+}
+
+// Redeem 核销优惠
+// 需在事务中调用：先原子递增全局计数（同时锁定优惠行），再校验单用户上限
+func (s *Service) Redeem(ctx context.Context, p *Promotion, redemption *Redemption) error {
+	if err := p.CheckAvailable(time.Now()); err != nil {
+		return err
+	}
+	if err := s.repo.IncrementUsage(ctx, p.ID); err != nil {
+		return err
+	}
+	if err := s.checkUserLimit(ctx, p, redemption.UserID); err != nil {
+		return err
+	}
+	return s.redemptionRepo.Create(ctx, redemption)
+}
+
+// Release 撤销订单的全部核销并归还使用次数，需在事务中调用
+func (s *Service) Release(ctx context.Context, orderID string) error {
+	redemptions, err := s.redemptionRepo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	for _, r := range redemptions {
+		if err := s.repo.DecrementUsage(ctx, r.PromotionID); err != nil {
+			return err
+		}
+		if err := s.redemptionRepo.DeleteByID(ctx, r.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkUserLimit 校验单用户使用上限
+func (s *Service) checkUserLimit(ctx context.Context, p *Promotion, userID string) error {
+	if p.PerUserLimit <= 0 {
+		return nil
+	}
+	count, err := s.redemptionRepo.CountByUser(ctx, p.ID, userID)
+	if err != nil {
+		return err
+	}
+	if count >= int64(p.PerUserLimit) {
+		return ErrUserUsageLimitReached
+	}
+	return nil
+}
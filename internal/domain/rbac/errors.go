@@ -31,4 +31,7 @@ var (
 	ErrUserRoleNotFound      = errors.New("用户角色关联不存在")
 	ErrRolePermissionNotFound = errors.New("角色权限关联不存在")
 	ErrRoleMenuNotFound      = errors.New("角色菜单关联不存在")
+
+	// 并发控制错误
+	ErrConcurrentModification = errors.New("数据已被其他请求修改，请刷新后重试")
 )
@@ -26,6 +26,7 @@ type Menu struct {
 	Component   string   // 前端组件路径（可选）
 	Permission  string   // 关联的权限码（可选）
 	Description string
+	Version     int // 乐观锁版本号，每次保存后递增
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
@@ -48,11 +49,20 @@ func NewMenu(name, path, icon string, menuType MenuType, parentID *string) (*Men
 		ParentID:  parentID,
 		SortOrder: 0,
 		IsVisible: true,
+		Version:   1,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}, nil
 }
 
+// CheckVersion 校验客户端读取时的版本号（If-Match），expected 为 0 时不校验
+func (m *Menu) CheckVersion(expected int) error {
+	if expected != 0 && expected != m.Version {
+		return ErrConcurrentModification
+	}
+	return nil
+}
+
 // UpdateInfo 更新菜单信息
 func (m *Menu) UpdateInfo(name, path, icon, component, permission, description string) error {
 	if name == "" {
@@ -13,6 +13,7 @@ type Permission struct {
 	Resource    string // 资源，如 "user", "order", "menu"
 	Action      string // 操作，如 "create", "read", "update", "delete"
 	Description string
+	Version     int // 乐观锁版本号，每次保存后递增
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
@@ -38,11 +39,20 @@ func NewPermission(name, resource, action, description string) (*Permission, err
 		Resource:    resource,
 		Action:      action,
 		Description: description,
+		Version:     1,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}, nil
 }
 
+// CheckVersion 校验客户端读取时的版本号（If-Match），expected 为 0 时不校验
+func (p *Permission) CheckVersion(expected int) error {
+	if expected != 0 && expected != p.Version {
+		return ErrConcurrentModification
+	}
+	return nil
+}
+
 // UpdateInfo 更新权限信息
 func (p *Permission) UpdateInfo(name, description string) error {
 	if name == "" {
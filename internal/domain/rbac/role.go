@@ -11,6 +11,7 @@ type Role struct {
 	Code        string // 唯一标识码，如 "admin", "user", "editor"
 	Description string
 	IsActive    bool
+	Version     int // 乐观锁版本号，每次保存后递增
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
@@ -30,11 +31,20 @@ func NewRole(name, code, description string) (*Role, error) {
 		Code:        code,
 		Description: description,
 		IsActive:    true,
+		Version:     1,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}, nil
 }
 
+// CheckVersion 校验客户端读取时的版本号（If-Match），expected 为 0 时不校验
+func (r *Role) CheckVersion(expected int) error {
+	if expected != 0 && expected != r.Version {
+		return ErrConcurrentModification
+	}
+	return nil
+}
+
 // Activate 激活角色
 func (r *Role) Activate() {
 	r.IsActive = true
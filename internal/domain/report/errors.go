@@ -0,0 +1,8 @@
+package report
+
+import "errors"
+
+var (
+	// ErrInvalidReportQuery 无效的报表查询条件
+	ErrInvalidReportQuery = errors.New("invalid report query")
+)
@@ -0,0 +1,216 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+)
+
+// MaxRangeDays 单次报表查询允许的最大天数
+const MaxRangeDays = 731
+
+// Granularity 报表汇总粒度
+type Granularity string
+
+const (
+	GranularityDay   Granularity = "day"
+	GranularityWeek  Granularity = "week" // 周一为一周的开始
+	GranularityMonth Granularity = "month"
+)
+
+// ParseGranularity 解析汇总粒度，为空时按天汇总
+func ParseGranularity(value string) (Granularity, error) {
+	if value == "" {
+		return GranularityDay, nil
+	}
+	g := Granularity(value)
+	switch g {
+	case GranularityDay, GranularityWeek, GranularityMonth:
+		return g, nil
+	}
+	return "", fmt.Errorf("%w: unsupported granularity %q", ErrInvalidReportQuery, value)
+}
+
+// PeriodStart 返回某天（UTC）所属汇总周期的起始日
+func (g Granularity) PeriodStart(day time.Time) time.Time {
+	day = truncateDay(day)
+	switch g {
+	case GranularityWeek:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case GranularityMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+// DateRange 报表日期区间（UTC），From 含当天，To 不含
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// NewDateRange 创建日期区间，边界按 UTC 取整到天
+func NewDateRange(from, to time.Time) (DateRange, error) {
+	r := DateRange{From: truncateDay(from), To: truncateDay(to)}
+	if !r.To.After(r.From) {
+		return r, fmt.Errorf("%w: range end must be after start", ErrInvalidReportQuery)
+	}
+	if r.Days() > MaxRangeDays {
+		return r, fmt.Errorf("%w: range exceeds %d days", ErrInvalidReportQuery, MaxRangeDays)
+	}
+	return r, nil
+}
+
+// Days 区间包含的天数
+func (r DateRange) Days() int {
+	return int(r.To.Sub(r.From).Hours() / 24)
+}
+
+// DailySales 按天、币种、订单状态汇总的订单数据，按下单日期（UTC）归属
+type DailySales struct {
+	Day         time.Time
+	Currency    string
+	Status      order.OrderStatus
+	OrderCount  int
+	GrossAmount float64 // 订单总额合计（含税）
+	TaxAmount   float64
+}
+
+// DailyRefunds 按天、币种汇总的成功退款，按退款发起日期（UTC）归属
+type DailyRefunds struct {
+	Day            time.Time
+	Currency       string
+	RefundCount    int
+	RefundedAmount float64
+}
+
+// ProductSales 商品销量汇总，只统计已成交订单
+type ProductSales struct {
+	ProductID   string
+	ProductName string
+	Currency    string
+	Quantity    int
+	Revenue     float64 // 订单项小计合计（不含税）
+	OrderCount  int
+}
+
+// StatusTotals 某订单状态的订单数与金额
+type StatusTotals struct {
+	OrderCount int
+	Amount     float64
+}
+
+// SalesPeriod 某汇总周期、某币种的销售指标
+type SalesPeriod struct {
+	PeriodStart       time.Time
+	Currency          string
+	OrderCount        int     // 成交订单数
+	GMV               float64 // 成交订单总额，不扣除退款
+	RefundCount       int
+	RefundedAmount    float64
+	NetSales          float64 // GMV 扣除退款
+	AverageOrderValue float64
+	ByStatus          map[order.OrderStatus]StatusTotals // 全部状态（含待支付、已取消）的订单分布
+}
+
+// IsSale 订单状态是否计入成交：已支付过的订单，含事后退款的订单
+func IsSale(status order.OrderStatus) bool {
+	switch status {
+	case order.StatusPaid, order.StatusCompleted, order.StatusPartiallyRefunded, order.StatusRefunded:
+		return true
+	}
+	return false
+}
+
+// SaleStatuses 计入成交的订单状态
+func SaleStatuses() []order.OrderStatus {
+	return []order.OrderStatus{order.StatusPaid, order.StatusCompleted, order.StatusPartiallyRefunded, order.StatusRefunded}
+}
+
+// Rollup 将按天的汇总数据按粒度合并为周期指标，按周期与币种排序
+func Rollup(g Granularity, sales []*DailySales, refunds []*DailyRefunds) []*SalesPeriod {
+	return aggregate(g.PeriodStart, sales, refunds)
+}
+
+// Summarize 将区间内的汇总数据按币种合并为一个周期
+func Summarize(r DateRange, sales []*DailySales, refunds []*DailyRefunds) []*SalesPeriod {
+	return aggregate(func(time.Time) time.Time { return r.From }, sales, refunds)
+}
+
+// periodKey 周期指标的分组键
+type periodKey struct {
+	start    time.Time
+	currency string
+}
+
+// aggregate 按 periodOf 计算的周期起始日与币种分组累加
+func aggregate(periodOf func(time.Time) time.Time, sales []*DailySales, refunds []*DailyRefunds) []*SalesPeriod {
+	periods := make(map[periodKey]*SalesPeriod)
+	get := func(day time.Time, currency string) *SalesPeriod {
+		key := periodKey{start: periodOf(day), currency: currency}
+		p, ok := periods[key]
+		if !ok {
+			p = &SalesPeriod{
+				PeriodStart: key.start,
+				Currency:    currency,
+				ByStatus:    make(map[order.OrderStatus]StatusTotals),
+			}
+			periods[key] = p
+		}
+		return p
+	}
+
+	for _, s := range sales {
+		p := get(s.Day, s.Currency)
+		totals := p.ByStatus[s.Status]
+		totals.OrderCount += s.OrderCount
+		totals.Amount += s.GrossAmount
+		p.ByStatus[s.Status] = totals
+		if IsSale(s.Status) {
+			p.OrderCount += s.OrderCount
+			p.GMV += s.GrossAmount
+		}
+	}
+	for _, r := range refunds {
+		p := get(r.Day, r.Currency)
+		p.RefundCount += r.RefundCount
+		p.RefundedAmount += r.RefundedAmount
+	}
+
+	result := make([]*SalesPeriod, 0, len(periods))
+	for _, p := range periods {
+		p.GMV = roundAmount(p.GMV)
+		p.RefundedAmount = roundAmount(p.RefundedAmount)
+		p.NetSales = roundAmount(p.GMV - p.RefundedAmount)
+		if p.OrderCount > 0 {
+			p.AverageOrderValue = roundAmount(p.GMV / float64(p.OrderCount))
+		}
+		for status, totals := range p.ByStatus {
+			totals.Amount = roundAmount(totals.Amount)
+			p.ByStatus[status] = totals
+		}
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].PeriodStart.Equal(result[j].PeriodStart) {
+			return result[i].PeriodStart.Before(result[j].PeriodStart)
+		}
+		return result[i].Currency < result[j].Currency
+	})
+	return result
+}
+
+// truncateDay 取 UTC 当天零点
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// roundAmount 金额保留两位小数
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,23 @@
+package report
+
+import (
+	"context"
+	"time"
+)
+
+// Repository 报表仓储接口
+// 报表查询只读取按天物化的汇总表，汇总表由 Refresh 增量重算
+type Repository interface {
+	// Refresh 重算自上次刷新以来有变动的日期的汇总，返回重算的天数
+	// 其他实例正在刷新时直接返回 0
+	Refresh(ctx context.Context, now time.Time) (int, error)
+
+	// ListDailySales 列出区间内按天、币种、订单状态汇总的订单数据，currency 为空时不限币种
+	ListDailySales(ctx context.Context, r DateRange, currency string) ([]*DailySales, error)
+
+	// ListDailyRefunds 列出区间内按天、币种汇总的退款数据
+	ListDailyRefunds(ctx context.Context, r DateRange, currency string) ([]*DailyRefunds, error)
+
+	// TopProducts 按销售额降序列出区间内的商品销量
+	TopProducts(ctx context.Context, r DateRange, currency string, limit int) ([]*ProductSales, error)
+}
@@ -0,0 +1,41 @@
+package subscription
+
+import "errors"
+
+var (
+	// ErrPlanNotFound 订阅计划未找到
+	ErrPlanNotFound = errors.New("subscription plan not found")
+
+	// ErrInvalidPlan 无效的订阅计划
+	ErrInvalidPlan = errors.New("invalid subscription plan")
+
+	// ErrPlanCodeAlreadyExists 计划编码已存在
+	ErrPlanCodeAlreadyExists = errors.New("subscription plan code already exists")
+
+	// ErrPlanInactive 计划已停售
+	ErrPlanInactive = errors.New("subscription plan is not active")
+
+	// ErrSubscriptionNotFound 订阅未找到
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+
+	// ErrAlreadySubscribed 已订阅该计划
+	ErrAlreadySubscribed = errors.New("user already has a subscription to this plan")
+
+	// ErrInvalidSubscriptionStatus 订阅状态不允许该操作
+	ErrInvalidSubscriptionStatus = errors.New("invalid subscription status for this operation")
+
+	// ErrSamePlan 变更的计划与当前计划相同
+	ErrSamePlan = errors.New("subscription is already on this plan")
+
+	// ErrCurrencyMismatch 计划货币不一致
+	ErrCurrencyMismatch = errors.New("subscription plans use different currencies")
+
+	// ErrInvalidPaymentMethod 无效的支付方式
+	ErrInvalidPaymentMethod = errors.New("invalid subscription payment method")
+
+	// ErrRenewalNotFound 续费记录未找到
+	ErrRenewalNotFound = errors.New("subscription renewal not found")
+
+	// ErrConcurrentModification 订阅或计划已被其他请求修改
+	ErrConcurrentModification = errors.New("subscription was modified concurrently")
+)
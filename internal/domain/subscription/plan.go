@@ -0,0 +1,134 @@
+package subscription
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+)
+
+// Interval 计费周期单位值对象
+type Interval string
+
+const (
+	IntervalDay   Interval = "day"
+	IntervalWeek  Interval = "week"
+	IntervalMonth Interval = "month"
+	IntervalYear  Interval = "year"
+)
+
+// IsValid 检查计费周期单位是否有效
+func (i Interval) IsValid() bool {
+	switch i {
+	case IntervalDay, IntervalWeek, IntervalMonth, IntervalYear:
+		return true
+	}
+	return false
+}
+
+// Plan 订阅计划聚合根：按固定周期收取同一商品的费用
+type Plan struct {
+	ID            string
+	Code          string
+	Name          string
+	Description   string
+	ProductID     string // 续费订单的商品
+	Category      string // 商品分类，用于计税
+	Price         order.Money
+	Interval      Interval
+	IntervalCount int // 每个计费周期包含的周期单位数，如 3 个月
+	TrialDays     int // 试用天数，0 表示无试用
+	IsActive      bool
+	Version       int // 乐观锁版本号，每次保存后递增
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// NewPlan 创建订阅计划
+func NewPlan(code, name, productID string, price order.Money, interval Interval, intervalCount int) (*Plan, error) {
+	code = NormalizeCode(code)
+	if code == "" || strings.TrimSpace(name) == "" || productID == "" {
+		return nil, fmt.Errorf("%w: code, name and product are required", ErrInvalidPlan)
+	}
+	if !interval.IsValid() || intervalCount <= 0 {
+		return nil, fmt.Errorf("%w: invalid billing interval", ErrInvalidPlan)
+	}
+
+	p := &Plan{
+		Code:          code,
+		Name:          name,
+		ProductID:     productID,
+		Interval:      interval,
+		IntervalCount: intervalCount,
+		IsActive:      true,
+		Version:       1,
+		CreatedAt:     time.Now(),
+	}
+	if err := p.UpdatePricing(price, 0); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// CheckVersion 校验客户端读取时的版本号（If-Match），expected 为 0 时不校验
+func (p *Plan) CheckVersion(expected int) error {
+	if expected != 0 && expected != p.Version {
+		return ErrConcurrentModification
+	}
+	return nil
+}
+
+// NormalizeCode 规范化计划编码（去空格并转大写）
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// UpdatePricing 更新周期价格与试用天数，已有订阅自下一周期起按新价格续费
+func (p *Plan) UpdatePricing(price order.Money, trialDays int) error {
+	if !price.IsPositive() || price.Currency == "" {
+		return fmt.Errorf("%w: price must be positive", ErrInvalidPlan)
+	}
+	if trialDays < 0 {
+		return fmt.Errorf("%w: trial days cannot be negative", ErrInvalidPlan)
+	}
+	p.Price = order.NewMoney(price.Amount, strings.ToUpper(price.Currency))
+	p.TrialDays = trialDays
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// Activate 启用计划
+func (p *Plan) Activate() {
+	p.IsActive = true
+	p.UpdatedAt = time.Now()
+}
+
+// Deactivate 停售计划，已有订阅继续续费
+func (p *Plan) Deactivate() {
+	p.IsActive = false
+	p.UpdatedAt = time.Now()
+}
+
+// PeriodEnd 计算自 start 起一个计费周期的结束时间
+func (p *Plan) PeriodEnd(start time.Time) time.Time {
+	switch p.Interval {
+	case IntervalDay:
+		return start.AddDate(0, 0, p.IntervalCount)
+	case IntervalWeek:
+		return start.AddDate(0, 0, 7*p.IntervalCount)
+	case IntervalYear:
+		return start.AddDate(p.IntervalCount, 0, 0)
+	default:
+		return start.AddDate(0, p.IntervalCount, 0)
+	}
+}
+
+// TrialEnd 计算自 start 起的试用结束时间，无试用时返回 nil
+func (p *Plan) TrialEnd(start time.Time) *time.Time {
+	if p.TrialDays <= 0 {
+		return nil
+	}
+	end := start.AddDate(0, 0, p.TrialDays)
+	return &end
+}
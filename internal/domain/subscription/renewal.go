@@ -0,0 +1,72 @@
+package subscription
+
+import (
+	"fmt"
+	"time"
+)
+
+// Renewal 续费记录：订阅的每个计费周期一条，以 (订阅, 周期起点) 唯一，在扣款前提交
+// 记录预先分配本次扣款的订单ID，续费中途失败后重试同一周期时沿用该订单及其支付尝试，
+// 网关幂等键随之不变，不会重复扣款；催缴重试时为新的尝试分配新订单
+type Renewal struct {
+	ID             string
+	SubscriptionID string
+	PeriodStart    time.Time  // 续费周期的起点，即续费前的周期结束时间
+	Attempt        int        // 分配当前订单时订阅的连续扣款失败次数
+	OrderID        string     // 当前尝试的扣款订单
+	ChargedAt      *time.Time // 扣款成功的时间
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// NewRenewal 为订阅即将开始的计费周期创建续费记录
+func NewRenewal(sub *Subscription, orderID string, now time.Time) (*Renewal, error) {
+	if orderID == "" {
+		return nil, fmt.Errorf("%w: renewal requires an order", ErrInvalidSubscriptionStatus)
+	}
+	return &Renewal{
+		SubscriptionID: sub.ID,
+		PeriodStart:    sub.CurrentPeriodEnd,
+		Attempt:        sub.FailedAttempts,
+		OrderID:        orderID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// NewFirstRenewal 为未完成订阅的首期扣款创建记录，以订阅创建时的周期起点唯一
+func NewFirstRenewal(sub *Subscription, orderID string, now time.Time) (*Renewal, error) {
+	r, err := NewRenewal(sub, orderID, now)
+	if err != nil {
+		return nil, err
+	}
+	r.PeriodStart = sub.CurrentPeriodStart
+	return r, nil
+}
+
+// IsCharged 是否已扣款成功
+func (r *Renewal) IsCharged() bool {
+	return r.ChargedAt != nil
+}
+
+// NeedsNewAttempt 当前订单的扣款失败已记入催缴，重试需要新的订单
+func (r *Renewal) NeedsNewAttempt(sub *Subscription) bool {
+	return !r.IsCharged() && r.Attempt != sub.FailedAttempts
+}
+
+// Retry 为催缴重试分配新的扣款订单
+func (r *Renewal) Retry(sub *Subscription, orderID string, now time.Time) error {
+	if r.IsCharged() {
+		return fmt.Errorf("%w: renewal already charged", ErrInvalidSubscriptionStatus)
+	}
+	r.Attempt = sub.FailedAttempts
+	r.OrderID = orderID
+	r.UpdatedAt = now
+	return nil
+}
+
+// MarkCharged 记录扣款成功
+func (r *Renewal) MarkCharged(now time.Time) {
+	r.ChargedAt = &now
+	r.UpdatedAt = now
+}
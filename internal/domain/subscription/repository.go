@@ -0,0 +1,61 @@
+package subscription
+
+import (
+	"context"
+	"time"
+)
+
+// PlanRepository 订阅计划仓储接口
+type PlanRepository interface {
+	// Create 创建计划
+	Create(ctx context.Context, plan *Plan) error
+
+	// Update 更新计划
+	Update(ctx context.Context, plan *Plan) error
+
+	// FindByID 根据ID查找计划
+	FindByID(ctx context.Context, id string) (*Plan, error)
+
+	// List 列出计划，activeOnly 时只返回在售计划
+	List(ctx context.Context, activeOnly bool) ([]*Plan, error)
+
+	// ExistsByCode 检查计划编码是否存在
+	ExistsByCode(ctx context.Context, code string) (bool, error)
+}
+
+// Repository 订阅仓储接口
+type Repository interface {
+	// Create 创建订阅，用户已有该计划未终止的订阅时返回 ErrAlreadySubscribed
+	Create(ctx context.Context, subscription *Subscription) error
+
+	// Update 更新订阅
+	Update(ctx context.Context, subscription *Subscription) error
+
+	// FindByID 根据ID查找订阅
+	FindByID(ctx context.Context, id string) (*Subscription, error)
+
+	// FindByIDForUpdate 根据ID查找并锁定订阅（需在事务中调用）
+	FindByIDForUpdate(ctx context.Context, id string) (*Subscription, error)
+
+	// ListByUserID 列出用户的订阅
+	ListByUserID(ctx context.Context, userID string) ([]*Subscription, error)
+
+	// ListDue 按ID顺序列出在 now 需要续费或期末终止的订阅（afterID 之后）
+	ListDue(ctx context.Context, now time.Time, afterID string, limit int) ([]*Subscription, error)
+
+	// LockDue 以 FOR UPDATE SKIP LOCKED 锁定仍需处理的订阅（需在事务中调用），
+	// 已被其他 worker 锁定或已处理时返回 nil
+	LockDue(ctx context.Context, id string, now time.Time) (*Subscription, error)
+}
+
+// RenewalRepository 续费记录仓储接口
+type RenewalRepository interface {
+	// Create 创建续费记录，同一订阅同一周期重复创建时违反唯一约束
+	Create(ctx context.Context, renewal *Renewal) error
+
+	// Update 更新续费记录
+	Update(ctx context.Context, renewal *Renewal) error
+
+	// FindByPeriod 查找订阅在指定周期起点的续费记录
+	FindByPeriod(ctx context.Context, subscriptionID string, periodStart time.Time) (*Renewal, error)
+}
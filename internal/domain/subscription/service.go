@@ -0,0 +1,48 @@
+package subscription
+
+import "context"
+
+// Service 订阅领域服务
+type Service struct {
+	repo     Repository
+	planRepo PlanRepository
+}
+
+// NewService 创建订阅领域服务
+func NewService(repo Repository, planRepo PlanRepository) *Service {
+	return &Service{
+		repo:     repo,
+		planRepo: planRepo,
+	}
+}
+
+// ValidatePlanCodeUniqueness 验证计划编码唯一性
+func (s *Service) ValidatePlanCodeUniqueness(ctx context.Context, code string) error {
+	exists, err := s.planRepo.ExistsByCode(ctx, NormalizeCode(code))
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrPlanCodeAlreadyExists
+	}
+	return nil
+}
+
+// ValidateNewSubscription 验证用户没有该计划未终止的订阅
+// 已有首期扣款未完成的订阅时返回该订阅，由调用方续上扣款而不是重新创建
+func (s *Service) ValidateNewSubscription(ctx context.Context, userID, planID string) (*Subscription, error) {
+	subscriptions, err := s.repo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range subscriptions {
+		if sub.PlanID != planID || sub.IsEnded() {
+			continue
+		}
+		if sub.IsIncomplete() {
+			return sub, nil
+		}
+		return nil, ErrAlreadySubscribed
+	}
+	return nil, nil
+}
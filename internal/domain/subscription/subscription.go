@@ -0,0 +1,314 @@
+package subscription
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+)
+
+// Status 订阅状态值对象
+type Status string
+
+const (
+	StatusIncomplete Status = "incomplete" // 首期扣款未完成，不参与续费
+	StatusTrialing   Status = "trialing"   // 试用中，试用结束时首次扣款
+	StatusActive     Status = "active"     // 当前周期已付款
+	StatusPastDue    Status = "past_due"   // 续费扣款失败，按重试计划催缴
+	StatusCanceled   Status = "canceled"   // 已终止，不再续费
+)
+
+// Subscription 订阅聚合根
+type Subscription struct {
+	ID                 string
+	UserID             string
+	PlanID             string
+	Status             Status
+	PaymentMethod      order.PaymentMethod
+	PaymentToken       string // 网关保存的支付方式令牌，用于离线续费扣款
+	CurrentPeriodStart time.Time
+	CurrentPeriodEnd   time.Time
+	TrialEnd           *time.Time
+	CancelAtPeriodEnd  bool
+	CanceledAt         *time.Time  // 申请取消的时间
+	EndedAt            *time.Time  // 订阅终止的时间
+	Credit             order.Money // 降级产生的余额，抵扣后续账单
+	FailedAttempts     int         // 当前周期连续扣款失败次数
+	NextRetryAt        *time.Time
+	LastOrderID        string // 最近一次成功扣款的订单
+	Version            int    // 乐观锁版本号，每次保存后递增
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// NewSubscription 创建订阅：有试用时首个周期为试用期，否则订阅处于未完成状态，首期扣款成功后由 Activate 生效
+func NewSubscription(userID string, plan *Plan, method order.PaymentMethod, token string, now time.Time) (*Subscription, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("%w: user is required", ErrInvalidSubscriptionStatus)
+	}
+	if !plan.IsActive {
+		return nil, ErrPlanInactive
+	}
+	if err := validatePaymentMethod(method, token); err != nil {
+		return nil, err
+	}
+
+	s := &Subscription{
+		UserID:             userID,
+		PlanID:             plan.ID,
+		Status:             StatusIncomplete,
+		PaymentMethod:      method,
+		PaymentToken:       token,
+		CurrentPeriodStart: now,
+		CurrentPeriodEnd:   plan.PeriodEnd(now),
+		Credit:             order.NewMoney(0, plan.Price.Currency),
+		Version:            1,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if trialEnd := plan.TrialEnd(now); trialEnd != nil {
+		s.Status = StatusTrialing
+		s.TrialEnd = trialEnd
+		s.CurrentPeriodEnd = *trialEnd
+	}
+	return s, nil
+}
+
+// CheckVersion 校验客户端读取时的版本号（If-Match），expected 为 0 时不校验
+func (s *Subscription) CheckVersion(expected int) error {
+	if expected != 0 && expected != s.Version {
+		return ErrConcurrentModification
+	}
+	return nil
+}
+
+// validatePaymentMethod 续费需离线扣款，只接受带令牌的网关支付方式
+func validatePaymentMethod(method order.PaymentMethod, token string) error {
+	if !method.IsValid() || method == order.PaymentMethodCash || token == "" {
+		return ErrInvalidPaymentMethod
+	}
+	return nil
+}
+
+// IsIncomplete 是否尚未完成首期扣款
+func (s *Subscription) IsIncomplete() bool {
+	return s.Status == StatusIncomplete
+}
+
+// Activate 首期扣款成功后生效，首个周期自生效时起算
+func (s *Subscription) Activate(plan *Plan, orderID string, creditUsed order.Money, now time.Time) error {
+	if !s.IsIncomplete() {
+		return fmt.Errorf("%w: cannot activate %s subscription", ErrInvalidSubscriptionStatus, s.Status)
+	}
+
+	s.RecordCharge(orderID, creditUsed, now)
+	s.Status = StatusActive
+	s.CurrentPeriodStart = now
+	s.CurrentPeriodEnd = plan.PeriodEnd(now)
+	return nil
+}
+
+// Abandon 首期扣款失败，终止未完成的订阅
+func (s *Subscription) Abandon(now time.Time) error {
+	if !s.IsIncomplete() {
+		return fmt.Errorf("%w: cannot abandon %s subscription", ErrInvalidSubscriptionStatus, s.Status)
+	}
+	s.end(now)
+	return nil
+}
+
+// IsTrialing 是否处于试用期
+func (s *Subscription) IsTrialing() bool {
+	return s.Status == StatusTrialing
+}
+
+// IsEnded 是否已终止
+func (s *Subscription) IsEnded() bool {
+	return s.Status == StatusCanceled
+}
+
+// ShouldEnd 申请了期末取消且当前周期已结束
+func (s *Subscription) ShouldEnd(now time.Time) bool {
+	return !s.IsEnded() && s.CancelAtPeriodEnd && !now.Before(s.CurrentPeriodEnd)
+}
+
+// CreditFor 本次账单可抵扣的余额，不超过账单金额
+func (s *Subscription) CreditFor(amount order.Money) order.Money {
+	if s.Credit.Currency != amount.Currency || !s.Credit.IsPositive() {
+		return order.NewMoney(0, amount.Currency)
+	}
+	return order.NewMoney(math.Min(s.Credit.Amount, amount.Amount), amount.Currency)
+}
+
+// RecordCharge 记录扣款成功的订单并扣减已抵扣的余额
+func (s *Subscription) RecordCharge(orderID string, creditUsed order.Money, now time.Time) {
+	if creditUsed.IsPositive() {
+		s.Credit = order.NewMoney(roundAmount(max(s.Credit.Amount-creditUsed.Amount, 0)), s.Credit.Currency)
+	}
+	s.LastOrderID = orderID
+	s.UpdatedAt = now
+}
+
+// Renew 续费扣款成功后进入下一个计费周期，清除催缴状态
+// 催缴期间补缴成功时，新周期仍自原周期结束时间起算
+func (s *Subscription) Renew(plan *Plan, now time.Time) error {
+	if s.Status != StatusTrialing && s.Status != StatusActive && s.Status != StatusPastDue {
+		return fmt.Errorf("%w: cannot renew %s subscription", ErrInvalidSubscriptionStatus, s.Status)
+	}
+
+	s.Status = StatusActive
+	s.CurrentPeriodStart = s.CurrentPeriodEnd
+	s.CurrentPeriodEnd = plan.PeriodEnd(s.CurrentPeriodStart)
+	s.FailedAttempts = 0
+	s.NextRetryAt = nil
+	s.UpdatedAt = now
+	return nil
+}
+
+// RecordPaymentFailure 记录续费扣款失败：按重试计划安排下一次扣款，重试用尽时终止订阅
+// 返回订阅是否因此终止
+func (s *Subscription) RecordPaymentFailure(schedule RetrySchedule, now time.Time) bool {
+	s.FailedAttempts++
+	s.UpdatedAt = now
+
+	delay, ok := schedule.Next(s.FailedAttempts)
+	if !ok {
+		s.end(now)
+		return true
+	}
+
+	retryAt := now.Add(delay)
+	s.Status = StatusPastDue
+	s.NextRetryAt = &retryAt
+	return false
+}
+
+// Cancel 申请取消：试用或已付款的订阅在当前周期结束时终止，催缴中的订阅立即终止
+// 首期扣款未完成的订阅不能取消，扣款结果确定后再处理
+func (s *Subscription) Cancel(now time.Time) error {
+	if s.IsEnded() {
+		return fmt.Errorf("%w: subscription already ended", ErrInvalidSubscriptionStatus)
+	}
+	if s.IsIncomplete() {
+		return fmt.Errorf("%w: first payment is still in progress", ErrInvalidSubscriptionStatus)
+	}
+
+	s.CanceledAt = &now
+	s.UpdatedAt = now
+	if s.Status == StatusPastDue {
+		s.end(now)
+		return nil
+	}
+	s.CancelAtPeriodEnd = true
+	return nil
+}
+
+// Resume 撤销期末取消
+func (s *Subscription) Resume(now time.Time) error {
+	if s.IsEnded() || !s.CancelAtPeriodEnd {
+		return fmt.Errorf("%w: subscription is not scheduled for cancellation", ErrInvalidSubscriptionStatus)
+	}
+	s.CancelAtPeriodEnd = false
+	s.CanceledAt = nil
+	s.UpdatedAt = now
+	return nil
+}
+
+// End 期末取消到期，终止订阅
+func (s *Subscription) End(now time.Time) error {
+	if !s.ShouldEnd(now) {
+		return fmt.Errorf("%w: subscription period has not ended", ErrInvalidSubscriptionStatus)
+	}
+	s.end(now)
+	return nil
+}
+
+// end 终止订阅
+func (s *Subscription) end(now time.Time) {
+	s.Status = StatusCanceled
+	s.EndedAt = &now
+	s.NextRetryAt = nil
+	s.UpdatedAt = now
+}
+
+// UpdatePaymentMethod 更换续费支付方式，催缴中的订阅在下一次 worker 执行时立即重试
+func (s *Subscription) UpdatePaymentMethod(method order.PaymentMethod, token string, now time.Time) error {
+	if s.IsEnded() {
+		return fmt.Errorf("%w: subscription already ended", ErrInvalidSubscriptionStatus)
+	}
+	if err := validatePaymentMethod(method, token); err != nil {
+		return err
+	}
+
+	s.PaymentMethod = method
+	s.PaymentToken = token
+	if s.Status == StatusPastDue {
+		s.NextRetryAt = &now
+	}
+	s.UpdatedAt = now
+	return nil
+}
+
+// ChangePlan 变更计划，返回按当前周期剩余时长计算的差价：
+// 正数需立即补缴，负数计入余额抵扣后续账单；试用期内变更不产生差价。
+// 新计划的计费周期自下一次续费起生效
+func (s *Subscription) ChangePlan(current, next *Plan, now time.Time) (order.Money, error) {
+	if s.Status != StatusTrialing && s.Status != StatusActive {
+		return order.Money{}, fmt.Errorf("%w: cannot change plan of %s subscription", ErrInvalidSubscriptionStatus, s.Status)
+	}
+	if current.ID == next.ID {
+		return order.Money{}, ErrSamePlan
+	}
+	if !next.IsActive {
+		return order.Money{}, ErrPlanInactive
+	}
+	if current.Price.Currency != next.Price.Currency {
+		return order.Money{}, ErrCurrencyMismatch
+	}
+
+	proration := order.NewMoney(0, next.Price.Currency)
+	if !s.IsTrialing() {
+		proration = Prorate(current, next, s.CurrentPeriodStart, s.CurrentPeriodEnd, now)
+	}
+	if proration.Amount < 0 {
+		s.Credit = order.NewMoney(roundAmount(s.Credit.Amount-proration.Amount), next.Price.Currency)
+	}
+
+	s.PlanID = next.ID
+	s.UpdatedAt = now
+	return proration, nil
+}
+
+// Prorate 计算在 now 从 current 改用 next 的差价：
+// next 按其计费周期折算的剩余时长费用，减去 current 在本周期未使用部分的费用
+func Prorate(current, next *Plan, periodStart, periodEnd, now time.Time) order.Money {
+	remaining := periodEnd.Sub(now)
+	period := periodEnd.Sub(periodStart)
+	if remaining <= 0 || period <= 0 {
+		return order.NewMoney(0, next.Price.Currency)
+	}
+	remaining = min(remaining, period)
+
+	unused := current.Price.Amount * remaining.Seconds() / period.Seconds()
+	nextPeriod := next.PeriodEnd(periodStart).Sub(periodStart)
+	cost := next.Price.Amount * remaining.Seconds() / nextPeriod.Seconds()
+
+	return order.NewMoney(roundAmount(cost-unused), next.Price.Currency)
+}
+
+// roundAmount 金额按分四舍五入
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// RetrySchedule 催缴重试计划：第 n 次扣款失败后等待第 n 项时长再重试，用尽后终止订阅
+type RetrySchedule []time.Duration
+
+// Next 返回第 attempt 次失败后的重试等待时长，重试用尽时返回 false
+func (r RetrySchedule) Next(attempt int) (time.Duration, bool) {
+	if attempt <= 0 || attempt > len(r) {
+		return 0, false
+	}
+	return r[attempt-1], true
+}
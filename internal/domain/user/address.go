@@ -0,0 +1,61 @@
+package user
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+)
+
+// SavedAddress 用户地址簿中的地址
+type SavedAddress struct {
+	ID                string
+	UserID            string
+	Label             string // 地址名称，如“家”“公司”
+	Address           order.Address
+	IsDefaultShipping bool // 默认收货地址
+	IsDefaultBilling  bool // 默认账单地址
+	Version           int  // 乐观锁版本号，每次保存后递增
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// NewSavedAddress 创建地址簿地址，地址需已通过 order.NewAddress 校验
+func NewSavedAddress(userID, label string, address order.Address) (*SavedAddress, error) {
+	if userID == "" {
+		return nil, errors.New("userID cannot be empty")
+	}
+
+	now := time.Now()
+	return &SavedAddress{
+		UserID:    userID,
+		Label:     strings.TrimSpace(label),
+		Address:   address,
+		Version:   1,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// CheckVersion 校验客户端读取时的版本号（If-Match），expected 为 0 时不校验
+func (a *SavedAddress) CheckVersion(expected int) error {
+	if expected != 0 && expected != a.Version {
+		return ErrConcurrentModification
+	}
+	return nil
+}
+
+// Update 修改地址内容
+func (a *SavedAddress) Update(label string, address order.Address) {
+	a.Label = strings.TrimSpace(label)
+	a.Address = address
+	a.UpdatedAt = time.Now()
+}
+
+// SetDefaults 设置默认收货与账单标记
+func (a *SavedAddress) SetDefaults(shipping, billing bool) {
+	a.IsDefaultShipping = shipping
+	a.IsDefaultBilling = billing
+	a.UpdatedAt = time.Now()
+}
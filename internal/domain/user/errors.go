@@ -20,4 +20,10 @@ var (
 
 	// ErrPasswordTooShort 密码太短
 	ErrPasswordTooShort = errors.New("password must be at least 8 characters")
+
+	// ErrAddressNotFound 地址未找到
+	ErrAddressNotFound = errors.New("address not found")
+
+	// ErrConcurrentModification 用户已被其他请求修改
+	ErrConcurrentModification = errors.New("user was modified concurrently")
 )
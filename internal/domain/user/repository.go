@@ -25,3 +25,24 @@ type Repository interface {
 	// ExistsByEmail 检查邮箱是否已存在
 	ExistsByEmail(ctx context.Context, email Email) (bool, error)
 }
+
+// AddressRepository 地址簿仓储接口
+type AddressRepository interface {
+	// Create 创建地址
+	Create(ctx context.Context, address *SavedAddress) error
+
+	// Update 更新地址
+	Update(ctx context.Context, address *SavedAddress) error
+
+	// Delete 删除地址
+	Delete(ctx context.Context, id string) error
+
+	// FindByID 根据ID查找地址
+	FindByID(ctx context.Context, id string) (*SavedAddress, error)
+
+	// ListByUserID 列出用户的全部地址，按创建时间升序
+	ListByUserID(ctx context.Context, userID string) ([]*SavedAddress, error)
+
+	// ClearDefaults 清除用户其他地址（exceptID 除外）上的默认收货/账单标记
+	ClearDefaults(ctx context.Context, userID, exceptID string, shipping, billing bool) error
+}
@@ -13,6 +13,7 @@ type User struct {
 	Password  Password
 	Username  string
 	IsActive  bool
+	Version   int // 乐观锁版本号，每次保存后递增
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -38,11 +39,20 @@ func NewUser(email, password, username string) (*User, error) {
 		Password:  p,
 		Username:  username,
 		IsActive:  true,
+		Version:   1,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}, nil
 }
 
+// CheckVersion 校验客户端读取时的版本号（If-Match），expected 为 0 时不校验
+func (u *User) CheckVersion(expected int) error {
+	if expected != 0 && expected != u.Version {
+		return ErrConcurrentModification
+	}
+	return nil
+}
+
 // ChangePassword 修改密码
 func (u *User) ChangePassword(newPassword string) error {
 	p, err := NewPassword(newPassword)
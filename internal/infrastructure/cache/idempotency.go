@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// idempotencyKeyPrefix 幂等记录的键前缀
+const idempotencyKeyPrefix = "idempotency:"
+
+// IdempotencyStore 基于 Redis 的幂等记录存储
+// 记录内容由调用方序列化，此处只负责原子占用、覆盖与删除
+type IdempotencyStore struct {
+	client *redis.Client
+}
+
+// NewIdempotencyStore 创建幂等记录存储
+func NewIdempotencyStore(client *redis.Client) *IdempotencyStore {
+	return &IdempotencyStore{client: client}
+}
+
+// Reserve 键不存在时以 value 占用并返回 true；已存在时返回 false 与已存储的值
+func (s *IdempotencyStore) Reserve(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, []byte, error) {
+	ok, err := s.client.SetNX(ctx, idempotencyKeyPrefix+key, value, ttl).Result()
+	if err != nil {
+		return false, nil, err
+	}
+	if ok {
+		return true, nil, nil
+	}
+
+	existing, err := s.client.Get(ctx, idempotencyKeyPrefix+key).Bytes()
+	if err != nil {
+		// 占用失败后记录恰好过期，按处理中返回，由客户端稍后重试
+		if errors.Is(err, redis.Nil) {
+			return false, nil, nil
+		}
+		return false, nil, err
+	}
+	return false, existing, nil
+}
+
+// Save 覆盖保存记录
+func (s *IdempotencyStore) Save(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.client.Set(ctx, idempotencyKeyPrefix+key, value, ttl).Err()
+}
+
+// Delete 删除记录
+func (s *IdempotencyStore) Delete(ctx context.Context, key string) error {
+	return s.client.Del(ctx, idempotencyKeyPrefix+key).Err()
+}
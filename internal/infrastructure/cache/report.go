@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// reportKeyPrefix 报表缓存的键前缀
+	reportKeyPrefix = "report:"
+
+	// reportVersionKey 报表缓存版本号的键，汇总表刷新后递增，旧版本的缓存自然过期
+	reportVersionKey = reportKeyPrefix + "version"
+)
+
+// ReportCache 基于 Redis 的报表结果缓存
+type ReportCache struct {
+	client *redis.Client
+}
+
+// NewReportCache 创建报表缓存
+func NewReportCache(client *redis.Client) *ReportCache {
+	return &ReportCache{client: client}
+}
+
+// Get 读取缓存，不存在时返回 false
+func (c *ReportCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, reportKeyPrefix+key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set 写入缓存
+func (c *ReportCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, reportKeyPrefix+key, value, ttl).Err()
+}
+
+// Version 读取当前缓存版本号，未设置时为 0
+func (c *ReportCache) Version(ctx context.Context) (int64, error) {
+	version, err := c.client.Get(ctx, reportVersionKey).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return version, err
+}
+
+// BumpVersion 递增缓存版本号并返回新版本，使全部已缓存的报表失效
+func (c *ReportCache) BumpVersion(ctx context.Context) (int64, error) {
+	return c.client.Incr(ctx, reportVersionKey).Result()
+}
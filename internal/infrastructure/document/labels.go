@@ -0,0 +1,137 @@
+package document
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// labels 单据文字（多语言）
+type labels struct {
+	Invoice        string
+	CreditNote     string
+	PackingSlip    string
+	Number         string
+	IssueDate      string
+	DueDate        string
+	PaidDate       string
+	OrderNumber    string
+	BillTo         string
+	ShipTo         string
+	Description    string
+	Quantity       string
+	UnitPrice      string
+	TaxRate        string
+	Amount         string
+	Subtotal       string
+	Tax            string
+	Total          string
+	VATID          string
+	CreditFor      string
+	Reason         string
+	Status         string
+	ShippingMethod string
+	Carrier        string
+	TrackingNumber string
+	Product        string
+	SKU            string
+	Page           string
+	Void           string
+
+	DecimalSep  string
+	ThousandSep string
+}
+
+// defaultLanguage 未配置或不支持的语言回退到英文
+const defaultLanguage = "en"
+
+// translations 支持的语言（标准字体仅覆盖西欧字符集）
+var translations = map[string]labels{
+	"en": {
+		Invoice: "INVOICE", CreditNote: "CREDIT NOTE", PackingSlip: "PACKING SLIP",
+		Number: "Number", IssueDate: "Issue date", DueDate: "Due date", PaidDate: "Paid on",
+		OrderNumber: "Order", BillTo: "Bill to", ShipTo: "Ship to",
+		Description: "Description", Quantity: "Qty", UnitPrice: "Unit price", TaxRate: "Tax", Amount: "Amount",
+		Subtotal: "Subtotal", Tax: "Tax", Total: "Total", VATID: "VAT ID",
+		CreditFor: "Credit for invoice", Reason: "Reason", Status: "Status",
+		ShippingMethod: "Shipping method", Carrier: "Carrier", TrackingNumber: "Tracking number",
+		Product: "Product", SKU: "SKU", Page: "Page", Void: "VOID",
+		DecimalSep: ".", ThousandSep: ",",
+	},
+	"de": {
+		Invoice: "RECHNUNG", CreditNote: "GUTSCHRIFT", PackingSlip: "LIEFERSCHEIN",
+		Number: "Nummer", IssueDate: "Rechnungsdatum", DueDate: "Fällig am", PaidDate: "Bezahlt am",
+		OrderNumber: "Bestellung", BillTo: "Rechnungsadresse", ShipTo: "Lieferadresse",
+		Description: "Beschreibung", Quantity: "Menge", UnitPrice: "Einzelpreis", TaxRate: "MwSt.", Amount: "Betrag",
+		Subtotal: "Zwischensumme", Tax: "MwSt.", Total: "Gesamt", VATID: "USt-IdNr.",
+		CreditFor: "Gutschrift zu Rechnung", Reason: "Grund", Status: "Status",
+		ShippingMethod: "Versandart", Carrier: "Versanddienstleister", TrackingNumber: "Sendungsnummer",
+		Product: "Artikel", SKU: "Artikelnr.", Page: "Seite", Void: "STORNIERT",
+		DecimalSep: ",", ThousandSep: ".",
+	},
+	"fr": {
+		Invoice: "FACTURE", CreditNote: "AVOIR", PackingSlip: "BON DE LIVRAISON",
+		Number: "Numéro", IssueDate: "Date d'émission", DueDate: "Échéance", PaidDate: "Payée le",
+		OrderNumber: "Commande", BillTo: "Facturer à", ShipTo: "Livrer à",
+		Description: "Description", Quantity: "Qté", UnitPrice: "Prix unitaire", TaxRate: "TVA", Amount: "Montant",
+		Subtotal: "Sous-total", Tax: "TVA", Total: "Total", VATID: "N° TVA",
+		CreditFor: "Avoir sur facture", Reason: "Motif", Status: "Statut",
+		ShippingMethod: "Mode de livraison", Carrier: "Transporteur", TrackingNumber: "N° de suivi",
+		Product: "Article", SKU: "Réf.", Page: "Page", Void: "ANNULÉE",
+		DecimalSep: ",", ThousandSep: " ",
+	},
+	"es": {
+		Invoice: "FACTURA", CreditNote: "NOTA DE CRÉDITO", PackingSlip: "ALBARÁN",
+		Number: "Número", IssueDate: "Fecha de emisión", DueDate: "Vencimiento", PaidDate: "Pagada el",
+		OrderNumber: "Pedido", BillTo: "Facturar a", ShipTo: "Enviar a",
+		Description: "Descripción", Quantity: "Cant.", UnitPrice: "Precio unitario", TaxRate: "IVA", Amount: "Importe",
+		Subtotal: "Subtotal", Tax: "IVA", Total: "Total", VATID: "NIF-IVA",
+		CreditFor: "Abono de la factura", Reason: "Motivo", Status: "Estado",
+		ShippingMethod: "Método de envío", Carrier: "Transportista", TrackingNumber: "N.º de seguimiento",
+		Product: "Artículo", SKU: "Ref.", Page: "Página", Void: "ANULADA",
+		DecimalSep: ",", ThousandSep: ".",
+	},
+}
+
+// SupportedLanguages 返回支持的语言代码
+func SupportedLanguages() []string {
+	return []string{"en", "de", "fr", "es"}
+}
+
+// labelsFor 获取语言对应的文字，支持 "de-DE" 形式
+func labelsFor(lang string) labels {
+	lang = strings.ToLower(lang)
+	if i := strings.IndexAny(lang, "-_"); i > 0 {
+		lang = lang[:i]
+	}
+	if l, ok := translations[lang]; ok {
+		return l
+	}
+	return translations[defaultLanguage]
+}
+
+// money 按语言格式化金额，如 "1,234.56 EUR" / "1.234,56 EUR"
+func (l labels) money(amount float64, currency string) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	cents := int64(math.Round(amount * 100))
+	whole := fmt.Sprintf("%d", cents/100)
+
+	var b strings.Builder
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(l.ThousandSep)
+		}
+		b.WriteRune(r)
+	}
+	return fmt.Sprintf("%s%s%s%02d %s", sign, b.String(), l.DecimalSep, cents%100, currency)
+}
+
+// percent 格式化税率，如 0.19 → "19%"，0.0725 → "7,25%"
+func (l labels) percent(rate float64) string {
+	s := strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", rate*100), "0"), ".")
+	return strings.Replace(s, ".", l.DecimalSep, 1) + "%"
+}
@@ -0,0 +1,190 @@
+package document
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+)
+
+// A4 页面尺寸（单位：pt）
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+)
+
+// color RGB 颜色（0-1）
+type color struct {
+	R, G, B float64
+}
+
+// black 默认文字颜色
+var black = color{0, 0, 0}
+
+// pdfWriter 纯 Go 实现的最小 PDF 生成器
+// 仅使用 PDF 标准 14 字体（Helvetica），无需嵌入字体与外部程序；
+// 文本按 WinAnsiEncoding 编码，覆盖西欧语言字符
+type pdfWriter struct {
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+}
+
+// newPDFWriter 创建 PDF 生成器
+func newPDFWriter() *pdfWriter {
+	w := &pdfWriter{}
+	w.AddPage()
+	return w
+}
+
+// AddPage 新增页面
+func (w *pdfWriter) AddPage() {
+	w.page = &bytes.Buffer{}
+	w.pages = append(w.pages, w.page)
+}
+
+// Text 在 (x, y) 处输出文本，y 为距页面顶部的距离
+func (w *pdfWriter) Text(x, y, size float64, bold bool, c color, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(w.page, "BT %.3f %.3f %.3f rg /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n",
+		c.R, c.G, c.B, font, size, x, pageHeight-y, escapeText(s))
+}
+
+// TextRight 右对齐输出文本，right 为文本右边界
+func (w *pdfWriter) TextRight(right, y, size float64, bold bool, c color, s string) {
+	w.Text(right-textWidth(s, size), y, size, bold, c, s)
+}
+
+// Rect 绘制填充矩形，y 为矩形上边距页面顶部的距离
+func (w *pdfWriter) Rect(x, y, width, height float64, c color) {
+	fmt.Fprintf(w.page, "%.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f\n",
+		c.R, c.G, c.B, x, pageHeight-y-height, width, height)
+}
+
+// Line 绘制水平线
+func (w *pdfWriter) Line(x1, x2, y, thickness float64, c color) {
+	fmt.Fprintf(w.page, "%.3f %.3f %.3f RG %.2f w %.2f %.2f m %.2f %.2f l S\n",
+		c.R, c.G, c.B, thickness, x1, pageHeight-y, x2, pageHeight-y)
+}
+
+// Bytes 输出完整 PDF 文件
+func (w *pdfWriter) Bytes() ([]byte, error) {
+	var out bytes.Buffer
+	offsets := make([]int, 0)
+
+	writeObj := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 1: Catalog, 2: Pages, 3-4: 字体, 之后每页两个对象（Page + Contents）
+	writeObj("<< /Type /Catalog /Pages 2 0 R >>")
+
+	kids := make([]string, len(w.pages))
+	for i := range w.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+	writeObj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(w.pages)))
+	writeObj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	writeObj("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range w.pages {
+		writeObj(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 6+i*2,
+		))
+
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		if _, err := zw.Write(page.Bytes()); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		writeObj(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", compressed.Len(), compressed.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes(), nil
+}
+
+// winAnsiExtra WinAnsiEncoding 中 0x80-0x9F 区间的字符
+var winAnsiExtra = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// escapeText 将 UTF-8 文本转换为 WinAnsi 编码的 PDF 字符串，无法编码的字符替换为 '?'
+func escapeText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		var c byte
+		switch {
+		case r < 0x80 || (r >= 0xA0 && r <= 0xFF):
+			c = byte(r)
+		case winAnsiExtra[r] != 0:
+			c = winAnsiExtra[r]
+		default:
+			c = '?'
+		}
+		switch c {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n', '\r', '\t':
+			b.WriteByte(' ')
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// textWidth 估算 Helvetica 文本宽度（数字与常用标点使用精确字宽，其余取平均值）
+func textWidth(s string, size float64) float64 {
+	units := 0
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			units += 556
+		case r == '.' || r == ',' || r == ' ' || r == ':':
+			units += 278
+		case r == '-':
+			units += 333
+		case r == '%':
+			units += 889
+		case r >= 'A' && r <= 'Z':
+			units += 667
+		case r == 'i' || r == 'l' || r == 'j':
+			units += 222
+		default:
+			units += 556
+		}
+	}
+	return float64(units) * size / 1000
+}
+
+// truncate 按宽度截断文本
+func truncate(s string, size, maxWidth float64) string {
+	if textWidth(s, size) <= maxWidth {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && textWidth(string(runes)+"...", size) > maxWidth {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
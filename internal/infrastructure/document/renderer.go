@@ -0,0 +1,384 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/invoice"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+)
+
+// Branding 单据品牌配置
+type Branding struct {
+	CompanyName     string
+	AddressLines    []string
+	Email           string
+	Website         string
+	VATID           string
+	AccentColor     string // 十六进制颜色，如 "#1F4E79"
+	FooterTemplate  string // text/template 模板，可用字段见 footerData
+	DefaultLanguage string
+}
+
+// footerData 页脚模板数据
+type footerData struct {
+	Company string
+	Email   string
+	Website string
+	VATID   string
+	Title   string
+	Number  string
+}
+
+// 版面参数
+const (
+	marginLeft   = 40.0
+	marginRight  = pageWidth - 40.0
+	contentLimit = pageHeight - 90.0
+	rowHeight    = 16.0
+)
+
+var (
+	gray      = color{0.4, 0.4, 0.4}
+	lightGray = color{0.85, 0.85, 0.85}
+	white     = color{1, 1, 1}
+)
+
+// PDFRenderer 纯 Go PDF 单据渲染器
+type PDFRenderer struct {
+	branding Branding
+	accent   color
+	footer   *template.Template
+}
+
+// NewPDFRenderer 创建 PDF 单据渲染器
+func NewPDFRenderer(branding Branding) (*PDFRenderer, error) {
+	accent, err := parseHexColor(branding.AccentColor)
+	if err != nil {
+		return nil, err
+	}
+
+	footer, err := template.New("footer").Parse(branding.FooterTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid document footer template: %w", err)
+	}
+
+	if branding.DefaultLanguage == "" {
+		branding.DefaultLanguage = defaultLanguage
+	}
+
+	return &PDFRenderer{
+		branding: branding,
+		accent:   accent,
+		footer:   footer,
+	}, nil
+}
+
+// DefaultLanguage 返回默认单据语言
+func (r *PDFRenderer) DefaultLanguage() string {
+	return r.branding.DefaultLanguage
+}
+
+// RenderInvoice 渲染发票或红字发票
+func (r *PDFRenderer) RenderInvoice(inv *invoice.Invoice, o *order.Order, lang string) ([]byte, error) {
+	l := r.labels(lang)
+	w := newPDFWriter()
+
+	title := l.Invoice
+	if inv.IsCreditNote() {
+		title = l.CreditNote
+	}
+	r.header(w, title)
+	r.sellerBlock(w)
+
+	// 单据信息
+	meta := [][2]string{{l.Number, inv.Number}}
+	if inv.IssuedAt != nil {
+		meta = append(meta, [2]string{l.IssueDate, formatDate(*inv.IssuedAt)})
+	}
+	if inv.PaidAt != nil {
+		meta = append(meta, [2]string{l.PaidDate, formatDate(*inv.PaidAt)})
+	} else if inv.DueAt != nil {
+		meta = append(meta, [2]string{l.DueDate, formatDate(*inv.DueAt)})
+	}
+	meta = append(meta, [2]string{l.OrderNumber, o.OrderNumber})
+	r.metaBlock(w, meta)
+
+	// 买方信息
+	y := 200.0
+	w.Text(marginLeft, y, 9, true, gray, l.BillTo)
+	y += 14
+	billTo := o.BillingAddress
+	if billTo == nil {
+		billTo = o.ShippingAddress
+	}
+	for _, line := range addressLines(billTo) {
+		w.Text(marginLeft, y, 10, false, black, line)
+		y += 13
+	}
+	if o.VATID != "" {
+		w.Text(marginLeft, y, 10, false, black, fmt.Sprintf("%s: %s", l.VATID, o.VATID))
+		y += 13
+	}
+
+	// 明细表
+	y = maxFloat(y+20, 290)
+	columns := []column{
+		{l.Description, marginLeft, false},
+		{l.Quantity, 330, true},
+		{l.UnitPrice, 420, true},
+		{l.TaxRate, 470, true},
+		{l.Amount, marginRight, true},
+	}
+	y = r.tableHeader(w, y, columns)
+	for _, line := range inv.Lines {
+		if y > contentLimit {
+			w.AddPage()
+			y = r.tableHeader(w, 60, columns)
+		}
+		w.Text(marginLeft, y, 9, false, black, truncate(line.Description, 9, 270))
+		w.TextRight(330, y, 9, false, black, strconv.Itoa(line.Quantity))
+		w.TextRight(420, y, 9, false, black, l.money(line.UnitPrice.Amount, line.UnitPrice.Currency))
+		w.TextRight(470, y, 9, false, black, l.percent(line.TaxRate))
+		w.TextRight(marginRight, y, 9, false, black, l.money(line.Total.Amount, line.Total.Currency))
+		y += rowHeight
+	}
+
+	// 合计
+	if y+70 > contentLimit {
+		w.AddPage()
+		y = 60
+	}
+	w.Line(330, marginRight, y-6, 0.5, lightGray)
+	y += 8
+	totals := [][2]string{
+		{l.Subtotal, l.money(inv.Subtotal.Amount, inv.Subtotal.Currency)},
+		{l.Tax, l.money(inv.TaxTotal.Amount, inv.TaxTotal.Currency)},
+	}
+	for _, t := range totals {
+		w.Text(360, y, 10, false, black, t[0])
+		w.TextRight(marginRight, y, 10, false, black, t[1])
+		y += rowHeight
+	}
+	w.Text(360, y, 11, true, black, l.Total)
+	w.TextRight(marginRight, y, 11, true, black, l.money(inv.Total.Amount, inv.Total.Currency))
+	y += rowHeight * 2
+
+	// 备注
+	notes := make([]string, 0, 2)
+	if inv.Reason != "" {
+		notes = append(notes, fmt.Sprintf("%s: %s", l.Reason, inv.Reason))
+	}
+	if inv.Note != "" {
+		notes = append(notes, inv.Note)
+	}
+	for _, note := range notes {
+		w.Text(marginLeft, y, 9, false, gray, truncate(note, 9, marginRight-marginLeft))
+		y += 13
+	}
+
+	if inv.Status == invoice.StatusVoid {
+		w.Text(200, 480, 60, true, lightGray, l.Void)
+	}
+
+	return r.finish(w, l, title, inv.Number)
+}
+
+// RenderPackingSlip 渲染发货装箱单（不含价格）
+func (r *PDFRenderer) RenderPackingSlip(s *order.Shipment, o *order.Order, lang string) ([]byte, error) {
+	l := r.labels(lang)
+	w := newPDFWriter()
+
+	r.header(w, l.PackingSlip)
+	r.sellerBlock(w)
+
+	meta := [][2]string{
+		{l.OrderNumber, o.OrderNumber},
+		{l.IssueDate, formatDate(time.Now())},
+		{l.ShippingMethod, s.ShippingMethod},
+	}
+	if s.Carrier != "" {
+		meta = append(meta, [2]string{l.Carrier, s.Carrier})
+	}
+	if s.TrackingNumber != "" {
+		meta = append(meta, [2]string{l.TrackingNumber, s.TrackingNumber})
+	}
+	r.metaBlock(w, meta)
+
+	y := 200.0
+	w.Text(marginLeft, y, 9, true, gray, l.ShipTo)
+	y += 14
+	for _, line := range addressLines(&s.Address) {
+		w.Text(marginLeft, y, 10, false, black, line)
+		y += 13
+	}
+
+	y = maxFloat(y+20, 290)
+	columns := []column{
+		{l.Product, marginLeft, false},
+		{l.SKU, 380, false},
+		{l.Quantity, marginRight, true},
+	}
+	y = r.tableHeader(w, y, columns)
+	items := make(map[string]*order.OrderItem, len(o.Items))
+	for _, item := range o.Items {
+		items[item.ID] = item
+	}
+	for _, line := range s.Lines {
+		item, ok := items[line.OrderItemID]
+		if !ok {
+			continue
+		}
+		if y > contentLimit {
+			w.AddPage()
+			y = r.tableHeader(w, 60, columns)
+		}
+		w.Text(marginLeft, y, 10, false, black, truncate(item.ProductName, 10, 330))
+		w.Text(380, y, 9, false, gray, truncate(item.ProductID, 9, 120))
+		w.TextRight(marginRight, y, 10, true, black, strconv.Itoa(line.Quantity))
+		y += rowHeight
+	}
+
+	return r.finish(w, l, l.PackingSlip, o.OrderNumber)
+}
+
+// column 表格列
+type column struct {
+	title      string
+	x          float64 // 左对齐列为左边界，右对齐列为右边界
+	alignRight bool
+}
+
+// labels 获取语言文字，未指定时使用默认语言
+func (r *PDFRenderer) labels(lang string) labels {
+	if lang == "" {
+		lang = r.branding.DefaultLanguage
+	}
+	return labelsFor(lang)
+}
+
+// header 绘制品牌页眉
+func (r *PDFRenderer) header(w *pdfWriter, title string) {
+	w.Rect(0, 0, pageWidth, 70, r.accent)
+	w.Text(marginLeft, 44, 20, true, white, r.branding.CompanyName)
+	w.TextRight(marginRight, 44, 16, true, white, title)
+}
+
+// sellerBlock 绘制卖方信息
+func (r *PDFRenderer) sellerBlock(w *pdfWriter) {
+	y := 100.0
+	lines := append([]string{}, r.branding.AddressLines...)
+	if r.branding.Email != "" {
+		lines = append(lines, r.branding.Email)
+	}
+	if r.branding.VATID != "" {
+		lines = append(lines, r.branding.VATID)
+	}
+	for _, line := range lines {
+		w.Text(marginLeft, y, 9, false, gray, line)
+		y += 12
+	}
+}
+
+// metaBlock 绘制单据信息（右侧）
+func (r *PDFRenderer) metaBlock(w *pdfWriter, rows [][2]string) {
+	y := 100.0
+	for _, row := range rows {
+		w.Text(360, y, 9, true, gray, row[0])
+		w.TextRight(marginRight, y, 9, false, black, row[1])
+		y += 13
+	}
+}
+
+// tableHeader 绘制表头，返回第一行的位置
+func (r *PDFRenderer) tableHeader(w *pdfWriter, y float64, columns []column) float64 {
+	w.Rect(marginLeft-4, y-12, marginRight-marginLeft+8, 18, lightGray)
+	for _, col := range columns {
+		if col.alignRight {
+			w.TextRight(col.x, y, 9, true, black, col.title)
+		} else {
+			w.Text(col.x, y, 9, true, black, col.title)
+		}
+	}
+	return y + rowHeight + 4
+}
+
+// finish 为每页添加页脚与页码并输出 PDF
+func (r *PDFRenderer) finish(w *pdfWriter, l labels, title, number string) ([]byte, error) {
+	var footer bytes.Buffer
+	if err := r.footer.Execute(&footer, footerData{
+		Company: r.branding.CompanyName,
+		Email:   r.branding.Email,
+		Website: r.branding.Website,
+		VATID:   r.branding.VATID,
+		Title:   title,
+		Number:  number,
+	}); err != nil {
+		return nil, err
+	}
+
+	for i, page := range w.pages {
+		w.page = page
+		w.Line(marginLeft, marginRight, pageHeight-50, 0.5, lightGray)
+		w.Text(marginLeft, pageHeight-36, 8, false, gray, truncate(footer.String(), 8, 420))
+		w.TextRight(marginRight, pageHeight-36, 8, false, gray, fmt.Sprintf("%s %d/%d", l.Page, i+1, len(w.pages)))
+	}
+
+	return w.Bytes()
+}
+
+// addressLines 地址分行
+func addressLines(a *order.Address) []string {
+	if a == nil {
+		return nil
+	}
+	lines := make([]string, 0, 3)
+	if a.Street != "" {
+		lines = append(lines, a.Street)
+	}
+	cityLine := strings.TrimSpace(strings.Join([]string{a.PostalCode, a.City, a.State}, " "))
+	if cityLine != "" {
+		lines = append(lines, cityLine)
+	}
+	if a.Country != "" {
+		lines = append(lines, a.Country)
+	}
+	return lines
+}
+
+// formatDate 单据日期格式（ISO 8601，各语言通用）
+func formatDate(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// parseHexColor 解析十六进制颜色
+func parseHexColor(hex string) (color, error) {
+	if hex == "" {
+		return color{0.12, 0.31, 0.47}, nil
+	}
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) != 6 {
+		return color{}, fmt.Errorf("invalid document accent color: %q", hex)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color{}, fmt.Errorf("invalid document accent color: %q", hex)
+	}
+	return color{
+		R: float64(v>>16&0xFF) / 255,
+		G: float64(v>>8&0xFF) / 255,
+		B: float64(v&0xFF) / 255,
+	}, nil
+}
+
+// maxFloat 返回较大值
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
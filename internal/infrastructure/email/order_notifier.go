@@ -0,0 +1,44 @@
+package email
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/user"
+)
+
+// Sender 邮件发送接口
+type Sender interface {
+	Send(to, subject, body string) error
+}
+
+// OrderNotifier 以邮件通知客户订单状态变化
+type OrderNotifier struct {
+	sender Sender
+	users  user.Repository
+}
+
+// NewOrderNotifier 创建订单邮件通知器
+func NewOrderNotifier(sender Sender, users user.Repository) *OrderNotifier {
+	return &OrderNotifier{
+		sender: sender,
+		users:  users,
+	}
+}
+
+// OrderCancelled 通知客户订单已取消
+func (n *OrderNotifier) OrderCancelled(ctx context.Context, o *order.Order, reason string) error {
+	u, err := n.users.FindByID(ctx, o.UserID)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("Your order %s has been cancelled", o.OrderNumber)
+	body := fmt.Sprintf("Hello %s,\r\n\r\n"+
+		"Your order %s (%.2f %s) has been cancelled: %s.\r\n"+
+		"If you still want these items, please place a new order.\r\n",
+		u.Username, o.OrderNumber, o.TotalAmount.Amount, o.TotalAmount.Currency, reason)
+
+	return n.sender.Send(u.Email.String(), subject, body)
+}
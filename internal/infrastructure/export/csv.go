@@ -0,0 +1,49 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+// utf8BOM 使 Excel 以 UTF-8 打开 CSV
+const utf8BOM = "\ufeff"
+
+// CSVWriter CSV 流式写入器
+type CSVWriter struct {
+	w *csv.Writer
+}
+
+// NewCSVWriter 创建 CSV 流式写入器
+func NewCSVWriter(w io.Writer) (*CSVWriter, error) {
+	if _, err := io.WriteString(w, utf8BOM); err != nil {
+		return nil, err
+	}
+	return &CSVWriter{w: csv.NewWriter(w)}, nil
+}
+
+// WriteRow 写入一行，行内容在缓冲区满时写出
+func (c *CSVWriter) WriteRow(values []string) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = escapeFormula(value)
+	}
+	return c.w.Write(record)
+}
+
+// Close 写出缓冲区中剩余的行
+func (c *CSVWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// escapeFormula 以单引号前缀转义可能被电子表格当作公式执行的文本（CSV 注入），数字保持原样
+func escapeFormula(value string) string {
+	if value == "" || isNumeric(value) {
+		return value
+	}
+	if strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
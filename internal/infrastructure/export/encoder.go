@@ -0,0 +1,36 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+)
+
+// Encoder 表格导出编码器，按格式创建流式写入器
+type Encoder struct{}
+
+// NewEncoder 创建表格导出编码器
+func NewEncoder() *Encoder {
+	return &Encoder{}
+}
+
+// NewWriter 按格式创建流式写入器
+func (e *Encoder) NewWriter(format order.ExportFormat, w io.Writer) (order.ExportWriter, error) {
+	switch format {
+	case order.ExportFormatCSV:
+		return NewCSVWriter(w)
+	case order.ExportFormatXLSX:
+		return NewXLSXWriter(w)
+	}
+	return nil, fmt.Errorf("%w: %q", order.ErrUnsupportedExportFormat, format)
+}
+
+// numericPattern 可作为数字输出的单元格；带前导零的编码保持文本
+var numericPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]{0,14})(\.[0-9]+)?$`)
+
+// isNumeric 单元格是否为数字
+func isNumeric(value string) bool {
+	return numericPattern.MatchString(value)
+}
@@ -0,0 +1,102 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+)
+
+// 假网关的脚本结果
+const (
+	FakeOutcomeSucceeded       = "succeeded"
+	FakeOutcomeRequiresAction  = "requires_action"
+	FakeOutcomeRequiresCapture = "requires_capture"
+	FakeOutcomeProcessing      = "processing"
+	FakeOutcomeDeclined        = "declined"
+	FakeOutcomeError           = "error"
+)
+
+// fakeTokenPrefix 支付方式令牌以此为前缀时强制指定结果，如 "fake:declined"
+const fakeTokenPrefix = "fake:"
+
+// FakeGateway 本地开发用的假网关，按脚本依次返回结果（循环）
+type FakeGateway struct {
+	mu       sync.Mutex
+	outcomes []string
+	next     int
+	seq      int
+}
+
+// NewFakeGateway 创建假网关，未配置脚本时总是成功
+func NewFakeGateway(outcomes []string) (*FakeGateway, error) {
+	if len(outcomes) == 0 {
+		outcomes = []string{FakeOutcomeSucceeded}
+	}
+	for _, outcome := range outcomes {
+		if !isFakeOutcome(outcome) {
+			return nil, fmt.Errorf("unknown fake gateway outcome %q", outcome)
+		}
+	}
+	return &FakeGateway{outcomes: outcomes}, nil
+}
+
+// ProcessPayment 按脚本返回扣款结果
+func (g *FakeGateway) ProcessPayment(ctx context.Context, req order.ChargeRequest) (*order.ChargeResult, error) {
+	g.mu.Lock()
+	outcome := g.outcomes[g.next%len(g.outcomes)]
+	g.next++
+	g.seq++
+	transactionID := fmt.Sprintf("fake_%s_%d", req.PaymentID, g.seq)
+	g.mu.Unlock()
+
+	if forced := strings.TrimPrefix(req.Token, fakeTokenPrefix); forced != req.Token && isFakeOutcome(forced) {
+		outcome = forced
+	}
+
+	result := &order.ChargeResult{TransactionID: transactionID, Response: "fake: " + outcome}
+	switch outcome {
+	case FakeOutcomeSucceeded:
+		result.Status = order.ChargeSucceeded
+	case FakeOutcomeRequiresAction:
+		result.Status = order.ChargeRequiresAction
+		result.ClientSecret = transactionID + "_secret"
+	case FakeOutcomeRequiresCapture:
+		result.Status = order.ChargeRequiresCapture
+	case FakeOutcomeProcessing:
+		result.Status = order.ChargeProcessing
+	case FakeOutcomeDeclined:
+		return nil, errors.New("fake: card declined")
+	default:
+		return nil, fmt.Errorf("%w: fake outcome", order.ErrGatewayUnavailable)
+	}
+	return result, nil
+}
+
+// CapturePayment 请款总是成功
+func (g *FakeGateway) CapturePayment(ctx context.Context, transactionID string, amount order.Money, idempotencyKey string) error {
+	return nil
+}
+
+// RefundPayment 退款总是成功
+func (g *FakeGateway) RefundPayment(ctx context.Context, transactionID string, amount order.Money, idempotencyKey string) error {
+	return nil
+}
+
+// CancelPayment 撤销总是成功
+func (g *FakeGateway) CancelPayment(ctx context.Context, transactionID string, idempotencyKey string) error {
+	return nil
+}
+
+// isFakeOutcome 是否为有效的脚本结果
+func isFakeOutcome(outcome string) bool {
+	switch outcome {
+	case FakeOutcomeSucceeded, FakeOutcomeRequiresAction, FakeOutcomeRequiresCapture,
+		FakeOutcomeProcessing, FakeOutcomeDeclined, FakeOutcomeError:
+		return true
+	}
+	return false
+}
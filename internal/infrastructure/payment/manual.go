@@ -0,0 +1,46 @@
+package payment
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+)
+
+// ManualGateway 线下收款网关（现金、银行转账等）
+// 不与任何外部系统交互，支付保持待确认，由管理员确认到账后完成
+type ManualGateway struct{}
+
+// NewManualGateway 创建线下收款网关
+func NewManualGateway() *ManualGateway {
+	return &ManualGateway{}
+}
+
+// ProcessPayment 登记待收款，交易号由支付ID生成，确认到账时可替换为收据号
+func (g *ManualGateway) ProcessPayment(ctx context.Context, req order.ChargeRequest) (*order.ChargeResult, error) {
+	return &order.ChargeResult{
+		TransactionID: "manual_" + req.PaymentID,
+		Status:        order.ChargeAwaitingReceipt,
+		Response:      "awaiting receipt",
+	}, nil
+}
+
+// CapturePayment 线下收款没有授权环节
+func (g *ManualGateway) CapturePayment(ctx context.Context, transactionID string, amount order.Money, idempotencyKey string) error {
+	return errors.New("manual payments cannot be captured")
+}
+
+// RefundPayment 线下退款由财务处理，此处仅记录
+func (g *ManualGateway) RefundPayment(ctx context.Context, transactionID string, amount order.Money, idempotencyKey string) error {
+	return nil
+}
+
+// CancelPayment 线下收款没有网关交易，撤销只需更新本地记录
+func (g *ManualGateway) CancelPayment(ctx context.Context, transactionID string, idempotencyKey string) error {
+	return nil
+}
+
+// RequiresManualConfirmation 需管理员确认到账
+func (g *ManualGateway) RequiresManualConfirmation() bool {
+	return true
+}
@@ -2,52 +2,283 @@ package payment
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
 )
 
-// StripeGateway Stripe支付网关
+const (
+	// DefaultStripeBaseURL Stripe API 地址
+	DefaultStripeBaseURL = "https://api.stripe.com"
+	// stripeAPIVersion 固定 API 版本，避免账户默认版本升级导致响应结构变化
+	stripeAPIVersion = "2024-06-20"
+)
+
+// StripeConfig Stripe 网关配置
+type StripeConfig struct {
+	SecretKey     string
+	BaseURL       string        // 可指向本地模拟服务，见 stripemock
+	CaptureMethod string        // automatic（默认）或 manual（先授权后请款）
+	Timeout       time.Duration // 单次请求超时
+}
+
+// StripeGateway Stripe支付网关（基于 PaymentIntent）
 type StripeGateway struct {
-	secretKey string
+	secretKey     string
+	baseURL       string
+	captureMethod string
+	client        *http.Client
 }
 
 // NewStripeGateway 创建Stripe网关
-func NewStripeGateway(secretKey string) *StripeGateway {
+func NewStripeGateway(cfg StripeConfig) *StripeGateway {
+	baseURL := strings.TrimRight(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultStripeBaseURL
+	}
+	captureMethod := cfg.CaptureMethod
+	if captureMethod != "manual" {
+		captureMethod = "automatic"
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+
 	return &StripeGateway{
-		secretKey: secretKey,
+		secretKey:     cfg.SecretKey,
+		baseURL:       baseURL,
+		captureMethod: captureMethod,
+		client:        &http.Client{Timeout: timeout},
+	}
+}
+
+// StripeError Stripe API 错误
+type StripeError struct {
+	HTTPStatus  int
+	Type        string `json:"type"`
+	Code        string `json:"code"`
+	DeclineCode string `json:"decline_code"`
+	Message     string `json:"message"`
+}
+
+// Error 实现 error 接口
+func (e *StripeError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("stripe: %s (%s)", e.Message, e.Code)
+	}
+	return "stripe: " + e.Message
+}
+
+// IsCardError 是否为持卡人侧错误（拒付、余额不足等），重试同一请求无意义
+func (e *StripeError) IsCardError() bool {
+	return e.Type == "card_error"
+}
+
+// Is 网关侧错误、限流与幂等键冲突时请求结果未知，视为 order.ErrGatewayUnavailable
+func (e *StripeError) Is(target error) bool {
+	if target != order.ErrGatewayUnavailable {
+		return false
 	}
+	return e.HTTPStatus >= 500 || e.HTTPStatus == http.StatusTooManyRequests ||
+		e.HTTPStatus == http.StatusConflict || e.Type == "idempotency_error"
 }
 
-// ProcessPayment 处理支付
-func (s *StripeGateway) ProcessPayment(ctx context.Context, amount order.Money, method order.PaymentMethod) (transactionID, response string, err error) {
-	// 这里是简化实现，实际应该调用Stripe API
-	// 示例实现：
-	transactionID = fmt.Sprintf("stripe_txn_%d", 123456)
-	response = "Payment processed successfully"
+// paymentIntent PaymentIntent 响应（仅解析需要的字段）
+type paymentIntent struct {
+	ID               string       `json:"id"`
+	Status           string       `json:"status"`
+	ClientSecret     string       `json:"client_secret"`
+	LastPaymentError *StripeError `json:"last_payment_error"`
+}
 
-	// 实际实现应该类似：
-	// params := &stripe.PaymentIntentParams{
-	//     Amount:   stripe.Int64(int64(amount.Amount * 100)),
-	//     Currency: stripe.String(strings.ToLower(amount.Currency)),
-	//     PaymentMethod: stripe.String(string(method)),
-	// }
-	// pi, err := paymentintent.New(params)
+// ProcessPayment 创建并确认 PaymentIntent
+func (s *StripeGateway) ProcessPayment(ctx context.Context, req order.ChargeRequest) (*order.ChargeResult, error) {
+	amount, err := toMinorUnits(req.Amount)
+	if err != nil {
+		return nil, err
+	}
 
-	return transactionID, response, nil
+	form := url.Values{}
+	form.Set("amount", strconv.FormatInt(amount, 10))
+	form.Set("currency", strings.ToLower(req.Amount.Currency))
+	form.Set("confirm", "true")
+	form.Set("capture_method", s.captureMethod)
+	form.Set("metadata[order_id]", req.OrderID)
+	form.Set("metadata[payment_id]", req.PaymentID)
+	if req.Token != "" {
+		form.Set("payment_method", req.Token)
+	}
+	// 未提供回跳地址时只允许卡支付，避免需要跳转的支付方式无法完成
+	if req.ReturnURL != "" {
+		form.Set("return_url", req.ReturnURL)
+	} else {
+		form.Set("payment_method_types[]", "card")
+	}
+
+	var pi paymentIntent
+	if err := s.post(ctx, "/v1/payment_intents", form, req.IdempotencyKey, &pi); err != nil {
+		return nil, err
+	}
+
+	result := &order.ChargeResult{
+		TransactionID: pi.ID,
+		Response:      pi.Status,
+	}
+	switch pi.Status {
+	case "succeeded":
+		result.Status = order.ChargeSucceeded
+	case "requires_action":
+		result.Status = order.ChargeRequiresAction
+		result.ClientSecret = pi.ClientSecret
+	case "requires_capture":
+		result.Status = order.ChargeRequiresCapture
+	case "processing":
+		result.Status = order.ChargeProcessing
+	default:
+		// requires_payment_method / canceled：确认失败
+		if pi.LastPaymentError != nil {
+			return nil, pi.LastPaymentError
+		}
+		return nil, fmt.Errorf("stripe: unexpected payment intent status %q", pi.Status)
+	}
+	return result, nil
+}
+
+// CapturePayment 对已授权的 PaymentIntent 请款
+func (s *StripeGateway) CapturePayment(ctx context.Context, transactionID string, amount order.Money, idempotencyKey string) error {
+	minor, err := toMinorUnits(amount)
+	if err != nil {
+		return err
+	}
+
+	form := url.Values{}
+	form.Set("amount_to_capture", strconv.FormatInt(minor, 10))
+
+	var pi paymentIntent
+	if err := s.post(ctx, "/v1/payment_intents/"+url.PathEscape(transactionID)+"/capture", form, idempotencyKey, &pi); err != nil {
+		return err
+	}
+	if pi.Status != "succeeded" {
+		return fmt.Errorf("stripe: capture left payment intent in status %q", pi.Status)
+	}
+	return nil
 }
 
 // RefundPayment 退款
-func (s *StripeGateway) RefundPayment(ctx context.Context, transactionID string, amount order.Money) error {
-	// 这里是简化实现，实际应该调用Stripe API
-	// 示例实现：
+func (s *StripeGateway) RefundPayment(ctx context.Context, transactionID string, amount order.Money, idempotencyKey string) error {
+	minor, err := toMinorUnits(amount)
+	if err != nil {
+		return err
+	}
+
+	form := url.Values{}
+	form.Set("payment_intent", transactionID)
+	form.Set("amount", strconv.FormatInt(minor, 10))
+
+	var refund struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	if err := s.post(ctx, "/v1/refunds", form, idempotencyKey, &refund); err != nil {
+		return err
+	}
+	if refund.Status == "failed" || refund.Status == "canceled" {
+		return fmt.Errorf("stripe: refund %s %s", refund.ID, refund.Status)
+	}
+	return nil
+}
+
+// CancelPayment 取消尚未收款的 PaymentIntent（待验证、处理中或已授权）
+func (s *StripeGateway) CancelPayment(ctx context.Context, transactionID string, idempotencyKey string) error {
+	var pi paymentIntent
+	if err := s.post(ctx, "/v1/payment_intents/"+url.PathEscape(transactionID)+"/cancel", url.Values{}, idempotencyKey, &pi); err != nil {
+		return err
+	}
+	if pi.Status != "canceled" {
+		return fmt.Errorf("stripe: cancel left payment intent in status %q", pi.Status)
+	}
+	return nil
+}
+
+// post 发送表单请求并解析响应；同一幂等键的重试由 Stripe 返回首次结果
+// 连接失败、超时与无法解析的响应包装为 order.ErrGatewayUnavailable
+func (s *StripeGateway) post(ctx context.Context, path string, form url.Values, idempotencyKey string, out interface{}) error {
+	if s.secretKey == "" {
+		return errors.New("stripe: secret key not configured")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.secretKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Stripe-Version", stripeAPIVersion)
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
 
-	// 实际实现应该类似：
-	// params := &stripe.RefundParams{
-	//     PaymentIntent: stripe.String(transactionID),
-	//     Amount: stripe.Int64(int64(amount.Amount * 100)),
-	// }
-	// _, err := refund.New(params)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: stripe: request failed: %v", order.ErrGatewayUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("%w: stripe: read response: %v", order.ErrGatewayUnavailable, err)
+	}
 
+	if resp.StatusCode >= 400 {
+		var envelope struct {
+			Error *StripeError `json:"error"`
+		}
+		if err := json.Unmarshal(body, &envelope); err != nil || envelope.Error == nil {
+			return &StripeError{HTTPStatus: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+		}
+		envelope.Error.HTTPStatus = resp.StatusCode
+		return envelope.Error
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("%w: stripe: decode response: %v", order.ErrGatewayUnavailable, err)
+	}
 	return nil
 }
+
+// zeroDecimalCurrencies 无小数位的货币，金额按原值传递
+var zeroDecimalCurrencies = map[string]bool{
+	"BIF": true, "CLP": true, "DJF": true, "GNF": true, "JPY": true, "KMF": true,
+	"KRW": true, "MGA": true, "PYG": true, "RWF": true, "UGX": true, "VND": true,
+	"VUV": true, "XAF": true, "XOF": true, "XPF": true,
+}
+
+// fromMinorUnits 最小货币单位转换为金额
+func fromMinorUnits(amount int64, currency string) order.Money {
+	currency = strings.ToUpper(currency)
+	if zeroDecimalCurrencies[currency] {
+		return order.NewMoney(float64(amount), currency)
+	}
+	return order.NewMoney(float64(amount)/100, currency)
+}
+
+// toMinorUnits 金额转换为最小货币单位（如美分）
+func toMinorUnits(m order.Money) (int64, error) {
+	if len(m.Currency) != 3 {
+		return 0, fmt.Errorf("stripe: invalid currency %q", m.Currency)
+	}
+	if zeroDecimalCurrencies[strings.ToUpper(m.Currency)] {
+		return int64(math.Round(m.Amount)), nil
+	}
+	return int64(math.Round(m.Amount * 100)), nil
+}
@@ -0,0 +1,172 @@
+package payment_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/payment"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/payment/stripemock"
+)
+
+// newTestGateway 启动模拟服务并创建指向它的 Stripe 网关
+func newTestGateway(t *testing.T, captureMethod string) *payment.StripeGateway {
+	t.Helper()
+	srv := stripemock.Start(stripemock.Options{})
+	t.Cleanup(srv.Close)
+
+	return payment.NewStripeGateway(payment.StripeConfig{
+		SecretKey:     "sk_test_mock",
+		BaseURL:       srv.URL,
+		CaptureMethod: captureMethod,
+	})
+}
+
+// chargeRequest 构造扣款请求
+func chargeRequest(token, key string) order.ChargeRequest {
+	return order.ChargeRequest{
+		PaymentID:      "pay_test",
+		OrderID:        "ord_test",
+		Amount:         order.NewMoney(49.99, "USD"),
+		Method:         order.PaymentMethodStripe,
+		Token:          token,
+		IdempotencyKey: key,
+	}
+}
+
+func TestStripeGateway_ChargeSucceeded(t *testing.T) {
+	gateway := newTestGateway(t, "")
+
+	result, err := gateway.ProcessPayment(context.Background(), chargeRequest(stripemock.TokenSucceeds, "payment-succeeded"))
+	if err != nil {
+		t.Fatalf("ProcessPayment: %v", err)
+	}
+	if result.Status != order.ChargeSucceeded || result.TransactionID == "" {
+		t.Fatalf("result = %+v, want succeeded with a transaction ID", result)
+	}
+	if result.ClientSecret != "" {
+		t.Errorf("ClientSecret = %q, want empty for a settled charge", result.ClientSecret)
+	}
+}
+
+func TestStripeGateway_ChargeDeclined(t *testing.T) {
+	gateway := newTestGateway(t, "")
+
+	for _, token := range []string{stripemock.TokenDeclined, stripemock.TokenInsufficient} {
+		t.Run(token, func(t *testing.T) {
+			_, err := gateway.ProcessPayment(context.Background(), chargeRequest(token, "payment-"+token))
+			var stripeErr *payment.StripeError
+			if !errors.As(err, &stripeErr) || !stripeErr.IsCardError() {
+				t.Fatalf("ProcessPayment error = %v, want a card error", err)
+			}
+			if errors.Is(err, order.ErrGatewayUnavailable) {
+				t.Error("a decline must not be reported as an unknown outcome")
+			}
+		})
+	}
+}
+
+func TestStripeGateway_ChargeRequiresAction(t *testing.T) {
+	gateway := newTestGateway(t, "")
+
+	result, err := gateway.ProcessPayment(context.Background(), chargeRequest(stripemock.TokenRequires3DS, "payment-3ds"))
+	if err != nil {
+		t.Fatalf("ProcessPayment: %v", err)
+	}
+	if result.Status != order.ChargeRequiresAction {
+		t.Fatalf("Status = %q, want %q", result.Status, order.ChargeRequiresAction)
+	}
+	if result.ClientSecret == "" {
+		t.Error("ClientSecret is empty, the frontend cannot complete 3DS")
+	}
+
+	// 持卡人放弃验证时撤销交易
+	if err := gateway.CancelPayment(context.Background(), result.TransactionID, "cancel-3ds"); err != nil {
+		t.Fatalf("CancelPayment: %v", err)
+	}
+}
+
+func TestStripeGateway_ManualCaptureAndPartialRefund(t *testing.T) {
+	ctx := context.Background()
+	gateway := newTestGateway(t, "manual")
+
+	result, err := gateway.ProcessPayment(ctx, chargeRequest(stripemock.TokenSucceeds, "payment-manual"))
+	if err != nil {
+		t.Fatalf("ProcessPayment: %v", err)
+	}
+	if result.Status != order.ChargeRequiresCapture {
+		t.Fatalf("Status = %q, want %q", result.Status, order.ChargeRequiresCapture)
+	}
+
+	// 请款前不能退款
+	if err := gateway.RefundPayment(ctx, result.TransactionID, order.NewMoney(10, "USD"), "refund-early"); err == nil {
+		t.Fatal("RefundPayment before capture succeeded, want an error")
+	}
+
+	if err := gateway.CapturePayment(ctx, result.TransactionID, order.NewMoney(49.99, "USD"), "capture-manual"); err != nil {
+		t.Fatalf("CapturePayment: %v", err)
+	}
+	if err := gateway.CancelPayment(ctx, result.TransactionID, "cancel-captured"); err == nil {
+		t.Error("CancelPayment after capture succeeded, want an error")
+	}
+
+	if err := gateway.RefundPayment(ctx, result.TransactionID, order.NewMoney(20, "USD"), "refund-partial-1"); err != nil {
+		t.Fatalf("partial RefundPayment: %v", err)
+	}
+	if err := gateway.RefundPayment(ctx, result.TransactionID, order.NewMoney(29.99, "USD"), "refund-partial-2"); err != nil {
+		t.Fatalf("remaining RefundPayment: %v", err)
+	}
+	if err := gateway.RefundPayment(ctx, result.TransactionID, order.NewMoney(0.01, "USD"), "refund-over"); err == nil {
+		t.Fatal("RefundPayment beyond the captured amount succeeded, want an error")
+	}
+}
+
+func TestStripeGateway_IdempotencyKeyReplayed(t *testing.T) {
+	ctx := context.Background()
+	gateway := newTestGateway(t, "")
+
+	first, err := gateway.ProcessPayment(ctx, chargeRequest(stripemock.TokenSucceeds, "payment-replayed"))
+	if err != nil {
+		t.Fatalf("first ProcessPayment: %v", err)
+	}
+	retry, err := gateway.ProcessPayment(ctx, chargeRequest(stripemock.TokenSucceeds, "payment-replayed"))
+	if err != nil {
+		t.Fatalf("retried ProcessPayment: %v", err)
+	}
+	if retry.TransactionID != first.TransactionID {
+		t.Fatalf("retry created PaymentIntent %s, want replay of %s", retry.TransactionID, first.TransactionID)
+	}
+
+	other, err := gateway.ProcessPayment(ctx, chargeRequest(stripemock.TokenSucceeds, "payment-other"))
+	if err != nil {
+		t.Fatalf("ProcessPayment with a new key: %v", err)
+	}
+	if other.TransactionID == first.TransactionID {
+		t.Fatal("a new idempotency key replayed the previous PaymentIntent")
+	}
+
+	// 同一幂等键携带不同参数：首次请求的结果仍以该键为准，按结果未知处理
+	_, err = gateway.ProcessPayment(ctx, chargeRequest(stripemock.TokenDeclined, "payment-replayed"))
+	if !errors.Is(err, order.ErrGatewayUnavailable) {
+		t.Fatalf("reused key with different parameters = %v, want ErrGatewayUnavailable", err)
+	}
+
+	// 同一退款幂等键重放不会重复退款
+	for i := 0; i < 2; i++ {
+		if err := gateway.RefundPayment(ctx, first.TransactionID, order.NewMoney(49.99, "USD"), "refund-replayed"); err != nil {
+			t.Fatalf("RefundPayment attempt %d: %v", i+1, err)
+		}
+	}
+}
+
+func TestStripeGateway_UnreachableIsUnknownOutcome(t *testing.T) {
+	srv := stripemock.Start(stripemock.Options{})
+	gateway := payment.NewStripeGateway(payment.StripeConfig{SecretKey: "sk_test_mock", BaseURL: srv.URL})
+	srv.Close()
+
+	_, err := gateway.ProcessPayment(context.Background(), chargeRequest(stripemock.TokenSucceeds, "payment-unreachable"))
+	if !errors.Is(err, order.ErrGatewayUnavailable) {
+		t.Fatalf("ProcessPayment error = %v, want ErrGatewayUnavailable", err)
+	}
+}
@@ -0,0 +1,169 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+)
+
+// DefaultWebhookTolerance 签名时间戳允许的最大偏差，防止重放
+const DefaultWebhookTolerance = 5 * time.Minute
+
+// StripeWebhookVerifier Stripe 回调验签与解析
+type StripeWebhookVerifier struct {
+	secret    string
+	tolerance time.Duration
+	now       func() time.Time
+}
+
+// NewStripeWebhookVerifier 创建 Stripe 回调验签器
+func NewStripeWebhookVerifier(secret string, tolerance time.Duration) *StripeWebhookVerifier {
+	if tolerance <= 0 {
+		tolerance = DefaultWebhookTolerance
+	}
+	return &StripeWebhookVerifier{
+		secret:    secret,
+		tolerance: tolerance,
+		now:       time.Now,
+	}
+}
+
+// stripeEvent Stripe 事件结构（仅解析需要的字段）
+type stripeEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object struct {
+			ID               string            `json:"id"`
+			Object           string            `json:"object"`
+			PaymentIntent    string            `json:"payment_intent"`
+			AmountRefunded   int64             `json:"amount_refunded"`
+			Currency         string            `json:"currency"`
+			Reason           string            `json:"reason"`
+			FailureMessage   string            `json:"failure_message"`
+			Metadata         map[string]string `json:"metadata"`
+			LastPaymentError *struct {
+				Message string `json:"message"`
+			} `json:"last_payment_error"`
+		} `json:"object"`
+	} `json:"data"`
+}
+
+// Verify 校验 Stripe-Signature 并解析为网关事件
+func (v *StripeWebhookVerifier) Verify(payload []byte, signature string) (*order.GatewayEvent, error) {
+	if err := v.verifySignature(payload, signature); err != nil {
+		return nil, err
+	}
+
+	var evt stripeEvent
+	if err := json.Unmarshal(payload, &evt); err != nil {
+		return nil, fmt.Errorf("%w: malformed payload", order.ErrInvalidWebhookSignature)
+	}
+	if evt.ID == "" || evt.Type == "" {
+		return nil, fmt.Errorf("%w: missing event id or type", order.ErrInvalidWebhookSignature)
+	}
+
+	obj := evt.Data.Object
+	event := &order.GatewayEvent{
+		ID:         evt.ID,
+		Provider:   "stripe",
+		Type:       evt.Type,
+		Kind:       order.GatewayEventIgnored,
+		Payload:    payload,
+		ReceivedAt: v.now(),
+	}
+
+	switch evt.Type {
+	case "payment_intent.succeeded":
+		event.Kind = order.GatewayEventSucceeded
+		event.TransactionID = obj.ID
+		event.PaymentID = obj.Metadata["payment_id"]
+	case "payment_intent.payment_failed":
+		event.Kind = order.GatewayEventFailed
+		event.TransactionID = obj.ID
+		event.PaymentID = obj.Metadata["payment_id"]
+		if obj.LastPaymentError != nil {
+			event.Reason = obj.LastPaymentError.Message
+		}
+	case "charge.refunded":
+		event.Kind = order.GatewayEventRefunded
+		event.TransactionID = obj.PaymentIntent
+		if obj.Currency != "" {
+			event.Amount = fromMinorUnits(obj.AmountRefunded, obj.Currency)
+		}
+	case "charge.dispute.created":
+		event.Kind = order.GatewayEventDisputed
+		event.TransactionID = obj.PaymentIntent
+		event.Reason = obj.Reason
+	}
+
+	return event, nil
+}
+
+// verifySignature 校验签名头 "t=<unix>,v1=<hex>[,v1=...]"
+// 签名内容为 "<t>.<payload>" 的 HMAC-SHA256，任一 v1 匹配即通过
+func (v *StripeWebhookVerifier) verifySignature(payload []byte, header string) error {
+	if v.secret == "" {
+		return fmt.Errorf("%w: webhook secret not configured", order.ErrInvalidWebhookSignature)
+	}
+
+	var timestamp string
+	signatures := make([]string, 0, 1)
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			timestamp = kv[1]
+		case "v1":
+			signatures = append(signatures, kv[1])
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return fmt.Errorf("%w: malformed signature header", order.ErrInvalidWebhookSignature)
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp", order.ErrInvalidWebhookSignature)
+	}
+	if age := v.now().Sub(time.Unix(ts, 0)); age > v.tolerance || age < -v.tolerance {
+		return fmt.Errorf("%w: timestamp outside tolerance", order.ErrInvalidWebhookSignature)
+	}
+
+	expected := computeSignature(v.secret, timestamp, payload)
+	for _, sig := range signatures {
+		decoded, err := hex.DecodeString(sig)
+		if err != nil {
+			continue
+		}
+		if hmac.Equal(decoded, expected) {
+			return nil
+		}
+	}
+	return order.ErrInvalidWebhookSignature
+}
+
+// SignStripePayload 生成 Stripe-Signature 头，用于本地签名测试夹具
+func SignStripePayload(secret string, payload []byte, at time.Time) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, hex.EncodeToString(computeSignature(secret, timestamp, payload)))
+}
+
+// computeSignature 计算签名
+func computeSignature(secret, timestamp string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,179 @@
+package payment
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+)
+
+const testWebhookSecret = "whsec_test_secret"
+
+// fixtureDir 签名夹具目录，与 scripts/stripe_webhook.sh 共用
+var fixtureDir = filepath.Join("..", "..", "..", "scripts", "fixtures", "stripe")
+
+// newTestVerifier 创建时钟固定在 now 的验签器
+func newTestVerifier(now time.Time) *StripeWebhookVerifier {
+	v := NewStripeWebhookVerifier(testWebhookSecret, DefaultWebhookTolerance)
+	v.now = func() time.Time { return now }
+	return v
+}
+
+// loadFixture 读取事件夹具
+func loadFixture(t *testing.T, eventType string) []byte {
+	t.Helper()
+	payload, err := os.ReadFile(filepath.Join(fixtureDir, eventType+".json"))
+	if err != nil {
+		t.Fatalf("read fixture %s: %v", eventType, err)
+	}
+	return payload
+}
+
+func TestStripeWebhookVerifier_ValidSignature(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	payload := loadFixture(t, "payment_intent.succeeded")
+
+	event, err := newTestVerifier(now).Verify(payload, SignStripePayload(testWebhookSecret, payload, now))
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if event.ID != "evt_test_succeeded_0001" || event.Provider != "stripe" {
+		t.Errorf("event = %s/%s, want evt_test_succeeded_0001/stripe", event.ID, event.Provider)
+	}
+	if !event.ReceivedAt.Equal(now) {
+		t.Errorf("ReceivedAt = %v, want %v", event.ReceivedAt, now)
+	}
+	if string(event.Payload) != string(payload) {
+		t.Error("Payload does not match the signed body")
+	}
+}
+
+func TestStripeWebhookVerifier_RejectsInvalidSignatures(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	payload := loadFixture(t, "payment_intent.succeeded")
+	tampered := bytes.Replace(payload, []byte("pi_test_0001"), []byte("pi_test_9999"), 1)
+
+	tests := []struct {
+		name      string
+		payload   []byte
+		signature string
+	}{
+		{"tampered body", tampered, SignStripePayload(testWebhookSecret, payload, now)},
+		{"wrong secret", payload, SignStripePayload("whsec_other", payload, now)},
+		{"stale timestamp", payload, SignStripePayload(testWebhookSecret, payload, now.Add(-DefaultWebhookTolerance-time.Second))},
+		{"future timestamp", payload, SignStripePayload(testWebhookSecret, payload, now.Add(DefaultWebhookTolerance+time.Second))},
+		{"missing v1", payload, fmt.Sprintf("t=%d", now.Unix())},
+		{"malformed header", payload, "garbage"},
+	}
+
+	v := newTestVerifier(now)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := v.Verify(tt.payload, tt.signature)
+			if !errors.Is(err, order.ErrInvalidWebhookSignature) {
+				t.Fatalf("Verify error = %v, want ErrInvalidWebhookSignature", err)
+			}
+		})
+	}
+}
+
+func TestStripeWebhookVerifier_MultipleV1Signatures(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	payload := loadFixture(t, "charge.refunded")
+	valid := SignStripePayload(testWebhookSecret, payload, now)
+	stale := SignStripePayload("whsec_rotated_out", payload, now)
+
+	// 密钥轮换期间 Stripe 同时携带新旧密钥的签名，任一匹配即通过
+	header := stale + "," + valid[len(fmt.Sprintf("t=%d,", now.Unix())):] + ",v1=not-hex"
+	if _, err := newTestVerifier(now).Verify(payload, header); err != nil {
+		t.Fatalf("Verify with multiple v1 entries: %v", err)
+	}
+
+	if _, err := newTestVerifier(now).Verify(payload, stale+",v1=not-hex"); !errors.Is(err, order.ErrInvalidWebhookSignature) {
+		t.Fatalf("Verify without a matching v1 = %v, want ErrInvalidWebhookSignature", err)
+	}
+}
+
+func TestStripeWebhookVerifier_EventKinds(t *testing.T) {
+	tests := []struct {
+		fixture       string
+		kind          order.GatewayEventKind
+		transactionID string
+		paymentID     string
+		reason        string
+		amount        order.Money
+	}{
+		{
+			fixture:       "payment_intent.succeeded",
+			kind:          order.GatewayEventSucceeded,
+			transactionID: "pi_test_0001",
+			paymentID:     "01JTESTPAYMENT000000000001",
+		},
+		{
+			fixture:       "payment_intent.payment_failed",
+			kind:          order.GatewayEventFailed,
+			transactionID: "pi_test_0001",
+			reason:        "Your card was declined.",
+		},
+		{
+			fixture:       "charge.refunded",
+			kind:          order.GatewayEventRefunded,
+			transactionID: "pi_test_0001",
+			amount:        order.NewMoney(100, "USD"),
+		},
+		{
+			fixture:       "charge.dispute.created",
+			kind:          order.GatewayEventDisputed,
+			transactionID: "pi_test_0001",
+			reason:        "fraudulent",
+		},
+	}
+
+	now := time.Unix(1700000000, 0)
+	v := newTestVerifier(now)
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			payload := loadFixture(t, tt.fixture)
+			event, err := v.Verify(payload, SignStripePayload(testWebhookSecret, payload, now))
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if event.Type != tt.fixture {
+				t.Errorf("Type = %q, want %q", event.Type, tt.fixture)
+			}
+			if event.Kind != tt.kind {
+				t.Errorf("Kind = %q, want %q", event.Kind, tt.kind)
+			}
+			if event.TransactionID != tt.transactionID {
+				t.Errorf("TransactionID = %q, want %q", event.TransactionID, tt.transactionID)
+			}
+			if event.PaymentID != tt.paymentID {
+				t.Errorf("PaymentID = %q, want %q", event.PaymentID, tt.paymentID)
+			}
+			if event.Reason != tt.reason {
+				t.Errorf("Reason = %q, want %q", event.Reason, tt.reason)
+			}
+			if event.Amount != tt.amount {
+				t.Errorf("Amount = %+v, want %+v", event.Amount, tt.amount)
+			}
+		})
+	}
+}
+
+func TestStripeWebhookVerifier_IgnoresUnknownEvents(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	payload := []byte(`{"id":"evt_test_other_0001","object":"event","type":"customer.created","data":{"object":{"id":"cus_test_0001"}}}`)
+
+	event, err := newTestVerifier(now).Verify(payload, SignStripePayload(testWebhookSecret, payload, now))
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if event.Kind != order.GatewayEventIgnored || event.TransactionID != "" {
+		t.Errorf("event = %q/%q, want ignored without transaction", event.Kind, event.TransactionID)
+	}
+}
@@ -0,0 +1,527 @@
+// Package stripemock 提供 Stripe PaymentIntent API 的本地模拟服务，
+// 供测试与本地调试时将 payment.stripe_base_url 指向它，无需真实账户与网络。
+package stripemock
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/payment"
+)
+
+// 与 Stripe 测试环境一致的特殊支付方式令牌
+const (
+	TokenSucceeds       = "pm_card_visa"
+	TokenDeclined       = "pm_card_chargeDeclined"
+	TokenInsufficient   = "pm_card_chargeDeclinedInsufficientFunds"
+	TokenRequires3DS    = "pm_card_threeDSecure2Required"
+	TokenAuthentication = "pm_card_authenticationRequired"
+)
+
+// Options 模拟服务配置
+type Options struct {
+	// WebhookURL 非空时，状态变更后向该地址投递已签名的事件
+	WebhookURL    string
+	WebhookSecret string
+}
+
+// Server Stripe 模拟服务（内存存储）
+type Server struct {
+	opts Options
+
+	idemMu      sync.Mutex // 串行化携带幂等键的请求，保证首次响应被完整记录
+	mu          sync.Mutex
+	intents     map[string]*PaymentIntent
+	refunds     map[string]*Refund
+	idempotency map[string]idempotentResponse
+	seq         int
+}
+
+// PaymentIntent 模拟的 PaymentIntent
+type PaymentIntent struct {
+	ID               string            `json:"id"`
+	Object           string            `json:"object"`
+	Amount           int64             `json:"amount"`
+	AmountReceived   int64             `json:"amount_received"`
+	AmountRefunded   int64             `json:"-"`
+	Currency         string            `json:"currency"`
+	Status           string            `json:"status"`
+	CaptureMethod    string            `json:"capture_method"`
+	PaymentMethod    string            `json:"payment_method,omitempty"`
+	ClientSecret     string            `json:"client_secret"`
+	Metadata         map[string]string `json:"metadata"`
+	LastPaymentError *apiError         `json:"last_payment_error"`
+	Created          int64             `json:"created"`
+}
+
+// Refund 模拟的退款
+type Refund struct {
+	ID            string `json:"id"`
+	Object        string `json:"object"`
+	Amount        int64  `json:"amount"`
+	PaymentIntent string `json:"payment_intent"`
+	Status        string `json:"status"`
+	Created       int64  `json:"created"`
+}
+
+// apiError Stripe 错误结构
+type apiError struct {
+	Type        string `json:"type"`
+	Code        string `json:"code,omitempty"`
+	DeclineCode string `json:"decline_code,omitempty"`
+	Message     string `json:"message"`
+}
+
+// idempotentResponse 幂等键对应的首次响应
+type idempotentResponse struct {
+	fingerprint string
+	status      int
+	body        []byte
+}
+
+// NewServer 创建模拟服务
+func NewServer(opts Options) *Server {
+	return &Server{
+		opts:        opts,
+		intents:     make(map[string]*PaymentIntent),
+		refunds:     make(map[string]*Refund),
+		idempotency: make(map[string]idempotentResponse),
+	}
+}
+
+// Start 在随机端口启动模拟服务，返回值的 URL 可作为 StripeConfig.BaseURL
+func Start(opts Options) *httptest.Server {
+	return httptest.NewServer(NewServer(opts))
+}
+
+// ServeHTTP 实现 http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer sk_") {
+		writeError(w, http.StatusUnauthorized, apiError{Type: "invalid_request_error", Message: "Invalid API Key provided"})
+		return
+	}
+
+	path := strings.Trim(r.URL.Path, "/")
+	parts := strings.Split(path, "/")
+
+	switch {
+	case r.Method == http.MethodGet && len(parts) == 3 && parts[1] == "payment_intents":
+		s.getIntent(w, parts[2])
+	case r.Method == http.MethodPost && path == "v1/payment_intents":
+		s.idempotent(w, r, s.createIntent)
+	case r.Method == http.MethodPost && len(parts) == 4 && parts[1] == "payment_intents" && parts[3] == "confirm":
+		s.idempotent(w, r, func(form formValues) (int, interface{}) { return s.confirmIntent(parts[2]) })
+	case r.Method == http.MethodPost && len(parts) == 4 && parts[1] == "payment_intents" && parts[3] == "capture":
+		s.idempotent(w, r, func(form formValues) (int, interface{}) { return s.captureIntent(parts[2], form) })
+	case r.Method == http.MethodPost && len(parts) == 4 && parts[1] == "payment_intents" && parts[3] == "cancel":
+		s.idempotent(w, r, func(form formValues) (int, interface{}) { return s.cancelIntent(parts[2]) })
+	case r.Method == http.MethodPost && path == "v1/refunds":
+		s.idempotent(w, r, s.createRefund)
+	default:
+		writeError(w, http.StatusNotFound, apiError{Type: "invalid_request_error", Message: "Unrecognized request URL"})
+	}
+}
+
+// Intent 返回 PaymentIntent 的快照，便于断言
+func (s *Server) Intent(id string) (PaymentIntent, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pi, ok := s.intents[id]
+	if !ok {
+		return PaymentIntent{}, false
+	}
+	return *pi, true
+}
+
+// formValues 解析后的表单
+type formValues map[string]string
+
+// idempotent 按 Idempotency-Key 重放首次响应；同一键携带不同参数时返回错误
+func (s *Server) idempotent(w http.ResponseWriter, r *http.Request, fn func(formValues) (int, interface{})) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, apiError{Type: "invalid_request_error", Message: err.Error()})
+		return
+	}
+	form, err := parseForm(string(body))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, apiError{Type: "invalid_request_error", Message: err.Error()})
+		return
+	}
+
+	key := r.Header.Get("Idempotency-Key")
+	sum := sha256.Sum256(append([]byte(r.URL.Path+"?"), body...))
+	fingerprint := hex.EncodeToString(sum[:])
+
+	if key != "" {
+		s.idemMu.Lock()
+		defer s.idemMu.Unlock()
+
+		s.mu.Lock()
+		prev, ok := s.idempotency[key]
+		s.mu.Unlock()
+		if ok {
+			if prev.fingerprint != fingerprint {
+				writeError(w, http.StatusBadRequest, apiError{
+					Type:    "idempotency_error",
+					Message: "Keys for idempotent requests can only be used with the same parameters they were first used with.",
+				})
+				return
+			}
+			w.Header().Set("Idempotent-Replayed", "true")
+			writeRaw(w, prev.status, prev.body)
+			return
+		}
+	}
+
+	status, resp := fn(form)
+
+	s.mu.Lock()
+	data, _ := json.Marshal(resp)
+	if key != "" {
+		s.idempotency[key] = idempotentResponse{fingerprint: fingerprint, status: status, body: data}
+	}
+	s.mu.Unlock()
+	writeRaw(w, status, data)
+}
+
+// createIntent POST /v1/payment_intents
+func (s *Server) createIntent(form formValues) (int, interface{}) {
+	amount, err := strconv.ParseInt(form["amount"], 10, 64)
+	if err != nil || amount <= 0 {
+		return errorBody(http.StatusBadRequest, apiError{Type: "invalid_request_error", Code: "parameter_invalid_integer", Message: "Invalid amount"})
+	}
+	if len(form["currency"]) != 3 {
+		return errorBody(http.StatusBadRequest, apiError{Type: "invalid_request_error", Code: "parameter_missing", Message: "Missing required param: currency."})
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	id := fmt.Sprintf("pi_mock_%06d", s.seq)
+	pi := &PaymentIntent{
+		ID:            id,
+		Object:        "payment_intent",
+		Amount:        amount,
+		Currency:      form["currency"],
+		Status:        "requires_payment_method",
+		CaptureMethod: form["capture_method"],
+		PaymentMethod: form["payment_method"],
+		ClientSecret:  id + "_secret_" + randomHex(8),
+		Metadata:      form.prefixed("metadata"),
+		Created:       time.Now().Unix(),
+	}
+	if pi.CaptureMethod == "" {
+		pi.CaptureMethod = "automatic"
+	}
+	if pi.PaymentMethod != "" {
+		pi.Status = "requires_confirmation"
+	}
+	s.intents[id] = pi
+
+	if form["confirm"] == "true" {
+		return s.confirmLocked(pi)
+	}
+	return http.StatusOK, pi
+}
+
+// confirmIntent POST /v1/payment_intents/:id/confirm，模拟持卡人完成 3DS 验证
+func (s *Server) confirmIntent(id string) (int, interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pi, ok := s.intents[id]
+	if !ok {
+		return notFound("payment_intent", id)
+	}
+	if pi.Status == "requires_action" {
+		s.settleLocked(pi)
+		return http.StatusOK, pi
+	}
+	return s.confirmLocked(pi)
+}
+
+// confirmLocked 按支付方式令牌模拟确认结果
+func (s *Server) confirmLocked(pi *PaymentIntent) (int, interface{}) {
+	switch pi.PaymentMethod {
+	case "":
+		return errorBody(http.StatusBadRequest, apiError{
+			Type:    "invalid_request_error",
+			Code:    "payment_intent_unexpected_state",
+			Message: "You cannot confirm this PaymentIntent because it's missing a payment method.",
+		})
+	case TokenDeclined, TokenInsufficient:
+		e := apiError{Type: "card_error", Code: "card_declined", DeclineCode: "generic_decline", Message: "Your card was declined."}
+		if pi.PaymentMethod == TokenInsufficient {
+			e.DeclineCode = "insufficient_funds"
+			e.Message = "Your card has insufficient funds."
+		}
+		pi.Status = "requires_payment_method"
+		pi.LastPaymentError = &e
+		s.emitLocked("payment_intent.payment_failed", pi)
+		return errorBody(http.StatusPaymentRequired, e)
+	case TokenRequires3DS, TokenAuthentication:
+		pi.Status = "requires_action"
+		return http.StatusOK, pi
+	}
+
+	s.settleLocked(pi)
+	return http.StatusOK, pi
+}
+
+// settleLocked 确认成功：自动请款直接成功，手动请款进入待请款
+func (s *Server) settleLocked(pi *PaymentIntent) {
+	pi.LastPaymentError = nil
+	if pi.CaptureMethod == "manual" {
+		pi.Status = "requires_capture"
+		s.emitLocked("payment_intent.amount_capturable_updated", pi)
+		return
+	}
+	pi.Status = "succeeded"
+	pi.AmountReceived = pi.Amount
+	s.emitLocked("payment_intent.succeeded", pi)
+}
+
+// captureIntent POST /v1/payment_intents/:id/capture
+func (s *Server) captureIntent(id string, form formValues) (int, interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pi, ok := s.intents[id]
+	if !ok {
+		return notFound("payment_intent", id)
+	}
+	if pi.Status != "requires_capture" {
+		return errorBody(http.StatusBadRequest, apiError{
+			Type:    "invalid_request_error",
+			Code:    "payment_intent_unexpected_state",
+			Message: fmt.Sprintf("This PaymentIntent could not be captured because it has a status of %s.", pi.Status),
+		})
+	}
+
+	amount := pi.Amount
+	if v, ok := form["amount_to_capture"]; ok {
+		parsed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || parsed <= 0 || parsed > pi.Amount {
+			return errorBody(http.StatusBadRequest, apiError{Type: "invalid_request_error", Code: "amount_too_large", Message: "Invalid amount_to_capture"})
+		}
+		amount = parsed
+	}
+
+	pi.Status = "succeeded"
+	pi.AmountReceived = amount
+	s.emitLocked("payment_intent.succeeded", pi)
+	return http.StatusOK, pi
+}
+
+// cancelIntent POST /v1/payment_intents/:id/cancel，已收款或已取消的 PaymentIntent 不能取消
+func (s *Server) cancelIntent(id string) (int, interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pi, ok := s.intents[id]
+	if !ok {
+		return notFound("payment_intent", id)
+	}
+	if pi.Status == "succeeded" || pi.Status == "canceled" {
+		return errorBody(http.StatusBadRequest, apiError{
+			Type:    "invalid_request_error",
+			Code:    "payment_intent_unexpected_state",
+			Message: fmt.Sprintf("You cannot cancel this PaymentIntent because it has a status of %s.", pi.Status),
+		})
+	}
+
+	pi.Status = "canceled"
+	s.emitLocked("payment_intent.canceled", pi)
+	return http.StatusOK, pi
+}
+
+// createRefund POST /v1/refunds
+func (s *Server) createRefund(form formValues) (int, interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pi, ok := s.intents[form["payment_intent"]]
+	if !ok {
+		return notFound("payment_intent", form["payment_intent"])
+	}
+	if pi.Status != "succeeded" {
+		return errorBody(http.StatusBadRequest, apiError{
+			Type:    "invalid_request_error",
+			Code:    "charge_not_refundable",
+			Message: "This PaymentIntent does not have a successful charge to refund.",
+		})
+	}
+
+	remaining := pi.AmountReceived - pi.AmountRefunded
+	amount := remaining
+	if v, ok := form["amount"]; ok {
+		parsed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || parsed <= 0 {
+			return errorBody(http.StatusBadRequest, apiError{Type: "invalid_request_error", Code: "parameter_invalid_integer", Message: "Invalid amount"})
+		}
+		amount = parsed
+	}
+	if amount > remaining {
+		return errorBody(http.StatusBadRequest, apiError{
+			Type:    "invalid_request_error",
+			Code:    "amount_too_large",
+			Message: "Refund amount is greater than unrefunded amount on charge.",
+		})
+	}
+
+	s.seq++
+	refund := &Refund{
+		ID:            fmt.Sprintf("re_mock_%06d", s.seq),
+		Object:        "refund",
+		Amount:        amount,
+		PaymentIntent: pi.ID,
+		Status:        "succeeded",
+		Created:       time.Now().Unix(),
+	}
+	s.refunds[refund.ID] = refund
+	pi.AmountRefunded += amount
+
+	s.emitLocked("charge.refunded", map[string]interface{}{
+		"id":              "ch_" + strings.TrimPrefix(pi.ID, "pi_"),
+		"object":          "charge",
+		"payment_intent":  pi.ID,
+		"amount":          pi.AmountReceived,
+		"amount_refunded": pi.AmountRefunded,
+		"refunded":        pi.AmountRefunded == pi.AmountReceived,
+	})
+	return http.StatusOK, refund
+}
+
+// getIntent GET /v1/payment_intents/:id
+func (s *Server) getIntent(w http.ResponseWriter, id string) {
+	s.mu.Lock()
+	pi, ok := s.intents[id]
+	var data []byte
+	if ok {
+		data, _ = json.Marshal(pi)
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		status, body := notFound("payment_intent", id)
+		data, _ = json.Marshal(body)
+		writeRaw(w, status, data)
+		return
+	}
+	writeRaw(w, http.StatusOK, data)
+}
+
+// emitLocked 向配置的回调地址异步投递已签名事件
+func (s *Server) emitLocked(eventType string, object interface{}) {
+	if s.opts.WebhookURL == "" {
+		return
+	}
+
+	s.seq++
+	payload, err := json.Marshal(map[string]interface{}{
+		"id":      fmt.Sprintf("evt_mock_%06d", s.seq),
+		"object":  "event",
+		"type":    eventType,
+		"created": time.Now().Unix(),
+		"data":    map[string]interface{}{"object": object},
+	})
+	if err != nil {
+		return
+	}
+	signature := payment.SignStripePayload(s.opts.WebhookSecret, payload, time.Now())
+
+	go func() {
+		req, err := http.NewRequest(http.MethodPost, s.opts.WebhookURL, bytes.NewReader(payload))
+		if err != nil {
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Stripe-Signature", signature)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			log.Printf("stripemock: deliver %s: %v", eventType, err)
+			return
+		}
+		resp.Body.Close()
+		log.Printf("stripemock: delivered %s (%d)", eventType, resp.StatusCode)
+	}()
+}
+
+// prefixed 提取 prefix[key]=value 形式的嵌套参数
+func (f formValues) prefixed(prefix string) map[string]string {
+	out := make(map[string]string)
+	for k, v := range f {
+		if strings.HasPrefix(k, prefix+"[") && strings.HasSuffix(k, "]") {
+			out[k[len(prefix)+1:len(k)-1]] = v
+		}
+	}
+	return out
+}
+
+// parseForm 解析表单，保留 Stripe 风格的方括号键名
+func parseForm(body string) (formValues, error) {
+	form := make(formValues)
+	if body == "" {
+		return form, nil
+	}
+	values, err := url.ParseQuery(body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range values {
+		if len(v) > 0 {
+			form[k] = v[0]
+		}
+	}
+	return form, nil
+}
+
+// notFound 资源不存在
+func notFound(kind, id string) (int, interface{}) {
+	return errorBody(http.StatusNotFound, apiError{
+		Type:    "invalid_request_error",
+		Code:    "resource_missing",
+		Message: fmt.Sprintf("No such %s: '%s'", kind, id),
+	})
+}
+
+// errorBody 构造错误响应体
+func errorBody(status int, e apiError) (int, interface{}) {
+	return status, map[string]apiError{"error": e}
+}
+
+// writeError 直接写出错误响应
+func writeError(w http.ResponseWriter, status int, e apiError) {
+	_, body := errorBody(status, e)
+	data, _ := json.Marshal(body)
+	writeRaw(w, status, data)
+}
+
+// writeRaw 写出 JSON 响应
+func writeRaw(w http.ResponseWriter, status int, data []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// randomHex 随机十六进制串
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
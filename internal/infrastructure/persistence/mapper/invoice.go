@@ -0,0 +1,100 @@
+package mapper
+
+import (
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/invoice"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/persistence/model"
+)
+
+// InvoiceToModel 转换发票到模型
+func InvoiceToModel(inv *invoice.Invoice) *model.Invoice {
+	var number *string
+	if inv.Number != "" {
+		n := inv.Number
+		number = &n
+	}
+
+	lines := make([]model.InvoiceLine, len(inv.Lines))
+	for i, line := range inv.Lines {
+		lines[i] = model.InvoiceLine{
+			ID:          line.ID,
+			InvoiceID:   inv.ID,
+			OrderItemID: line.OrderItemID,
+			Description: line.Description,
+			Quantity:    line.Quantity,
+			UnitPrice:   line.UnitPrice.Amount,
+			TaxRate:     line.TaxRate,
+			TaxAmount:   line.TaxAmount.Amount,
+			Total:       line.Total.Amount,
+			Currency:    line.Total.Currency,
+		}
+	}
+
+	return &model.Invoice{
+		ID:               inv.ID,
+		OrderID:          inv.OrderID,
+		UserID:           inv.UserID,
+		Type:             string(inv.Type),
+		InvoiceNumber:    number,
+		RelatedInvoiceID: inv.RelatedInvoiceID,
+		Reason:           inv.Reason,
+		Note:             inv.Note,
+		Subtotal:         inv.Subtotal.Amount,
+		TaxTotal:         inv.TaxTotal.Amount,
+		Amount:           inv.Total.Amount,
+		Currency:         inv.Total.Currency,
+		Status:           string(inv.Status),
+		IssuedAt:         inv.IssuedAt,
+		DueAt:            inv.DueAt,
+		PaidAt:           inv.PaidAt,
+		VoidedAt:         inv.VoidedAt,
+		CreatedAt:        inv.CreatedAt,
+		UpdatedAt:        inv.UpdatedAt,
+		Lines:            lines,
+	}
+}
+
+// InvoiceToDomain 转换模型到发票
+func InvoiceToDomain(m *model.Invoice) *invoice.Invoice {
+	number := ""
+	if m.InvoiceNumber != nil {
+		number = *m.InvoiceNumber
+	}
+
+	lines := make([]*invoice.Line, len(m.Lines))
+	for i, line := range m.Lines {
+		lines[i] = &invoice.Line{
+			ID:          line.ID,
+			InvoiceID:   line.InvoiceID,
+			OrderItemID: line.OrderItemID,
+			Description: line.Description,
+			Quantity:    line.Quantity,
+			UnitPrice:   order.NewMoney(line.UnitPrice, line.Currency),
+			TaxRate:     line.TaxRate,
+			TaxAmount:   order.NewMoney(line.TaxAmount, line.Currency),
+			Total:       order.NewMoney(line.Total, line.Currency),
+		}
+	}
+
+	return &invoice.Invoice{
+		ID:               m.ID,
+		OrderID:          m.OrderID,
+		UserID:           m.UserID,
+		Type:             invoice.Type(m.Type),
+		Number:           number,
+		Status:           invoice.Status(m.Status),
+		RelatedInvoiceID: m.RelatedInvoiceID,
+		Reason:           m.Reason,
+		Note:             m.Note,
+		Lines:            lines,
+		Subtotal:         order.NewMoney(m.Subtotal, m.Currency),
+		TaxTotal:         order.NewMoney(m.TaxTotal, m.Currency),
+		Total:            order.NewMoney(m.Amount, m.Currency),
+		IssuedAt:         m.IssuedAt,
+		DueAt:            m.DueAt,
+		PaidAt:           m.PaidAt,
+		VoidedAt:         m.VoidedAt,
+		CreatedAt:        m.CreatedAt,
+		UpdatedAt:        m.UpdatedAt,
+	}
+}
@@ -1,6 +1,8 @@
 package mapper
 
 import (
+	"encoding/json"
+
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/persistence/model"
 )
@@ -8,14 +10,28 @@ import (
 // OrderToModel 转换订单到模型
 func OrderToModel(o *order.Order) *model.Order {
 	m := &model.Order{
-		ID:          o.ID,
-		UserID:      o.UserID,
-		OrderNumber: o.OrderNumber,
-		Status:      string(o.Status),
-		TotalAmount: o.TotalAmount.Amount,
-		Currency:    o.TotalAmount.Currency,
-		CreatedAt:   o.CreatedAt,
-		UpdatedAt:   o.UpdatedAt,
+		ID:              o.ID,
+		UserID:          o.UserID,
+		OrderNumber:     o.OrderNumber,
+		Status:          string(o.Status),
+		Subtotal:        o.Subtotal.Amount,
+		TaxTotal:        o.TaxTotal.Amount,
+		TotalAmount:     o.TotalAmount.Amount,
+		Currency:        o.TotalAmount.Currency,
+		VATID:           o.VATID,
+		TaxInclusive:    o.TaxInclusive,
+		ReverseCharge:   o.ReverseCharge,
+		TaxNote:         o.TaxNote,
+		AttentionReason: o.AttentionReason,
+		Version:         o.Version,
+		CreatedAt:       o.CreatedAt,
+		UpdatedAt:       o.UpdatedAt,
+	}
+	if o.ShippingAddress != nil {
+		m.ShippingAddress = addressToModel(*o.ShippingAddress)
+	}
+	if o.BillingAddress != nil {
+		m.BillingAddress = addressToModel(*o.BillingAddress)
 	}
 
 	// 转换订单项
@@ -26,28 +42,89 @@ func OrderToModel(o *order.Order) *model.Order {
 			OrderID:     item.OrderID,
 			ProductID:   item.ProductID,
 			ProductName: item.ProductName,
+			Category:    item.Category,
 			Quantity:    item.Quantity,
 			UnitPrice:   item.UnitPrice.Amount,
 			Subtotal:    item.Subtotal.Amount,
+			TaxName:     item.TaxName,
+			TaxRate:     item.TaxRate,
+			TaxAmount:   item.TaxAmount.Amount,
 			Currency:    item.UnitPrice.Currency,
-			CreatedAt:   item.CreatedAt,
+			WeightKg:    item.WeightKg,
+
+			FulfilledQuantity: item.FulfilledQuantity,
+			DeliveredQuantity: item.DeliveredQuantity,
+
+			CreatedAt: item.CreatedAt,
 		}
 	}
 	m.Items = items
 
+	// 转换调整行
+	adjustments := make([]model.OrderAdjustment, len(o.Adjustments))
+	for i, adj := range o.Adjustments {
+		adjustments[i] = model.OrderAdjustment{
+			ID:        adj.ID,
+			OrderID:   adj.OrderID,
+			Type:      string(adj.Type),
+			Label:     adj.Label,
+			SourceID:  adj.SourceID,
+			Code:      adj.Code,
+			Amount:    adj.Amount.Amount,
+			Currency:  adj.Amount.Currency,
+			CreatedAt: adj.CreatedAt,
+		}
+		if len(adj.ItemIDs) > 0 {
+			itemIDsJSON, _ := json.Marshal(adj.ItemIDs)
+			adjustments[i].ItemIDs = string(itemIDsJSON)
+		}
+	}
+	m.Adjustments = adjustments
+
+	// 转换状态变更历史
+	history := make([]model.OrderStatusHistory, len(o.History))
+	for i, h := range o.History {
+		history[i] = model.OrderStatusHistory{
+			ID:         h.ID,
+			OrderID:    o.ID,
+			FromStatus: string(h.From),
+			ToStatus:   string(h.To),
+			Actor:      h.Actor,
+			Note:       h.Note,
+			CreatedAt:  h.CreatedAt,
+		}
+	}
+	m.History = history
+
 	return m
 }
 
 // OrderToDomain 转换模型到订单
 func OrderToDomain(m *model.Order) *order.Order {
 	o := &order.Order{
-		ID:          m.ID,
-		UserID:      m.UserID,
-		OrderNumber: m.OrderNumber,
-		Status:      order.OrderStatus(m.Status),
-		TotalAmount: order.NewMoney(m.TotalAmount, m.Currency),
-		CreatedAt:   m.CreatedAt,
-		UpdatedAt:   m.UpdatedAt,
+		ID:              m.ID,
+		UserID:          m.UserID,
+		OrderNumber:     m.OrderNumber,
+		Status:          order.OrderStatus(m.Status),
+		Subtotal:        order.NewMoney(m.Subtotal, m.Currency),
+		TaxTotal:        order.NewMoney(m.TaxTotal, m.Currency),
+		TotalAmount:     order.NewMoney(m.TotalAmount, m.Currency),
+		VATID:           m.VATID,
+		TaxInclusive:    m.TaxInclusive,
+		ReverseCharge:   m.ReverseCharge,
+		TaxNote:         m.TaxNote,
+		AttentionReason: m.AttentionReason,
+		Version:         m.Version,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+	}
+	if m.ShippingAddress.Country != "" {
+		address := addressToDomain(m.ShippingAddress)
+		o.ShippingAddress = &address
+	}
+	if m.BillingAddress.Country != "" {
+		address := addressToDomain(m.BillingAddress)
+		o.BillingAddress = &address
 	}
 
 	// 转换订单项
@@ -58,17 +135,82 @@ func OrderToDomain(m *model.Order) *order.Order {
 			OrderID:     item.OrderID,
 			ProductID:   item.ProductID,
 			ProductName: item.ProductName,
+			Category:    item.Category,
 			Quantity:    item.Quantity,
 			UnitPrice:   order.NewMoney(item.UnitPrice, item.Currency),
 			Subtotal:    order.NewMoney(item.Subtotal, item.Currency),
-			CreatedAt:   item.CreatedAt,
+			TaxName:     item.TaxName,
+			TaxRate:     item.TaxRate,
+			TaxAmount:   order.NewMoney(item.TaxAmount, item.Currency),
+			WeightKg:    item.WeightKg,
+
+			FulfilledQuantity: item.FulfilledQuantity,
+			DeliveredQuantity: item.DeliveredQuantity,
+
+			CreatedAt: item.CreatedAt,
 		}
 	}
 	o.Items = items
 
+	// 转换调整行
+	adjustments := make([]*order.Adjustment, len(m.Adjustments))
+	for i, adj := range m.Adjustments {
+		adjustments[i] = &order.Adjustment{
+			ID:        adj.ID,
+			OrderID:   adj.OrderID,
+			Type:      order.AdjustmentType(adj.Type),
+			Label:     adj.Label,
+			SourceID:  adj.SourceID,
+			Code:      adj.Code,
+			Amount:    order.NewMoney(adj.Amount, adj.Currency),
+			CreatedAt: adj.CreatedAt,
+		}
+		if adj.ItemIDs != "" {
+			_ = json.Unmarshal([]byte(adj.ItemIDs), &adjustments[i].ItemIDs)
+		}
+	}
+	o.Adjustments = adjustments
+
+	// 转换状态变更历史
+	history := make([]*order.StatusChange, len(m.History))
+	for i, h := range m.History {
+		history[i] = &order.StatusChange{
+			ID:        h.ID,
+			OrderID:   h.OrderID,
+			From:      order.OrderStatus(h.FromStatus),
+			To:        order.OrderStatus(h.ToStatus),
+			Actor:     h.Actor,
+			Note:      h.Note,
+			CreatedAt: h.CreatedAt,
+		}
+	}
+	o.History = history
+
 	return o
 }
 
+// addressToModel 转换地址到内嵌列
+func addressToModel(a order.Address) model.OrderAddress {
+	return model.OrderAddress{
+		Street:     a.Street,
+		City:       a.City,
+		State:      a.State,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+	}
+}
+
+// addressToDomain 转换内嵌列到地址（历史快照不再重新校验）
+func addressToDomain(m model.OrderAddress) order.Address {
+	return order.Address{
+		Street:     m.Street,
+		City:       m.City,
+		State:      m.State,
+		PostalCode: m.PostalCode,
+		Country:    m.Country,
+	}
+}
+
 // PaymentToModel 转换支付到模型
 func PaymentToModel(p *order.Payment) *model.Payment {
 	return &model.Payment{
@@ -80,6 +222,8 @@ func PaymentToModel(p *order.Payment) *model.Payment {
 		Status:          string(p.Status),
 		TransactionID:   p.TransactionID,
 		GatewayResponse: p.GatewayResponse,
+		RefundedAmount:  p.RefundedAmount.Amount,
+		Version:         p.Version,
 		CreatedAt:       p.CreatedAt,
 		UpdatedAt:       p.UpdatedAt,
 	}
@@ -95,6 +239,77 @@ func PaymentToDomain(m *model.Payment) *order.Payment {
 		Status:          order.PaymentStatus(m.Status),
 		TransactionID:   m.TransactionID,
 		GatewayResponse: m.GatewayResponse,
+		RefundedAmount:  order.NewMoney(m.RefundedAmount, m.Currency),
+		Version:         m.Version,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+	}
+}
+
+// RefundToModel 转换退款到模型
+func RefundToModel(r *order.Refund) *model.Refund {
+	lines := make([]model.RefundLine, len(r.Lines))
+	for i, line := range r.Lines {
+		lines[i] = model.RefundLine{
+			ID:          line.ID,
+			RefundID:    r.ID,
+			OrderItemID: line.OrderItemID,
+			Quantity:    line.Quantity,
+			Amount:      line.Amount.Amount,
+		}
+	}
+
+	var key *string
+	if r.IdempotencyKey != "" {
+		key = &r.IdempotencyKey
+	}
+
+	return &model.Refund{
+		ID:              r.ID,
+		OrderID:         r.OrderID,
+		PaymentID:       r.PaymentID,
+		Amount:          r.Amount.Amount,
+		Currency:        r.Amount.Currency,
+		Reason:          r.Reason,
+		Status:          string(r.Status),
+		IdempotencyKey:  key,
+		Actor:           r.Actor,
+		GatewayResponse: r.GatewayResponse,
+		CreatedAt:       r.CreatedAt,
+		UpdatedAt:       r.UpdatedAt,
+		Lines:           lines,
+	}
+}
+
+// RefundToDomain 转换模型到退款
+func RefundToDomain(m *model.Refund) *order.Refund {
+	lines := make([]*order.RefundLine, len(m.Lines))
+	for i, line := range m.Lines {
+		lines[i] = &order.RefundLine{
+			ID:          line.ID,
+			RefundID:    line.RefundID,
+			OrderItemID: line.OrderItemID,
+			Quantity:    line.Quantity,
+			Amount:      order.NewMoney(line.Amount, m.Currency),
+		}
+	}
+
+	key := ""
+	if m.IdempotencyKey != nil {
+		key = *m.IdempotencyKey
+	}
+
+	return &order.Refund{
+		ID:              m.ID,
+		OrderID:         m.OrderID,
+		PaymentID:       m.PaymentID,
+		Amount:          order.NewMoney(m.Amount, m.Currency),
+		Reason:          m.Reason,
+		Status:          order.RefundStatus(m.Status),
+		IdempotencyKey:  key,
+		Actor:           m.Actor,
+		GatewayResponse: m.GatewayResponse,
+		Lines:           lines,
 		CreatedAt:       m.CreatedAt,
 		UpdatedAt:       m.UpdatedAt,
 	}
@@ -108,6 +323,9 @@ func ShipmentToModel(s *order.Shipment) *model.Shipment {
 		TrackingNumber: s.TrackingNumber,
 		Carrier:        s.Carrier,
 		ShippingMethod: s.ShippingMethod,
+		ShippingCost:   s.ShippingCost.Amount,
+		Currency:       s.ShippingCost.Currency,
+		LabelURL:       s.LabelURL,
 		Street:         s.Address.Street,
 		City:           s.Address.City,
 		State:          s.Address.State,
@@ -117,16 +335,54 @@ func ShipmentToModel(s *order.Shipment) *model.Shipment {
 		EstimatedDate:  s.EstimatedDate,
 		ShippedAt:      s.ShippedAt,
 		DeliveredAt:    s.DeliveredAt,
+		Lines:          shipmentLinesToModel(s),
+		Events:         shipmentEventsToModel(s),
+		Version:        s.Version,
 		CreatedAt:      s.CreatedAt,
 		UpdatedAt:      s.UpdatedAt,
 	}
 }
 
-// ShipmentToDomain 转换模型到发货
+// shipmentLinesToModel 转换发货行到模型
+func shipmentLinesToModel(s *order.Shipment) []model.ShipmentLine {
+	lines := make([]model.ShipmentLine, len(s.Lines))
+	for i, line := range s.Lines {
+		lines[i] = model.ShipmentLine{
+			ID:          line.ID,
+			ShipmentID:  s.ID,
+			OrderItemID: line.OrderItemID,
+			Quantity:    line.Quantity,
+		}
+	}
+	return lines
+}
+
+// shipmentEventsToModel 转换发货跟踪事件到模型
+func shipmentEventsToModel(s *order.Shipment) []model.ShipmentEvent {
+	events := make([]model.ShipmentEvent, len(s.Events))
+	for i, e := range s.Events {
+		events[i] = model.ShipmentEvent{
+			ID:          e.ID,
+			ShipmentID:  s.ID,
+			Status:      e.Status,
+			Location:    e.Location,
+			Description: e.Description,
+			RawPayload:  e.RawPayload,
+			OccurredAt:  e.OccurredAt,
+			CreatedAt:   e.CreatedAt,
+		}
+	}
+	return events
+}
+
+// ShipmentToDomain 转换模型到发货（地址为历史快照，不按当前邮编规则重新校验）
 func ShipmentToDomain(m *model.Shipment) (*order.Shipment, error) {
-	address, err := order.NewAddress(m.Street, m.City, m.State, m.PostalCode, m.Country)
-	if err != nil {
-		return nil, err
+	address := order.Address{
+		Street:     m.Street,
+		City:       m.City,
+		State:      m.State,
+		PostalCode: m.PostalCode,
+		Country:    m.Country,
 	}
 
 	return &order.Shipment{
@@ -135,12 +391,204 @@ func ShipmentToDomain(m *model.Shipment) (*order.Shipment, error) {
 		TrackingNumber: m.TrackingNumber,
 		Carrier:        m.Carrier,
 		ShippingMethod: m.ShippingMethod,
+		ShippingCost:   order.NewMoney(m.ShippingCost, m.Currency),
+		LabelURL:       m.LabelURL,
 		Address:        address,
+		Lines:          shipmentLinesToDomain(m.Lines),
 		Status:         order.ShipmentStatus(m.Status),
 		EstimatedDate:  m.EstimatedDate,
 		ShippedAt:      m.ShippedAt,
 		DeliveredAt:    m.DeliveredAt,
+		Events:         shipmentEventsToDomain(m.Events),
+		Version:        m.Version,
 		CreatedAt:      m.CreatedAt,
 		UpdatedAt:      m.UpdatedAt,
 	}, nil
 }
+
+// shipmentLinesToDomain 转换模型到发货行
+func shipmentLinesToDomain(models []model.ShipmentLine) []*order.ShipmentLine {
+	lines := make([]*order.ShipmentLine, len(models))
+	for i, l := range models {
+		lines[i] = &order.ShipmentLine{
+			ID:          l.ID,
+			ShipmentID:  l.ShipmentID,
+			OrderItemID: l.OrderItemID,
+			Quantity:    l.Quantity,
+		}
+	}
+	return lines
+}
+
+// shipmentEventsToDomain 转换模型到发货跟踪事件
+func shipmentEventsToDomain(models []model.ShipmentEvent) []*order.ShipmentEvent {
+	events := make([]*order.ShipmentEvent, len(models))
+	for i, e := range models {
+		events[i] = &order.ShipmentEvent{
+			ID:          e.ID,
+			ShipmentID:  e.ShipmentID,
+			Status:      e.Status,
+			Location:    e.Location,
+			Description: e.Description,
+			RawPayload:  e.RawPayload,
+			OccurredAt:  e.OccurredAt,
+			CreatedAt:   e.CreatedAt,
+		}
+	}
+	return events
+}
+
+// ReturnToModel 转换退货到模型
+func ReturnToModel(r *order.Return) *model.Return {
+	lines := make([]model.ReturnLine, len(r.Lines))
+	for i, line := range r.Lines {
+		lines[i] = model.ReturnLine{
+			ID:               line.ID,
+			ReturnID:         r.ID,
+			OrderItemID:      line.OrderItemID,
+			Quantity:         line.Quantity,
+			AcceptedQuantity: line.AcceptedQuantity,
+		}
+	}
+
+	return &model.Return{
+		ID:             r.ID,
+		OrderID:        r.OrderID,
+		UserID:         r.UserID,
+		Status:         string(r.Status),
+		Reason:         r.Reason,
+		Note:           r.Note,
+		Carrier:        r.Carrier,
+		TrackingNumber: r.TrackingNumber,
+		LabelURL:       r.LabelURL,
+		RefundID:       r.RefundID,
+		ApprovedAt:     r.ApprovedAt,
+		RejectedAt:     r.RejectedAt,
+		ReceivedAt:     r.ReceivedAt,
+		RefundedAt:     r.RefundedAt,
+		CreatedAt:      r.CreatedAt,
+		UpdatedAt:      r.UpdatedAt,
+		Lines:          lines,
+	}
+}
+
+// ReturnToDomain 转换模型到退货
+func ReturnToDomain(m *model.Return) *order.Return {
+	lines := make([]*order.ReturnLine, len(m.Lines))
+	for i, line := range m.Lines {
+		lines[i] = &order.ReturnLine{
+			ID:               line.ID,
+			ReturnID:         line.ReturnID,
+			OrderItemID:      line.OrderItemID,
+			Quantity:         line.Quantity,
+			AcceptedQuantity: line.AcceptedQuantity,
+		}
+	}
+
+	return &order.Return{
+		ID:             m.ID,
+		OrderID:        m.OrderID,
+		UserID:         m.UserID,
+		Status:         order.ReturnStatus(m.Status),
+		Reason:         m.Reason,
+		Note:           m.Note,
+		Lines:          lines,
+		Carrier:        m.Carrier,
+		TrackingNumber: m.TrackingNumber,
+		LabelURL:       m.LabelURL,
+		RefundID:       m.RefundID,
+		ApprovedAt:     m.ApprovedAt,
+		RejectedAt:     m.RejectedAt,
+		ReceivedAt:     m.ReceivedAt,
+		RefundedAt:     m.RefundedAt,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}
+}
+
+// FraudAssessmentToModel 转换欺诈筛查记录到模型
+func FraudAssessmentToModel(a *order.FraudAssessment) *model.FraudAssessment {
+	rulesJSON, _ := json.Marshal(a.Rules)
+	return &model.FraudAssessment{
+		ID:            a.ID,
+		OrderID:       a.OrderID,
+		UserID:        a.UserID,
+		ClientIP:      a.ClientIP,
+		Score:         a.Score,
+		Rules:         string(rulesJSON),
+		Outcome:       string(a.Outcome),
+		Amount:        a.Amount.Amount,
+		Currency:      a.Amount.Currency,
+		PaymentMethod: string(a.PaymentMethod),
+		PaymentToken:  a.PaymentToken,
+		ReturnURL:     a.ReturnURL,
+		Decision:      string(a.Decision),
+		DecidedBy:     a.DecidedBy,
+		DecisionNote:  a.DecisionNote,
+		DecidedAt:     a.DecidedAt,
+		CreatedAt:     a.CreatedAt,
+	}
+}
+
+// FraudAssessmentToDomain 转换模型到欺诈筛查记录
+func FraudAssessmentToDomain(m *model.FraudAssessment) *order.FraudAssessment {
+	rules := make([]string, 0)
+	if m.Rules != "" {
+		_ = json.Unmarshal([]byte(m.Rules), &rules)
+	}
+
+	return &order.FraudAssessment{
+		ID:            m.ID,
+		OrderID:       m.OrderID,
+		UserID:        m.UserID,
+		ClientIP:      m.ClientIP,
+		Score:         m.Score,
+		Rules:         rules,
+		Outcome:       order.FraudOutcome(m.Outcome),
+		Amount:        order.NewMoney(m.Amount, m.Currency),
+		PaymentMethod: order.PaymentMethod(m.PaymentMethod),
+		PaymentToken:  m.PaymentToken,
+		ReturnURL:     m.ReturnURL,
+		Decision:      order.FraudDecision(m.Decision),
+		DecidedBy:     m.DecidedBy,
+		DecisionNote:  m.DecisionNote,
+		DecidedAt:     m.DecidedAt,
+		CreatedAt:     m.CreatedAt,
+	}
+}
+
+// ExportJobToModel 转换导出任务到模型
+func ExportJobToModel(j *order.ExportJob) *model.ExportJob {
+	return &model.ExportJob{
+		ID:          j.ID,
+		RequestedBy: j.RequestedBy,
+		Format:      string(j.Format),
+		Filters:     j.Filters,
+		Status:      string(j.Status),
+		RowCount:    j.RowCount,
+		FileKey:     j.FileKey,
+		Error:       j.Error,
+		StartedAt:   j.StartedAt,
+		CompletedAt: j.CompletedAt,
+		CreatedAt:   j.CreatedAt,
+		UpdatedAt:   j.UpdatedAt,
+	}
+}
+
+// ExportJobToDomain 转换模型到导出任务
+func ExportJobToDomain(m *model.ExportJob) *order.ExportJob {
+	return &order.ExportJob{
+		ID:          m.ID,
+		RequestedBy: m.RequestedBy,
+		Format:      order.ExportFormat(m.Format),
+		Filters:     m.Filters,
+		Status:      order.ExportJobStatus(m.Status),
+		RowCount:    m.RowCount,
+		FileKey:     m.FileKey,
+		Error:       m.Error,
+		StartedAt:   m.StartedAt,
+		CompletedAt: m.CompletedAt,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
@@ -0,0 +1,95 @@
+package mapper
+
+import (
+	"encoding/json"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/promotion"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/persistence/model"
+)
+
+// PromotionToModel 转换优惠到模型
+func PromotionToModel(p *promotion.Promotion) *model.Promotion {
+	productIDsJSON, _ := json.Marshal(p.ProductIDs)
+	categoriesJSON, _ := json.Marshal(p.Categories)
+	return &model.Promotion{
+		ID:           p.ID,
+		Code:         p.Code,
+		Name:         p.Name,
+		Description:  p.Description,
+		DiscountType: string(p.DiscountType),
+		Value:        p.Value,
+		Currency:     p.Currency,
+		MinSpend:     p.MinSpend,
+		UsageLimit:   p.UsageLimit,
+		PerUserLimit: p.PerUserLimit,
+		UsedCount:    p.UsedCount,
+		ProductIDs:   string(productIDsJSON),
+		Categories:   string(categoriesJSON),
+		StartsAt:     p.StartsAt,
+		EndsAt:       p.EndsAt,
+		IsActive:     p.IsActive,
+		Version:      p.Version,
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
+	}
+}
+
+// PromotionToDomain 转换模型到优惠
+func PromotionToDomain(m *model.Promotion) *promotion.Promotion {
+	productIDs := make([]string, 0)
+	categories := make([]string, 0)
+	if m.ProductIDs != "" {
+		_ = json.Unmarshal([]byte(m.ProductIDs), &productIDs)
+	}
+	if m.Categories != "" {
+		_ = json.Unmarshal([]byte(m.Categories), &categories)
+	}
+
+	return &promotion.Promotion{
+		ID:           m.ID,
+		Code:         m.Code,
+		Name:         m.Name,
+		Description:  m.Description,
+		DiscountType: promotion.DiscountType(m.DiscountType),
+		Value:        m.Value,
+		Currency:     m.Currency,
+		MinSpend:     m.MinSpend,
+		UsageLimit:   m.UsageLimit,
+		PerUserLimit: m.PerUserLimit,
+		UsedCount:    m.UsedCount,
+		ProductIDs:   productIDs,
+		Categories:   categories,
+		StartsAt:     m.StartsAt,
+		EndsAt:       m.EndsAt,
+		IsActive:     m.IsActive,
+		Version:      m.Version,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	}
+}
+
+// RedemptionToModel 转换核销记录到模型
+func RedemptionToModel(r *promotion.Redemption) *model.PromotionRedemption {
+	return &model.PromotionRedemption{
+		ID:          r.ID,
+		PromotionID: r.PromotionID,
+		UserID:      r.UserID,
+		OrderID:     r.OrderID,
+		Amount:      r.Amount.Amount,
+		Currency:    r.Amount.Currency,
+		CreatedAt:   r.CreatedAt,
+	}
+}
+
+// RedemptionToDomain 转换模型到核销记录
+func RedemptionToDomain(m *model.PromotionRedemption) *promotion.Redemption {
+	return &promotion.Redemption{
+		ID:          m.ID,
+		PromotionID: m.PromotionID,
+		UserID:      m.UserID,
+		OrderID:     m.OrderID,
+		Amount:      order.NewMoney(m.Amount, m.Currency),
+		CreatedAt:   m.CreatedAt,
+	}
+}
@@ -17,6 +17,7 @@ func RoleToDomain(m *model.Role) *rbac.Role {
 		Code:        m.Code,
 		Description: m.Description,
 		IsActive:    m.IsActive,
+		Version:     m.Version,
 		CreatedAt:   m.CreatedAt,
 		UpdatedAt:   m.UpdatedAt,
 	}
@@ -34,6 +35,7 @@ func RoleToModel(d *rbac.Role) *model.Role {
 		Code:        d.Code,
 		Description: d.Description,
 		IsActive:    d.IsActive,
+		Version:     d.Version,
 		CreatedAt:   d.CreatedAt,
 		UpdatedAt:   d.UpdatedAt,
 	}
@@ -61,6 +63,7 @@ func PermissionToDomain(m *model.Permission) *rbac.Permission {
 		Resource:    m.Resource,
 		Action:      m.Action,
 		Description: m.Description,
+		Version:     m.Version,
 		CreatedAt:   m.CreatedAt,
 		UpdatedAt:   m.UpdatedAt,
 	}
@@ -79,6 +82,7 @@ func PermissionToModel(d *rbac.Permission) *model.Permission {
 		Resource:    d.Resource,
 		Action:      d.Action,
 		Description: d.Description,
+		Version:     d.Version,
 		CreatedAt:   d.CreatedAt,
 		UpdatedAt:   d.UpdatedAt,
 	}
@@ -111,6 +115,7 @@ func MenuToDomain(m *model.Menu) *rbac.Menu {
 		Component:   m.Component,
 		Permission:  m.Permission,
 		Description: m.Description,
+		Version:     m.Version,
 		CreatedAt:   m.CreatedAt,
 		UpdatedAt:   m.UpdatedAt,
 	}
@@ -134,6 +139,7 @@ func MenuToModel(d *rbac.Menu) *model.Menu {
 		Component:   d.Component,
 		Permission:  d.Permission,
 		Description: d.Description,
+		Version:     d.Version,
 		CreatedAt:   d.CreatedAt,
 		UpdatedAt:   d.UpdatedAt,
 	}
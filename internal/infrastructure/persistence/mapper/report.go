@@ -0,0 +1,41 @@
+package mapper
+
+import (
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/report"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/persistence/model"
+)
+
+// SalesDailySummaryToDomain 转换模型到按天销售汇总
+func SalesDailySummaryToDomain(m *model.SalesDailySummary) *report.DailySales {
+	return &report.DailySales{
+		Day:         m.Day.UTC(),
+		Currency:    m.Currency,
+		Status:      order.OrderStatus(m.Status),
+		OrderCount:  m.OrderCount,
+		GrossAmount: m.GrossAmount,
+		TaxAmount:   m.TaxAmount,
+	}
+}
+
+// RefundDailySummaryToDomain 转换模型到按天退款汇总
+func RefundDailySummaryToDomain(m *model.RefundDailySummary) *report.DailyRefunds {
+	return &report.DailyRefunds{
+		Day:            m.Day.UTC(),
+		Currency:       m.Currency,
+		RefundCount:    m.RefundCount,
+		RefundedAmount: m.RefundedAmount,
+	}
+}
+
+// ProductDailySalesToDomain 转换模型到商品销量汇总
+func ProductDailySalesToDomain(m *model.ProductDailySales) *report.ProductSales {
+	return &report.ProductSales{
+		ProductID:   m.ProductID,
+		ProductName: m.ProductName,
+		Currency:    m.Currency,
+		Quantity:    m.Quantity,
+		Revenue:     m.Revenue,
+		OrderCount:  m.OrderCount,
+	}
+}
@@ -0,0 +1,127 @@
+package mapper
+
+import (
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/subscription"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/persistence/model"
+)
+
+// PlanToModel 转换订阅计划到模型
+func PlanToModel(p *subscription.Plan) *model.SubscriptionPlan {
+	return &model.SubscriptionPlan{
+		ID:            p.ID,
+		Code:          p.Code,
+		Name:          p.Name,
+		Description:   p.Description,
+		ProductID:     p.ProductID,
+		Category:      p.Category,
+		Price:         p.Price.Amount,
+		Currency:      p.Price.Currency,
+		Interval:      string(p.Interval),
+		IntervalCount: p.IntervalCount,
+		TrialDays:     p.TrialDays,
+		IsActive:      p.IsActive,
+		Version:       p.Version,
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
+	}
+}
+
+// PlanToDomain 转换模型到订阅计划
+func PlanToDomain(m *model.SubscriptionPlan) *subscription.Plan {
+	return &subscription.Plan{
+		ID:            m.ID,
+		Code:          m.Code,
+		Name:          m.Name,
+		Description:   m.Description,
+		ProductID:     m.ProductID,
+		Category:      m.Category,
+		Price:         order.NewMoney(m.Price, m.Currency),
+		Interval:      subscription.Interval(m.Interval),
+		IntervalCount: m.IntervalCount,
+		TrialDays:     m.TrialDays,
+		IsActive:      m.IsActive,
+		Version:       m.Version,
+		CreatedAt:     m.CreatedAt,
+		UpdatedAt:     m.UpdatedAt,
+	}
+}
+
+// SubscriptionToModel 转换订阅到模型
+func SubscriptionToModel(s *subscription.Subscription) *model.Subscription {
+	return &model.Subscription{
+		ID:                 s.ID,
+		UserID:             s.UserID,
+		PlanID:             s.PlanID,
+		Status:             string(s.Status),
+		PaymentMethod:      string(s.PaymentMethod),
+		PaymentToken:       s.PaymentToken,
+		CurrentPeriodStart: s.CurrentPeriodStart,
+		CurrentPeriodEnd:   s.CurrentPeriodEnd,
+		TrialEnd:           s.TrialEnd,
+		CancelAtPeriodEnd:  s.CancelAtPeriodEnd,
+		CanceledAt:         s.CanceledAt,
+		EndedAt:            s.EndedAt,
+		Credit:             s.Credit.Amount,
+		CreditCurrency:     s.Credit.Currency,
+		FailedAttempts:     s.FailedAttempts,
+		NextRetryAt:        s.NextRetryAt,
+		LastOrderID:        s.LastOrderID,
+		Version:            s.Version,
+		CreatedAt:          s.CreatedAt,
+		UpdatedAt:          s.UpdatedAt,
+	}
+}
+
+// SubscriptionToDomain 转换模型到订阅
+func SubscriptionToDomain(m *model.Subscription) *subscription.Subscription {
+	return &subscription.Subscription{
+		ID:                 m.ID,
+		UserID:             m.UserID,
+		PlanID:             m.PlanID,
+		Status:             subscription.Status(m.Status),
+		PaymentMethod:      order.PaymentMethod(m.PaymentMethod),
+		PaymentToken:       m.PaymentToken,
+		CurrentPeriodStart: m.CurrentPeriodStart,
+		CurrentPeriodEnd:   m.CurrentPeriodEnd,
+		TrialEnd:           m.TrialEnd,
+		CancelAtPeriodEnd:  m.CancelAtPeriodEnd,
+		CanceledAt:         m.CanceledAt,
+		EndedAt:            m.EndedAt,
+		Credit:             order.NewMoney(m.Credit, m.CreditCurrency),
+		FailedAttempts:     m.FailedAttempts,
+		NextRetryAt:        m.NextRetryAt,
+		LastOrderID:        m.LastOrderID,
+		Version:            m.Version,
+		CreatedAt:          m.CreatedAt,
+		UpdatedAt:          m.UpdatedAt,
+	}
+}
+
+// RenewalToModel 转换续费记录到模型
+func RenewalToModel(r *subscription.Renewal) *model.SubscriptionRenewal {
+	return &model.SubscriptionRenewal{
+		ID:             r.ID,
+		SubscriptionID: r.SubscriptionID,
+		PeriodStart:    r.PeriodStart,
+		Attempt:        r.Attempt,
+		OrderID:        r.OrderID,
+		ChargedAt:      r.ChargedAt,
+		CreatedAt:      r.CreatedAt,
+		UpdatedAt:      r.UpdatedAt,
+	}
+}
+
+// RenewalToDomain 转换模型到续费记录
+func RenewalToDomain(m *model.SubscriptionRenewal) *subscription.Renewal {
+	return &subscription.Renewal{
+		ID:             m.ID,
+		SubscriptionID: m.SubscriptionID,
+		PeriodStart:    m.PeriodStart,
+		Attempt:        m.Attempt,
+		OrderID:        m.OrderID,
+		ChargedAt:      m.ChargedAt,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}
+}
@@ -13,6 +13,7 @@ func UserToModel(u *user.User) *model.User {
 		Password:  u.Password.Hash(),
 		Username:  u.Username,
 		IsActive:  u.IsActive,
+		Version:   u.Version,
 		CreatedAt: u.CreatedAt,
 		UpdatedAt: u.UpdatedAt,
 	}
@@ -31,7 +32,38 @@ func UserToDomain(m *model.User) (*user.User, error) {
 		Password:  user.NewPasswordFromHash(m.Password),
 		Username:  m.Username,
 		IsActive:  m.IsActive,
+		Version:   m.Version,
 		CreatedAt: m.CreatedAt,
 		UpdatedAt: m.UpdatedAt,
 	}, nil
 }
+
+// SavedAddressToModel 将地址簿地址转换为GORM模型
+func SavedAddressToModel(a *user.SavedAddress) *model.UserAddress {
+	return &model.UserAddress{
+		ID:                a.ID,
+		UserID:            a.UserID,
+		Label:             a.Label,
+		Address:           addressToModel(a.Address),
+		IsDefaultShipping: a.IsDefaultShipping,
+		IsDefaultBilling:  a.IsDefaultBilling,
+		Version:           a.Version,
+		CreatedAt:         a.CreatedAt,
+		UpdatedAt:         a.UpdatedAt,
+	}
+}
+
+// SavedAddressToDomain 将GORM模型转换为地址簿地址
+func SavedAddressToDomain(m *model.UserAddress) *user.SavedAddress {
+	return &user.SavedAddress{
+		ID:                m.ID,
+		UserID:            m.UserID,
+		Label:             m.Label,
+		Address:           addressToDomain(m.Address),
+		IsDefaultShipping: m.IsDefaultShipping,
+		IsDefaultBilling:  m.IsDefaultBilling,
+		Version:           m.Version,
+		CreatedAt:         m.CreatedAt,
+		UpdatedAt:         m.UpdatedAt,
+	}
+}
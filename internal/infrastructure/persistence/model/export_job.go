@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// ExportJob GORM导出任务模型
+type ExportJob struct {
+	ID          string `gorm:"primaryKey;type:varchar(26)"`
+	RequestedBy string `gorm:"index;not null;type:varchar(26)"`
+	Format      string `gorm:"not null;type:varchar(10)"`
+	Filters     string `gorm:"type:text"` // JSON
+	Status      string `gorm:"index;not null;type:varchar(20);default:'pending'"`
+	RowCount    int    `gorm:"not null;default:0"`
+	FileKey     string `gorm:"type:varchar(255)"`
+	Error       string `gorm:"type:text"`
+	StartedAt   *time.Time
+	CompletedAt *time.Time
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (ExportJob) TableName() string {
+	return "export_jobs"
+}
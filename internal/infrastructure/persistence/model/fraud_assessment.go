@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// FraudAssessment GORM欺诈筛查记录模型
+type FraudAssessment struct {
+	ID            string  `gorm:"primaryKey;type:varchar(26)"`
+	OrderID       string  `gorm:"index;not null;type:varchar(26)"`
+	UserID        string  `gorm:"index:idx_fraud_user_created;not null;type:varchar(26)"`
+	ClientIP      string  `gorm:"index:idx_fraud_ip_created;type:varchar(45)"`
+	Score         int     `gorm:"not null;default:0"`
+	Rules         string  `gorm:"type:text"` // JSON array
+	Outcome       string  `gorm:"index;not null;type:varchar(20)"`
+	Amount        float64 `gorm:"not null;type:decimal(10,2)"`
+	Currency      string  `gorm:"not null;type:varchar(3)"`
+	PaymentMethod string  `gorm:"type:varchar(50)"`
+	PaymentToken  string  `gorm:"type:varchar(255)"`
+	ReturnURL     string  `gorm:"type:varchar(500)"`
+	Decision      string  `gorm:"type:varchar(20)"`
+	DecidedBy     string  `gorm:"type:varchar(26)"`
+	DecisionNote  string  `gorm:"type:text"`
+	DecidedAt     *time.Time
+	CreatedAt     time.Time `gorm:"index:idx_fraud_user_created;index:idx_fraud_ip_created;autoCreateTime"`
+}
+
+// TableName 指定表名
+func (FraudAssessment) TableName() string {
+	return "fraud_assessments"
+}
@@ -2,24 +2,65 @@ package model
 
 import "time"
 
-// Invoice GORM发票模型
+// Invoice GORM发票模型（发票与红字发票共用）
 type Invoice struct {
-	ID          string    `gorm:"primaryKey;type:varchar(26)"`
-	OrderID     string    `gorm:"uniqueIndex;not null;type:varchar(26)"`
-	InvoiceNumber string  `gorm:"uniqueIndex;not null;type:varchar(50)"`
-	Amount      float64   `gorm:"not null;type:decimal(10,2)"`
-	Currency    string    `gorm:"not null;type:varchar(3);default:'USD'"`
-	Status      string    `gorm:"not null;type:varchar(20);default:'draft'"`
-	IssuedAt    *time.Time
-	DueAt       *time.Time
-	PaidAt      *time.Time
-	CreatedAt   time.Time `gorm:"autoCreateTime"`
-	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
-
-	Order Order `gorm:"foreignKey:OrderID"`
+	ID               string  `gorm:"primaryKey;type:varchar(26)"`
+	OrderID          string  `gorm:"index:idx_invoices_order;not null;type:varchar(26)"`
+	UserID           string  `gorm:"index;type:varchar(26)"`
+	Type             string  `gorm:"not null;type:varchar(20);default:'invoice'"`
+	InvoiceNumber    *string `gorm:"uniqueIndex;type:varchar(50)"`
+	RelatedInvoiceID string  `gorm:"index;type:varchar(26)"`
+	Reason           string  `gorm:"type:varchar(255)"`
+	Note             string  `gorm:"type:varchar(255)"`
+	Subtotal         float64 `gorm:"not null;type:decimal(10,2);default:0"`
+	TaxTotal         float64 `gorm:"not null;type:decimal(10,2);default:0"`
+	Amount           float64 `gorm:"not null;type:decimal(10,2)"`
+	Currency         string  `gorm:"not null;type:varchar(3);default:'USD'"`
+	Status           string  `gorm:"not null;type:varchar(20);default:'draft'"`
+	IssuedAt         *time.Time
+	DueAt            *time.Time
+	PaidAt           *time.Time
+	VoidedAt         *time.Time
+	CreatedAt        time.Time `gorm:"autoCreateTime"`
+	UpdatedAt        time.Time `gorm:"autoUpdateTime"`
+
+	Order Order         `gorm:"foreignKey:OrderID"`
+	Lines []InvoiceLine `gorm:"foreignKey:InvoiceID"`
 }
 
 // TableName 指定表名
 func (Invoice) TableName() string {
 	return "invoices"
 }
+
+// InvoiceLine GORM发票行模型
+type InvoiceLine struct {
+	ID          string  `gorm:"primaryKey;type:varchar(26)"`
+	InvoiceID   string  `gorm:"index;not null;type:varchar(26)"`
+	OrderItemID string  `gorm:"type:varchar(26)"`
+	Description string  `gorm:"type:varchar(255)"`
+	Quantity    int     `gorm:"not null"`
+	UnitPrice   float64 `gorm:"not null;type:decimal(10,2)"`
+	TaxRate     float64 `gorm:"not null;type:decimal(6,4);default:0"`
+	TaxAmount   float64 `gorm:"not null;type:decimal(10,2);default:0"`
+	Total       float64 `gorm:"not null;type:decimal(10,2)"`
+	Currency    string  `gorm:"not null;type:varchar(3);default:'USD'"`
+}
+
+// TableName 指定表名
+func (InvoiceLine) TableName() string {
+	return "invoice_lines"
+}
+
+// InvoiceSequence GORM发票编号序列模型（按序列与年份各一行）
+type InvoiceSequence struct {
+	Series    string    `gorm:"primaryKey;type:varchar(10)"`
+	Year      int       `gorm:"primaryKey"`
+	LastValue int64     `gorm:"not null;default:0"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (InvoiceSequence) TableName() string {
+	return "invoice_sequences"
+}
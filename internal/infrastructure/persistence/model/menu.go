@@ -15,6 +15,7 @@ type Menu struct {
 	Component   string    `gorm:"type:varchar(255)"` // 前端组件路径
 	Permission  string    `gorm:"type:varchar(100)"` // 关联的权限码
 	Description string    `gorm:"type:varchar(500)"`
+	Version     int       `gorm:"not null;default:1"` // 乐观锁版本号
 	CreatedAt   time.Time `gorm:"autoCreateTime"`
 	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
 
@@ -4,23 +4,47 @@ import "time"
 
 // Order GORM订单模型
 type Order struct {
-	ID          string    `gorm:"primaryKey;type:varchar(26)"`
-	UserID      string    `gorm:"index;not null;type:varchar(26)"`
-	OrderNumber string    `gorm:"uniqueIndex;not null;type:varchar(50)"`
-	Status      string    `gorm:"not null;type:varchar(20);default:'pending'"`
-	TotalAmount float64   `gorm:"not null;type:decimal(10,2);default:0"`
-	Currency    string    `gorm:"not null;type:varchar(3);default:'USD'"`
-	CreatedAt   time.Time `gorm:"autoCreateTime"`
-	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+	ID          string  `gorm:"primaryKey;type:varchar(26)"`
+	UserID      string  `gorm:"index;not null;type:varchar(26)"`
+	OrderNumber string  `gorm:"uniqueIndex;not null;type:varchar(50)"`
+	Status      string  `gorm:"not null;type:varchar(20);default:'pending'"`
+	Subtotal    float64 `gorm:"not null;type:decimal(10,2);default:0"`
+	TaxTotal    float64 `gorm:"not null;type:decimal(10,2);default:0"`
+	TotalAmount float64 `gorm:"not null;type:decimal(10,2);default:0"`
+	Currency    string  `gorm:"not null;type:varchar(3);default:'USD'"`
+
+	ShippingAddress OrderAddress `gorm:"embedded;embeddedPrefix:shipping_"`
+	BillingAddress  OrderAddress `gorm:"embedded;embeddedPrefix:billing_"`
+	VATID           string       `gorm:"column:vat_id;type:varchar(20)"`
+	TaxInclusive    bool         `gorm:"default:false"`
+	ReverseCharge   bool         `gorm:"default:false"`
+	TaxNote         string       `gorm:"type:varchar(255)"`
+	AttentionReason string       `gorm:"type:varchar(255)"` // 待管理员处理的原因
+
+	Version int `gorm:"not null;default:1"` // 乐观锁版本号，更新时以版本号为条件
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"index;autoUpdateTime"` // 报表增量刷新按更新时间查找变动
 
 	// 关联关系
-	User      User        `gorm:"foreignKey:UserID"`
-	Items     []OrderItem `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
-	Payment   *Payment    `gorm:"foreignKey:OrderID"`
-	Shipment  *Shipment   `gorm:"foreignKey:OrderID"`
+	User        User                 `gorm:"foreignKey:UserID"`
+	Items       []OrderItem          `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+	Adjustments []OrderAdjustment    `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+	History     []OrderStatusHistory `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+	Payment     *Payment             `gorm:"foreignKey:OrderID"`
+	Shipment    *Shipment            `gorm:"foreignKey:OrderID"`
 }
 
 // TableName 指定表名
 func (Order) TableName() string {
 	return "orders"
 }
+
+// OrderAddress 订单地址快照（内嵌列）
+type OrderAddress struct {
+	Street     string `gorm:"type:varchar(255)"`
+	City       string `gorm:"type:varchar(100)"`
+	State      string `gorm:"type:varchar(100)"`
+	PostalCode string `gorm:"type:varchar(20)"`
+	Country    string `gorm:"type:varchar(100)"`
+}
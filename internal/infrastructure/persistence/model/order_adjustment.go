@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// OrderAdjustment GORM订单调整行模型
+type OrderAdjustment struct {
+	ID        string    `gorm:"primaryKey;type:varchar(26)"`
+	OrderID   string    `gorm:"index;not null;type:varchar(26)"`
+	Type      string    `gorm:"not null;type:varchar(20)"`
+	Label     string    `gorm:"type:varchar(255)"`
+	SourceID  string    `gorm:"index;type:varchar(26)"`
+	Code      string    `gorm:"type:varchar(50)"`
+	Amount    float64   `gorm:"not null;type:decimal(10,2)"`
+	Currency  string    `gorm:"not null;type:varchar(3);default:'USD'"`
+	ItemIDs   string    `gorm:"type:text"` // JSON array，为空表示适用整单
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+
+	Order Order `gorm:"foreignKey:OrderID"`
+}
+
+// TableName 指定表名
+func (OrderAdjustment) TableName() string {
+	return "order_adjustments"
+}
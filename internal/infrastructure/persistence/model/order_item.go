@@ -4,15 +4,24 @@ import "time"
 
 // OrderItem GORM订单项模型
 type OrderItem struct {
-	ID          string    `gorm:"primaryKey;type:varchar(26)"`
-	OrderID     string    `gorm:"index;not null;type:varchar(26)"`
-	ProductID   string    `gorm:"not null;type:varchar(26)"`
-	ProductName string    `gorm:"not null;type:varchar(255)"`
-	Quantity    int       `gorm:"not null;default:1"`
-	UnitPrice   float64   `gorm:"not null;type:decimal(10,2)"`
-	Subtotal    float64   `gorm:"not null;type:decimal(10,2)"`
-	Currency    string    `gorm:"not null;type:varchar(3);default:'USD'"`
-	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	ID          string  `gorm:"primaryKey;type:varchar(26)"`
+	OrderID     string  `gorm:"index;not null;type:varchar(26)"`
+	ProductID   string  `gorm:"not null;type:varchar(26)"`
+	ProductName string  `gorm:"not null;type:varchar(255)"`
+	Category    string  `gorm:"type:varchar(100)"`
+	Quantity    int     `gorm:"not null;default:1"`
+	UnitPrice   float64 `gorm:"not null;type:decimal(10,2)"`
+	Subtotal    float64 `gorm:"not null;type:decimal(10,2)"`
+	TaxName     string  `gorm:"type:varchar(100)"`
+	TaxRate     float64 `gorm:"not null;type:decimal(6,4);default:0"`
+	TaxAmount   float64 `gorm:"not null;type:decimal(10,2);default:0"`
+	Currency    string  `gorm:"not null;type:varchar(3);default:'USD'"`
+	WeightKg    float64 `gorm:"not null;type:decimal(10,3);default:0"`
+
+	FulfilledQuantity int `gorm:"not null;default:0"`
+	DeliveredQuantity int `gorm:"not null;default:0"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime"`
 
 	Order Order `gorm:"foreignKey:OrderID"`
 }
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// OrderStatusHistory GORM订单状态变更历史模型
+type OrderStatusHistory struct {
+	ID         string    `gorm:"primaryKey;type:varchar(26)"`
+	OrderID    string    `gorm:"index;not null;type:varchar(26)"`
+	FromStatus string    `gorm:"type:varchar(20)"`
+	ToStatus   string    `gorm:"not null;type:varchar(20)"`
+	Actor      string    `gorm:"not null;type:varchar(50)"`
+	Note       string    `gorm:"type:text"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (OrderStatusHistory) TableName() string {
+	return "order_status_history"
+}
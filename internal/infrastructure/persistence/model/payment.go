@@ -5,14 +5,16 @@ import "time"
 // Payment GORM支付模型
 type Payment struct {
 	ID              string    `gorm:"primaryKey;type:varchar(26)"`
-	OrderID         string    `gorm:"uniqueIndex;not null;type:varchar(26)"`
+	OrderID         string    `gorm:"not null;type:varchar(26);index:idx_payments_order_created,priority:1"`
 	Amount          float64   `gorm:"not null;type:decimal(10,2)"`
 	Currency        string    `gorm:"not null;type:varchar(3);default:'USD'"`
 	Method          string    `gorm:"not null;type:varchar(20)"`
 	Status          string    `gorm:"not null;type:varchar(20);default:'pending'"`
-	TransactionID   string    `gorm:"type:varchar(255)"`
+	TransactionID   string    `gorm:"index;type:varchar(255)"`
 	GatewayResponse string    `gorm:"type:text"`
-	CreatedAt       time.Time `gorm:"autoCreateTime"`
+	RefundedAmount  float64   `gorm:"not null;type:decimal(10,2);default:0"`
+	Version         int       `gorm:"not null;default:1"` // 乐观锁版本号
+	CreatedAt       time.Time `gorm:"autoCreateTime;index:idx_payments_order_created,priority:2"`
 	UpdatedAt       time.Time `gorm:"autoUpdateTime"`
 
 	Order Order `gorm:"foreignKey:OrderID"`
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// PaymentGatewayEvent GORM支付网关回调事件模型（按网关事件ID去重）
+type PaymentGatewayEvent struct {
+	ID            string    `gorm:"primaryKey;type:varchar(255)"`
+	Provider      string    `gorm:"primaryKey;type:varchar(20)"`
+	Type          string    `gorm:"not null;type:varchar(100)"`
+	Kind          string    `gorm:"not null;type:varchar(20)"`
+	TransactionID string    `gorm:"index;type:varchar(255)"`
+	Payload       string    `gorm:"type:text"`
+	ReceivedAt    time.Time `gorm:"not null"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (PaymentGatewayEvent) TableName() string {
+	return "payment_gateway_events"
+}
@@ -10,6 +10,7 @@ type Permission struct {
 	Resource    string    `gorm:"not null;index;type:varchar(50)"`         // 如 user
 	Action      string    `gorm:"not null;index;type:varchar(50)"`         // 如 create
 	Description string    `gorm:"type:varchar(500)"`
+	Version     int       `gorm:"not null;default:1"` // 乐观锁版本号
 	CreatedAt   time.Time `gorm:"autoCreateTime"`
 	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
 
@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+// Promotion GORM优惠模型
+type Promotion struct {
+	ID           string  `gorm:"primaryKey;type:varchar(26)"`
+	Code         string  `gorm:"uniqueIndex;not null;type:varchar(50)"`
+	Name         string  `gorm:"not null;type:varchar(255)"`
+	Description  string  `gorm:"type:varchar(500)"`
+	DiscountType string  `gorm:"not null;type:varchar(20)"`
+	Value        float64 `gorm:"not null;type:decimal(10,2)"`
+	Currency     string  `gorm:"type:varchar(3)"`
+	MinSpend     float64 `gorm:"not null;type:decimal(10,2);default:0"`
+	UsageLimit   int     `gorm:"not null;default:0"`
+	PerUserLimit int     `gorm:"not null;default:0"`
+	UsedCount    int     `gorm:"not null;default:0"`
+	ProductIDs   string  `gorm:"type:text"` // JSON array
+	Categories   string  `gorm:"type:text"` // JSON array
+	StartsAt     *time.Time
+	EndsAt       *time.Time
+	IsActive     bool      `gorm:"default:true"`
+	Version      int       `gorm:"not null;default:1"` // 乐观锁版本号
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (Promotion) TableName() string {
+	return "promotions"
+}
+
+// PromotionRedemption GORM优惠核销模型
+type PromotionRedemption struct {
+	ID          string    `gorm:"primaryKey;type:varchar(26)"`
+	PromotionID string    `gorm:"index:idx_redemption_promotion_user;not null;type:varchar(26)"`
+	UserID      string    `gorm:"index:idx_redemption_promotion_user;not null;type:varchar(26)"`
+	OrderID     string    `gorm:"index;not null;type:varchar(26)"`
+	Amount      float64   `gorm:"not null;type:decimal(10,2)"`
+	Currency    string    `gorm:"not null;type:varchar(3);default:'USD'"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+
+	Promotion Promotion `gorm:"foreignKey:PromotionID"`
+}
+
+// TableName 指定表名
+func (PromotionRedemption) TableName() string {
+	return "promotion_redemptions"
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// Refund GORM退款模型
+type Refund struct {
+	ID              string    `gorm:"primaryKey;type:varchar(26)"`
+	OrderID         string    `gorm:"index;not null;type:varchar(26)"`
+	PaymentID       string    `gorm:"index;not null;type:varchar(26)"`
+	Amount          float64   `gorm:"not null;type:decimal(10,2)"`
+	Currency        string    `gorm:"not null;type:varchar(3)"`
+	Reason          string    `gorm:"type:text"`
+	Status          string    `gorm:"not null;type:varchar(20);default:'pending'"`
+	IdempotencyKey  *string   `gorm:"uniqueIndex;type:varchar(255)"`
+	Actor           string    `gorm:"not null;type:varchar(50)"`
+	GatewayResponse string    `gorm:"type:text"`
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `gorm:"index;autoUpdateTime"`
+
+	Lines []RefundLine `gorm:"foreignKey:RefundID"`
+}
+
+// TableName 指定表名
+func (Refund) TableName() string {
+	return "refunds"
+}
+
+// RefundLine GORM退款行模型
+type RefundLine struct {
+	ID          string  `gorm:"primaryKey;type:varchar(26)"`
+	RefundID    string  `gorm:"index;not null;type:varchar(26)"`
+	OrderItemID string  `gorm:"not null;type:varchar(26)"`
+	Quantity    int     `gorm:"not null"`
+	Amount      float64 `gorm:"not null;type:decimal(10,2)"`
+}
+
+// TableName 指定表名
+func (RefundLine) TableName() string {
+	return "refund_lines"
+}
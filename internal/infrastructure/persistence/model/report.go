@@ -0,0 +1,62 @@
+package model
+
+import "time"
+
+// SalesDailySummary GORM按天销售汇总模型，由报表刷新任务从 orders 重算
+type SalesDailySummary struct {
+	Day         time.Time `gorm:"primaryKey;type:date"`
+	Currency    string    `gorm:"primaryKey;type:varchar(3)"`
+	Status      string    `gorm:"primaryKey;type:varchar(20)"`
+	OrderCount  int       `gorm:"not null;default:0"`
+	GrossAmount float64   `gorm:"not null;type:decimal(14,2);default:0"`
+	TaxAmount   float64   `gorm:"not null;type:decimal(14,2);default:0"`
+	RefreshedAt time.Time `gorm:"not null"`
+}
+
+// TableName 指定表名
+func (SalesDailySummary) TableName() string {
+	return "sales_daily_summaries"
+}
+
+// RefundDailySummary GORM按天退款汇总模型，由报表刷新任务从 refunds 重算
+type RefundDailySummary struct {
+	Day            time.Time `gorm:"primaryKey;type:date"`
+	Currency       string    `gorm:"primaryKey;type:varchar(3)"`
+	RefundCount    int       `gorm:"not null;default:0"`
+	RefundedAmount float64   `gorm:"not null;type:decimal(14,2);default:0"`
+	RefreshedAt    time.Time `gorm:"not null"`
+}
+
+// TableName 指定表名
+func (RefundDailySummary) TableName() string {
+	return "refund_daily_summaries"
+}
+
+// ProductDailySales GORM按天商品销量汇总模型，由报表刷新任务从 order_items 重算
+type ProductDailySales struct {
+	Day         time.Time `gorm:"primaryKey;type:date"`
+	ProductID   string    `gorm:"primaryKey;type:varchar(26)"`
+	Currency    string    `gorm:"primaryKey;type:varchar(3)"`
+	ProductName string    `gorm:"not null;type:varchar(255)"`
+	Quantity    int       `gorm:"not null;default:0"`
+	Revenue     float64   `gorm:"not null;type:decimal(14,2);default:0"`
+	OrderCount  int       `gorm:"not null;default:0"`
+	RefreshedAt time.Time `gorm:"not null"`
+}
+
+// TableName 指定表名
+func (ProductDailySales) TableName() string {
+	return "product_daily_sales"
+}
+
+// ReportRefreshState GORM报表刷新进度模型，记录增量刷新的水位
+type ReportRefreshState struct {
+	Name           string    `gorm:"primaryKey;type:varchar(50)"`
+	RefreshedUntil time.Time `gorm:"not null"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (ReportRefreshState) TableName() string {
+	return "report_refresh_states"
+}
@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// Return GORM退货模型
+type Return struct {
+	ID             string `gorm:"primaryKey;type:varchar(26)"`
+	OrderID        string `gorm:"index;not null;type:varchar(26)"`
+	UserID         string `gorm:"index;not null;type:varchar(26)"`
+	Status         string `gorm:"not null;type:varchar(20);default:'requested'"`
+	Reason         string `gorm:"type:text"`
+	Note           string `gorm:"type:text"`
+	Carrier        string `gorm:"type:varchar(100)"`
+	TrackingNumber string `gorm:"type:varchar(100)"`
+	LabelURL       string `gorm:"type:varchar(500)"`
+	RefundID       string `gorm:"type:varchar(26)"`
+	ApprovedAt     *time.Time
+	RejectedAt     *time.Time
+	ReceivedAt     *time.Time
+	RefundedAt     *time.Time
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime"`
+
+	Lines []ReturnLine `gorm:"foreignKey:ReturnID"`
+}
+
+// TableName 指定表名
+func (Return) TableName() string {
+	return "returns"
+}
+
+// ReturnLine GORM退货行模型
+type ReturnLine struct {
+	ID               string `gorm:"primaryKey;type:varchar(26)"`
+	ReturnID         string `gorm:"index;not null;type:varchar(26)"`
+	OrderItemID      string `gorm:"not null;type:varchar(26)"`
+	Quantity         int    `gorm:"not null"`
+	AcceptedQuantity int    `gorm:"not null;default:0"`
+}
+
+// TableName 指定表名
+func (ReturnLine) TableName() string {
+	return "return_lines"
+}
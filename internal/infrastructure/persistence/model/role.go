@@ -9,6 +9,7 @@ type Role struct {
 	Code        string    `gorm:"uniqueIndex;not null;type:varchar(50)"`
 	Description string    `gorm:"type:varchar(500)"`
 	IsActive    bool      `gorm:"default:true"`
+	Version     int       `gorm:"not null;default:1"` // 乐观锁版本号
 	CreatedAt   time.Time `gorm:"autoCreateTime"`
 	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
 
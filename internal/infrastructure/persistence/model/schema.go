@@ -10,13 +10,42 @@ func AllModels() []interface{} {
 		&TwoFactor{},
 		&PersonalAccessToken{},
 		&Session{},
+		&UserAddress{},
 
 		// Order相关
 		&Order{},
 		&OrderItem{},
+		&OrderAdjustment{},
+		&OrderStatusHistory{},
 		&Payment{},
+		&PaymentGatewayEvent{},
+		&FraudAssessment{},
+		&Refund{},
+		&RefundLine{},
+		&Return{},
+		&ReturnLine{},
 		&Shipment{},
+		&ShipmentLine{},
+		&ShipmentEvent{},
+		&ExportJob{},
 		&Invoice{},
+		&InvoiceLine{},
+		&InvoiceSequence{},
+
+		// Promotion相关
+		&Promotion{},
+		&PromotionRedemption{},
+
+		// Subscription相关
+		&SubscriptionPlan{},
+		&Subscription{},
+		&SubscriptionRenewal{},
+
+		// Report相关
+		&SalesDailySummary{},
+		&RefundDailySummary{},
+		&ProductDailySales{},
+		&ReportRefreshState{},
 
 		// RBAC相关
 		&Role{},
@@ -28,8 +57,35 @@ func AllModels() []interface{} {
 	}
 }
 
+// OrderNumberSequence 订单号计数器使用的 PostgreSQL 序列
+const OrderNumberSequence = "order_number_seq"
+
+// legacyIndexes 旧版本遗留、已被替换的索引
+// AutoMigrate 不会删除索引，需在迁移前显式清理
+var legacyIndexes = []struct {
+	model interface{}
+	name  string
+}{
+	// invoices.order_id 原为唯一索引，红字发票需要同一订单多条记录
+	{&Invoice{}, "idx_invoices_order_id"},
+	// payments.order_id 原为唯一索引，同一订单需保存多次支付尝试
+	{&Payment{}, "idx_payments_order_id"},
+	// shipments.order_id 原为唯一索引，同一订单可分多次发货
+	{&Shipment{}, "idx_shipments_order_id"},
+}
+
 // AutoMigrate 自动迁移所有模型
 func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(AllModels()...)
+	migrator := db.Migrator()
+	for _, idx := range legacyIndexes {
+		if migrator.HasTable(idx.model) && migrator.HasIndex(idx.model, idx.name) {
+			if err := migrator.DropIndex(idx.model, idx.name); err != nil {
+				return err
+			}
+		}
+	}
+	if err := db.AutoMigrate(AllModels()...); err != nil {
+		return err
+	}
+	return db.Exec("CREATE SEQUENCE IF NOT EXISTS " + OrderNumberSequence).Error
 }
-
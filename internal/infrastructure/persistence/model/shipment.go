@@ -5,10 +5,13 @@ import "time"
 // Shipment GORM发货模型
 type Shipment struct {
 	ID             string     `gorm:"primaryKey;type:varchar(26)"`
-	OrderID        string     `gorm:"uniqueIndex;not null;type:varchar(26)"`
+	OrderID        string     `gorm:"not null;type:varchar(26);index:idx_shipments_order_created,priority:1"`
 	TrackingNumber string     `gorm:"type:varchar(100)"`
 	Carrier        string     `gorm:"type:varchar(100)"`
 	ShippingMethod string     `gorm:"not null;type:varchar(50)"`
+	ShippingCost   float64    `gorm:"not null;type:decimal(10,2);default:0"`
+	Currency       string     `gorm:"type:varchar(3)"`
+	LabelURL       string     `gorm:"type:varchar(500)"`
 	Street         string     `gorm:"not null;type:varchar(255)"`
 	City           string     `gorm:"not null;type:varchar(100)"`
 	State          string     `gorm:"type:varchar(100)"`
@@ -18,10 +21,13 @@ type Shipment struct {
 	EstimatedDate  *time.Time
 	ShippedAt      *time.Time
 	DeliveredAt    *time.Time
-	CreatedAt      time.Time `gorm:"autoCreateTime"`
+	Version        int       `gorm:"not null;default:1"` // 乐观锁版本号
+	CreatedAt      time.Time `gorm:"autoCreateTime;index:idx_shipments_order_created,priority:2"`
 	UpdatedAt      time.Time `gorm:"autoUpdateTime"`
 
-	Order Order `gorm:"foreignKey:OrderID"`
+	Order  Order           `gorm:"foreignKey:OrderID"`
+	Lines  []ShipmentLine  `gorm:"foreignKey:ShipmentID;constraint:OnDelete:CASCADE"`
+	Events []ShipmentEvent `gorm:"foreignKey:ShipmentID;constraint:OnDelete:CASCADE"`
 }
 
 // TableName 指定表名
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// ShipmentEvent GORM发货跟踪事件模型
+type ShipmentEvent struct {
+	ID          string    `gorm:"primaryKey;type:varchar(26)"`
+	ShipmentID  string    `gorm:"not null;type:varchar(26);index:idx_shipment_events_shipment_occurred,priority:1"`
+	Status      string    `gorm:"not null;type:varchar(50)"`
+	Location    string    `gorm:"type:varchar(255)"`
+	Description string    `gorm:"type:text"`
+	RawPayload  string    `gorm:"type:text"`
+	OccurredAt  time.Time `gorm:"not null;index:idx_shipment_events_shipment_occurred,priority:2"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName 指定表名
+func (ShipmentEvent) TableName() string {
+	return "shipment_events"
+}
@@ -0,0 +1,14 @@
+package model
+
+// ShipmentLine GORM发货行模型
+type ShipmentLine struct {
+	ID          string `gorm:"primaryKey;type:varchar(26)"`
+	ShipmentID  string `gorm:"index;not null;type:varchar(26)"`
+	OrderItemID string `gorm:"index;not null;type:varchar(26)"`
+	Quantity    int    `gorm:"not null"`
+}
+
+// TableName 指定表名
+func (ShipmentLine) TableName() string {
+	return "shipment_lines"
+}
@@ -0,0 +1,75 @@
+package model
+
+import "time"
+
+// SubscriptionPlan GORM订阅计划模型
+type SubscriptionPlan struct {
+	ID            string    `gorm:"primaryKey;type:varchar(26)"`
+	Code          string    `gorm:"uniqueIndex;not null;type:varchar(50)"`
+	Name          string    `gorm:"not null;type:varchar(255)"`
+	Description   string    `gorm:"type:varchar(500)"`
+	ProductID     string    `gorm:"not null;type:varchar(100)"`
+	Category      string    `gorm:"type:varchar(100)"`
+	Price         float64   `gorm:"not null;type:decimal(10,2)"`
+	Currency      string    `gorm:"not null;type:varchar(3);default:'USD'"`
+	Interval      string    `gorm:"not null;type:varchar(10)"`
+	IntervalCount int       `gorm:"not null;default:1"`
+	TrialDays     int       `gorm:"not null;default:0"`
+	IsActive      bool      `gorm:"default:true"`
+	Version       int       `gorm:"not null;default:1"` // 乐观锁版本号
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (SubscriptionPlan) TableName() string {
+	return "subscription_plans"
+}
+
+// Subscription GORM订阅模型，同一用户同一计划最多一个未终止的订阅
+type Subscription struct {
+	ID                 string    `gorm:"primaryKey;type:varchar(26)"`
+	UserID             string    `gorm:"index;uniqueIndex:idx_subscription_user_plan_open,where:status <> 'canceled';not null;type:varchar(26)"`
+	PlanID             string    `gorm:"index;uniqueIndex:idx_subscription_user_plan_open;not null;type:varchar(26)"`
+	Status             string    `gorm:"index;not null;type:varchar(20)"`
+	PaymentMethod      string    `gorm:"not null;type:varchar(50)"`
+	PaymentToken       string    `gorm:"type:varchar(255)"`
+	CurrentPeriodStart time.Time `gorm:"not null"`
+	CurrentPeriodEnd   time.Time `gorm:"index;not null"`
+	TrialEnd           *time.Time
+	CancelAtPeriodEnd  bool `gorm:"not null;default:false"`
+	CanceledAt         *time.Time
+	EndedAt            *time.Time
+	Credit             float64    `gorm:"not null;type:decimal(10,2);default:0"`
+	CreditCurrency     string     `gorm:"type:varchar(3)"`
+	FailedAttempts     int        `gorm:"not null;default:0"`
+	NextRetryAt        *time.Time `gorm:"index"`
+	LastOrderID        string     `gorm:"type:varchar(26)"`
+	Version            int        `gorm:"not null;default:1"` // 乐观锁版本号
+	CreatedAt          time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt          time.Time  `gorm:"autoUpdateTime"`
+
+	Plan SubscriptionPlan `gorm:"foreignKey:PlanID"`
+}
+
+// TableName 指定表名
+func (Subscription) TableName() string {
+	return "subscriptions"
+}
+
+// SubscriptionRenewal GORM续费记录模型，(订阅, 周期起点) 唯一，保证每个周期只扣款一次
+type SubscriptionRenewal struct {
+	ID             string    `gorm:"primaryKey;type:varchar(26)"`
+	SubscriptionID string    `gorm:"uniqueIndex:idx_renewal_subscription_period;not null;type:varchar(26)"`
+	PeriodStart    time.Time `gorm:"uniqueIndex:idx_renewal_subscription_period;not null"`
+	Attempt        int       `gorm:"not null;default:0"`
+	OrderID        string    `gorm:"not null;type:varchar(26)"`
+	ChargedAt      *time.Time
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (SubscriptionRenewal) TableName() string {
+	return "subscription_renewals"
+}
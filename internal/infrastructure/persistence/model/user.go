@@ -9,6 +9,7 @@ type User struct {
 	Password  string    `gorm:"not null;type:varchar(255)"`
 	Username  string    `gorm:"not null;type:varchar(100)"`
 	IsActive  bool      `gorm:"default:true"`
+	Version   int       `gorm:"not null;default:1"` // 乐观锁版本号
 	CreatedAt time.Time `gorm:"autoCreateTime"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime"`
 
@@ -17,6 +18,7 @@ type User struct {
 	PATs      []PersonalAccessToken `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
 	Sessions  []Session             `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
 	Orders    []Order               `gorm:"foreignKey:UserID"`
+	Addresses []UserAddress         `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
 }
 
 // TableName 指定表名
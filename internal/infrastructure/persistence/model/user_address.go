@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// UserAddress GORM地址簿模型
+type UserAddress struct {
+	ID                string       `gorm:"primaryKey;type:varchar(26)"`
+	UserID            string       `gorm:"index;not null;type:varchar(26)"`
+	Label             string       `gorm:"type:varchar(100)"`
+	Address           OrderAddress `gorm:"embedded"`
+	IsDefaultShipping bool         `gorm:"default:false"`
+	IsDefaultBilling  bool         `gorm:"default:false"`
+	Version           int          `gorm:"not null;default:1"` // 乐观锁版本号
+	CreatedAt         time.Time    `gorm:"autoCreateTime"`
+	UpdatedAt         time.Time    `gorm:"autoUpdateTime"`
+}
+
+// TableName 指定表名
+func (UserAddress) TableName() string {
+	return "user_addresses"
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/invoice"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/persistence"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/persistence/mapper"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/persistence/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InvoiceRepository 发票仓储实现
+type InvoiceRepository struct {
+	db *gorm.DB
+}
+
+// NewInvoiceRepository 创建发票仓储
+func NewInvoiceRepository(db *gorm.DB) invoice.Repository {
+	return &InvoiceRepository{db: db}
+}
+
+func (r *InvoiceRepository) Create(ctx context.Context, inv *invoice.Invoice) error {
+	m := mapper.InvoiceToModel(inv)
+	return persistence.GetDB(ctx, r.db).Create(m).Error
+}
+
+// Update 更新发票头（发票行开具后不可变）
+func (r *InvoiceRepository) Update(ctx context.Context, inv *invoice.Invoice) error {
+	m := mapper.InvoiceToModel(inv)
+	return persistence.GetDB(ctx, r.db).Omit(clause.Associations).Save(m).Error
+}
+
+func (r *InvoiceRepository) FindByID(ctx context.Context, id string) (*invoice.Invoice, error) {
+	var m model.Invoice
+	if err := persistence.GetDB(ctx, r.db).Preload("Lines").First(&m, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, invoice.ErrInvoiceNotFound
+		}
+		return nil, err
+	}
+	return mapper.InvoiceToDomain(&m), nil
+}
+
+func (r *InvoiceRepository) FindActiveByOrderID(ctx context.Context, orderID string) (*invoice.Invoice, error) {
+	var m model.Invoice
+	err := persistence.GetDB(ctx, r.db).Preload("Lines").
+		Where("order_id = ? AND type = ? AND status <> ?", orderID, string(invoice.TypeInvoice), string(invoice.StatusVoid)).
+		First(&m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, invoice.ErrInvoiceNotFound
+		}
+		return nil, err
+	}
+	return mapper.InvoiceToDomain(&m), nil
+}
+
+func (r *InvoiceRepository) ListByOrderID(ctx context.Context, orderID string) ([]*invoice.Invoice, error) {
+	var models []model.Invoice
+	if err := persistence.GetDB(ctx, r.db).Preload("Lines").Where("order_id = ?", orderID).Order("created_at ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	invoices := make([]*invoice.Invoice, len(models))
+	for i, m := range models {
+		invoices[i] = mapper.InvoiceToDomain(&m)
+	}
+	return invoices, nil
+}
+
+func (r *InvoiceRepository) List(ctx context.Context, filter invoice.ListFilter, offset, limit int) ([]*invoice.Invoice, int64, error) {
+	var models []model.Invoice
+	var total int64
+
+	query := persistence.GetDB(ctx, r.db).Model(&model.Invoice{})
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.OrderID != "" {
+		query = query.Where("order_id = ?", filter.OrderID)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", string(filter.Type))
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", string(filter.Status))
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("Lines").Order("created_at DESC").Offset(offset).Limit(limit).Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+
+	invoices := make([]*invoice.Invoice, len(models))
+	for i, m := range models {
+		invoices[i] = mapper.InvoiceToDomain(&m)
+	}
+
+	return invoices, total, nil
+}
+
+func (r *InvoiceRepository) SumCredited(ctx context.Context, invoiceID string) (float64, error) {
+	var sum float64
+	err := persistence.GetDB(ctx, r.db).Model(&model.Invoice{}).
+		Where("related_invoice_id = ? AND type = ? AND status <> ?", invoiceID, string(invoice.TypeCreditNote), string(invoice.StatusVoid)).
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&sum).Error
+	return sum, err
+}
+
+// InvoiceSequenceRepository 发票编号序列实现
+type InvoiceSequenceRepository struct {
+	db *gorm.DB
+}
+
+// NewInvoiceSequenceRepository 创建发票编号序列
+func NewInvoiceSequenceRepository(db *gorm.DB) invoice.NumberSequence {
+	return &InvoiceSequenceRepository{db: db}
+}
+
+// Next 以 upsert 递增序号；更新持有行锁直至事务结束，
+// 并发开票按序等待，事务回滚时序号随之回滚，编号不会出现缺口
+func (r *InvoiceSequenceRepository) Next(ctx context.Context, series string, year int) (int64, error) {
+	var next int64
+	err := persistence.GetDB(ctx, r.db).Raw(
+		`INSERT INTO invoice_sequences (series, year, last_value, updated_at)
+		VALUES (?, ?, 1, NOW())
+		ON CONFLICT (series, year)
+		DO UPDATE SET last_value = invoice_sequences.last_value + 1, updated_at = NOW()
+		RETURNING last_value`,
+		series, year,
+	).Scan(&next).Error
+	return next, err
+}
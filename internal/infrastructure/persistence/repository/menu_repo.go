@@ -27,10 +27,15 @@ func (r *MenuRepo) Create(ctx context.Context, menu *rbac.Menu) error {
 	return r.db.WithContext(ctx).Create(m).Error
 }
 
-// Update 更新菜单
+// Update 以版本号为条件更新菜单，版本号已变化时返回 rbac.ErrConcurrentModification
 func (r *MenuRepo) Update(ctx context.Context, menu *rbac.Menu) error {
 	m := mapper.MenuToModel(menu)
-	return r.db.WithContext(ctx).Model(m).Updates(m).Error
+	m.Version = menu.Version + 1
+	if err := updateVersioned(r.db.WithContext(ctx), m, menu.ID, menu.Version, rbac.ErrConcurrentModification); err != nil {
+		return err
+	}
+	menu.Version = m.Version
+	return nil
 }
 
 // Delete 删除菜单
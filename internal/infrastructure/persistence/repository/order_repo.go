@@ -3,11 +3,17 @@ package repository
 import (
 	"context"
 	"errors"
+	"math/rand"
+	"strings"
+	"time"
 
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/persistence"
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/persistence/mapper"
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/persistence/model"
+	"github.com/oklog/ulid/v2"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // OrderRepository 订单仓储实现
@@ -21,18 +27,64 @@ func NewOrderRepository(db *gorm.DB) order.OrderRepository {
 }
 
 func (r *OrderRepository) Create(ctx context.Context, o *order.Order) error {
+	assignHistoryIDs(o)
 	m := mapper.OrderToModel(o)
-	return r.db.WithContext(ctx).Create(m).Error
+	return persistence.GetDB(ctx, r.db).Create(m).Error
 }
 
+// Update 更新订单，新增的状态变更记录随订单一并写入，已移除的调整行（如被替换的运费）一并删除
+// 订单项的发货与送达数量会变化，关联记录按完整字段保存
+// 先以版本号为条件递增版本号并锁定订单行，版本号已变化时返回 order.ErrConcurrentModification，不写入任何记录
 func (r *OrderRepository) Update(ctx context.Context, o *order.Order) error {
+	assignHistoryIDs(o)
 	m := mapper.OrderToModel(o)
-	return r.db.WithContext(ctx).Save(m).Error
+	m.Version = o.Version + 1
+
+	err := persistence.GetDB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := claimVersion(tx, &model.Order{}, o.ID, o.Version, order.ErrConcurrentModification); err != nil {
+			return err
+		}
+
+		removed := tx.Where("order_id = ?", o.ID)
+		if len(o.Adjustments) > 0 {
+			ids := make([]string, len(o.Adjustments))
+			for i, adj := range o.Adjustments {
+				ids[i] = adj.ID
+			}
+			removed = removed.Where("id NOT IN ?", ids)
+		}
+		if err := removed.Delete(&model.OrderAdjustment{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Session(&gorm.Session{FullSaveAssociations: true}).Save(m).Error
+	})
+	if err != nil {
+		return err
+	}
+	o.Version = m.Version
+	return nil
 }
 
 func (r *OrderRepository) FindByID(ctx context.Context, id string) (*order.Order, error) {
 	var m model.Order
-	if err := r.db.WithContext(ctx).Preload("Items").First(&m, "id = ?", id).Error; err != nil {
+	if err := persistence.GetDB(ctx, r.db).Preload("Items").Preload("Adjustments").Preload("History", orderHistory).First(&m, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, order.ErrOrderNotFound
+		}
+		return nil, err
+	}
+	return mapper.OrderToDomain(&m), nil
+}
+
+// FindByIDForUpdate 以 SELECT ... FOR UPDATE 锁定订单，串行化同一订单上的支付尝试
+func (r *OrderRepository) FindByIDForUpdate(ctx context.Context, id string) (*order.Order, error) {
+	var m model.Order
+	err := persistence.GetDB(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Items").Preload("Adjustments").Preload("History", orderHistory).
+		First(&m, "id = ?", id).Error
+	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, order.ErrOrderNotFound
 		}
@@ -41,9 +93,30 @@ func (r *OrderRepository) FindByID(ctx context.Context, id string) (*order.Order
 	return mapper.OrderToDomain(&m), nil
 }
 
+// LockExpiredPending 以 FOR UPDATE SKIP LOCKED 锁定超时的待支付订单，多个 worker 并发执行时互不重复处理
+func (r *OrderRepository) LockExpiredPending(ctx context.Context, createdBefore time.Time, afterID string, limit int) ([]*order.Order, error) {
+	var models []model.Order
+	err := persistence.GetDB(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Preload("Items").Preload("Adjustments").Preload("History", orderHistory).
+		Where("status = ? AND created_at < ? AND id > ?", string(order.StatusPending), createdBefore, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]*order.Order, len(models))
+	for i := range models {
+		orders[i] = mapper.OrderToDomain(&models[i])
+	}
+	return orders, nil
+}
+
 func (r *OrderRepository) FindByOrderNumber(ctx context.Context, orderNumber string) (*order.Order, error) {
 	var m model.Order
-	if err := r.db.WithContext(ctx).Preload("Items").First(&m, "order_number = ?", orderNumber).Error; err != nil {
+	if err := persistence.GetDB(ctx, r.db).Preload("Items").Preload("Adjustments").Preload("History", orderHistory).First(&m, "order_number = ?", orderNumber).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, order.ErrOrderNotFound
 		}
@@ -56,11 +129,56 @@ func (r *OrderRepository) ListByUserID(ctx context.Context, userID string, offse
 	var models []model.Order
 	var total int64
 
-	if err := r.db.WithContext(ctx).Model(&model.Order{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+	if err := persistence.GetDB(ctx, r.db).Model(&model.Order{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := persistence.GetDB(ctx, r.db).Preload("Items").Preload("Adjustments").Where("user_id = ?", userID).Offset(offset).Limit(limit).Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+
+	orders := make([]*order.Order, len(models))
+	for i, m := range models {
+		orders[i] = mapper.OrderToDomain(&m)
+	}
+
+	return orders, total, nil
+}
+
+// orderSortColumns 排序字段到列的白名单映射
+var orderSortColumns = map[order.SortKey]string{
+	order.SortByCreatedAt:   "orders.created_at",
+	order.SortByUpdatedAt:   "orders.updated_at",
+	order.SortByTotalAmount: "orders.total_amount",
+	order.SortByOrderNumber: "orders.order_number",
+	order.SortByStatus:      "orders.status",
+}
+
+// Search 按条件搜索订单
+// 计数与分页共用同一过滤条件；排序末尾追加主键，保证翻页时顺序稳定
+func (r *OrderRepository) Search(ctx context.Context, criteria order.SearchCriteria) ([]*order.Order, int64, error) {
+	query := searchFilters(persistence.GetDB(ctx, r.db).Model(&model.Order{}), criteria)
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	if err := r.db.WithContext(ctx).Preload("Items").Where("user_id = ?", userID).Offset(offset).Limit(limit).Find(&models).Error; err != nil {
+	sorts := criteria.Sort
+	if len(sorts) == 0 {
+		sorts = []order.SortField{{Key: order.SortByCreatedAt, Desc: true}}
+	}
+	for _, field := range sorts {
+		column, ok := orderSortColumns[field.Key]
+		if !ok {
+			return nil, 0, order.ErrInvalidSearchCriteria
+		}
+		query = query.Order(clause.OrderByColumn{Column: clause.Column{Name: column, Raw: true}, Desc: field.Desc})
+	}
+	query = query.Order("orders.id")
+
+	var models []model.Order
+	if err := query.Preload("Items").Preload("Adjustments").Offset(criteria.Offset).Limit(criteria.Limit).Find(&models).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -72,8 +190,94 @@ func (r *OrderRepository) ListByUserID(ctx context.Context, userID string, offse
 	return orders, total, nil
 }
 
+// SearchAfter 按条件以主键键集分页搜索订单，导出时逐批读取，避免深分页与全量加载
+func (r *OrderRepository) SearchAfter(ctx context.Context, criteria order.SearchCriteria, afterID string, limit int) ([]*order.Order, error) {
+	query := searchFilters(persistence.GetDB(ctx, r.db).Model(&model.Order{}), criteria)
+	if afterID != "" {
+		query = query.Where("orders.id > ?", afterID)
+	}
+
+	var models []model.Order
+	if err := query.Preload("Items").Preload("Adjustments").Order("orders.id").Limit(limit).Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	orders := make([]*order.Order, len(models))
+	for i := range models {
+		orders[i] = mapper.OrderToDomain(&models[i])
+	}
+	return orders, nil
+}
+
+// searchFilters 追加搜索条件中的过滤项
+func searchFilters(query *gorm.DB, criteria order.SearchCriteria) *gorm.DB {
+	if len(criteria.Statuses) > 0 {
+		statuses := make([]string, len(criteria.Statuses))
+		for i, status := range criteria.Statuses {
+			statuses[i] = string(status)
+		}
+		query = query.Where("orders.status IN ?", statuses)
+	}
+	if criteria.UserID != "" {
+		query = query.Where("orders.user_id = ?", criteria.UserID)
+	}
+	if criteria.CreatedFrom != nil {
+		query = query.Where("orders.created_at >= ?", *criteria.CreatedFrom)
+	}
+	if criteria.CreatedTo != nil {
+		query = query.Where("orders.created_at < ?", *criteria.CreatedTo)
+	}
+	if criteria.MinAmount != nil {
+		query = query.Where("orders.total_amount >= ?", *criteria.MinAmount)
+	}
+	if criteria.MaxAmount != nil {
+		query = query.Where("orders.total_amount <= ?", *criteria.MaxAmount)
+	}
+	if criteria.Currency != "" {
+		query = query.Where("orders.currency = ?", strings.ToUpper(criteria.Currency))
+	}
+	if criteria.PaymentMethod != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM payments WHERE payments.order_id = orders.id AND payments.method = ?)", string(criteria.PaymentMethod))
+	}
+	if criteria.NeedsAttention {
+		query = query.Where("orders.attention_reason <> ''")
+	}
+	if criteria.OrderNumberPrefix != "" {
+		query = query.Where("orders.order_number LIKE ?", escapeLike(criteria.OrderNumberPrefix)+"%")
+	}
+	if criteria.Query != "" {
+		pattern := "%" + escapeLike(criteria.Query) + "%"
+		query = query.Where(
+			"orders.order_number ILIKE ? OR EXISTS (SELECT 1 FROM order_items WHERE order_items.order_id = orders.id AND order_items.product_name ILIKE ?)",
+			pattern, pattern,
+		)
+	}
+	return query
+}
+
+// escapeLike 转义 LIKE 通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// orderHistory 状态变更历史按时间排序
+func orderHistory(db *gorm.DB) *gorm.DB {
+	return db.Order("created_at ASC")
+}
+
+// assignHistoryIDs 为新增的状态变更记录分配ID
+func assignHistoryIDs(o *order.Order) {
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
+	for _, h := range o.History {
+		if h.ID == "" {
+			h.ID = ulid.MustNew(ulid.Timestamp(h.CreatedAt), entropy).String()
+			h.OrderID = o.ID
+		}
+	}
+}
+
 func (r *OrderRepository) Delete(ctx context.Context, id string) error {
-	return r.db.WithContext(ctx).Delete(&model.Order{}, "id = ?", id).Error
+	return persistence.GetDB(ctx, r.db).Delete(&model.Order{}, "id = ?", id).Error
 }
 
 // PaymentRepository 支付仓储实现
@@ -88,17 +292,73 @@ func NewPaymentRepository(db *gorm.DB) order.PaymentRepository {
 
 func (r *PaymentRepository) Create(ctx context.Context, payment *order.Payment) error {
 	m := mapper.PaymentToModel(payment)
-	return r.db.WithContext(ctx).Create(m).Error
+	return persistence.GetDB(ctx, r.db).Create(m).Error
 }
 
+// Update 以版本号为条件更新支付，版本号已变化时返回 order.ErrConcurrentModification
 func (r *PaymentRepository) Update(ctx context.Context, payment *order.Payment) error {
 	m := mapper.PaymentToModel(payment)
-	return r.db.WithContext(ctx).Save(m).Error
+	m.Version = payment.Version + 1
+	if err := updateVersioned(persistence.GetDB(ctx, r.db), m, payment.ID, payment.Version, order.ErrConcurrentModification); err != nil {
+		return err
+	}
+	payment.Version = m.Version
+	return nil
 }
 
 func (r *PaymentRepository) FindByID(ctx context.Context, id string) (*order.Payment, error) {
 	var m model.Payment
-	if err := r.db.WithContext(ctx).First(&m, "id = ?", id).Error; err != nil {
+	if err := persistence.GetDB(ctx, r.db).First(&m, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, order.ErrPaymentNotFound
+		}
+		return nil, err
+	}
+	return mapper.PaymentToDomain(&m), nil
+}
+
+func (r *PaymentRepository) ListByOrderIDs(ctx context.Context, orderIDs []string) ([]*order.Payment, error) {
+	if len(orderIDs) == 0 {
+		return []*order.Payment{}, nil
+	}
+
+	var models []model.Payment
+	if err := persistence.GetDB(ctx, r.db).
+		Where("order_id IN ?", orderIDs).
+		Order("created_at ASC, id ASC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	payments := make([]*order.Payment, len(models))
+	for i := range models {
+		payments[i] = mapper.PaymentToDomain(&models[i])
+	}
+	return payments, nil
+}
+
+func (r *PaymentRepository) ListByOrderID(ctx context.Context, orderID string) ([]*order.Payment, error) {
+	var models []model.Payment
+	if err := persistence.GetDB(ctx, r.db).
+		Where("order_id = ?", orderID).
+		Order("created_at ASC, id ASC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	payments := make([]*order.Payment, len(models))
+	for i := range models {
+		payments[i] = mapper.PaymentToDomain(&models[i])
+	}
+	return payments, nil
+}
+
+func (r *PaymentRepository) FindLatestByOrderID(ctx context.Context, orderID string) (*order.Payment, error) {
+	var m model.Payment
+	if err := persistence.GetDB(ctx, r.db).
+		Where("order_id = ?", orderID).
+		Order("created_at DESC, id DESC").
+		First(&m).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, order.ErrPaymentNotFound
 		}
@@ -107,9 +367,19 @@ func (r *PaymentRepository) FindByID(ctx context.Context, id string) (*order.Pay
 	return mapper.PaymentToDomain(&m), nil
 }
 
-func (r *PaymentRepository) FindByOrderID(ctx context.Context, orderID string) (*order.Payment, error) {
+func (r *PaymentRepository) FindCapturedByOrderID(ctx context.Context, orderID string) (*order.Payment, error) {
+	captured := []string{
+		string(order.PaymentStatusCompleted),
+		string(order.PaymentStatusPartiallyRefunded),
+		string(order.PaymentStatusRefunded),
+		string(order.PaymentStatusDisputed),
+	}
+
 	var m model.Payment
-	if err := r.db.WithContext(ctx).First(&m, "order_id = ?", orderID).Error; err != nil {
+	if err := persistence.GetDB(ctx, r.db).
+		Where("order_id = ? AND status IN ?", orderID, captured).
+		Order("created_at ASC, id ASC").
+		First(&m).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, order.ErrPaymentNotFound
 		}
@@ -120,7 +390,7 @@ func (r *PaymentRepository) FindByOrderID(ctx context.Context, orderID string) (
 
 func (r *PaymentRepository) FindByTransactionID(ctx context.Context, transactionID string) (*order.Payment, error) {
 	var m model.Payment
-	if err := r.db.WithContext(ctx).First(&m, "transaction_id = ?", transactionID).Error; err != nil {
+	if err := persistence.GetDB(ctx, r.db).First(&m, "transaction_id = ?", transactionID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, order.ErrPaymentNotFound
 		}
@@ -129,6 +399,129 @@ func (r *PaymentRepository) FindByTransactionID(ctx context.Context, transaction
 	return mapper.PaymentToDomain(&m), nil
 }
 
+// FindByIDForUpdate 以 SELECT ... FOR UPDATE 锁定支付，串行化同一支付上的退款
+func (r *PaymentRepository) FindByIDForUpdate(ctx context.Context, id string) (*order.Payment, error) {
+	var m model.Payment
+	err := persistence.GetDB(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&m, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, order.ErrPaymentNotFound
+		}
+		return nil, err
+	}
+	return mapper.PaymentToDomain(&m), nil
+}
+
+// RefundRepository 退款仓储实现
+type RefundRepository struct {
+	db *gorm.DB
+}
+
+// NewRefundRepository 创建退款仓储
+func NewRefundRepository(db *gorm.DB) order.RefundRepository {
+	return &RefundRepository{db: db}
+}
+
+func (r *RefundRepository) Create(ctx context.Context, refund *order.Refund) error {
+	m := mapper.RefundToModel(refund)
+	return persistence.GetDB(ctx, r.db).Create(m).Error
+}
+
+// Update 退款行创建后不再变化，只更新退款本身
+func (r *RefundRepository) Update(ctx context.Context, refund *order.Refund) error {
+	m := mapper.RefundToModel(refund)
+	return persistence.GetDB(ctx, r.db).Omit(clause.Associations).Save(m).Error
+}
+
+func (r *RefundRepository) FindByID(ctx context.Context, id string) (*order.Refund, error) {
+	return r.findOne(ctx, "id = ?", id)
+}
+
+// FindByIDForUpdate 锁定退款，串行化同一退款的并发处理
+func (r *RefundRepository) FindByIDForUpdate(ctx context.Context, id string) (*order.Refund, error) {
+	var m model.Refund
+	err := persistence.GetDB(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Lines").
+		First(&m, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, order.ErrRefundNotFound
+		}
+		return nil, err
+	}
+	return mapper.RefundToDomain(&m), nil
+}
+
+func (r *RefundRepository) FindByIdempotencyKey(ctx context.Context, key string) (*order.Refund, error) {
+	return r.findOne(ctx, "idempotency_key = ?", key)
+}
+
+func (r *RefundRepository) ListPending(ctx context.Context, updatedBefore time.Time, afterID string, limit int) ([]*order.Refund, error) {
+	var models []model.Refund
+	err := persistence.GetDB(ctx, r.db).
+		Preload("Lines").
+		Where("status = ? AND updated_at < ? AND id > ?", string(order.RefundStatusPending), updatedBefore, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	refunds := make([]*order.Refund, len(models))
+	for i := range models {
+		refunds[i] = mapper.RefundToDomain(&models[i])
+	}
+	return refunds, nil
+}
+
+func (r *RefundRepository) ListByPaymentID(ctx context.Context, paymentID string) ([]*order.Refund, error) {
+	return r.list(ctx, "payment_id = ?", paymentID)
+}
+
+func (r *RefundRepository) ListByOrderID(ctx context.Context, orderID string) ([]*order.Refund, error) {
+	return r.list(ctx, "order_id = ?", orderID)
+}
+
+func (r *RefundRepository) ListByOrderIDs(ctx context.Context, orderIDs []string) ([]*order.Refund, error) {
+	if len(orderIDs) == 0 {
+		return []*order.Refund{}, nil
+	}
+	return r.list(ctx, "order_id IN ?", orderIDs)
+}
+
+func (r *RefundRepository) findOne(ctx context.Context, query string, args ...interface{}) (*order.Refund, error) {
+	var m model.Refund
+	if err := persistence.GetDB(ctx, r.db).Preload("Lines").Where(query, args...).First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, order.ErrRefundNotFound
+		}
+		return nil, err
+	}
+	return mapper.RefundToDomain(&m), nil
+}
+
+func (r *RefundRepository) list(ctx context.Context, query string, args ...interface{}) ([]*order.Refund, error) {
+	var models []model.Refund
+	err := persistence.GetDB(ctx, r.db).
+		Preload("Lines").
+		Where(query, args...).
+		Order("created_at ASC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	refunds := make([]*order.Refund, len(models))
+	for i := range models {
+		refunds[i] = mapper.RefundToDomain(&models[i])
+	}
+	return refunds, nil
+}
+
 // ShipmentRepository 发货仓储实现
 type ShipmentRepository struct {
 	db *gorm.DB
@@ -140,18 +533,35 @@ func NewShipmentRepository(db *gorm.DB) order.ShipmentRepository {
 }
 
 func (r *ShipmentRepository) Create(ctx context.Context, shipment *order.Shipment) error {
+	assignShipmentLineIDs(shipment)
+	assignShipmentEventIDs(shipment)
 	m := mapper.ShipmentToModel(shipment)
-	return r.db.WithContext(ctx).Create(m).Error
+	return persistence.GetDB(ctx, r.db).Create(m).Error
 }
 
+// Update 更新发货，新增的跟踪事件随发货一并写入
+// 先以版本号为条件递增版本号并锁定发货行，版本号已变化时返回 order.ErrConcurrentModification，不写入任何记录
 func (r *ShipmentRepository) Update(ctx context.Context, shipment *order.Shipment) error {
+	assignShipmentEventIDs(shipment)
 	m := mapper.ShipmentToModel(shipment)
-	return r.db.WithContext(ctx).Save(m).Error
+	m.Version = shipment.Version + 1
+
+	err := persistence.GetDB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := claimVersion(tx, &model.Shipment{}, shipment.ID, shipment.Version, order.ErrConcurrentModification); err != nil {
+			return err
+		}
+		return tx.Save(m).Error
+	})
+	if err != nil {
+		return err
+	}
+	shipment.Version = m.Version
+	return nil
 }
 
 func (r *ShipmentRepository) FindByID(ctx context.Context, id string) (*order.Shipment, error) {
 	var m model.Shipment
-	if err := r.db.WithContext(ctx).First(&m, "id = ?", id).Error; err != nil {
+	if err := persistence.GetDB(ctx, r.db).Preload("Lines").Preload("Events", shipmentEvents).First(&m, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, order.ErrShipmentNotFound
 		}
@@ -160,9 +570,14 @@ func (r *ShipmentRepository) FindByID(ctx context.Context, id string) (*order.Sh
 	return mapper.ShipmentToDomain(&m)
 }
 
-func (r *ShipmentRepository) FindByOrderID(ctx context.Context, orderID string) (*order.Shipment, error) {
+// FindByIDForUpdate 以 SELECT ... FOR UPDATE 锁定发货，串行化同一发货上的状态变更
+func (r *ShipmentRepository) FindByIDForUpdate(ctx context.Context, id string) (*order.Shipment, error) {
 	var m model.Shipment
-	if err := r.db.WithContext(ctx).First(&m, "order_id = ?", orderID).Error; err != nil {
+	err := persistence.GetDB(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Lines").Preload("Events", shipmentEvents).
+		First(&m, "id = ?", id).Error
+	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, order.ErrShipmentNotFound
 		}
@@ -171,9 +586,58 @@ func (r *ShipmentRepository) FindByOrderID(ctx context.Context, orderID string)
 	return mapper.ShipmentToDomain(&m)
 }
 
+// ListByOrderID 列出订单的全部发货，按创建时间升序
+func (r *ShipmentRepository) ListByOrderID(ctx context.Context, orderID string) ([]*order.Shipment, error) {
+	var models []model.Shipment
+	err := persistence.GetDB(ctx, r.db).
+		Preload("Lines").Preload("Events", shipmentEvents).
+		Where("order_id = ?", orderID).
+		Order("created_at ASC, id ASC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	shipments := make([]*order.Shipment, 0, len(models))
+	for i := range models {
+		s, err := mapper.ShipmentToDomain(&models[i])
+		if err != nil {
+			return nil, err
+		}
+		shipments = append(shipments, s)
+	}
+	return shipments, nil
+}
+
+func (r *ShipmentRepository) ListByOrderIDs(ctx context.Context, orderIDs []string) ([]*order.Shipment, error) {
+	if len(orderIDs) == 0 {
+		return []*order.Shipment{}, nil
+	}
+
+	var models []model.Shipment
+	err := persistence.GetDB(ctx, r.db).
+		Preload("Lines").
+		Where("order_id IN ?", orderIDs).
+		Order("created_at ASC, id ASC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	shipments := make([]*order.Shipment, 0, len(models))
+	for i := range models {
+		s, err := mapper.ShipmentToDomain(&models[i])
+		if err != nil {
+			return nil, err
+		}
+		shipments = append(shipments, s)
+	}
+	return shipments, nil
+}
+
 func (r *ShipmentRepository) FindByTrackingNumber(ctx context.Context, trackingNumber string) (*order.Shipment, error) {
 	var m model.Shipment
-	if err := r.db.WithContext(ctx).First(&m, "tracking_number = ?", trackingNumber).Error; err != nil {
+	if err := persistence.GetDB(ctx, r.db).Preload("Lines").Preload("Events", shipmentEvents).First(&m, "tracking_number = ?", trackingNumber).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, order.ErrShipmentNotFound
 		}
@@ -181,3 +645,288 @@ func (r *ShipmentRepository) FindByTrackingNumber(ctx context.Context, trackingN
 	}
 	return mapper.ShipmentToDomain(&m)
 }
+
+// ListByStatus 按ID升序列出指定状态的发货，取 afterID 之后的至多 limit 条
+func (r *ShipmentRepository) ListByStatus(ctx context.Context, status order.ShipmentStatus, afterID string, limit int) ([]*order.Shipment, error) {
+	var models []model.Shipment
+	err := persistence.GetDB(ctx, r.db).
+		Preload("Lines").Preload("Events", shipmentEvents).
+		Where("status = ? AND id > ?", string(status), afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	shipments := make([]*order.Shipment, 0, len(models))
+	for i := range models {
+		s, err := mapper.ShipmentToDomain(&models[i])
+		if err != nil {
+			return nil, err
+		}
+		shipments = append(shipments, s)
+	}
+	return shipments, nil
+}
+
+// shipmentEvents 跟踪事件按发生时间排序
+func shipmentEvents(db *gorm.DB) *gorm.DB {
+	return db.Order("occurred_at ASC, id ASC")
+}
+
+// assignShipmentLineIDs 为发货行分配ID
+func assignShipmentLineIDs(s *order.Shipment) {
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
+	for _, line := range s.Lines {
+		if line.ID == "" {
+			line.ID = ulid.MustNew(ulid.Timestamp(s.CreatedAt), entropy).String()
+			line.ShipmentID = s.ID
+		}
+	}
+}
+
+// assignShipmentEventIDs 为新增的跟踪事件分配ID
+func assignShipmentEventIDs(s *order.Shipment) {
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
+	for _, e := range s.Events {
+		if e.ID == "" {
+			e.ID = ulid.MustNew(ulid.Timestamp(e.CreatedAt), entropy).String()
+			e.ShipmentID = s.ID
+		}
+	}
+}
+
+// ExportJobRepository 导出任务仓储实现
+type ExportJobRepository struct {
+	db *gorm.DB
+}
+
+// NewExportJobRepository 创建导出任务仓储
+func NewExportJobRepository(db *gorm.DB) order.ExportJobRepository {
+	return &ExportJobRepository{db: db}
+}
+
+func (r *ExportJobRepository) Create(ctx context.Context, job *order.ExportJob) error {
+	m := mapper.ExportJobToModel(job)
+	return persistence.GetDB(ctx, r.db).Create(m).Error
+}
+
+func (r *ExportJobRepository) Update(ctx context.Context, job *order.ExportJob) error {
+	m := mapper.ExportJobToModel(job)
+	return persistence.GetDB(ctx, r.db).Save(m).Error
+}
+
+func (r *ExportJobRepository) FindByID(ctx context.Context, id string) (*order.ExportJob, error) {
+	var m model.ExportJob
+	if err := persistence.GetDB(ctx, r.db).First(&m, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, order.ErrExportJobNotFound
+		}
+		return nil, err
+	}
+	return mapper.ExportJobToDomain(&m), nil
+}
+
+// LockNext 以 FOR UPDATE SKIP LOCKED 领取任务，多个 worker 副本不会重复执行同一任务
+func (r *ExportJobRepository) LockNext(ctx context.Context, staleBefore time.Time) (*order.ExportJob, error) {
+	var models []model.ExportJob
+	err := persistence.GetDB(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? OR (status = ? AND started_at < ?)",
+			string(order.ExportJobPending), string(order.ExportJobRunning), staleBefore).
+		Order("created_at ASC, id ASC").
+		Limit(1).
+		Find(&models).Error
+	if err != nil || len(models) == 0 {
+		return nil, err
+	}
+	return mapper.ExportJobToDomain(&models[0]), nil
+}
+
+// GatewayEventRepository 支付网关回调事件仓储实现
+type GatewayEventRepository struct {
+	db *gorm.DB
+}
+
+// NewGatewayEventRepository 创建支付网关回调事件仓储
+func NewGatewayEventRepository(db *gorm.DB) order.GatewayEventRepository {
+	return &GatewayEventRepository{db: db}
+}
+
+// Record 以主键冲突判断重复投递，与事件处理在同一事务中执行，处理失败时记录一并回滚以便网关重试
+func (r *GatewayEventRepository) Record(ctx context.Context, event *order.GatewayEvent) (bool, error) {
+	m := &model.PaymentGatewayEvent{
+		ID:            event.ID,
+		Provider:      event.Provider,
+		Type:          event.Type,
+		Kind:          string(event.Kind),
+		TransactionID: event.TransactionID,
+		Payload:       string(event.Payload),
+		ReceivedAt:    event.ReceivedAt,
+	}
+	result := persistence.GetDB(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(m)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ReturnRepository 退货仓储实现
+type ReturnRepository struct {
+	db *gorm.DB
+}
+
+// NewReturnRepository 创建退货仓储
+func NewReturnRepository(db *gorm.DB) order.ReturnRepository {
+	return &ReturnRepository{db: db}
+}
+
+func (r *ReturnRepository) Create(ctx context.Context, ret *order.Return) error {
+	m := mapper.ReturnToModel(ret)
+	return persistence.GetDB(ctx, r.db).Create(m).Error
+}
+
+// Update 更新退货，质检合格数量随退货行一并写入
+func (r *ReturnRepository) Update(ctx context.Context, ret *order.Return) error {
+	m := mapper.ReturnToModel(ret)
+	return persistence.GetDB(ctx, r.db).Session(&gorm.Session{FullSaveAssociations: true}).Save(m).Error
+}
+
+func (r *ReturnRepository) FindByID(ctx context.Context, id string) (*order.Return, error) {
+	var m model.Return
+	if err := persistence.GetDB(ctx, r.db).Preload("Lines").First(&m, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, order.ErrReturnNotFound
+		}
+		return nil, err
+	}
+	return mapper.ReturnToDomain(&m), nil
+}
+
+// FindByIDForUpdate 根据ID查找并锁定退货，需在事务中调用
+func (r *ReturnRepository) FindByIDForUpdate(ctx context.Context, id string) (*order.Return, error) {
+	var m model.Return
+	err := persistence.GetDB(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Lines").
+		First(&m, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, order.ErrReturnNotFound
+		}
+		return nil, err
+	}
+	return mapper.ReturnToDomain(&m), nil
+}
+
+// ListByOrderID 列出订单的全部退货，按创建时间升序
+func (r *ReturnRepository) ListByOrderID(ctx context.Context, orderID string) ([]*order.Return, error) {
+	var models []model.Return
+	err := persistence.GetDB(ctx, r.db).
+		Preload("Lines").
+		Where("order_id = ?", orderID).
+		Order("created_at ASC, id ASC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	returns := make([]*order.Return, len(models))
+	for i := range models {
+		returns[i] = mapper.ReturnToDomain(&models[i])
+	}
+	return returns, nil
+}
+
+// FraudAssessmentRepository 欺诈筛查记录仓储实现
+type FraudAssessmentRepository struct {
+	db *gorm.DB
+}
+
+// NewFraudAssessmentRepository 创建欺诈筛查记录仓储
+func NewFraudAssessmentRepository(db *gorm.DB) order.FraudAssessmentRepository {
+	return &FraudAssessmentRepository{db: db}
+}
+
+func (r *FraudAssessmentRepository) Create(ctx context.Context, a *order.FraudAssessment) error {
+	m := mapper.FraudAssessmentToModel(a)
+	return persistence.GetDB(ctx, r.db).Create(m).Error
+}
+
+func (r *FraudAssessmentRepository) Update(ctx context.Context, a *order.FraudAssessment) error {
+	m := mapper.FraudAssessmentToModel(a)
+	return persistence.GetDB(ctx, r.db).Save(m).Error
+}
+
+func (r *FraudAssessmentRepository) ListByOrderID(ctx context.Context, orderID string) ([]*order.FraudAssessment, error) {
+	var models []model.FraudAssessment
+	if err := persistence.GetDB(ctx, r.db).Where("order_id = ?", orderID).Order("created_at ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return fraudAssessmentsToDomain(models), nil
+}
+
+func (r *FraudAssessmentRepository) ListAwaitingReview(ctx context.Context, offset, limit int) ([]*order.FraudAssessment, int64, error) {
+	var models []model.FraudAssessment
+	var total int64
+
+	db := persistence.GetDB(ctx, r.db).Model(&model.FraudAssessment{}).
+		Where("outcome = ? AND (decision IS NULL OR decision = '')", string(order.FraudOutcomeReview))
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := db.Order("created_at ASC").Offset(offset).Limit(limit).Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+	return fraudAssessmentsToDomain(models), total, nil
+}
+
+func (r *FraudAssessmentRepository) CountUserOrdersSince(ctx context.Context, userID, excludeOrderID string, since time.Time) (int64, error) {
+	return r.countOrdersSince(ctx, "user_id = ?", userID, excludeOrderID, since)
+}
+
+func (r *FraudAssessmentRepository) CountIPOrdersSince(ctx context.Context, clientIP, excludeOrderID string, since time.Time) (int64, error) {
+	return r.countOrdersSince(ctx, "client_ip = ?", clientIP, excludeOrderID, since)
+}
+
+// countOrdersSince 按条件统计 since 之后经筛查的不同订单数
+func (r *FraudAssessmentRepository) countOrdersSince(ctx context.Context, condition, value, excludeOrderID string, since time.Time) (int64, error) {
+	var count int64
+	err := persistence.GetDB(ctx, r.db).Model(&model.FraudAssessment{}).
+		Where(condition, value).
+		Where("order_id <> ? AND created_at >= ?", excludeOrderID, since).
+		Distinct("order_id").
+		Count(&count).Error
+	return count, err
+}
+
+// fraudAssessmentsToDomain 批量转换欺诈筛查记录
+func fraudAssessmentsToDomain(models []model.FraudAssessment) []*order.FraudAssessment {
+	assessments := make([]*order.FraudAssessment, len(models))
+	for i := range models {
+		assessments[i] = mapper.FraudAssessmentToDomain(&models[i])
+	}
+	return assessments
+}
+
+// OrderNumberGenerator 基于 PostgreSQL 序列的订单号生成器
+type OrderNumberGenerator struct {
+	db     *gorm.DB
+	format order.OrderNumberFormat
+}
+
+// NewOrderNumberGenerator 创建订单号生成器
+func NewOrderNumberGenerator(db *gorm.DB, format order.OrderNumberFormat) order.OrderNumberGenerator {
+	return &OrderNumberGenerator{db: db, format: format}
+}
+
+// Generate 从序列取下一个计数生成订单号
+// nextval 不受事务回滚影响，多实例并发取号不会重复也不会相互等待，回滚的订单会留下编号空缺
+func (g *OrderNumberGenerator) Generate(ctx context.Context) (string, error) {
+	var seq int64
+	if err := persistence.GetDB(ctx, g.db).Raw("SELECT nextval(?)", model.OrderNumberSequence).Scan(&seq).Error; err != nil {
+		return "", err
+	}
+	return g.format.Format(time.Now(), seq), nil
+}
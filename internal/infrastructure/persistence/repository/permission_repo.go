@@ -27,10 +27,15 @@ func (r *PermissionRepo) Create(ctx context.Context, permission *rbac.Permission
 	return r.db.WithContext(ctx).Create(m).Error
 }
 
-// Update 更新权限
+// Update 以版本号为条件更新权限，版本号已变化时返回 rbac.ErrConcurrentModification
 func (r *PermissionRepo) Update(ctx context.Context, permission *rbac.Permission) error {
 	m := mapper.PermissionToModel(permission)
-	return r.db.WithContext(ctx).Model(m).Updates(m).Error
+	m.Version = permission.Version + 1
+	if err := updateVersioned(r.db.WithContext(ctx), m, permission.ID, permission.Version, rbac.ErrConcurrentModification); err != nil {
+		return err
+	}
+	permission.Version = m.Version
+	return nil
 }
 
 // Delete 删除权限
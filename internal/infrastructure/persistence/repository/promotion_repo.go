@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/promotion"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/persistence"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/persistence/mapper"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/persistence/model"
+	"gorm.io/gorm"
+)
+
+// PromotionRepository 优惠仓储实现
+type PromotionRepository struct {
+	db *gorm.DB
+}
+
+// NewPromotionRepository 创建优惠仓储
+func NewPromotionRepository(db *gorm.DB) promotion.Repository {
+	return &PromotionRepository{db: db}
+}
+
+func (r *PromotionRepository) Create(ctx context.Context, p *promotion.Promotion) error {
+	m := mapper.PromotionToModel(p)
+	return persistence.GetDB(ctx, r.db).Create(m).Error
+}
+
+// Update 以版本号为条件更新优惠（不覆盖由 IncrementUsage 维护的使用次数），
+// 版本号已变化时返回 promotion.ErrConcurrentModification
+func (r *PromotionRepository) Update(ctx context.Context, p *promotion.Promotion) error {
+	m := mapper.PromotionToModel(p)
+	m.Version = p.Version + 1
+	if err := updateVersioned(persistence.GetDB(ctx, r.db), m, p.ID, p.Version, promotion.ErrConcurrentModification, "used_count"); err != nil {
+		return err
+	}
+	p.Version = m.Version
+	return nil
+}
+
+func (r *PromotionRepository) Delete(ctx context.Context, id string) error {
+	return persistence.GetDB(ctx, r.db).Delete(&model.Promotion{}, "id = ?", id).Error
+}
+
+func (r *PromotionRepository) FindByID(ctx context.Context, id string) (*promotion.Promotion, error) {
+	var m model.Promotion
+	if err := persistence.GetDB(ctx, r.db).First(&m, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, promotion.ErrPromotionNotFound
+		}
+		return nil, err
+	}
+	return mapper.PromotionToDomain(&m), nil
+}
+
+func (r *PromotionRepository) FindByCode(ctx context.Context, code string) (*promotion.Promotion, error) {
+	var m model.Promotion
+	if err := persistence.GetDB(ctx, r.db).First(&m, "code = ?", code).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, promotion.ErrPromotionNotFound
+		}
+		return nil, err
+	}
+	return mapper.PromotionToDomain(&m), nil
+}
+
+func (r *PromotionRepository) List(ctx context.Context, offset, limit int) ([]*promotion.Promotion, int64, error) {
+	var models []model.Promotion
+	var total int64
+
+	db := persistence.GetDB(ctx, r.db)
+	if err := db.Model(&model.Promotion{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := db.Order("created_at DESC").Offset(offset).Limit(limit).Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+
+	promotions := make([]*promotion.Promotion, len(models))
+	for i, m := range models {
+		promotions[i] = mapper.PromotionToDomain(&m)
+	}
+
+	return promotions, total, nil
+}
+
+func (r *PromotionRepository) ExistsByCode(ctx context.Context, code string) (bool, error) {
+	var count int64
+	err := persistence.GetDB(ctx, r.db).Model(&model.Promotion{}).Where("code = ?", code).Count(&count).Error
+	return count > 0, err
+}
+
+// IncrementUsage 条件更新使用次数，行锁保证并发下不超过全局上限
+func (r *PromotionRepository) IncrementUsage(ctx context.Context, id string) error {
+	result := persistence.GetDB(ctx, r.db).Model(&model.Promotion{}).
+		Where("id = ? AND (usage_limit = 0 OR used_count < usage_limit)", id).
+		Update("used_count", gorm.Expr("used_count + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return promotion.ErrUsageLimitReached
+	}
+	return nil
+}
+
+// DecrementUsage 归还一次使用次数，不低于零
+func (r *PromotionRepository) DecrementUsage(ctx context.Context, id string) error {
+	return persistence.GetDB(ctx, r.db).Model(&model.Promotion{}).
+		Where("id = ? AND used_count > 0", id).
+		Update("used_count", gorm.Expr("used_count - 1")).Error
+}
+
+// RedemptionRepository 核销记录仓储实现
+type RedemptionRepository struct {
+	db *gorm.DB
+}
+
+// NewRedemptionRepository 创建核销记录仓储
+func NewRedemptionRepository(db *gorm.DB) promotion.RedemptionRepository {
+	return &RedemptionRepository{db: db}
+}
+
+func (r *RedemptionRepository) Create(ctx context.Context, redemption *promotion.Redemption) error {
+	m := mapper.RedemptionToModel(redemption)
+	return persistence.GetDB(ctx, r.db).Create(m).Error
+}
+
+func (r *RedemptionRepository) CountByUser(ctx context.Context, promotionID, userID string) (int64, error) {
+	var count int64
+	err := persistence.GetDB(ctx, r.db).Model(&model.PromotionRedemption{}).
+		Where("promotion_id = ? AND user_id = ?", promotionID, userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *RedemptionRepository) FindByOrderID(ctx context.Context, orderID string) ([]*promotion.Redemption, error) {
+	var models []model.PromotionRedemption
+	if err := persistence.GetDB(ctx, r.db).Where("order_id = ?", orderID).Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	redemptions := make([]*promotion.Redemption, len(models))
+	for i, m := range models {
+		redemptions[i] = mapper.RedemptionToDomain(&m)
+	}
+	return redemptions, nil
+}
+
+func (r *RedemptionRepository) DeleteByID(ctx context.Context, id string) error {
+	return persistence.GetDB(ctx, r.db).Delete(&model.PromotionRedemption{}, "id = ?", id).Error
+}
@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/report"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/persistence"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/persistence/mapper"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/persistence/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// salesRefreshState 销售汇总刷新进度的记录名
+	salesRefreshState = "sales"
+
+	// refreshOverlap 增量刷新向前重叠的时间，覆盖水位之前开始、之后才提交的事务
+	refreshOverlap = 5 * time.Minute
+
+	// reportDateLayout 汇总表 date 列的参数格式
+	reportDateLayout = "2006-01-02"
+)
+
+// ReportRepository 报表仓储实现
+type ReportRepository struct {
+	db *gorm.DB
+}
+
+// NewReportRepository 创建报表仓储
+func NewReportRepository(db *gorm.DB) report.Repository {
+	return &ReportRepository{db: db}
+}
+
+// Refresh 在单个事务中锁定刷新进度，找出水位之后有变动的订单与退款所在日期，
+// 删除并重算这些日期的汇总行，最后推进水位；进度行已被其他实例锁定时跳过本次刷新
+func (r *ReportRepository) Refresh(ctx context.Context, now time.Time) (int, error) {
+	refreshed := 0
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		state := model.ReportRefreshState{Name: salesRefreshState}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&state).Error; err != nil {
+			return err
+		}
+
+		var states []model.ReportRefreshState
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("name = ?", salesRefreshState).
+			Limit(1).
+			Find(&states).Error
+		if err != nil || len(states) == 0 {
+			return err
+		}
+		since := states[0].RefreshedUntil
+		if !since.IsZero() {
+			since = since.Add(-refreshOverlap)
+		}
+
+		salesDays, err := changedDays(tx, "orders", since)
+		if err != nil {
+			return err
+		}
+		refundDays, err := changedDays(tx, "refunds", since)
+		if err != nil {
+			return err
+		}
+
+		if err := refreshSales(tx, salesDays, now); err != nil {
+			return err
+		}
+		if err := refreshRefunds(tx, refundDays, now); err != nil {
+			return err
+		}
+
+		refreshed = countDistinctDays(salesDays, refundDays)
+		return tx.Model(&model.ReportRefreshState{}).
+			Where("name = ?", salesRefreshState).
+			Update("refreshed_until", now).Error
+	})
+	return refreshed, err
+}
+
+// changedDays 查找 updated_at 不早于 since 的记录所在的下单/发起日期（UTC），since 为零值时返回全部日期
+func changedDays(tx *gorm.DB, table string, since time.Time) ([]time.Time, error) {
+	query := tx.Table(table).Select("DISTINCT (created_at AT TIME ZONE 'UTC')::date AS day")
+	if !since.IsZero() {
+		query = query.Where("updated_at >= ?", since)
+	}
+
+	var days []time.Time
+	if err := query.Scan(&days).Error; err != nil {
+		return nil, err
+	}
+	for i, day := range days {
+		days[i] = day.UTC()
+	}
+	return days, nil
+}
+
+// refreshSales 重算指定日期的订单与商品销量汇总
+func refreshSales(tx *gorm.DB, days []time.Time, now time.Time) error {
+	if len(days) == 0 {
+		return nil
+	}
+	dates, from, to := dayBounds(days)
+
+	if err := tx.Where("day IN ?", dates).Delete(&model.SalesDailySummary{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("day IN ?", dates).Delete(&model.ProductDailySales{}).Error; err != nil {
+		return err
+	}
+
+	err := tx.Exec(
+		`INSERT INTO sales_daily_summaries (day, currency, status, order_count, gross_amount, tax_amount, refreshed_at)
+		SELECT (created_at AT TIME ZONE 'UTC')::date, currency, status, COUNT(*), SUM(total_amount), SUM(tax_total), ?
+		FROM orders
+		WHERE created_at >= ? AND created_at < ? AND (created_at AT TIME ZONE 'UTC')::date IN ?
+		GROUP BY 1, 2, 3`,
+		now, from, to, dates,
+	).Error
+	if err != nil {
+		return err
+	}
+
+	saleStatuses := make([]string, 0, len(report.SaleStatuses()))
+	for _, status := range report.SaleStatuses() {
+		saleStatuses = append(saleStatuses, string(status))
+	}
+	return tx.Exec(
+		`INSERT INTO product_daily_sales (day, product_id, currency, product_name, quantity, revenue, order_count, refreshed_at)
+		SELECT (o.created_at AT TIME ZONE 'UTC')::date, oi.product_id, o.currency, MAX(oi.product_name),
+			SUM(oi.quantity), SUM(oi.subtotal), COUNT(DISTINCT o.id), ?
+		FROM order_items oi
+		JOIN orders o ON o.id = oi.order_id
+		WHERE o.status IN ? AND o.created_at >= ? AND o.created_at < ? AND (o.created_at AT TIME ZONE 'UTC')::date IN ?
+		GROUP BY 1, 2, 3`,
+		now, saleStatuses, from, to, dates,
+	).Error
+}
+
+// refreshRefunds 重算指定日期的成功退款汇总
+func refreshRefunds(tx *gorm.DB, days []time.Time, now time.Time) error {
+	if len(days) == 0 {
+		return nil
+	}
+	dates, from, to := dayBounds(days)
+
+	if err := tx.Where("day IN ?", dates).Delete(&model.RefundDailySummary{}).Error; err != nil {
+		return err
+	}
+	return tx.Exec(
+		`INSERT INTO refund_daily_summaries (day, currency, refund_count, refunded_amount, refreshed_at)
+		SELECT (created_at AT TIME ZONE 'UTC')::date, currency, COUNT(*), SUM(amount), ?
+		FROM refunds
+		WHERE status = ? AND created_at >= ? AND created_at < ? AND (created_at AT TIME ZONE 'UTC')::date IN ?
+		GROUP BY 1, 2`,
+		now, string(order.RefundStatusSucceeded), from, to, dates,
+	).Error
+}
+
+// dayBounds 返回日期参数列表与覆盖全部日期的时间区间（用于命中 created_at 索引）
+func dayBounds(days []time.Time) ([]string, time.Time, time.Time) {
+	dates := make([]string, len(days))
+	from, to := days[0], days[0]
+	for i, day := range days {
+		dates[i] = day.Format(reportDateLayout)
+		if day.Before(from) {
+			from = day
+		}
+		if day.After(to) {
+			to = day
+		}
+	}
+	return dates, from, to.AddDate(0, 0, 1)
+}
+
+// countDistinctDays 统计两组日期合并去重后的天数
+func countDistinctDays(groups ...[]time.Time) int {
+	seen := make(map[time.Time]struct{})
+	for _, days := range groups {
+		for _, day := range days {
+			seen[day] = struct{}{}
+		}
+	}
+	return len(seen)
+}
+
+func (r *ReportRepository) ListDailySales(ctx context.Context, rng report.DateRange, currency string) ([]*report.DailySales, error) {
+	var models []model.SalesDailySummary
+	query := reportRangeQuery(persistence.GetDB(ctx, r.db), rng, currency)
+	if err := query.Order("day ASC, currency ASC, status ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	rows := make([]*report.DailySales, len(models))
+	for i := range models {
+		rows[i] = mapper.SalesDailySummaryToDomain(&models[i])
+	}
+	return rows, nil
+}
+
+func (r *ReportRepository) ListDailyRefunds(ctx context.Context, rng report.DateRange, currency string) ([]*report.DailyRefunds, error) {
+	var models []model.RefundDailySummary
+	query := reportRangeQuery(persistence.GetDB(ctx, r.db), rng, currency)
+	if err := query.Order("day ASC, currency ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	rows := make([]*report.DailyRefunds, len(models))
+	for i := range models {
+		rows[i] = mapper.RefundDailySummaryToDomain(&models[i])
+	}
+	return rows, nil
+}
+
+// TopProducts 合并区间内各天的商品销量；不同币种分别统计，不做汇率换算
+func (r *ReportRepository) TopProducts(ctx context.Context, rng report.DateRange, currency string, limit int) ([]*report.ProductSales, error) {
+	var models []model.ProductDailySales
+	err := reportRangeQuery(persistence.GetDB(ctx, r.db).Model(&model.ProductDailySales{}), rng, currency).
+		Select("product_id, currency, MAX(product_name) AS product_name, SUM(quantity) AS quantity, " +
+			"SUM(revenue) AS revenue, SUM(order_count) AS order_count").
+		Group("product_id, currency").
+		Order("revenue DESC, product_id ASC").
+		Limit(limit).
+		Scan(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]*report.ProductSales, len(models))
+	for i := range models {
+		rows[i] = mapper.ProductDailySalesToDomain(&models[i])
+	}
+	return rows, nil
+}
+
+// reportRangeQuery 应用汇总表的日期区间与币种过滤
+func reportRangeQuery(query *gorm.DB, rng report.DateRange, currency string) *gorm.DB {
+	query = query.Where("day >= ? AND day < ?", rng.From.Format(reportDateLayout), rng.To.Format(reportDateLayout))
+	if currency != "" {
+		query = query.Where("currency = ?", currency)
+	}
+	return query
+}
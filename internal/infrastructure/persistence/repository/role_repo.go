@@ -27,10 +27,15 @@ func (r *RoleRepo) Create(ctx context.Context, role *rbac.Role) error {
 	return r.db.WithContext(ctx).Create(m).Error
 }
 
-// Update 更新角色
+// Update 以版本号为条件更新角色，版本号已变化时返回 rbac.ErrConcurrentModification
 func (r *RoleRepo) Update(ctx context.Context, role *rbac.Role) error {
 	m := mapper.RoleToModel(role)
-	return r.db.WithContext(ctx).Model(m).Updates(m).Error
+	m.Version = role.Version + 1
+	if err := updateVersioned(r.db.WithContext(ctx), m, role.ID, role.Version, rbac.ErrConcurrentModification); err != nil {
+		return err
+	}
+	role.Version = m.Version
+	return nil
 }
 
 // Delete 删除角色
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/subscription"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/persistence"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/persistence/mapper"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/persistence/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// dueSubscription 需要续费或期末终止的订阅条件：试用或已付款的周期已结束，或催缴到了重试时间
+const dueSubscription = "((status IN ? AND current_period_end <= ?) OR (status = ? AND next_retry_at <= ?))"
+
+// PlanRepository 订阅计划仓储实现
+type PlanRepository struct {
+	db *gorm.DB
+}
+
+// NewPlanRepository 创建订阅计划仓储
+func NewPlanRepository(db *gorm.DB) subscription.PlanRepository {
+	return &PlanRepository{db: db}
+}
+
+func (r *PlanRepository) Create(ctx context.Context, p *subscription.Plan) error {
+	m := mapper.PlanToModel(p)
+	return persistence.GetDB(ctx, r.db).Create(m).Error
+}
+
+// Update 以版本号为条件更新计划，版本号已变化时返回 subscription.ErrConcurrentModification
+func (r *PlanRepository) Update(ctx context.Context, p *subscription.Plan) error {
+	m := mapper.PlanToModel(p)
+	m.Version = p.Version + 1
+	if err := updateVersioned(persistence.GetDB(ctx, r.db), m, p.ID, p.Version, subscription.ErrConcurrentModification); err != nil {
+		return err
+	}
+	p.Version = m.Version
+	return nil
+}
+
+func (r *PlanRepository) FindByID(ctx context.Context, id string) (*subscription.Plan, error) {
+	var m model.SubscriptionPlan
+	if err := persistence.GetDB(ctx, r.db).First(&m, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, subscription.ErrPlanNotFound
+		}
+		return nil, err
+	}
+	return mapper.PlanToDomain(&m), nil
+}
+
+func (r *PlanRepository) List(ctx context.Context, activeOnly bool) ([]*subscription.Plan, error) {
+	var models []model.SubscriptionPlan
+	db := persistence.GetDB(ctx, r.db)
+	if activeOnly {
+		db = db.Where("is_active = ?", true)
+	}
+	if err := db.Order("price ASC, code ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	plans := make([]*subscription.Plan, len(models))
+	for i := range models {
+		plans[i] = mapper.PlanToDomain(&models[i])
+	}
+	return plans, nil
+}
+
+func (r *PlanRepository) ExistsByCode(ctx context.Context, code string) (bool, error) {
+	var count int64
+	err := persistence.GetDB(ctx, r.db).Model(&model.SubscriptionPlan{}).Where("code = ?", code).Count(&count).Error
+	return count > 0, err
+}
+
+// SubscriptionRepository 订阅仓储实现
+type SubscriptionRepository struct {
+	db *gorm.DB
+}
+
+// NewSubscriptionRepository 创建订阅仓储
+func NewSubscriptionRepository(db *gorm.DB) subscription.Repository {
+	return &SubscriptionRepository{db: db}
+}
+
+// Create 创建订阅，(用户, 计划) 的未终止订阅唯一，并发订阅同一计划时返回 subscription.ErrAlreadySubscribed
+func (r *SubscriptionRepository) Create(ctx context.Context, s *subscription.Subscription) error {
+	m := mapper.SubscriptionToModel(s)
+	result := persistence.GetDB(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(m)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return subscription.ErrAlreadySubscribed
+	}
+	return nil
+}
+
+// Update 以版本号为条件更新订阅（不含计划），版本号已变化时返回 subscription.ErrConcurrentModification
+func (r *SubscriptionRepository) Update(ctx context.Context, s *subscription.Subscription) error {
+	m := mapper.SubscriptionToModel(s)
+	m.Version = s.Version + 1
+	if err := updateVersioned(persistence.GetDB(ctx, r.db), m, s.ID, s.Version, subscription.ErrConcurrentModification); err != nil {
+		return err
+	}
+	s.Version = m.Version
+	return nil
+}
+
+func (r *SubscriptionRepository) FindByID(ctx context.Context, id string) (*subscription.Subscription, error) {
+	return r.find(persistence.GetDB(ctx, r.db), id)
+}
+
+// FindByIDForUpdate 以 SELECT ... FOR UPDATE 锁定订阅
+func (r *SubscriptionRepository) FindByIDForUpdate(ctx context.Context, id string) (*subscription.Subscription, error) {
+	return r.find(persistence.GetDB(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}), id)
+}
+
+func (r *SubscriptionRepository) find(db *gorm.DB, id string) (*subscription.Subscription, error) {
+	var m model.Subscription
+	if err := db.First(&m, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, subscription.ErrSubscriptionNotFound
+		}
+		return nil, err
+	}
+	return mapper.SubscriptionToDomain(&m), nil
+}
+
+func (r *SubscriptionRepository) ListByUserID(ctx context.Context, userID string) ([]*subscription.Subscription, error) {
+	var models []model.Subscription
+	if err := persistence.GetDB(ctx, r.db).Where("user_id = ?", userID).Order("created_at DESC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	subscriptions := make([]*subscription.Subscription, len(models))
+	for i := range models {
+		subscriptions[i] = mapper.SubscriptionToDomain(&models[i])
+	}
+	return subscriptions, nil
+}
+
+func (r *SubscriptionRepository) ListDue(ctx context.Context, now time.Time, afterID string, limit int) ([]*subscription.Subscription, error) {
+	var models []model.Subscription
+	err := persistence.GetDB(ctx, r.db).
+		Where(dueSubscription, dueStatuses(), now, string(subscription.StatusPastDue), now).
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions := make([]*subscription.Subscription, len(models))
+	for i := range models {
+		subscriptions[i] = mapper.SubscriptionToDomain(&models[i])
+	}
+	return subscriptions, nil
+}
+
+// LockDue 以 FOR UPDATE SKIP LOCKED 锁定仍需处理的订阅，多个 worker 并发执行时互不重复扣款
+func (r *SubscriptionRepository) LockDue(ctx context.Context, id string, now time.Time) (*subscription.Subscription, error) {
+	var models []model.Subscription
+	err := persistence.GetDB(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("id = ?", id).
+		Where(dueSubscription, dueStatuses(), now, string(subscription.StatusPastDue), now).
+		Limit(1).
+		Find(&models).Error
+	if err != nil || len(models) == 0 {
+		return nil, err
+	}
+	return mapper.SubscriptionToDomain(&models[0]), nil
+}
+
+// RenewalRepository 续费记录仓储实现
+type RenewalRepository struct {
+	db *gorm.DB
+}
+
+// NewRenewalRepository 创建续费记录仓储
+func NewRenewalRepository(db *gorm.DB) subscription.RenewalRepository {
+	return &RenewalRepository{db: db}
+}
+
+func (r *RenewalRepository) Create(ctx context.Context, renewal *subscription.Renewal) error {
+	m := mapper.RenewalToModel(renewal)
+	return persistence.GetDB(ctx, r.db).Create(m).Error
+}
+
+func (r *RenewalRepository) Update(ctx context.Context, renewal *subscription.Renewal) error {
+	m := mapper.RenewalToModel(renewal)
+	return persistence.GetDB(ctx, r.db).Omit("CreatedAt").Save(m).Error
+}
+
+func (r *RenewalRepository) FindByPeriod(ctx context.Context, subscriptionID string, periodStart time.Time) (*subscription.Renewal, error) {
+	var m model.SubscriptionRenewal
+	err := persistence.GetDB(ctx, r.db).
+		Where("subscription_id = ? AND period_start = ?", subscriptionID, periodStart).
+		First(&m).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, subscription.ErrRenewalNotFound
+		}
+		return nil, err
+	}
+	return mapper.RenewalToDomain(&m), nil
+}
+
+// dueStatuses 按周期结束时间续费的订阅状态
+func dueStatuses() []string {
+	return []string{string(subscription.StatusTrialing), string(subscription.StatusActive)}
+}
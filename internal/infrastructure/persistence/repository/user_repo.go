@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"strings"
 
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/user"
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/infrastructure/persistence/mapper"
@@ -26,10 +27,15 @@ func (r *UserRepository) Create(ctx context.Context, u *user.User) error {
 	return r.db.WithContext(ctx).Create(m).Error
 }
 
-// Update 更新用户
+// Update 以版本号为条件更新用户，版本号已变化时返回 user.ErrConcurrentModification
 func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
 	m := mapper.UserToModel(u)
-	return r.db.WithContext(ctx).Save(m).Error
+	m.Version = u.Version + 1
+	if err := updateVersioned(r.db.WithContext(ctx), m, u.ID, u.Version, user.ErrConcurrentModification); err != nil {
+		return err
+	}
+	u.Version = m.Version
+	return nil
 }
 
 // Delete 删除用户
@@ -94,3 +100,90 @@ func (r *UserRepository) ExistsByEmail(ctx context.Context, email user.Email) (b
 	err := r.db.WithContext(ctx).Model(&model.User{}).Where("email = ?", email.String()).Count(&count).Error
 	return count > 0, err
 }
+
+// AddressRepository 地址簿仓储实现
+type AddressRepository struct {
+	db *gorm.DB
+}
+
+// NewAddressRepository 创建地址簿仓储
+func NewAddressRepository(db *gorm.DB) user.AddressRepository {
+	return &AddressRepository{db: db}
+}
+
+// Create 创建地址
+func (r *AddressRepository) Create(ctx context.Context, a *user.SavedAddress) error {
+	m := mapper.SavedAddressToModel(a)
+	return r.db.WithContext(ctx).Create(m).Error
+}
+
+// Update 以版本号为条件更新地址，版本号已变化时返回 user.ErrConcurrentModification
+func (r *AddressRepository) Update(ctx context.Context, a *user.SavedAddress) error {
+	m := mapper.SavedAddressToModel(a)
+	m.Version = a.Version + 1
+	if err := updateVersioned(r.db.WithContext(ctx), m, a.ID, a.Version, user.ErrConcurrentModification); err != nil {
+		return err
+	}
+	a.Version = m.Version
+	return nil
+}
+
+// Delete 删除地址
+func (r *AddressRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&model.UserAddress{}, "id = ?", id).Error
+}
+
+// FindByID 根据ID查找地址
+func (r *AddressRepository) FindByID(ctx context.Context, id string) (*user.SavedAddress, error) {
+	var m model.UserAddress
+	if err := r.db.WithContext(ctx).First(&m, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, user.ErrAddressNotFound
+		}
+		return nil, err
+	}
+	return mapper.SavedAddressToDomain(&m), nil
+}
+
+// ListByUserID 列出用户的全部地址
+func (r *AddressRepository) ListByUserID(ctx context.Context, userID string) ([]*user.SavedAddress, error) {
+	var models []model.UserAddress
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC, id ASC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]*user.SavedAddress, len(models))
+	for i := range models {
+		addresses[i] = mapper.SavedAddressToDomain(&models[i])
+	}
+	return addresses, nil
+}
+
+// ClearDefaults 清除用户其他地址上的默认标记
+func (r *AddressRepository) ClearDefaults(ctx context.Context, userID, exceptID string, shipping, billing bool) error {
+	updates := map[string]interface{}{}
+	var cleared []string
+	if shipping {
+		updates["is_default_shipping"] = false
+		cleared = append(cleared, "is_default_shipping")
+	}
+	if billing {
+		updates["is_default_billing"] = false
+		cleared = append(cleared, "is_default_billing")
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	// 仅更新实际被取消默认标记的地址，并递增其版本号，使基于旧版本的修改不会恢复默认标记
+	updates["version"] = gorm.Expr("version + 1")
+
+	return r.db.WithContext(ctx).
+		Model(&model.UserAddress{}).
+		Where("user_id = ? AND id <> ?", userID, exceptID).
+		Where("(" + strings.Join(cleared, " OR ") + ")").
+		Updates(updates).Error
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// updateVersioned 以版本号为条件整行更新聚合根（不含关联与 omit 列出的列），m 的版本号需已设为 version+1
+// 记录不存在或版本号已被其他请求递增时返回 conflict
+func updateVersioned(db *gorm.DB, m interface{}, id string, version int, conflict error, omit ...string) error {
+	result := db.Model(m).
+		Where("id = ? AND version = ?", id, version).
+		Select("*").
+		Omit(append([]string{"id", "created_at", clause.Associations}, omit...)...).
+		Updates(m)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return conflict
+	}
+	return nil
+}
+
+// claimVersion 以版本号为条件递增版本号并锁定行直至事务结束，
+// 用于需要连同关联记录一并保存的聚合根；版本号已变化时返回 conflict
+func claimVersion(db *gorm.DB, m interface{}, id string, version int, conflict error) error {
+	result := db.Model(m).
+		Where("id = ? AND version = ?", id, version).
+		UpdateColumn("version", gorm.Expr("version + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return conflict
+	}
+	return nil
+}
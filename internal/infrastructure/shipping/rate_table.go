@@ -0,0 +1,153 @@
+package shipping
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+	"github.com/oklog/ulid/v2"
+)
+
+// Zone 运费区域（按收货国家匹配，"*" 匹配所有国家）
+type Zone struct {
+	Name      string
+	Countries []string
+}
+
+// WeightBand 重量档：不超过 MaxWeightKg 的包裹按 Amount 计费
+type WeightBand struct {
+	MaxWeightKg float64
+	Amount      float64
+}
+
+// Service 运输服务在某一区域的费率
+type Service struct {
+	Code  string
+	Name  string
+	Zone  string
+	Days  int // 预计运输天数
+	Bands []WeightBand
+}
+
+// Config 运费表配置
+type Config struct {
+	Carrier        string // 承运商名称
+	Currency       string
+	TrackingPrefix string // 生成追踪号的前缀
+	Zones          []Zone
+	Services       []Service
+}
+
+// RateTable 基于配置运费表（区域 × 重量档）的离线承运商
+// 报价与购买面单无需网络；离线承运商没有轨迹来源，跟踪事件由管理员录入
+type RateTable struct {
+	config Config
+}
+
+// NewRateTable 创建运费表承运商，重量档按上限升序整理
+func NewRateTable(config Config) *RateTable {
+	for i := range config.Services {
+		bands := append([]WeightBand(nil), config.Services[i].Bands...)
+		sort.Slice(bands, func(a, b int) bool {
+			return bands[a].MaxWeightKg < bands[b].MaxWeightKg
+		})
+		config.Services[i].Bands = bands
+	}
+	return &RateTable{config: config}
+}
+
+// Quote 按收货国家所在区域与包裹重量报价，超出最高重量档的服务不提供，结果按运费升序
+func (t *RateTable) Quote(ctx context.Context, destination order.Address, parcel order.Parcel) ([]order.ShippingRate, error) {
+	zone, ok := t.zoneFor(destination.Country)
+	if !ok {
+		return []order.ShippingRate{}, nil
+	}
+
+	rates := make([]order.ShippingRate, 0)
+	for _, svc := range t.config.Services {
+		if svc.Zone != zone {
+			continue
+		}
+		band, ok := bandFor(svc.Bands, parcel.WeightKg)
+		if !ok {
+			continue
+		}
+		rates = append(rates, order.ShippingRate{
+			Carrier:       t.config.Carrier,
+			Service:       svc.Code,
+			ServiceName:   svc.Name,
+			Amount:        order.NewMoney(band.Amount, t.config.Currency),
+			EstimatedDays: svc.Days,
+		})
+	}
+
+	sort.SliceStable(rates, func(i, j int) bool {
+		return rates[i].Amount.Amount < rates[j].Amount.Amount
+	})
+	return rates, nil
+}
+
+// PurchaseLabel 生成追踪号作为面单，离线承运商不生成面单文件
+func (t *RateTable) PurchaseLabel(ctx context.Context, shipment *order.Shipment, parcel order.Parcel) (*order.ShippingLabel, error) {
+	if shipment.Carrier != "" && shipment.Carrier != t.config.Carrier {
+		return nil, fmt.Errorf("carrier %q is not supported by the rate table", shipment.Carrier)
+	}
+	if _, err := t.Quote(ctx, shipment.Address, parcel); err != nil {
+		return nil, err
+	}
+
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
+	return &order.ShippingLabel{
+		Carrier:        t.config.Carrier,
+		TrackingNumber: t.config.TrackingPrefix + ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String(),
+	}, nil
+}
+
+// PurchaseReturnLabel 为退货生成追踪号，取件地址需在可配送区域内
+func (t *RateTable) PurchaseReturnLabel(ctx context.Context, from order.Address, parcel order.Parcel) (*order.ShippingLabel, error) {
+	if _, err := t.Quote(ctx, from, parcel); err != nil {
+		return nil, err
+	}
+
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(time.Now().UnixNano())), 0)
+	return &order.ShippingLabel{
+		Carrier:        t.config.Carrier,
+		TrackingNumber: t.config.TrackingPrefix + "R" + ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String(),
+	}, nil
+}
+
+// Track 离线承运商没有轨迹来源，始终返回空
+func (t *RateTable) Track(ctx context.Context, trackingNumber string) ([]order.TrackingUpdate, error) {
+	return nil, nil
+}
+
+// zoneFor 查找收货国家所在区域，具体国家优先于通配区域
+func (t *RateTable) zoneFor(country string) (string, bool) {
+	country = strings.ToUpper(country)
+	fallback := ""
+	for _, zone := range t.config.Zones {
+		for _, c := range zone.Countries {
+			if strings.ToUpper(c) == country {
+				return zone.Name, true
+			}
+			if c == "*" && fallback == "" {
+				fallback = zone.Name
+			}
+		}
+	}
+	return fallback, fallback != ""
+}
+
+// bandFor 查找包裹重量所在的重量档
+func bandFor(bands []WeightBand, weightKg float64) (WeightBand, bool) {
+	for _, band := range bands {
+		if weightKg <= band.MaxWeightKg {
+			return band, true
+		}
+	}
+	return WeightBand{}, false
+}
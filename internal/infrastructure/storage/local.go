@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalBlobStore 基于本地文件系统的对象存储
+type LocalBlobStore struct {
+	basePath string
+}
+
+// defaultBasePath 未配置时的默认存储目录
+const defaultBasePath = "./storage"
+
+// NewLocalBlobStore 创建本地对象存储
+func NewLocalBlobStore(basePath string) (*LocalBlobStore, error) {
+	if basePath == "" {
+		basePath = defaultBasePath
+	}
+	if err := os.MkdirAll(basePath, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create blob store directory: %w", err)
+	}
+	return &LocalBlobStore{basePath: basePath}, nil
+}
+
+// Get 读取对象，不存在时 found 为 false
+func (s *LocalBlobStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return data, true, nil
+}
+
+// Put 写入对象（先写临时文件再重命名，避免读到不完整的内容）
+func (s *LocalBlobStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".blob-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Create 以流式写入对象，Close 时提交（同样先写临时文件再重命名）
+func (s *LocalBlobStore) Create(ctx context.Context, key, contentType string) (io.WriteCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".blob-*")
+	if err != nil {
+		return nil, err
+	}
+	return &blobWriter{File: tmp, path: path}, nil
+}
+
+// Open 以流式读取对象，不存在时 found 为 false
+func (s *LocalBlobStore) Open(ctx context.Context, key string) (io.ReadCloser, bool, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return f, true, nil
+}
+
+// blobWriter 写入临时文件，关闭时重命名为目标文件
+type blobWriter struct {
+	*os.File
+	path string
+}
+
+// Close 关闭临时文件并提交，失败时删除临时文件
+func (w *blobWriter) Close() error {
+	if err := w.File.Close(); err != nil {
+		os.Remove(w.File.Name())
+		return err
+	}
+	if err := os.Rename(w.File.Name(), w.path); err != nil {
+		os.Remove(w.File.Name())
+		return err
+	}
+	return nil
+}
+
+// path 将对象键转换为文件路径，拒绝越出存储目录的键
+func (s *LocalBlobStore) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if strings.Contains(key, "..") || clean == "/" {
+		return "", fmt.Errorf("invalid blob key: %q", key)
+	}
+	return filepath.Join(s.basePath, clean), nil
+}
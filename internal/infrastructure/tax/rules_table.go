@@ -0,0 +1,154 @@
+package tax
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/domain/order"
+)
+
+// Rule 税率规则（按国家/州匹配）
+type Rule struct {
+	Country   string
+	State     string // 为空表示适用于整个国家
+	Name      string
+	Rate      float64
+	Inclusive *bool // 为空时使用全局配置
+}
+
+// Config 税率表配置
+type Config struct {
+	OriginCountry          string   // 卖方所在国家
+	PricesIncludeTax       bool     // 默认是否含税定价
+	ReverseChargeCountries []string // 适用 B2B 反向征收的国家（通常为欧盟成员国）
+	Rules                  []Rule
+}
+
+// vatIDPattern 增值税号格式：两位国家前缀 + 2-13 位字母数字
+var vatIDPattern = regexp.MustCompile(`^[A-Z]{2}[0-9A-Z]{2,13}$`)
+
+// RulesTable 基于配置税率表的税额计算器
+type RulesTable struct {
+	config Config
+}
+
+// NewRulesTable 创建税率表计算器
+func NewRulesTable(config Config) *RulesTable {
+	return &RulesTable{config: config}
+}
+
+// Calculate 计算订单税额
+func (t *RulesTable) Calculate(ctx context.Context, o *order.Order) (*order.TaxBreakdown, error) {
+	breakdown := &order.TaxBreakdown{
+		Lines:     make([]order.TaxLine, 0, len(o.Items)),
+		Inclusive: t.config.PricesIncludeTax,
+	}
+
+	// 无收货地址时无法确定辖区，不计税
+	if o.ShippingAddress == nil {
+		return breakdown, nil
+	}
+
+	country := strings.ToUpper(o.ShippingAddress.Country)
+	state := strings.ToUpper(o.ShippingAddress.State)
+
+	rule, ok := t.match(country, state)
+	if !ok {
+		return breakdown, nil
+	}
+	if rule.Inclusive != nil {
+		breakdown.Inclusive = *rule.Inclusive
+	}
+
+	if t.isReverseCharge(country, o.VATID) {
+		breakdown.ReverseCharge = true
+		breakdown.Note = fmt.Sprintf("Reverse charge: VAT to be accounted for by the recipient (%s)", normalizeVATID(o.VATID))
+		return breakdown, nil
+	}
+
+	jurisdiction := country
+	if rule.State != "" {
+		jurisdiction = country + "-" + strings.ToUpper(rule.State)
+	}
+
+	for _, item := range o.Items {
+		base := o.TaxableAmount(item)
+
+		var amount float64
+		if breakdown.Inclusive {
+			amount = base - base/(1+rule.Rate)
+		} else {
+			amount = base * rule.Rate
+		}
+
+		breakdown.Lines = append(breakdown.Lines, order.TaxLine{
+			ItemID:       item.ID,
+			Jurisdiction: jurisdiction,
+			Name:         rule.Name,
+			Rate:         rule.Rate,
+			Amount:       order.NewMoney(math.Round(amount*100)/100, item.Subtotal.Currency),
+		})
+	}
+
+	return breakdown, nil
+}
+
+// match 查找税率规则：优先匹配国家+州，其次匹配国家级规则
+func (t *RulesTable) match(country, state string) (Rule, bool) {
+	var countryRule *Rule
+	for i := range t.config.Rules {
+		rule := t.config.Rules[i]
+		if !strings.EqualFold(rule.Country, country) {
+			continue
+		}
+		if rule.State == "" {
+			if countryRule == nil {
+				countryRule = &t.config.Rules[i]
+			}
+			continue
+		}
+		if state != "" && strings.EqualFold(rule.State, state) {
+			return rule, true
+		}
+	}
+	if countryRule != nil {
+		return *countryRule, true
+	}
+	return Rule{}, false
+}
+
+// isReverseCharge 判断是否适用反向征收：买方提供有效税号，且位于其他反向征收国家
+func (t *RulesTable) isReverseCharge(country, vatID string) bool {
+	vatID = normalizeVATID(vatID)
+	if vatID == "" || !vatIDPattern.MatchString(vatID) {
+		return false
+	}
+	if strings.EqualFold(country, t.config.OriginCountry) {
+		return false
+	}
+
+	// 希腊增值税号使用 EL 前缀
+	prefix := vatID[:2]
+	if prefix == "EL" {
+		prefix = "GR"
+	}
+	if prefix != country {
+		return false
+	}
+
+	for _, c := range t.config.ReverseChargeCountries {
+		if strings.EqualFold(c, country) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeVATID 规范化增值税号（去除空格、点和横线并转大写）
+func normalizeVATID(vatID string) string {
+	replacer := strings.NewReplacer(" ", "", ".", "", "-", "")
+	return strings.ToUpper(replacer.Replace(vatID))
+}
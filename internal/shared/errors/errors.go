@@ -19,6 +19,9 @@ const (
 	CodeValidation      ErrorCode = "VALIDATION_ERROR"
 	CodeTooManyRequests ErrorCode = "TOO_MANY_REQUESTS"
 
+	// 请求格式正确但无法处理（如幂等键与请求不匹配）
+	CodeUnprocessableEntity ErrorCode = "UNPROCESSABLE_ENTITY"
+
 	// 用户相关错误码
 	CodeUserNotFound       ErrorCode = "USER_NOT_FOUND"
 	CodeUserAlreadyExists  ErrorCode = "USER_ALREADY_EXISTS"
@@ -6,7 +6,9 @@ import (
 	"os"
 
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/commands/api"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/commands/export"
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/commands/migrate"
+	"github.com/lwmacct/251112-go-ddd-skeleton/internal/commands/stripemock"
 	"github.com/lwmacct/251112-go-ddd-skeleton/internal/commands/worker"
 	"github.com/urfave/cli/v3"
 )
@@ -36,11 +38,12 @@ func buildCommands() []*cli.Command {
 		api.Command,     // 🟢 API Service - REST API 服务
 		worker.Command,  // 🔧 Worker - 后台任务处理器
 		migrate.Command, // 🗄️  Migrate - 数据库迁移工具
+		export.Command,  // 📤 Export - 数据导出工具
 	}
 
 	if os.Getenv("SHOW_CLI_ITEM") == "1" {
 		// 可以在这里添加额外的调试或开发命令
-		commands = append([]*cli.Command{}, commands...)
+		commands = append(commands, stripemock.Command) // 💳 Stripe Mock - 本地支付网关模拟
 	}
 
 	return commands
@@ -0,0 +1,40 @@
+#!/bin/bash
+
+# Stripe 回调测试脚本
+# 使用本地密钥对夹具签名后投递到 /api/webhooks/stripe
+#
+# 用法:
+#   STRIPE_WEBHOOK_SECRET=whsec_xxx ./scripts/stripe_webhook.sh payment_intent.succeeded [pi_xxx]
+#
+# 第二个参数可替换夹具中的 pi_test_0001，以便指向真实的支付交易号
+
+set -e
+
+API_URL="${API_URL:-http://localhost:8080}"
+SECRET="${STRIPE_WEBHOOK_SECRET:-whsec_your-stripe-webhook-secret}"
+FIXTURE_DIR="$(cd "$(dirname "$0")" && pwd)/fixtures/stripe"
+
+EVENT="${1:?usage: $0 <event-type> [payment-intent-id]}"
+FIXTURE="${FIXTURE_DIR}/${EVENT}.json"
+if [ ! -f "$FIXTURE" ]; then
+  echo "fixture not found: $FIXTURE" >&2
+  exit 1
+fi
+
+PAYLOAD="$(cat "$FIXTURE")"
+if [ -n "$2" ]; then
+  PAYLOAD="${PAYLOAD//pi_test_0001/$2}"
+fi
+# 每次投递使用新的事件ID，重复投递同一ID可验证去重（设置 KEEP_EVENT_ID=1）
+if [ -z "$KEEP_EVENT_ID" ]; then
+  PAYLOAD="$(printf '%s' "$PAYLOAD" | sed -E "s/\"id\": \"evt_([a-z_]+)_[0-9]+\"/\"id\": \"evt_\1_$(date +%s%N)\"/")"
+fi
+
+TIMESTAMP="$(date +%s)"
+SIGNATURE="$(printf '%s.%s' "$TIMESTAMP" "$PAYLOAD" | openssl dgst -sha256 -hmac "$SECRET" | sed 's/^.* //')"
+
+curl -sS -X POST "${API_URL}/api/webhooks/stripe" \
+  -H "Content-Type: application/json" \
+  -H "Stripe-Signature: t=${TIMESTAMP},v1=${SIGNATURE}" \
+  --data-binary "$PAYLOAD"
+echo